@@ -31,6 +31,7 @@ import (
 
 	"k8s.io/ingress-gce/pkg/context"
 	"k8s.io/ingress-gce/pkg/flags"
+	"k8s.io/ingress-gce/pkg/neg/syncers/labels"
 	"k8s.io/ingress-gce/pkg/version"
 )
 
@@ -45,6 +46,17 @@ func RunHTTPServer(healthChecker func() context.HealthCheckResults, logger klog.
 	klog.Fatal(http.ListenAndServe(fmt.Sprintf(":%v", flags.F.HealthzPort), nil))
 }
 
+// RunLabelPropagationWebhookServer starts the HTTPS server of the admission
+// webhook warning about pod labels which will be truncated by NEG label
+// propagation.
+func RunLabelPropagationWebhookServer(lpConfigStore *labels.ConfigStore, logger klog.Logger) {
+	mux := http.NewServeMux()
+	mux.Handle(labels.TruncationWarningWebhookPath, labels.NewTruncationWarningWebhook(lpConfigStore, logger))
+
+	logger.V(0).Info("Running label propagation webhook server", "port", flags.F.LabelPropagationWebhookPort)
+	klog.Fatal(http.ListenAndServeTLS(fmt.Sprintf(":%v", flags.F.LabelPropagationWebhookPort), flags.F.LabelPropagationWebhookCertFile, flags.F.LabelPropagationWebhookKeyFile, mux))
+}
+
 func RunSIGTERMHandler(closeStopCh func(), logger klog.Logger) {
 	// Multiple SIGTERMs will get dropped
 	signalChan := make(chan os.Signal, 1)
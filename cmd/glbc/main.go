@@ -32,6 +32,7 @@ import (
 	flag "github.com/spf13/pflag"
 	crdclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	clientset "k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
@@ -177,6 +178,11 @@ func main() {
 		if _, err := crdHandler.EnsureCRD(serviceAttachmentCRDMeta, true); err != nil {
 			klog.Fatalf("Failed to ensure ServiceAttachment CRD: %v", err)
 		}
+		if flags.F.EnablePSCConsumer {
+			if _, err := crdHandler.EnsureCRD(serviceattachment.ConsumerCRDMeta(), true); err != nil {
+				klog.Fatalf("Failed to ensure ServiceAttachmentConsumer CRD: %v", err)
+			}
+		}
 
 		svcAttachmentClient, err = serviceattachmentclient.NewForConfig(kubeConfig)
 		if err != nil {
@@ -256,6 +262,10 @@ func main() {
 		ASMConfigMapNamespace:         flags.F.ASMConfigMapBasedConfigNamespace,
 		ASMConfigMapName:              flags.F.ASMConfigMapBasedConfigCMName,
 		MaxIGSize:                     flags.F.MaxIGSize,
+		ManagedIGNodeLabel:            flags.F.ManagedInstanceGroupsNodeLabel,
+		MaxConcurrentIGZoneSyncs:      flags.F.MaxConcurrentIGZoneSyncs,
+		IGAddInstancesBatchSize:       flags.F.IGAddInstancesBatchSize,
+		EnableMultipleIGs:             flags.F.EnableMultipleIGs,
 		EnableL4ILBDualStack:          flags.F.EnableL4ILBDualStack,
 		EnableL4NetLBDualStack:        flags.F.EnableL4NetLBDualStack,
 		EnableL4StrongSessionAffinity: flags.F.EnableL4StrongSessionAffinity,
@@ -264,6 +274,7 @@ func main() {
 		EnableWeightedL4ILB:           flags.F.EnableWeightedL4ILB,
 		EnableWeightedL4NetLB:         flags.F.EnableWeightedL4NetLB,
 		DisableL4LBFirewall:           flags.F.DisableL4LBFirewall,
+		EnablePSCConsumer:             flags.F.EnablePSCConsumer,
 	}
 	ctx := ingctx.NewControllerContext(kubeConfig, kubeClient, backendConfigClient, frontendConfigClient, firewallCRClient, svcNegClient, ingParamsClient, svcAttachmentClient, networkClient, nodeTopologyClient, eventRecorderKubeClient, cloud, namer, kubeSystemUID, ctxConfig, rootLogger)
 	go app.RunHTTPServer(ctx.HealthCheck, rootLogger)
@@ -420,6 +431,11 @@ func runControllers(ctx *ingctx.ControllerContext, option runOption, logger klog
 		pscController := psc.NewController(ctx, option.stopCh, logger)
 		runWithWg(pscController.Run, option.wg)
 		logger.V(0).Info("PSC Controller started")
+		if flags.F.EnablePSCConsumer {
+			pscConsumerController := psc.NewConsumerController(ctx, option.stopCh, logger)
+			runWithWg(pscConsumerController.Run, option.wg)
+			logger.V(0).Info("PSC Consumer Controller started")
+		}
 	}
 
 	go app.RunSIGTERMHandler(option.closeStopCh, logger)
@@ -446,6 +462,12 @@ func runControllers(ctx *ingctx.ControllerContext, option runOption, logger klog
 		runWithWg(l4netlbController.Run, option.wg)
 		logger.V(0).Info("L4NetLB controller started")
 	}
+
+	if flags.F.EnableL4OrphanScanner && (flags.F.RunL4Controller || flags.F.RunL4NetLBController) {
+		l4OrphanScanner := l4lb.NewL4OrphanScanner(ctx, option.stopCh, logger)
+		runWithWg(l4OrphanScanner.Run, option.wg)
+		logger.V(0).Info("L4 orphan scanner started", "dryRun", flags.F.L4OrphanScanDryRun)
+	}
 }
 
 func makeNEGLeaderElectionConfig(ctx *ingctx.ControllerContext, option runOption, logger klog.Logger) (*leaderelection.LeaderElectionConfig, error) {
@@ -530,6 +552,26 @@ func createNEGController(ctx *ingctx.ControllerContext, stopCh <-chan struct{},
 			logger.Error(err, "Failed to retrieve pod label propagation config")
 		}
 	}
+	lpConfigStore, err := labels.NewConfigStore(lpConfig, logger)
+	if err != nil {
+		logger.Error(err, "Invalid pod label propagation config, no labels are propagated")
+		lpConfigStore, _ = labels.NewConfigStore(labels.PodLabelPropagationConfig{}, logger)
+	}
+	if flags.F.EnableNEGLabelPropagation && flags.F.LabelPropagationConfigMapName != "" {
+		informerFactory := informers.NewSharedInformerFactoryWithOptions(
+			ctx.KubeClient,
+			flags.F.ResyncPeriod,
+			informers.WithNamespace(flags.F.LabelPropagationConfigMapNS),
+			informers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+				listOptions.FieldSelector = fmt.Sprintf("metadata.name=%s", flags.F.LabelPropagationConfigMapName)
+			}))
+		configMapInformer := informerFactory.Core().V1().ConfigMaps().Informer()
+		lpConfigStore.RegisterInformer(configMapInformer, flags.F.LabelPropagationConfigMapNS, flags.F.LabelPropagationConfigMapName)
+		go configMapInformer.Run(stopCh)
+	}
+	if flags.F.EnableNEGLabelPropagation && flags.F.LabelPropagationWebhookPort != 0 {
+		go app.RunLabelPropagationWebhookServer(lpConfigStore, logger)
+	}
 
 	// The following adapter will use Network Selflink as Network Url instead of the NetworkUrl itself.
 	// Network Selflink is always composed by the network name even if the cluster was initialized with Network Id.
@@ -570,7 +612,7 @@ func createNEGController(ctx *ingctx.ControllerContext, stopCh <-chan struct{},
 		flags.F.EnableDualStackNEG,
 		enableAsm,
 		asmServiceNEGSkipNamespaces,
-		lpConfig,
+		lpConfigStore,
 		flags.F.EnableMultiNetworking,
 		ctx.EnableIngressRegionalExternal,
 		stopCh,
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// FirewallOptionsKey is the annotation key for the logging and priority overrides of the
+	// VPC firewall rules managed for a Service or an Ingress. The value is a JSON encoded
+	// FirewallOptions, e.g.
+	// {"logging": true, "logMetadata": "EXCLUDE_ALL_METADATA", "priority": 900}
+	// On a Service, it applies to the firewall rules of its L4 load balancer, except the health
+	// check firewall rule shared with other Services. On an Ingress, it only applies to the
+	// firewall rule of the Ingress when per Ingress firewall rules are enabled.
+	FirewallOptionsKey = "networking.gke.io/firewall-options"
+
+	// Metadata modes of firewall rule logging.
+	FirewallLogMetadataIncludeAll = "INCLUDE_ALL_METADATA"
+	FirewallLogMetadataExcludeAll = "EXCLUDE_ALL_METADATA"
+
+	// MaxFirewallPriority is the lowest priority of a GCE firewall rule.
+	MaxFirewallPriority = 65535
+)
+
+var ErrFirewallOptionsInvalid = errors.New("firewall options annotation is invalid")
+
+// FirewallOptions is the format of the annotation associated with the FirewallOptionsKey key.
+// Fields that are not specified keep the values of the controller flags.
+type FirewallOptions struct {
+	// Logging enables firewall rules logging.
+	Logging *bool `json:"logging,omitempty"`
+	// LogMetadata is the metadata mode of the logs, if logging is enabled.
+	LogMetadata string `json:"logMetadata,omitempty"`
+	// Priority of the firewall rules, from 0 (highest) to 65535.
+	Priority *int64 `json:"priority,omitempty"`
+}
+
+// validate returns an error if the options can't be applied to a GCE firewall rule.
+func (o *FirewallOptions) validate() error {
+	switch o.LogMetadata {
+	case "", FirewallLogMetadataIncludeAll, FirewallLogMetadataExcludeAll:
+	default:
+		return fmt.Errorf("logMetadata %q is not one of %s, %s", o.LogMetadata, FirewallLogMetadataIncludeAll, FirewallLogMetadataExcludeAll)
+	}
+	if o.LogMetadata != "" && o.Logging != nil && !*o.Logging {
+		return fmt.Errorf("logMetadata can only be set if logging is enabled")
+	}
+	if o.Priority != nil && (*o.Priority < 0 || *o.Priority > MaxFirewallPriority) {
+		return fmt.Errorf("priority %d is out of range [0, %d]", *o.Priority, MaxFirewallPriority)
+	}
+	return nil
+}
+
+// FirewallOptions returns the overrides of the firewall options annotation, or nil
+// if the annotation is not specified.
+func (svc *Service) FirewallOptions() (*FirewallOptions, error) {
+	annotation, ok := svc.v[FirewallOptionsKey]
+	if !ok {
+		return nil, nil
+	}
+	return parseFirewallOptions(annotation)
+}
+
+// FirewallOptions returns the overrides of the firewall options annotation, or nil
+// if the annotation is not specified.
+func (ing *Ingress) FirewallOptions() (*FirewallOptions, error) {
+	annotation, ok := ing.v[FirewallOptionsKey]
+	if !ok {
+		return nil, nil
+	}
+	return parseFirewallOptions(annotation)
+}
+
+func parseFirewallOptions(annotation string) (*FirewallOptions, error) {
+	var res FirewallOptions
+	decoder := json.NewDecoder(strings.NewReader(annotation))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&res); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFirewallOptionsInvalid, err)
+	}
+	if err := res.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFirewallOptionsInvalid, err)
+	}
+	return &res, nil
+}
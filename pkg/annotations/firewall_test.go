@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotations
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFirewallOptions(t *testing.T) {
+	enabled, disabled := true, false
+	priority, highestPriority := int64(900), int64(0)
+
+	for _, tc := range []struct {
+		desc       string
+		annotation string
+		want       *FirewallOptions
+		wantErr    bool
+	}{
+		{
+			desc: "annotation not specified",
+		},
+		{
+			desc:       "all options",
+			annotation: `{"logging": true, "logMetadata": "EXCLUDE_ALL_METADATA", "priority": 900}`,
+			want: &FirewallOptions{
+				Logging:     &enabled,
+				LogMetadata: FirewallLogMetadataExcludeAll,
+				Priority:    &priority,
+			},
+		},
+		{
+			desc:       "logging disabled",
+			annotation: `{"logging": false}`,
+			want:       &FirewallOptions{Logging: &disabled},
+		},
+		{
+			desc:       "highest priority",
+			annotation: `{"priority": 0}`,
+			want:       &FirewallOptions{Priority: &highestPriority},
+		},
+		{
+			desc:       "metadata of logging enabled by flag",
+			annotation: `{"logMetadata": "INCLUDE_ALL_METADATA"}`,
+			want:       &FirewallOptions{LogMetadata: FirewallLogMetadataIncludeAll},
+		},
+		{
+			desc:       "invalid json",
+			annotation: `{"logging": true`,
+			wantErr:    true,
+		},
+		{
+			desc:       "unknown option",
+			annotation: `{"action": "DENY"}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "unknown metadata",
+			annotation: `{"logging": true, "logMetadata": "CUSTOM_METADATA"}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "metadata with logging disabled",
+			annotation: `{"logging": false, "logMetadata": "INCLUDE_ALL_METADATA"}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "priority out of range",
+			annotation: `{"priority": 65536}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "negative priority",
+			annotation: `{"priority": -1}`,
+			wantErr:    true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			annotations := map[string]string{}
+			if tc.annotation != "" {
+				annotations[FirewallOptionsKey] = tc.annotation
+			}
+			svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+			ing := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+
+			for name, options := range map[string]func() (*FirewallOptions, error){
+				"Service": FromService(svc).FirewallOptions,
+				"Ingress": FromIngress(ing).FirewallOptions,
+			} {
+				got, err := options()
+				if gotErr := err != nil; gotErr != tc.wantErr {
+					t.Errorf("%s FirewallOptions() = %v, want error %v", name, err, tc.wantErr)
+				}
+				if err != nil && !errors.Is(err, ErrFirewallOptionsInvalid) {
+					t.Errorf("%s FirewallOptions() = %v, want %v", name, err, ErrFirewallOptionsInvalid)
+				}
+				if !reflect.DeepEqual(got, tc.want) {
+					t.Errorf("%s FirewallOptions() = %+v, want %+v", name, got, tc.want)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package annotations
 
 import (
 	"errors"
+	"slices"
 	"strings"
 
 	"google.golang.org/api/googleapi"
@@ -14,10 +15,14 @@ type IPVersion = string
 const (
 	// StaticL4AddressesAnnotationKey is new annotation key to specify static IPs (by name) for the services.
 	// Supports both IPv4 and IPv6
-	StaticL4AddressesAnnotationKey           = "networking.gke.io/load-balancer-ip-addresses"
-	maxNumberOfAddresses                     = 2
-	IPv4Version                    IPVersion = "IPV4"
-	IPv6Version                    IPVersion = "IPV6"
+	StaticL4AddressesAnnotationKey = "networking.gke.io/load-balancer-ip-addresses"
+	// MaxAddressesPerIPVersion is the maximum number of addresses of an IP version in the
+	// static addresses annotation. The first address of a version is the main address of the
+	// Service, the others get additional forwarding rules.
+	MaxAddressesPerIPVersion           = 5
+	maxNumberOfAddresses               = 2 * MaxAddressesPerIPVersion
+	IPv4Version              IPVersion = "IPV4"
+	IPv6Version              IPVersion = "IPV6"
 )
 
 // IPv4AddressAnnotation return IPv4 address from networking.gke.io/load-balancer-ip-addresses annotation.
@@ -32,23 +37,53 @@ func (svc *Service) IPv6AddressAnnotation(cloud *gce.Cloud) (string, error) {
 	return ipAddressFromAnnotation(svc, cloud, IPv6Version)
 }
 
-// ipAddressFromAnnotation checks annotation networking.gke.io/load-balancer-ip-addresses,
-// which should store comma separate names of IP Addresses reserved in google cloud,
-// and returns first address that matches required IpVersion (IPV4 or IPV6).
+// AdditionalIPv4AddressesAnnotation returns the IPv4 addresses after the first one from
+// networking.gke.io/load-balancer-ip-addresses annotation.
+func (svc *Service) AdditionalIPv4AddressesAnnotation(cloud *gce.Cloud) ([]string, error) {
+	addresses, err := ipAddressesFromAnnotation(svc, cloud, IPv4Version)
+	if len(addresses) < 2 {
+		return nil, err
+	}
+	return addresses[1:], err
+}
+
+// AdditionalIPv6AddressesAnnotation returns the IPv6 addresses after the first one from
+// networking.gke.io/load-balancer-ip-addresses annotation.
+func (svc *Service) AdditionalIPv6AddressesAnnotation(cloud *gce.Cloud) ([]string, error) {
+	addresses, err := ipAddressesFromAnnotation(svc, cloud, IPv6Version)
+	if len(addresses) < 2 {
+		return nil, err
+	}
+	return addresses[1:], err
+}
+
+// ipAddressFromAnnotation returns the first address from networking.gke.io/load-balancer-ip-addresses
+// annotation that matches required IpVersion (IPV4 or IPV6).
 func ipAddressFromAnnotation(svc *Service, cloud *gce.Cloud, ipVersion string) (string, error) {
+	addresses, err := ipAddressesFromAnnotation(svc, cloud, ipVersion)
+	if err != nil || len(addresses) == 0 {
+		return "", err
+	}
+	return addresses[0], nil
+}
+
+// ipAddressesFromAnnotation checks annotation networking.gke.io/load-balancer-ip-addresses,
+// which should store comma separate names of IP Addresses reserved in google cloud,
+// and returns the distinct addresses that match required IpVersion (IPV4 or IPV6), in the annotation order.
+func ipAddressesFromAnnotation(svc *Service, cloud *gce.Cloud, ipVersion string) ([]string, error) {
 	annotationVal, ok := svc.v[StaticL4AddressesAnnotationKey]
 	if !ok {
-		return "", nil
+		return nil, nil
 	}
 
 	addressNames := strings.Split(annotationVal, ",")
 
-	// Truncated to 2 values (this is technically maximum, 1 IPv4 and 1 IPv6 address)
-	// to not make too many API calls.
+	// Truncated to the maximum number of IPv4 and IPv6 addresses to not make too many API calls.
 	if len(addressNames) > maxNumberOfAddresses {
 		addressNames = addressNames[:maxNumberOfAddresses]
 	}
 
+	var addresses []string
 	for _, addressName := range addressNames {
 		trimmedAddressName := strings.TrimSpace(addressName)
 		cloudAddress, err := cloud.GetRegionAddress(trimmedAddressName, cloud.Region())
@@ -56,17 +91,17 @@ func ipAddressFromAnnotation(svc *Service, cloud *gce.Cloud, ipVersion string) (
 			if isNotFoundError(err) {
 				continue
 			}
-			return "", err
+			return nil, err
 		}
 		if cloudAddress.IpVersion == "" {
 			cloudAddress.IpVersion = IPv4Version
 		}
 
-		if cloudAddress.IpVersion == ipVersion {
-			return cloudAddress.Address, nil
+		if cloudAddress.IpVersion == ipVersion && len(addresses) < MaxAddressesPerIPVersion && !slices.Contains(addresses, cloudAddress.Address) {
+			addresses = append(addresses, cloudAddress.Address)
 		}
 	}
-	return "", nil
+	return addresses, nil
 }
 
 func isNotFoundError(err error) bool {
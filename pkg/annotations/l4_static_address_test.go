@@ -2,6 +2,7 @@ package annotations
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"google.golang.org/api/compute/v1"
@@ -20,6 +21,11 @@ func TestAddressFromAnnotation(t *testing.T) {
 	ipv4AddressName := "ipv4-address"
 	ipv4AddressNoVersionName := "ipv4-address-no-version"
 	ipv6AddressName := "ipv6-address"
+	secondIPv6Address := compute.Address{
+		Name:      "ipv6-address-2",
+		Address:   "0::2",
+		IpVersion: IPv6Version,
+	}
 
 	ipv4Address := compute.Address{
 		Name:      ipv4AddressName,
@@ -36,11 +42,13 @@ func TestAddressFromAnnotation(t *testing.T) {
 		IpVersion: IPv6Version,
 	}
 	testCases := []struct {
-		desc              string
-		reservedAddresses []compute.Address
-		annotationVal     string
-		wantIPv4Address   string
-		wantIPv6Address   string
+		desc               string
+		reservedAddresses  []compute.Address
+		annotationVal      string
+		wantIPv4Address    string
+		wantIPv6Address    string
+		wantAdditionalIPv4 []string
+		wantAdditionalIPv6 []string
 	}{
 		{
 			desc: "Single existing IPv4 address",
@@ -91,6 +99,20 @@ func TestAddressFromAnnotation(t *testing.T) {
 			wantIPv4Address: ipv4Address.Address,
 			wantIPv6Address: ipv6Address.Address,
 		},
+		{
+			desc: "Multiple addresses per IP version",
+			reservedAddresses: []compute.Address{
+				ipv4Address,
+				ipv4AddressNoVersion,
+				ipv6Address,
+				secondIPv6Address,
+			},
+			annotationVal:      fmt.Sprintf("%s,%s,%s,%s", ipv6Address.Name, ipv4AddressNoVersion.Name, ipv4Address.Name, secondIPv6Address.Name),
+			wantIPv4Address:    ipv4AddressNoVersion.Address,
+			wantIPv6Address:    ipv6Address.Address,
+			wantAdditionalIPv4: []string{ipv4Address.Address},
+			wantAdditionalIPv6: []string{secondIPv6Address.Address},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -126,6 +148,22 @@ func TestAddressFromAnnotation(t *testing.T) {
 			if ipv6Addr != tc.wantIPv6Address {
 				t.Errorf("IPv6AddressAnnotation(..., %s) returned %s, not equal to expected = %s", tc.annotationVal, ipv6Addr, tc.wantIPv6Address)
 			}
+
+			// Verify getting expected additional addresses from annotation.
+			additionalIPv4, err := FromService(svc).AdditionalIPv4AddressesAnnotation(fakeGCE)
+			if err != nil {
+				t.Fatalf("AdditionalIPv4AddressesAnnotation(..., %s) returned error %v", tc.annotationVal, err)
+			}
+			if !reflect.DeepEqual(additionalIPv4, tc.wantAdditionalIPv4) {
+				t.Errorf("AdditionalIPv4AddressesAnnotation(..., %s) returned %v, not equal to expected = %v", tc.annotationVal, additionalIPv4, tc.wantAdditionalIPv4)
+			}
+			additionalIPv6, err := FromService(svc).AdditionalIPv6AddressesAnnotation(fakeGCE)
+			if err != nil {
+				t.Fatalf("AdditionalIPv6AddressesAnnotation(..., %s) returned error %v", tc.annotationVal, err)
+			}
+			if !reflect.DeepEqual(additionalIPv6, tc.wantAdditionalIPv6) {
+				t.Errorf("AdditionalIPv6AddressesAnnotation(..., %s) returned %v, not equal to expected = %v", tc.annotationVal, additionalIPv6, tc.wantAdditionalIPv6)
+			}
 		})
 	}
 }
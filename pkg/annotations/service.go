@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
@@ -69,6 +70,34 @@ const (
 	// THCAnnotationKey is the boolean annotation key to enable Transparent Health Checks.
 	THCAnnotationKey = "networking.gke.io/transparent-health-checker"
 
+	// NEGReadinessBackendServicesKey is the annotation key to select the
+	// backend services which must report an endpoint as healthy before the NEG
+	// readiness gate of the corresponding pod is marked True. The value is
+	// either "all", or a comma separated list of backend service names.
+	// If not set, the endpoint being healthy in any backend service is enough.
+	// If invalid, pods are not marked ready until it is fixed.
+	// Examples:
+	// - `all`
+	// - `k8s1-internal-backend,k8s1-external-backend`
+	NEGReadinessBackendServicesKey = "networking.gke.io/neg-readiness-backend-services"
+	// NEGReadinessAllBackendServices requires the endpoint to be healthy in
+	// all backend services the NEG is attached to.
+	NEGReadinessAllBackendServices = "all"
+
+	// NEGSyncPriorityKey is the annotation key to set the priority of the NEG
+	// syncers of the Service. When the number of concurrent NEG syncs is
+	// limited, syncers of higher priority get a larger share of the syncs.
+	// Valid values are "high", "normal" and "low". Defaults to "normal".
+	NEGSyncPriorityKey = "networking.gke.io/neg-sync-priority"
+	// NEGSyncPriorityHigh is the priority for critical frontends, which should
+	// converge first during cluster-wide events such as node pool upgrades.
+	NEGSyncPriorityHigh NEGSyncPriority = "high"
+	// NEGSyncPriorityNormal is the default priority.
+	NEGSyncPriorityNormal NEGSyncPriority = "normal"
+	// NEGSyncPriorityLow is the priority for Services that can tolerate
+	// slower convergence.
+	NEGSyncPriorityLow NEGSyncPriority = "low"
+
 	// ProtocolHTTP protocol for a service
 	ProtocolHTTP AppProtocol = "HTTP"
 	// ProtocolHTTPS protocol for a service
@@ -86,12 +115,24 @@ const (
 	// UDPForwardingRuleKey is the annotation key used by l4 controller to record
 	// GCP UDP forwarding rule name.
 	UDPForwardingRuleKey = ServiceStatusPrefix + "/udp-" + ForwardingRuleResource
+	// L3ForwardingRuleKey is the annotation key used by l4 controller to record
+	// GCP L3_DEFAULT forwarding rule name of services that mix protocols.
+	L3ForwardingRuleKey = ServiceStatusPrefix + "/l3-" + ForwardingRuleResource
 	// TCPForwardingRuleIPv6Key is the annotation key used by l4 controller to record
 	// GCP IPv6 TCP forwarding rule name.
 	TCPForwardingRuleIPv6Key = TCPForwardingRuleKey + IPv6Suffix
 	// UDPForwardingRuleIPv6Key is the annotation key used by l4 controller to record
 	// GCP IPv6 UDP forwarding rule name.
 	UDPForwardingRuleIPv6Key = UDPForwardingRuleKey + IPv6Suffix
+	// L3ForwardingRuleIPv6Key is the annotation key used by l4 controller to record
+	// GCP IPv6 L3_DEFAULT forwarding rule name of services that mix protocols.
+	L3ForwardingRuleIPv6Key = L3ForwardingRuleKey + IPv6Suffix
+	// AdditionalForwardingRulesKey is the annotation key used by l4 controller to record
+	// the comma separated names of the GCP forwarding rules of the additional IPv4 addresses.
+	AdditionalForwardingRulesKey = ServiceStatusPrefix + "/additional-" + ForwardingRuleResource + "s"
+	// AdditionalForwardingRulesIPv6Key is the annotation key used by l4 controller to record
+	// the comma separated names of the GCP forwarding rules of the additional IPv6 addresses.
+	AdditionalForwardingRulesIPv6Key = AdditionalForwardingRulesKey + IPv6Suffix
 	// BackendServiceKey is the annotation key used by l4 controller to record
 	// GCP Backend service name.
 	BackendServiceKey = ServiceStatusPrefix + "/" + BackendServiceResource
@@ -135,8 +176,203 @@ const (
 	WeightedL4AnnotationKey = "networking.gke.io/weighted-load-balancing"
 	// Service annotation value for using pods-per-node Weighted load balancing in both ILB and NetlB
 	WeightedL4AnnotationPodsPerNode = "pods-per-node"
+
+	// MakeBeforeBreakAnnotationKey enables make-before-break recreation of the forwarding rule of
+	// an L4 Service. When an immutable field, like the network tier of an L4 NetLB Service, the subnet
+	// of an L4 ILB Service or the IP version, changes a replacement forwarding rule is created next
+	// to the existing one, which is only removed after a soak period.
+	MakeBeforeBreakAnnotationKey = "networking.gke.io/l4-make-before-break"
+	MakeBeforeBreakEnabled       = "enabled"
+	// RemovePreviousForwardingRuleAnnotationKey removes the forwarding rule replaced in a
+	// make-before-break recreation without waiting for the soak period to end.
+	// It is removed from the Service by the sync that handles it.
+	RemovePreviousForwardingRuleAnnotationKey = "networking.gke.io/l4-remove-previous-forwarding-rule"
+	// TargetPoolMigrationAnnotationKey opts a legacy target pool based L4 NetLB Service in to the
+	// migration to backend services. It is only honored together with the RBS annotation.
+	// The forwarding rule is recreated pointing at the backend service, keeping its IP, and the
+	// target pool resources are deleted afterwards. Connections are briefly interrupted while the
+	// forwarding rule is recreated. It is removed from the Service once the migration is completed.
+	TargetPoolMigrationAnnotationKey = "networking.gke.io/l4-target-pool-migration"
+	TargetPoolMigrationEnabled       = "enabled"
+
+	// RegionalInternalLoadBalancerClass is the Service LoadBalancerClass that
+	// selects the L4 ILB controller, regardless of the load balancer type annotation.
+	RegionalInternalLoadBalancerClass = "networking.gke.io/l4-regional-internal"
+	// RegionalExternalLoadBalancerClass is the Service LoadBalancerClass that
+	// selects the L4 RBS NetLB controller, regardless of the RBS annotation.
+	RegionalExternalLoadBalancerClass = "networking.gke.io/l4-regional-external"
+
+	// L4OptionsKey is the annotation key for the consolidated options of an L4 ILB Service.
+	// The value is a JSON encoded L4Options, e.g.
+	// {"globalAccess": true, "subnet": "my-subnet", "labels": {"team": "a"}, "serviceDirectory": {"namespace": "ns", "service": "svc"}}
+	// Options set in this annotation take precedence over the global access and subnet annotations.
+	// Labels are only set on the forwarding rules: the compute API has no labels on backend
+	// services, so backendServiceLabels is rejected.
+	L4OptionsKey = "networking.gke.io/l4-options"
+
+	// L4HealthCheckKey is the annotation key for the health check overrides of an L4 Service.
+	// The value is a JSON encoded L4HealthCheckOptions, e.g.
+	// {"checkIntervalSec": 5, "timeoutSec": 2, "healthyThreshold": 2, "unhealthyThreshold": 3}
+	// Services with the Cluster external traffic policy that specify overrides get a dedicated
+	// health check, instead of the one shared by all such Services.
+	L4HealthCheckKey = "networking.gke.io/l4-health-check"
+
+	// L4ConnectionTrackingKey is the annotation key for the connection tracking policy of the
+	// backend service of an L4 Service. The value is a JSON encoded L4ConnectionTrackingOptions, e.g.
+	// {"trackingMode": "PER_SESSION", "connectionPersistenceOnUnhealthyBackends": "NEVER_PERSIST", "idleTimeoutSec": 3600}
+	L4ConnectionTrackingKey = "networking.gke.io/l4-connection-tracking"
+)
+
+const (
+	// maxL4OptionsLabels is the maximum number of labels of a GCE resource.
+	maxL4OptionsLabels = 64
+	// maxL4HealthCheckSeconds is the maximum check interval and timeout of a GCE health check.
+	maxL4HealthCheckSeconds = 300
+	// maxL4HealthCheckThreshold is the maximum healthy and unhealthy threshold of a GCE health check.
+	maxL4HealthCheckThreshold = 10
+	// maxL4ConnectionTrackingIdleTimeoutSec is the maximum idle timeout of a connection tracking entry, 16 hours.
+	maxL4ConnectionTrackingIdleTimeoutSec = 57600
+)
+
+const (
+	// Connection tracking modes of a backend service.
+	TrackingModePerConnection = "PER_CONNECTION"
+	TrackingModePerSession    = "PER_SESSION"
+
+	// Connection persistence modes of a backend service on unhealthy backends.
+	ConnectionPersistenceDefaultForProtocol = "DEFAULT_FOR_PROTOCOL"
+	ConnectionPersistenceNeverPersist       = "NEVER_PERSIST"
+	ConnectionPersistenceAlwaysPersist      = "ALWAYS_PERSIST"
 )
 
+var (
+	// gceNameRegexp matches valid GCE resource and Service Directory names (RFC1035).
+	gceNameRegexp = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+	// gceLabelKeyRegexp and gceLabelValueRegexp match valid GCE resource label keys and values.
+	gceLabelKeyRegexp   = regexp.MustCompile(`^[a-z][-_a-z0-9]{0,62}$`)
+	gceLabelValueRegexp = regexp.MustCompile(`^[-_a-z0-9]{0,63}$`)
+)
+
+// L4Options is the format of the annotation associated with the L4OptionsKey key.
+type L4Options struct {
+	// GlobalAccess allows clients from all regions to access the load balancer.
+	GlobalAccess *bool `json:"globalAccess,omitempty"`
+	// Subnet is the name of the subnet the load balancer IP is allocated from.
+	Subnet string `json:"subnet,omitempty"`
+	// Labels are the GCE resource labels set on the forwarding rules of the load balancer.
+	// If not specified, labels of existing forwarding rules are left untouched, while
+	// an empty map removes them.
+	Labels map[string]string `json:"labels,omitempty"`
+	// BackendServiceLabels is not supported, as backend services have no labels. It is
+	// declared so that it is rejected with an explicit error.
+	BackendServiceLabels map[string]string `json:"backendServiceLabels,omitempty"`
+	// ServiceDirectory registers the IPv4 forwarding rule of the load balancer in Service Directory.
+	ServiceDirectory *L4ServiceDirectoryRegistration `json:"serviceDirectory,omitempty"`
+}
+
+// L4ServiceDirectoryRegistration is the Service Directory service that a forwarding rule is registered under.
+type L4ServiceDirectoryRegistration struct {
+	Namespace string `json:"namespace"`
+	Service   string `json:"service"`
+}
+
+// validate returns an error if the options can't be applied to GCE resources.
+func (o *L4Options) validate() error {
+	if o.Subnet != "" && !gceNameRegexp.MatchString(o.Subnet) {
+		return fmt.Errorf("invalid subnet name %q", o.Subnet)
+	}
+	if o.BackendServiceLabels != nil {
+		return fmt.Errorf("labels on backend services are not supported, labels are only set on forwarding rules")
+	}
+	if len(o.Labels) > maxL4OptionsLabels {
+		return fmt.Errorf("%d labels specified, at most %d are allowed", len(o.Labels), maxL4OptionsLabels)
+	}
+	for k, v := range o.Labels {
+		if !gceLabelKeyRegexp.MatchString(k) {
+			return fmt.Errorf("invalid label key %q", k)
+		}
+		if !gceLabelValueRegexp.MatchString(v) {
+			return fmt.Errorf("invalid value %q of label %q", v, k)
+		}
+	}
+	if sd := o.ServiceDirectory; sd != nil {
+		if !gceNameRegexp.MatchString(sd.Namespace) {
+			return fmt.Errorf("invalid Service Directory namespace %q", sd.Namespace)
+		}
+		if !gceNameRegexp.MatchString(sd.Service) {
+			return fmt.Errorf("invalid Service Directory service %q", sd.Service)
+		}
+	}
+	return nil
+}
+
+// L4HealthCheckOptions is the format of the annotation associated with the L4HealthCheckKey key.
+// Fields that are not specified keep the values chosen by the controller.
+type L4HealthCheckOptions struct {
+	CheckIntervalSec   int64 `json:"checkIntervalSec,omitempty"`
+	TimeoutSec         int64 `json:"timeoutSec,omitempty"`
+	HealthyThreshold   int64 `json:"healthyThreshold,omitempty"`
+	UnhealthyThreshold int64 `json:"unhealthyThreshold,omitempty"`
+	// Port and RequestPath can only be overridden for Services with the Local external traffic
+	// policy, the health check of other Services is served by kube-proxy.
+	Port        int32  `json:"port,omitempty"`
+	RequestPath string `json:"requestPath,omitempty"`
+}
+
+// validate returns an error if the overrides can't be applied to a GCE health check.
+func (o *L4HealthCheckOptions) validate() error {
+	if o.CheckIntervalSec < 0 || o.CheckIntervalSec > maxL4HealthCheckSeconds {
+		return fmt.Errorf("checkIntervalSec %d is out of range [0, %d]", o.CheckIntervalSec, maxL4HealthCheckSeconds)
+	}
+	if o.TimeoutSec < 0 || o.TimeoutSec > maxL4HealthCheckSeconds {
+		return fmt.Errorf("timeoutSec %d is out of range [0, %d]", o.TimeoutSec, maxL4HealthCheckSeconds)
+	}
+	if o.HealthyThreshold < 0 || o.HealthyThreshold > maxL4HealthCheckThreshold {
+		return fmt.Errorf("healthyThreshold %d is out of range [0, %d]", o.HealthyThreshold, maxL4HealthCheckThreshold)
+	}
+	if o.UnhealthyThreshold < 0 || o.UnhealthyThreshold > maxL4HealthCheckThreshold {
+		return fmt.Errorf("unhealthyThreshold %d is out of range [0, %d]", o.UnhealthyThreshold, maxL4HealthCheckThreshold)
+	}
+	if o.Port < 0 || o.Port > 65535 {
+		return fmt.Errorf("port %d is out of range [0, 65535]", o.Port)
+	}
+	if o.RequestPath != "" && !strings.HasPrefix(o.RequestPath, "/") {
+		return fmt.Errorf("requestPath %q does not start with /", o.RequestPath)
+	}
+	return nil
+}
+
+// L4ConnectionTrackingOptions is the format of the annotation associated with the L4ConnectionTrackingKey key.
+// Fields that are not specified keep the GCE defaults.
+type L4ConnectionTrackingOptions struct {
+	TrackingMode                             string `json:"trackingMode,omitempty"`
+	ConnectionPersistenceOnUnhealthyBackends string `json:"connectionPersistenceOnUnhealthyBackends,omitempty"`
+	// IdleTimeoutSec can only be set with the PER_SESSION tracking mode.
+	IdleTimeoutSec int64 `json:"idleTimeoutSec,omitempty"`
+}
+
+// validate returns an error if the policy can't be applied to a GCE backend service.
+func (o *L4ConnectionTrackingOptions) validate() error {
+	switch o.TrackingMode {
+	case "", TrackingModePerConnection, TrackingModePerSession:
+	default:
+		return fmt.Errorf("trackingMode %q is not one of %s, %s", o.TrackingMode, TrackingModePerConnection, TrackingModePerSession)
+	}
+	switch o.ConnectionPersistenceOnUnhealthyBackends {
+	case "", ConnectionPersistenceDefaultForProtocol, ConnectionPersistenceNeverPersist, ConnectionPersistenceAlwaysPersist:
+	default:
+		return fmt.Errorf("connectionPersistenceOnUnhealthyBackends %q is not one of %s, %s, %s", o.ConnectionPersistenceOnUnhealthyBackends,
+			ConnectionPersistenceDefaultForProtocol, ConnectionPersistenceNeverPersist, ConnectionPersistenceAlwaysPersist)
+	}
+	if o.IdleTimeoutSec < 0 || o.IdleTimeoutSec > maxL4ConnectionTrackingIdleTimeoutSec {
+		return fmt.Errorf("idleTimeoutSec %d is out of range [0, %d]", o.IdleTimeoutSec, maxL4ConnectionTrackingIdleTimeoutSec)
+	}
+	if o.IdleTimeoutSec != 0 && o.TrackingMode != TrackingModePerSession {
+		return fmt.Errorf("idleTimeoutSec can only be set with the %s tracking mode", TrackingModePerSession)
+	}
+	return nil
+}
+
 // NegAnnotation is the format of the annotation associated with the
 // NEGAnnotationKey key.
 type NegAnnotation struct {
@@ -231,6 +467,9 @@ func ParseNegStatus(annotation string) (NegStatus, error) {
 // AppProtocol describes the service protocol.
 type AppProtocol string
 
+// NEGSyncPriority is the priority of the NEG syncers of a Service.
+type NEGSyncPriority string
+
 // Service represents Service annotations.
 type Service struct {
 	v map[string]string
@@ -243,6 +482,7 @@ func FromService(obj *v1.Service) *Service {
 
 // WantsL4ILB checks if the given service requires L4 ILB.
 // the function returns a boolean as well as the loadbalancer type(string).
+// A LoadBalancerClass, if set, takes precedence over the load balancer type annotation.
 func WantsL4ILB(service *v1.Service) (bool, string) {
 	if service == nil {
 		return false, ""
@@ -250,6 +490,9 @@ func WantsL4ILB(service *v1.Service) (bool, string) {
 	if service.Spec.Type != v1.ServiceTypeLoadBalancer {
 		return false, fmt.Sprintf("Type : %s", service.Spec.Type)
 	}
+	if lbClass := service.Spec.LoadBalancerClass; lbClass != nil {
+		return *lbClass == RegionalInternalLoadBalancerClass, fmt.Sprintf("Type : %s, LoadBalancerClass : %s", service.Spec.Type, *lbClass)
+	}
 	ltype := GetLoadBalancerAnnotationType(service)
 	if ltype == LBTypeInternal {
 		return true, fmt.Sprintf("Type : %s, LBType : %s", service.Spec.Type, ltype)
@@ -258,6 +501,7 @@ func WantsL4ILB(service *v1.Service) (bool, string) {
 }
 
 // WantsL4NetLB checks if the given service requires L4 NetLb.
+// A LoadBalancerClass, if set, takes precedence over the load balancer type annotation.
 func WantsL4NetLB(service *v1.Service) (bool, string) {
 	if service == nil {
 		return false, ""
@@ -265,10 +509,27 @@ func WantsL4NetLB(service *v1.Service) (bool, string) {
 	if service.Spec.Type != v1.ServiceTypeLoadBalancer {
 		return false, fmt.Sprintf("Type : %s", service.Spec.Type)
 	}
+	if lbClass := service.Spec.LoadBalancerClass; lbClass != nil {
+		return *lbClass == RegionalExternalLoadBalancerClass, fmt.Sprintf("Type : %s, LoadBalancerClass : %s", service.Spec.Type, *lbClass)
+	}
 	ltype := GetLoadBalancerAnnotationType(service)
 	return ltype != LBTypeInternal, fmt.Sprintf("Type : %s, LBType : %s", service.Spec.Type, ltype)
 }
 
+// HasLoadBalancerClass checks if the given service has the given LoadBalancerClass.
+func HasLoadBalancerClass(service *v1.Service, lbClass string) bool {
+	if service == nil || service.Spec.LoadBalancerClass == nil {
+		return false
+	}
+	return *service.Spec.LoadBalancerClass == lbClass
+}
+
+// HasGKELoadBalancerClass checks if the given service has a LoadBalancerClass
+// that is handled by the L4 controllers.
+func HasGKELoadBalancerClass(service *v1.Service) bool {
+	return HasLoadBalancerClass(service, RegionalInternalLoadBalancerClass) || HasLoadBalancerClass(service, RegionalExternalLoadBalancerClass)
+}
+
 // HasRBSAnnotation checks if the given service has the RBS annotation.
 func HasRBSAnnotation(service *v1.Service) bool {
 	if service == nil {
@@ -281,6 +542,45 @@ func HasRBSAnnotation(service *v1.Service) bool {
 	return false
 }
 
+// HasMakeBeforeBreakAnnotation checks if the given service enables make-before-break
+// recreation of its forwarding rule.
+func HasMakeBeforeBreakAnnotation(service *v1.Service) bool {
+	if service == nil {
+		return false
+	}
+
+	if val, ok := service.Annotations[MakeBeforeBreakAnnotationKey]; ok && val == MakeBeforeBreakEnabled {
+		return true
+	}
+	return false
+}
+
+// HasTargetPoolMigrationAnnotation checks if the given service requests the migration
+// of its target pool based load balancer to backend services.
+func HasTargetPoolMigrationAnnotation(service *v1.Service) bool {
+	if service == nil {
+		return false
+	}
+
+	if val, ok := service.Annotations[TargetPoolMigrationAnnotationKey]; ok && val == TargetPoolMigrationEnabled {
+		return true
+	}
+	return false
+}
+
+// WantsPreviousForwardingRuleRemoved checks if the given service requests the removal of the
+// forwarding rule replaced in a make-before-break recreation before the end of the soak period.
+func WantsPreviousForwardingRuleRemoved(service *v1.Service) bool {
+	if service == nil {
+		return false
+	}
+
+	if val, ok := service.Annotations[RemovePreviousForwardingRuleAnnotationKey]; ok && val == "true" {
+		return true
+	}
+	return false
+}
+
 // HasStrongSessionAffinityAnnotation checks if the given service has the strong session affinity annotation.
 func HasStrongSessionAffinityAnnotation(service *v1.Service) bool {
 	if service == nil {
@@ -364,6 +664,11 @@ var (
 	ErrBackendConfigAnnotationMissing = errors.New("BackendConfig annotation is missing")
 	ErrNEGAnnotationInvalid           = errors.New("NEG annotation is invalid.")
 	ErrTHCAnnotationInvalid           = errors.New("THC annotation is invalid")
+	ErrNEGReadinessAnnotationInvalid  = errors.New("NEG readiness backend services annotation is invalid")
+	ErrNEGSyncPriorityInvalid         = errors.New("NEG sync priority annotation is invalid")
+	ErrL4OptionsInvalid               = errors.New("L4 options annotation is invalid")
+	ErrL4HealthCheckOptionsInvalid    = errors.New("L4 health check annotation is invalid")
+	ErrL4ConnectionTrackingInvalid    = errors.New("L4 connection tracking annotation is invalid")
 )
 
 // NEGAnnotation returns true if NEG annotation is found.
@@ -383,6 +688,102 @@ func (svc *Service) NEGAnnotation() (*NegAnnotation, bool, error) {
 	return &res, true, nil
 }
 
+// NEGReadinessBackendServices returns the backend services that must report
+// the endpoints of the Service as healthy before their pods are marked ready.
+// requireAll is true if all backend services the NEG is attached to are
+// required. If neither requireAll nor backendServices are returned, any backend
+// service is sufficient.
+func (svc *Service) NEGReadinessBackendServices() (requireAll bool, backendServices []string, err error) {
+	annotation, ok := svc.v[NEGReadinessBackendServicesKey]
+	if !ok {
+		return false, nil, nil
+	}
+	annotation = strings.TrimSpace(annotation)
+	if annotation == NEGReadinessAllBackendServices {
+		return true, nil, nil
+	}
+	for _, name := range strings.Split(annotation, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || name == NEGReadinessAllBackendServices {
+			return false, nil, fmt.Errorf("%w: %q", ErrNEGReadinessAnnotationInvalid, annotation)
+		}
+		backendServices = append(backendServices, name)
+	}
+	return false, backendServices, nil
+}
+
+// NEGSyncPriority returns the priority of the NEG syncers of the Service.
+// NEGSyncPriorityNormal is returned if the annotation is not specified or
+// is invalid, in which case an error is also returned.
+func (svc *Service) NEGSyncPriority() (NEGSyncPriority, error) {
+	val, ok := svc.v[NEGSyncPriorityKey]
+	if !ok {
+		return NEGSyncPriorityNormal, nil
+	}
+	switch priority := NEGSyncPriority(val); priority {
+	case NEGSyncPriorityHigh, NEGSyncPriorityNormal, NEGSyncPriorityLow:
+		return priority, nil
+	}
+	return NEGSyncPriorityNormal, fmt.Errorf("%w: %q, valid values are: %s/%s/%s", ErrNEGSyncPriorityInvalid, val, NEGSyncPriorityHigh, NEGSyncPriorityNormal, NEGSyncPriorityLow)
+}
+
+// L4Options returns the options of the L4 options annotation, or nil if the
+// annotation is not specified.
+func (svc *Service) L4Options() (*L4Options, error) {
+	annotation, ok := svc.v[L4OptionsKey]
+	if !ok {
+		return nil, nil
+	}
+	var res L4Options
+	decoder := json.NewDecoder(strings.NewReader(annotation))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&res); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrL4OptionsInvalid, err)
+	}
+	if err := res.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrL4OptionsInvalid, err)
+	}
+	return &res, nil
+}
+
+// L4HealthCheckOptions returns the health check overrides of the L4 health check
+// annotation, or nil if the annotation is not specified.
+func (svc *Service) L4HealthCheckOptions() (*L4HealthCheckOptions, error) {
+	annotation, ok := svc.v[L4HealthCheckKey]
+	if !ok {
+		return nil, nil
+	}
+	var res L4HealthCheckOptions
+	decoder := json.NewDecoder(strings.NewReader(annotation))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&res); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrL4HealthCheckOptionsInvalid, err)
+	}
+	if err := res.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrL4HealthCheckOptionsInvalid, err)
+	}
+	return &res, nil
+}
+
+// L4ConnectionTrackingOptions returns the connection tracking policy of the L4 connection
+// tracking annotation, or nil if the annotation is not specified.
+func (svc *Service) L4ConnectionTrackingOptions() (*L4ConnectionTrackingOptions, error) {
+	annotation, ok := svc.v[L4ConnectionTrackingKey]
+	if !ok {
+		return nil, nil
+	}
+	var res L4ConnectionTrackingOptions
+	decoder := json.NewDecoder(strings.NewReader(annotation))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&res); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrL4ConnectionTrackingInvalid, err)
+	}
+	if err := res.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrL4ConnectionTrackingInvalid, err)
+	}
+	return &res, nil
+}
+
 // IsThcAnnotated returns true if a THC annotation is found and its value is true.
 func (svc *Service) IsThcAnnotated() (bool, error) {
 	var res THCAnnotation
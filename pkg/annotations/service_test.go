@@ -17,6 +17,7 @@ limitations under the License.
 package annotations
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -715,3 +716,387 @@ func TestHasStrongSessionAffinityAnnotation(t *testing.T) {
 		})
 	}
 }
+
+func TestWantsL4WithLoadBalancerClass(t *testing.T) {
+	internalClass := RegionalInternalLoadBalancerClass
+	externalClass := RegionalExternalLoadBalancerClass
+	otherClass := "example.com/other-class"
+	for _, tc := range []struct {
+		desc         string
+		lbClass      *string
+		annotations  map[string]string
+		wantILB      bool
+		wantNetLB    bool
+		wantGKEClass bool
+	}{
+		{
+			desc:      "no class, no annotation",
+			wantNetLB: true,
+		},
+		{
+			desc:        "no class, internal annotation",
+			annotations: map[string]string{ServiceAnnotationLoadBalancerType: string(LBTypeInternal)},
+			wantILB:     true,
+		},
+		{
+			desc:         "internal class",
+			lbClass:      &internalClass,
+			wantILB:      true,
+			wantGKEClass: true,
+		},
+		{
+			desc:         "external class ignores internal annotation",
+			lbClass:      &externalClass,
+			annotations:  map[string]string{ServiceAnnotationLoadBalancerType: string(LBTypeInternal)},
+			wantNetLB:    true,
+			wantGKEClass: true,
+		},
+		{
+			desc:    "other class",
+			lbClass: &otherClass,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			svc := &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+				Spec: v1.ServiceSpec{
+					Type:              v1.ServiceTypeLoadBalancer,
+					LoadBalancerClass: tc.lbClass,
+				},
+			}
+			if got, _ := WantsL4ILB(svc); got != tc.wantILB {
+				t.Errorf("WantsL4ILB() = %v, want %v", got, tc.wantILB)
+			}
+			if got, _ := WantsL4NetLB(svc); got != tc.wantNetLB {
+				t.Errorf("WantsL4NetLB() = %v, want %v", got, tc.wantNetLB)
+			}
+			if got := HasGKELoadBalancerClass(svc); got != tc.wantGKEClass {
+				t.Errorf("HasGKELoadBalancerClass() = %v, want %v", got, tc.wantGKEClass)
+			}
+		})
+	}
+}
+
+func TestNEGReadinessBackendServices(t *testing.T) {
+	for _, tc := range []struct {
+		desc            string
+		annotation      string
+		wantAll         bool
+		wantBackendSvcs []string
+		wantErr         bool
+	}{
+		{
+			desc: "annotation not specified",
+		},
+		{
+			desc:       "all backend services",
+			annotation: "all",
+			wantAll:    true,
+		},
+		{
+			desc:            "named backend services",
+			annotation:      "bs1, bs2",
+			wantBackendSvcs: []string{"bs1", "bs2"},
+		},
+		{
+			desc:       "empty backend service name",
+			annotation: "bs1,,bs2",
+			wantErr:    true,
+		},
+		{
+			desc:       "all mixed with named backend services",
+			annotation: "bs1,all",
+			wantErr:    true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+			if tc.annotation != "" {
+				svc.Annotations[NEGReadinessBackendServicesKey] = tc.annotation
+			}
+			all, backendSvcs, err := FromService(svc).NEGReadinessBackendServices()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("NEGReadinessBackendServices() = %v, want error %v", err, tc.wantErr)
+			}
+			if all != tc.wantAll {
+				t.Errorf("NEGReadinessBackendServices() returned all = %v, want %v", all, tc.wantAll)
+			}
+			if !reflect.DeepEqual(backendSvcs, tc.wantBackendSvcs) {
+				t.Errorf("NEGReadinessBackendServices() returned backend services %v, want %v", backendSvcs, tc.wantBackendSvcs)
+			}
+		})
+	}
+}
+
+func TestNEGSyncPriority(t *testing.T) {
+	for _, tc := range []struct {
+		desc       string
+		annotation string
+		want       NEGSyncPriority
+		wantErr    bool
+	}{
+		{
+			desc: "annotation not specified",
+			want: NEGSyncPriorityNormal,
+		},
+		{
+			desc:       "high priority",
+			annotation: "high",
+			want:       NEGSyncPriorityHigh,
+		},
+		{
+			desc:       "low priority",
+			annotation: "low",
+			want:       NEGSyncPriorityLow,
+		},
+		{
+			desc:       "invalid priority",
+			annotation: "urgent",
+			want:       NEGSyncPriorityNormal,
+			wantErr:    true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+			if tc.annotation != "" {
+				svc.Annotations[NEGSyncPriorityKey] = tc.annotation
+			}
+			got, err := FromService(svc).NEGSyncPriority()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("NEGSyncPriority() = %v, want error %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("NEGSyncPriority() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestL4Options(t *testing.T) {
+	globalAccess := false
+	for _, tc := range []struct {
+		desc       string
+		annotation string
+		want       *L4Options
+		wantErr    bool
+	}{
+		{
+			desc: "annotation not specified",
+		},
+		{
+			desc:       "all options",
+			annotation: `{"globalAccess": false, "subnet": "my-subnet", "labels": {"team": "dns", "env": ""}, "serviceDirectory": {"namespace": "ns", "service": "svc"}}`,
+			want: &L4Options{
+				GlobalAccess:     &globalAccess,
+				Subnet:           "my-subnet",
+				Labels:           map[string]string{"team": "dns", "env": ""},
+				ServiceDirectory: &L4ServiceDirectoryRegistration{Namespace: "ns", Service: "svc"},
+			},
+		},
+		{
+			desc:       "empty labels",
+			annotation: `{"labels": {}}`,
+			want:       &L4Options{Labels: map[string]string{}},
+		},
+		{
+			desc:       "invalid json",
+			annotation: `{"globalAccess": true`,
+			wantErr:    true,
+		},
+		{
+			desc:       "unknown option",
+			annotation: `{"globalacess": true}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "invalid subnet",
+			annotation: `{"subnet": "My_Subnet"}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "invalid label key",
+			annotation: `{"labels": {"Team": "dns"}}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "invalid label value",
+			annotation: `{"labels": {"team": "DNS"}}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "backend service labels",
+			annotation: `{"labels": {"team": "dns"}, "backendServiceLabels": {"team": "dns"}}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "missing service directory service",
+			annotation: `{"serviceDirectory": {"namespace": "ns"}}`,
+			wantErr:    true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+			if tc.annotation != "" {
+				svc.Annotations[L4OptionsKey] = tc.annotation
+			}
+			got, err := FromService(svc).L4Options()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("L4Options() = %v, want error %v", err, tc.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrL4OptionsInvalid) {
+				t.Errorf("L4Options() = %v, want %v", err, ErrL4OptionsInvalid)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("L4Options() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestL4HealthCheckOptions(t *testing.T) {
+	for _, tc := range []struct {
+		desc       string
+		annotation string
+		want       *L4HealthCheckOptions
+		wantErr    bool
+	}{
+		{
+			desc: "annotation not specified",
+		},
+		{
+			desc:       "all options",
+			annotation: `{"checkIntervalSec": 5, "timeoutSec": 2, "healthyThreshold": 2, "unhealthyThreshold": 4, "port": 8080, "requestPath": "/ready"}`,
+			want: &L4HealthCheckOptions{
+				CheckIntervalSec:   5,
+				TimeoutSec:         2,
+				HealthyThreshold:   2,
+				UnhealthyThreshold: 4,
+				Port:               8080,
+				RequestPath:        "/ready",
+			},
+		},
+		{
+			desc:       "invalid json",
+			annotation: `{"checkIntervalSec": 5`,
+			wantErr:    true,
+		},
+		{
+			desc:       "unknown option",
+			annotation: `{"interval": 5}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "interval out of range",
+			annotation: `{"checkIntervalSec": 301}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "threshold out of range",
+			annotation: `{"unhealthyThreshold": 11}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "port out of range",
+			annotation: `{"port": 70000}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "relative request path",
+			annotation: `{"requestPath": "ready"}`,
+			wantErr:    true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+			if tc.annotation != "" {
+				svc.Annotations[L4HealthCheckKey] = tc.annotation
+			}
+			got, err := FromService(svc).L4HealthCheckOptions()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("L4HealthCheckOptions() = %v, want error %v", err, tc.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrL4HealthCheckOptionsInvalid) {
+				t.Errorf("L4HealthCheckOptions() = %v, want %v", err, ErrL4HealthCheckOptionsInvalid)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("L4HealthCheckOptions() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestL4ConnectionTrackingOptions(t *testing.T) {
+	for _, tc := range []struct {
+		desc       string
+		annotation string
+		want       *L4ConnectionTrackingOptions
+		wantErr    bool
+	}{
+		{
+			desc: "annotation not specified",
+		},
+		{
+			desc:       "all options",
+			annotation: `{"trackingMode": "PER_SESSION", "connectionPersistenceOnUnhealthyBackends": "NEVER_PERSIST", "idleTimeoutSec": 3600}`,
+			want: &L4ConnectionTrackingOptions{
+				TrackingMode:                             TrackingModePerSession,
+				ConnectionPersistenceOnUnhealthyBackends: ConnectionPersistenceNeverPersist,
+				IdleTimeoutSec:                           3600,
+			},
+		},
+		{
+			desc:       "persistence only",
+			annotation: `{"connectionPersistenceOnUnhealthyBackends": "ALWAYS_PERSIST"}`,
+			want: &L4ConnectionTrackingOptions{
+				ConnectionPersistenceOnUnhealthyBackends: ConnectionPersistenceAlwaysPersist,
+			},
+		},
+		{
+			desc:       "invalid json",
+			annotation: `{"trackingMode": "PER_SESSION"`,
+			wantErr:    true,
+		},
+		{
+			desc:       "unknown option",
+			annotation: `{"enableStrongAffinity": true}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "unknown tracking mode",
+			annotation: `{"trackingMode": "PER_PACKET"}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "unknown persistence",
+			annotation: `{"connectionPersistenceOnUnhealthyBackends": "SOMETIMES"}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "idle timeout out of range",
+			annotation: `{"trackingMode": "PER_SESSION", "idleTimeoutSec": 57601}`,
+			wantErr:    true,
+		},
+		{
+			desc:       "idle timeout without per session tracking",
+			annotation: `{"idleTimeoutSec": 3600}`,
+			wantErr:    true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+			if tc.annotation != "" {
+				svc.Annotations[L4ConnectionTrackingKey] = tc.annotation
+			}
+			got, err := FromService(svc).L4ConnectionTrackingOptions()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("L4ConnectionTrackingOptions() = %v, want error %v", err, tc.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrL4ConnectionTrackingInvalid) {
+				t.Errorf("L4ConnectionTrackingOptions() = %v, want %v", err, ErrL4ConnectionTrackingInvalid)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("L4ConnectionTrackingOptions() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
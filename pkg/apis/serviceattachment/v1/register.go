@@ -46,6 +46,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&ServiceAttachment{},
 		&ServiceAttachmentList{},
+		&ServiceAttachmentConsumer{},
+		&ServiceAttachmentConsumerList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
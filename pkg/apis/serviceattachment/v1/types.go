@@ -46,7 +46,7 @@ type ServiceAttachmentSpec struct {
 	NATSubnets []string `json:"natSubnets,omitempty"`
 
 	// ResourceRef is the reference to the K8s resource that created the forwarding rule
-	// Only Services can be used as a reference
+	// Services and internal Ingresses (apiGroup networking.k8s.io) can be used as a reference
 	// +required
 	ResourceRef corev1.TypedLocalObjectReference `json:"resourceRef,omitempty"`
 
@@ -65,6 +65,49 @@ type ServiceAttachmentSpec struct {
 	// +optional
 	// +listType=atomic
 	ConsumerRejectList []string `json:"consumerRejectList,omitempty"`
+
+	// ConsumerConnectionDecisions are the approval decisions for consumer forwarding rules
+	// connecting to this ServiceAttachment, used with the ACCEPT_MANUAL connection preference.
+	// Decisions apply to the project of the consumer forwarding rule and are added to the
+	// consumer accept or reject lists. Projects in ConsumerAllowList or ConsumerRejectList
+	// take precedence over decisions.
+	// +optional
+	// +listType=atomic
+	ConsumerConnectionDecisions []ConsumerConnectionDecision `json:"consumerConnectionDecisions,omitempty"`
+
+	// ReconcileConnections when set updates the status of existing consumer
+	// connections when the consumer accept or reject lists change
+	// +optional
+	ReconcileConnections bool `json:"reconcileConnections,omitempty"`
+
+	// DomainNames are the DNS domain names used by consumers to reach this ServiceAttachment
+	// +optional
+	// +listType=atomic
+	DomainNames []string `json:"domainNames,omitempty"`
+}
+
+const (
+	// ConsumerConnectionAccept accepts the connections of the consumer project.
+	ConsumerConnectionAccept = "ACCEPT"
+	// ConsumerConnectionReject rejects the connections of the consumer project.
+	ConsumerConnectionReject = "REJECT"
+)
+
+// ConsumerConnectionDecision is the approval decision for a consumer forwarding rule
+// +k8s:openapi-gen=true
+type ConsumerConnectionDecision struct {
+	// ForwardingRuleURL is the URL of the consumer forwarding rule, as reported in
+	// the ConsumerForwardingRules of the status
+	// +required
+	ForwardingRuleURL string `json:"forwardingRuleURL,omitempty"`
+
+	// Decision is either ACCEPT or REJECT
+	// +required
+	Decision string `json:"decision,omitempty"`
+
+	// ConnectionLimit is the connection limit for the consumer project when accepted
+	// +optional
+	ConnectionLimit int64 `json:"connectionLimit,omitempty"`
 }
 
 // ConsumerProject is the consumer project and project level configuration
@@ -121,8 +164,47 @@ type ServiceAttachmentStatus struct {
 	// LastModifiedTimestamp tracks last time Status was updated
 	// +optional
 	LastModifiedTimestamp metav1.Time `json:"lastModifiedTimestamp,omitempty"`
+
+	// AppliedSpecHash is the hash of the configuration last applied to the GCE Service
+	// Attachment. It is used to tell spec updates from changes made outside of the controller.
+	// +optional
+	AppliedSpecHash string `json:"appliedSpecHash,omitempty"`
+
+	// Conditions are the current conditions of the ServiceAttachment
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
+// Condition contains details for the current condition of the ServiceAttachment
+// +k8s:openapi-gen=true
+type Condition struct {
+	// Type is the type of the condition.
+	// +required
+	Type string `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	// +required
+	Status metav1.ConditionStatus `json:"status"`
+	// Last time the condition transitioned from one status to another.
+	// +required
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// The reason for the condition's last transition
+	// +required
+	Reason string `json:"reason"`
+	// A human readable message indicating details about the transition.
+	// This field may be empty.
+	// +required
+	Message string `json:"message"`
+}
+
+// These are valid conditions of a ServiceAttachment.
+const (
+	// Drifted means that the GCE Service Attachment was changed outside of the controller
+	// and did not match the spec in the last reconciliation. The changes were reverted.
+	Drifted = "Drifted"
+)
+
 // ConsumerForwardingRule is a reference to the PSC consumer forwarding rule
 // +k8s:openapi-gen=true
 type ConsumerForwardingRule struct {
@@ -141,3 +223,103 @@ type ServiceAttachmentList struct {
 
 	Items []ServiceAttachment `json:"items"`
 }
+
+// ServiceAttachmentConsumer represents a Private Service Connect endpoint in the cluster's
+// network that connects to a published GCE Service Attachment
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type ServiceAttachmentConsumer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServiceAttachmentConsumerSpec   `json:"spec,omitempty"`
+	Status ServiceAttachmentConsumerStatus `json:"status,omitempty"`
+}
+
+// ServiceAttachmentConsumerSpec is the spec for a ServiceAttachmentConsumer resource
+// +k8s:openapi-gen=true
+type ServiceAttachmentConsumerSpec struct {
+	// ServiceAttachmentURL is the URL of the GCE Service Attachment to connect to
+	// +required
+	ServiceAttachmentURL string `json:"serviceAttachmentURL,omitempty"`
+
+	// Subnetwork is the name or URL of the subnet to reserve the internal IP address
+	// of the endpoint in. Defaults to the subnet of the cluster.
+	// +optional
+	Subnetwork string `json:"subnetwork,omitempty"`
+
+	// IPAddress is the internal IP address to reserve for the endpoint. An address
+	// of the subnet is allocated if empty.
+	// +optional
+	IPAddress string `json:"ipAddress,omitempty"`
+
+	// Service when set creates a headless Service with the name of the ServiceAttachmentConsumer,
+	// and an EndpointSlice pointing at the IP address of the endpoint, so that clients
+	// in the cluster can reach the published service by DNS name
+	// +optional
+	Service *ConsumerServiceSpec `json:"service,omitempty"`
+}
+
+// ConsumerServiceSpec configures the headless Service of a ServiceAttachmentConsumer
+// +k8s:openapi-gen=true
+type ConsumerServiceSpec struct {
+	// Ports are the ports of the published service exposed by the Service
+	// +required
+	// +listType=atomic
+	Ports []ConsumerServicePort `json:"ports,omitempty"`
+}
+
+// ConsumerServicePort is a port of the headless Service of a ServiceAttachmentConsumer
+// +k8s:openapi-gen=true
+type ConsumerServicePort struct {
+	// Name is the name of the port, required if there is more than one port
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Protocol is the protocol of the port. Defaults to TCP.
+	// +optional
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+
+	// Port is the port number
+	// +required
+	Port int32 `json:"port,omitempty"`
+}
+
+// ServiceAttachmentConsumerStatus is the status for a ServiceAttachmentConsumer resource
+// +k8s:openapi-gen=true
+type ServiceAttachmentConsumerStatus struct {
+	// IPAddress is the internal IP address of the endpoint
+	// +optional
+	IPAddress string `json:"ipAddress,omitempty"`
+
+	// AddressURL is the URL of the GCE Address resource reserved for the endpoint
+	// +optional
+	AddressURL string `json:"addressURL,omitempty"`
+
+	// ForwardingRuleURL is the URL of the GCE consumer Forwarding Rule of the endpoint
+	// +optional
+	ForwardingRuleURL string `json:"forwardingRuleURL,omitempty"`
+
+	// ConnectionStatus is the status of the connection to the Service Attachment,
+	// as reported by GCE
+	// +optional
+	ConnectionStatus string `json:"connectionStatus,omitempty"`
+
+	// ServiceName is the name of the headless Service of the endpoint
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+
+	// LastModifiedTimestamp tracks last time Status was updated
+	// +optional
+	LastModifiedTimestamp metav1.Time `json:"lastModifiedTimestamp,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// ServiceAttachmentConsumerList is a list of ServiceAttachmentConsumer resources
+type ServiceAttachmentConsumerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ServiceAttachmentConsumer `json:"items"`
+}
@@ -25,6 +25,39 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsumerConnectionDecision) DeepCopyInto(out *ConsumerConnectionDecision) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsumerConnectionDecision.
+func (in *ConsumerConnectionDecision) DeepCopy() *ConsumerConnectionDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsumerConnectionDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConsumerForwardingRule) DeepCopyInto(out *ConsumerForwardingRule) {
 	*out = *in
@@ -67,6 +100,43 @@ func (in *ConsumerProject) DeepCopy() *ConsumerProject {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsumerServicePort) DeepCopyInto(out *ConsumerServicePort) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsumerServicePort.
+func (in *ConsumerServicePort) DeepCopy() *ConsumerServicePort {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsumerServicePort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsumerServiceSpec) DeepCopyInto(out *ConsumerServiceSpec) {
+	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]ConsumerServicePort, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsumerServiceSpec.
+func (in *ConsumerServiceSpec) DeepCopy() *ConsumerServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsumerServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAttachment) DeepCopyInto(out *ServiceAttachment) {
 	*out = *in
@@ -95,6 +165,105 @@ func (in *ServiceAttachment) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAttachmentConsumer) DeepCopyInto(out *ServiceAttachmentConsumer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAttachmentConsumer.
+func (in *ServiceAttachmentConsumer) DeepCopy() *ServiceAttachmentConsumer {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAttachmentConsumer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceAttachmentConsumer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAttachmentConsumerList) DeepCopyInto(out *ServiceAttachmentConsumerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServiceAttachmentConsumer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAttachmentConsumerList.
+func (in *ServiceAttachmentConsumerList) DeepCopy() *ServiceAttachmentConsumerList {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAttachmentConsumerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceAttachmentConsumerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAttachmentConsumerSpec) DeepCopyInto(out *ServiceAttachmentConsumerSpec) {
+	*out = *in
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ConsumerServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAttachmentConsumerSpec.
+func (in *ServiceAttachmentConsumerSpec) DeepCopy() *ServiceAttachmentConsumerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAttachmentConsumerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAttachmentConsumerStatus) DeepCopyInto(out *ServiceAttachmentConsumerStatus) {
+	*out = *in
+	in.LastModifiedTimestamp.DeepCopyInto(&out.LastModifiedTimestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAttachmentConsumerStatus.
+func (in *ServiceAttachmentConsumerStatus) DeepCopy() *ServiceAttachmentConsumerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAttachmentConsumerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAttachmentList) DeepCopyInto(out *ServiceAttachmentList) {
 	*out = *in
@@ -149,6 +318,16 @@ func (in *ServiceAttachmentSpec) DeepCopyInto(out *ServiceAttachmentSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ConsumerConnectionDecisions != nil {
+		in, out := &in.ConsumerConnectionDecisions, &out.ConsumerConnectionDecisions
+		*out = make([]ConsumerConnectionDecision, len(*in))
+		copy(*out, *in)
+	}
+	if in.DomainNames != nil {
+		in, out := &in.DomainNames, &out.DomainNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -171,6 +350,13 @@ func (in *ServiceAttachmentStatus) DeepCopyInto(out *ServiceAttachmentStatus) {
 		copy(*out, *in)
 	}
 	in.LastModifiedTimestamp.DeepCopyInto(&out.LastModifiedTimestamp)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -30,11 +30,107 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerForwardingRule":  schema_pkg_apis_serviceattachment_v1_ConsumerForwardingRule(ref),
-		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerProject":         schema_pkg_apis_serviceattachment_v1_ConsumerProject(ref),
-		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ServiceAttachment":       schema_pkg_apis_serviceattachment_v1_ServiceAttachment(ref),
-		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ServiceAttachmentSpec":   schema_pkg_apis_serviceattachment_v1_ServiceAttachmentSpec(ref),
-		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ServiceAttachmentStatus": schema_pkg_apis_serviceattachment_v1_ServiceAttachmentStatus(ref),
+		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.Condition":                       schema_pkg_apis_serviceattachment_v1_Condition(ref),
+		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerConnectionDecision":      schema_pkg_apis_serviceattachment_v1_ConsumerConnectionDecision(ref),
+		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerForwardingRule":          schema_pkg_apis_serviceattachment_v1_ConsumerForwardingRule(ref),
+		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerProject":                 schema_pkg_apis_serviceattachment_v1_ConsumerProject(ref),
+		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerServicePort":             schema_pkg_apis_serviceattachment_v1_ConsumerServicePort(ref),
+		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerServiceSpec":             schema_pkg_apis_serviceattachment_v1_ConsumerServiceSpec(ref),
+		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ServiceAttachment":               schema_pkg_apis_serviceattachment_v1_ServiceAttachment(ref),
+		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ServiceAttachmentConsumer":       schema_pkg_apis_serviceattachment_v1_ServiceAttachmentConsumer(ref),
+		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ServiceAttachmentConsumerSpec":   schema_pkg_apis_serviceattachment_v1_ServiceAttachmentConsumerSpec(ref),
+		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ServiceAttachmentConsumerStatus": schema_pkg_apis_serviceattachment_v1_ServiceAttachmentConsumerStatus(ref),
+		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ServiceAttachmentSpec":           schema_pkg_apis_serviceattachment_v1_ServiceAttachmentSpec(ref),
+		"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ServiceAttachmentStatus":         schema_pkg_apis_serviceattachment_v1_ServiceAttachmentStatus(ref),
+	}
+}
+
+func schema_pkg_apis_serviceattachment_v1_Condition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Condition contains details for the current condition of the ServiceAttachment",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type is the type of the condition.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status of the condition, one of True, False, Unknown.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Last time the condition transitioned from one status to another.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The reason for the condition's last transition",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A human readable message indicating details about the transition. This field may be empty.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"type", "status", "lastTransitionTime", "reason", "message"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_serviceattachment_v1_ConsumerConnectionDecision(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConsumerConnectionDecision is the approval decision for a consumer forwarding rule",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"forwardingRuleURL": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ForwardingRuleURL is the URL of the consumer forwarding rule, as reported in the ConsumerForwardingRules of the status",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"decision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Decision is either ACCEPT or REJECT",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"connectionLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConnectionLimit is the connection limit for the consumer project when accepted",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -132,6 +228,74 @@ func schema_pkg_apis_serviceattachment_v1_ConsumerProject(ref common.ReferenceCa
 	}
 }
 
+func schema_pkg_apis_serviceattachment_v1_ConsumerServicePort(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConsumerServicePort is a port of the headless Service of a ServiceAttachmentConsumer",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the port, required if there is more than one port",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"protocol": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Protocol is the protocol of the port. Defaults to TCP.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"port": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Port is the port number",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_serviceattachment_v1_ConsumerServiceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConsumerServiceSpec configures the headless Service of a ServiceAttachmentConsumer",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"ports": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Ports are the ports of the published service exposed by the Service",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerServicePort"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerServicePort"},
+	}
+}
+
 func schema_pkg_apis_serviceattachment_v1_ServiceAttachment(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -179,6 +343,152 @@ func schema_pkg_apis_serviceattachment_v1_ServiceAttachment(ref common.Reference
 	}
 }
 
+func schema_pkg_apis_serviceattachment_v1_ServiceAttachmentConsumer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ServiceAttachmentConsumer represents a Private Service Connect endpoint in the cluster's network that connects to a published GCE Service Attachment",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ServiceAttachmentConsumerSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ServiceAttachmentConsumerStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ServiceAttachmentConsumerSpec", "k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ServiceAttachmentConsumerStatus"},
+	}
+}
+
+func schema_pkg_apis_serviceattachment_v1_ServiceAttachmentConsumerSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ServiceAttachmentConsumerSpec is the spec for a ServiceAttachmentConsumer resource",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"serviceAttachmentURL": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServiceAttachmentURL is the URL of the GCE Service Attachment to connect to",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"subnetwork": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Subnetwork is the name or URL of the subnet to reserve the internal IP address of the endpoint in. Defaults to the subnet of the cluster.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ipAddress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IPAddress is the internal IP address to reserve for the endpoint. An address of the subnet is allocated if empty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"service": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Service when set creates a headless Service with the name of the ServiceAttachmentConsumer, and an EndpointSlice pointing at the IP address of the endpoint, so that clients in the cluster can reach the published service by DNS name",
+							Ref:         ref("k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerServiceSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerServiceSpec"},
+	}
+}
+
+func schema_pkg_apis_serviceattachment_v1_ServiceAttachmentConsumerStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ServiceAttachmentConsumerStatus is the status for a ServiceAttachmentConsumer resource",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"ipAddress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IPAddress is the internal IP address of the endpoint",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"addressURL": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AddressURL is the URL of the GCE Address resource reserved for the endpoint",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"forwardingRuleURL": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ForwardingRuleURL is the URL of the GCE consumer Forwarding Rule of the endpoint",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"connectionStatus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConnectionStatus is the status of the connection to the Service Attachment, as reported by GCE",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"serviceName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServiceName is the name of the headless Service of the endpoint",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastModifiedTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastModifiedTimestamp tracks last time Status was updated",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_serviceattachment_v1_ServiceAttachmentSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -215,7 +525,7 @@ func schema_pkg_apis_serviceattachment_v1_ServiceAttachmentSpec(ref common.Refer
 					},
 					"resourceRef": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ResourceRef is the reference to the K8s resource that created the forwarding rule Only Services can be used as a reference",
+							Description: "ResourceRef is the reference to the K8s resource that created the forwarding rule Services and internal Ingresses (apiGroup networking.k8s.io) can be used as a reference",
 							Default:     map[string]interface{}{},
 							Ref:         ref("k8s.io/api/core/v1.TypedLocalObjectReference"),
 						},
@@ -266,11 +576,57 @@ func schema_pkg_apis_serviceattachment_v1_ServiceAttachmentSpec(ref common.Refer
 							},
 						},
 					},
+					"reconcileConnections": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReconcileConnections when set updates the status of existing consumer connections when the consumer accept or reject lists change",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"domainNames": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "DomainNames are the DNS domain names used by consumers to reach this ServiceAttachment",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"consumerConnectionDecisions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "ConsumerConnectionDecisions are the approval decisions for consumer forwarding rules connecting to this ServiceAttachment, used with the ACCEPT_MANUAL connection preference. Decisions apply to the project of the consumer forwarding rule and are added to the consumer accept or reject lists. Projects in ConsumerAllowList or ConsumerRejectList take precedence over decisions.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerConnectionDecision"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.TypedLocalObjectReference", "k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerProject"},
+			"k8s.io/api/core/v1.TypedLocalObjectReference", "k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerConnectionDecision", "k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerProject"},
 	}
 }
 
@@ -321,10 +677,39 @@ func schema_pkg_apis_serviceattachment_v1_ServiceAttachmentStatus(ref common.Ref
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
+					"appliedSpecHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AppliedSpecHash is the hash of the configuration last applied to the GCE Service Attachment. It is used to tell spec updates from changes made outside of the controller.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"type",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions are the current conditions of the ServiceAttachment",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.Condition"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time", "k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerForwardingRule"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time", "k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.Condition", "k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ConsumerForwardingRule"},
 	}
 }
@@ -46,7 +46,7 @@ type ServiceAttachmentSpec struct {
 	NATSubnets []string `json:"natSubnets,omitempty"`
 
 	// ResourceRef is the reference to the K8s resource that created the forwarding rule
-	// Only Services can be used as a reference
+	// Services and internal Ingresses (apiGroup networking.k8s.io) can be used as a reference
 	// +required
 	ResourceRef corev1.TypedLocalObjectReference `json:"resourceRef,omitempty"`
 
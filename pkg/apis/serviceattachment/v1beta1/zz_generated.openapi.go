@@ -215,7 +215,7 @@ func schema_pkg_apis_serviceattachment_v1beta1_ServiceAttachmentSpec(ref common.
 					},
 					"resourceRef": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ResourceRef is the reference to the K8s resource that created the forwarding rule Only Services can be used as a reference",
+							Description: "ResourceRef is the reference to the K8s resource that created the forwarding rule Services and internal Ingresses (apiGroup networking.k8s.io) can be used as a reference",
 							Default:     map[string]interface{}{},
 							Ref:         ref("k8s.io/api/core/v1.TypedLocalObjectReference"),
 						},
@@ -35,8 +35,78 @@ type ServiceNetworkEndpointGroup struct {
 	Status ServiceNetworkEndpointGroupStatus `json:"status,omitempty"`
 }
 
-// ServiceNetworkEndpointGroupSpec is the spec for a ServiceNetworkEndpointGroup resource
-type ServiceNetworkEndpointGroupSpec struct{}
+// ServiceNetworkEndpointGroupSpec is the spec for a ServiceNetworkEndpointGroup resource.
+// The spec is empty for ServiceNetworkEndpointGroups created by the NEG controller
+// for a Service. A user created ServiceNetworkEndpointGroup sets exactly one of
+// the fields below to declare a NEG that is not backed by Kubernetes endpoints.
+// Such a NEG can be referenced as a resource backend in an Ingress.
+// +k8s:openapi-gen=true
+type ServiceNetworkEndpointGroupSpec struct {
+	// Serverless declares a regional serverless NEG pointing at a Cloud Run
+	// service or a Cloud Function.
+	// +optional
+	Serverless *ServerlessNEGSpec `json:"serverless,omitempty"`
+
+	// Internet declares a global INTERNET_FQDN_PORT NEG pointing at an
+	// endpoint outside of Google Cloud.
+	// +optional
+	Internet *InternetNEGSpec `json:"internet,omitempty"`
+}
+
+// ServerlessNEGSpec describes the serverless backend of a SERVERLESS NEG.
+// Exactly one of CloudRun and CloudFunction must be set.
+// +k8s:openapi-gen=true
+type ServerlessNEGSpec struct {
+	// CloudRun points the NEG at a Cloud Run service.
+	// +optional
+	CloudRun *CloudRunNEGSpec `json:"cloudRun,omitempty"`
+
+	// CloudFunction points the NEG at a Cloud Function.
+	// +optional
+	CloudFunction *CloudFunctionNEGSpec `json:"cloudFunction,omitempty"`
+}
+
+// CloudRunNEGSpec identifies a Cloud Run service.
+// +k8s:openapi-gen=true
+type CloudRunNEGSpec struct {
+	// Service is the name of the Cloud Run service.
+	// +optional
+	Service string `json:"service,omitempty"`
+
+	// Tag optionally selects a named revision of the service.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// URLMask is a template used to parse the service and tag from the
+	// request URL. Either Service or URLMask must be set.
+	// +optional
+	URLMask string `json:"urlMask,omitempty"`
+}
+
+// CloudFunctionNEGSpec identifies a Cloud Function.
+// +k8s:openapi-gen=true
+type CloudFunctionNEGSpec struct {
+	// Function is the name of the Cloud Function.
+	// +optional
+	Function string `json:"function,omitempty"`
+
+	// URLMask is a template used to parse the function from the request URL.
+	// Either Function or URLMask must be set.
+	// +optional
+	URLMask string `json:"urlMask,omitempty"`
+}
+
+// InternetNEGSpec describes the single endpoint of an INTERNET_FQDN_PORT NEG.
+// +k8s:openapi-gen=true
+type InternetNEGSpec struct {
+	// FQDN is the fully qualified domain name of the endpoint.
+	// +required
+	FQDN string `json:"fqdn"`
+
+	// Port is the port of the endpoint. Defaults to 443.
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
 
 // ServiceNetworkEndpointGroupStatus is the status for a ServiceNetworkEndpointGroup resource
 // +k8s:openapi-gen=true
@@ -102,6 +172,8 @@ const (
 	VmIpPortEndpointType      = NetworkEndpointType("GCE_VM_IP_PORT")
 	VmIpEndpointType          = NetworkEndpointType("GCE_VM_IP")
 	NonGCPPrivateEndpointType = NetworkEndpointType("NON_GCP_PRIVATE_IP_PORT")
+	ServerlessEndpointType    = NetworkEndpointType("SERVERLESS")
+	InternetFQDNEndpointType  = NetworkEndpointType("INTERNET_FQDN_PORT")
 )
 
 // TODO: Replace Condition with standard Condition
@@ -114,7 +186,7 @@ type Condition struct {
 	// Status of the condition, one of True, False, Unknown.
 	// +required
 	Status corev1.ConditionStatus `json:"status" protobuf:"bytes,2,opt,name=status"`
-	// ObservedGeneration will not be set for ServiceNetworkEndpointGroup as the spec is empty.
+	// ObservedGeneration is only set for ServiceNetworkEndpointGroups with a non-empty spec.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty" protobuf:"varint,3,opt,name=observedGeneration"`
 	// Last time the condition transitioned from one status to another.
@@ -25,6 +25,38 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFunctionNEGSpec) DeepCopyInto(out *CloudFunctionNEGSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFunctionNEGSpec.
+func (in *CloudFunctionNEGSpec) DeepCopy() *CloudFunctionNEGSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFunctionNEGSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudRunNEGSpec) DeepCopyInto(out *CloudRunNEGSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudRunNEGSpec.
+func (in *CloudRunNEGSpec) DeepCopy() *CloudRunNEGSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudRunNEGSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -42,6 +74,22 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternetNEGSpec) DeepCopyInto(out *InternetNEGSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternetNEGSpec.
+func (in *InternetNEGSpec) DeepCopy() *InternetNEGSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternetNEGSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NegObjectReference) DeepCopyInto(out *NegObjectReference) {
 	*out = *in
@@ -58,12 +106,38 @@ func (in *NegObjectReference) DeepCopy() *NegObjectReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerlessNEGSpec) DeepCopyInto(out *ServerlessNEGSpec) {
+	*out = *in
+	if in.CloudRun != nil {
+		in, out := &in.CloudRun, &out.CloudRun
+		*out = new(CloudRunNEGSpec)
+		**out = **in
+	}
+	if in.CloudFunction != nil {
+		in, out := &in.CloudFunction, &out.CloudFunction
+		*out = new(CloudFunctionNEGSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerlessNEGSpec.
+func (in *ServerlessNEGSpec) DeepCopy() *ServerlessNEGSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerlessNEGSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceNetworkEndpointGroup) DeepCopyInto(out *ServiceNetworkEndpointGroup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -122,6 +196,16 @@ func (in *ServiceNetworkEndpointGroupList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceNetworkEndpointGroupSpec) DeepCopyInto(out *ServiceNetworkEndpointGroupSpec) {
 	*out = *in
+	if in.Serverless != nil {
+		in, out := &in.Serverless, &out.Serverless
+		*out = new(ServerlessNEGSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Internet != nil {
+		in, out := &in.Internet, &out.Internet
+		*out = new(InternetNEGSpec)
+		**out = **in
+	}
 	return
 }
 
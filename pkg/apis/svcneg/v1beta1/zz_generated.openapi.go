@@ -30,13 +30,79 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
+		"k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.CloudFunctionNEGSpec":              schema_pkg_apis_svcneg_v1beta1_CloudFunctionNEGSpec(ref),
+		"k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.CloudRunNEGSpec":                   schema_pkg_apis_svcneg_v1beta1_CloudRunNEGSpec(ref),
 		"k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.Condition":                         schema_pkg_apis_svcneg_v1beta1_Condition(ref),
+		"k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.InternetNEGSpec":                   schema_pkg_apis_svcneg_v1beta1_InternetNEGSpec(ref),
 		"k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.NegObjectReference":                schema_pkg_apis_svcneg_v1beta1_NegObjectReference(ref),
+		"k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.ServerlessNEGSpec":                 schema_pkg_apis_svcneg_v1beta1_ServerlessNEGSpec(ref),
 		"k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.ServiceNetworkEndpointGroup":       schema_pkg_apis_svcneg_v1beta1_ServiceNetworkEndpointGroup(ref),
+		"k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.ServiceNetworkEndpointGroupSpec":   schema_pkg_apis_svcneg_v1beta1_ServiceNetworkEndpointGroupSpec(ref),
 		"k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.ServiceNetworkEndpointGroupStatus": schema_pkg_apis_svcneg_v1beta1_ServiceNetworkEndpointGroupStatus(ref),
 	}
 }
 
+func schema_pkg_apis_svcneg_v1beta1_CloudFunctionNEGSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CloudFunctionNEGSpec identifies a Cloud Function.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"function": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Function is the name of the Cloud Function.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"urlMask": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URLMask is a template used to parse the function from the request URL. Either Function or URLMask must be set.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_svcneg_v1beta1_CloudRunNEGSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CloudRunNEGSpec identifies a Cloud Run service.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"service": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Service is the name of the Cloud Run service.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tag": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tag optionally selects a named revision of the service.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"urlMask": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URLMask is a template used to parse the service and tag from the request URL. Either Service or URLMask must be set.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_svcneg_v1beta1_Condition(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -62,7 +128,7 @@ func schema_pkg_apis_svcneg_v1beta1_Condition(ref common.ReferenceCallback) comm
 					},
 					"observedGeneration": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ObservedGeneration will not be set for ServiceNetworkEndpointGroup as the spec is empty.",
+							Description: "ObservedGeneration is only set for ServiceNetworkEndpointGroups with a non-empty spec.",
 							Type:        []string{"integer"},
 							Format:      "int64",
 						},
@@ -99,6 +165,35 @@ func schema_pkg_apis_svcneg_v1beta1_Condition(ref common.ReferenceCallback) comm
 	}
 }
 
+func schema_pkg_apis_svcneg_v1beta1_InternetNEGSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "InternetNEGSpec describes the single endpoint of an INTERNET_FQDN_PORT NEG.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"fqdn": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FQDN is the fully qualified domain name of the endpoint.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"port": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Port is the port of the endpoint. Defaults to 443.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"fqdn"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_svcneg_v1beta1_NegObjectReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -149,6 +244,33 @@ func schema_pkg_apis_svcneg_v1beta1_NegObjectReference(ref common.ReferenceCallb
 	}
 }
 
+func schema_pkg_apis_svcneg_v1beta1_ServerlessNEGSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ServerlessNEGSpec describes the serverless backend of a SERVERLESS NEG. Exactly one of CloudRun and CloudFunction must be set.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"cloudRun": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CloudRun points the NEG at a Cloud Run service.",
+							Ref:         ref("k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.CloudRunNEGSpec"),
+						},
+					},
+					"cloudFunction": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CloudFunction points the NEG at a Cloud Function.",
+							Ref:         ref("k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.CloudFunctionNEGSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.CloudFunctionNEGSpec", "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.CloudRunNEGSpec"},
+	}
+}
+
 func schema_pkg_apis_svcneg_v1beta1_ServiceNetworkEndpointGroup(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -195,6 +317,33 @@ func schema_pkg_apis_svcneg_v1beta1_ServiceNetworkEndpointGroup(ref common.Refer
 	}
 }
 
+func schema_pkg_apis_svcneg_v1beta1_ServiceNetworkEndpointGroupSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ServiceNetworkEndpointGroupSpec is the spec for a ServiceNetworkEndpointGroup resource. The spec is empty for ServiceNetworkEndpointGroups created by the NEG controller for a Service. A user created ServiceNetworkEndpointGroup sets exactly one of the fields below to declare a NEG that is not backed by Kubernetes endpoints. Such a NEG can be referenced as a resource backend in an Ingress.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"serverless": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Serverless declares a regional serverless NEG pointing at a Cloud Run service or a Cloud Function.",
+							Ref:         ref("k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.ServerlessNEGSpec"),
+						},
+					},
+					"internet": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Internet declares a global INTERNET_FQDN_PORT NEG pointing at an endpoint outside of Google Cloud.",
+							Ref:         ref("k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.InternetNEGSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.InternetNEGSpec", "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1.ServerlessNEGSpec"},
+	}
+}
+
 func schema_pkg_apis_svcneg_v1beta1_ServiceNetworkEndpointGroupStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
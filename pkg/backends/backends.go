@@ -35,6 +35,7 @@ const (
 	DefaultConnectionDrainingTimeoutSeconds = 30
 	defaultTrackingMode                     = "PER_CONNECTION"
 	PerSessionTrackingMode                  = "PER_SESSION" // the only one supported with strong session affinity
+	defaultConnectionPersistence            = "DEFAULT_FOR_PROTOCOL"
 )
 
 // LocalityLBPolicyType is the type of locality lb policy the backend service should use.
@@ -130,6 +131,12 @@ func (b *Backends) Create(sp utils.ServicePort, hcLink string, beLogger klog.Log
 			SampleRate: 1.0,
 		},
 	}
+	if sp.IsResourceNEG() {
+		// Serverless and internet NEGs have neither a port nor health checks.
+		be.Port = 0
+		be.PortName = ""
+		be.HealthChecks = nil
+	}
 
 	if sp.L7ILBEnabled {
 		// This enables l7-ILB and advanced traffic management features
@@ -363,8 +370,9 @@ func (b *Backends) EnsureL4BackendService(params L4BackendServiceParams, beLogge
 		LocalityLbPolicy:    string(params.LocalityLbPolicy),
 	}
 
-	// We need this configuration only for Strong Session Affinity feature
-	if b.useConnectionTrackingPolicy {
+	// The connection tracking policy is managed if the pool uses it for Strong Session Affinity,
+	// or if the Service specifies its own policy.
+	if b.useConnectionTrackingPolicy || params.ConnectionTrackingPolicy != nil {
 		beLogger.V(2).Info(fmt.Sprintf("EnsureL4BackendService: using connection tracking policy: %+v", params.ConnectionTrackingPolicy))
 		expectedBS.ConnectionTrackingPolicy = params.ConnectionTrackingPolicy
 	}
@@ -402,7 +410,11 @@ func (b *Backends) EnsureL4BackendService(params L4BackendServiceParams, beLogge
 		}
 	}
 
-	if backendSvcEqual(expectedBS, currentBS, b.useConnectionTrackingPolicy) {
+	// A customized policy is compared even if the Service no longer specifies one, so that it is
+	// reset to the defaults when the Service stops using it.
+	compareConnectionTracking := b.useConnectionTrackingPolicy || params.ConnectionTrackingPolicy != nil ||
+		connectionTrackingPolicyCustomized(currentBS.ConnectionTrackingPolicy)
+	if backendSvcEqual(expectedBS, currentBS, compareConnectionTracking) {
 		beLogger.V(2).Info("EnsureL4BackendService: backend service did not change, skipping update")
 		return currentBS, utils.ResourceResync, nil
 	}
@@ -460,5 +472,26 @@ func connectionTrackingPolicyEqual(a, b *composite.BackendServiceConnectionTrack
 	}
 	return a.TrackingMode == b.TrackingMode &&
 		a.EnableStrongAffinity == b.EnableStrongAffinity &&
-		a.IdleTimeoutSec == b.IdleTimeoutSec
+		a.IdleTimeoutSec == b.IdleTimeoutSec &&
+		connectionPersistence(a) == connectionPersistence(b)
+}
+
+// connectionPersistence returns the connection persistence on unhealthy backends of the policy,
+// an unspecified value is the same as the default one.
+func connectionPersistence(p *composite.BackendServiceConnectionTrackingPolicy) string {
+	if p.ConnectionPersistenceOnUnhealthyBackends == "" {
+		return defaultConnectionPersistence
+	}
+	return p.ConnectionPersistenceOnUnhealthyBackends
+}
+
+// connectionTrackingPolicyCustomized returns true if the policy tracks connections
+// or persists them on unhealthy backends differently from the defaults.
+func connectionTrackingPolicyCustomized(p *composite.BackendServiceConnectionTrackingPolicy) bool {
+	if p == nil {
+		return false
+	}
+	return p.EnableStrongAffinity ||
+		p.TrackingMode == PerSessionTrackingMode ||
+		connectionPersistence(p) != defaultConnectionPersistence
 }
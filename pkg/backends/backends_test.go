@@ -145,13 +145,13 @@ func TestEnsureL4BackendService(t *testing.T) {
 			if bs.ConnectionDraining == nil || bs.ConnectionDraining.DrainingTimeoutSec != DefaultConnectionDrainingTimeoutSeconds {
 				t.Errorf("BackendService.ConnectionDraining was not populated correctly, want=connection draining with %q, got=%q", DefaultConnectionDrainingTimeoutSeconds, bs.ConnectionDraining)
 			}
-			if tc.enableStrongSessionAffinity {
+			if tc.enableStrongSessionAffinity || tc.connectionTrackingPolicy != nil {
 				if diff := cmp.Diff(bs.ConnectionTrackingPolicy, tc.connectionTrackingPolicy); diff != "" {
 					t.Errorf("BackendService.ConnectionTrackingPolicy was not populated correctly, expected to be different: %s", diff)
 				}
 			} else {
 				if bs.ConnectionTrackingPolicy != nil {
-					t.Errorf("ConnectionTrackingPolicy should not be set for non strong session affinity services.")
+					t.Errorf("ConnectionTrackingPolicy should not be set for services without a connection tracking policy.")
 				}
 			}
 		})
@@ -261,6 +261,79 @@ func TestEnsureL4BackendServiceUpdate(t *testing.T) {
 	}
 }
 
+func TestEnsureL4BackendServiceConnectionTrackingPolicyUpdate(t *testing.T) {
+	namespacedName := types.NamespacedName{Name: "test-service", Namespace: "test-ns"}
+	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+	(fakeGCE.Compute().(*cloud.MockGCE)).MockRegionBackendServices.UpdateHook = mock.UpdateRegionBackendServiceHook
+	l4namer := namer.NewL4Namer(kubeSystemUID, nil)
+	backendPool := NewPoolWithConnectionTrackingPolicy(fakeGCE, l4namer, false)
+
+	backendParams := L4BackendServiceParams{
+		Name:            l4namer.L4Backend(namespacedName.Namespace, namespacedName.Name),
+		HealthCheckLink: l4namer.L4HealthCheck(namespacedName.Namespace, namespacedName.Name, false),
+		Protocol:        "TCP",
+		SessionAffinity: string(v1.ServiceAffinityClientIP),
+		Scheme:          string(cloud.SchemeInternal),
+		NamespacedName:  namespacedName,
+		NetworkInfo:     network.DefaultNetwork(fakeGCE),
+	}
+	for _, step := range []struct {
+		desc         string
+		policy       *composite.BackendServiceConnectionTrackingPolicy
+		expectUpdate utils.ResourceSyncStatus
+	}{
+		{
+			desc:         "create without policy",
+			expectUpdate: utils.ResourceUpdate,
+		},
+		{
+			desc: "set policy",
+			policy: &composite.BackendServiceConnectionTrackingPolicy{
+				TrackingMode:   perSessionTrackingMode,
+				IdleTimeoutSec: prolongedIdleTimeout,
+			},
+			expectUpdate: utils.ResourceUpdate,
+		},
+		{
+			desc: "unchanged policy",
+			policy: &composite.BackendServiceConnectionTrackingPolicy{
+				TrackingMode:   perSessionTrackingMode,
+				IdleTimeoutSec: prolongedIdleTimeout,
+			},
+			expectUpdate: utils.ResourceResync,
+		},
+		{
+			desc: "change connection persistence",
+			policy: &composite.BackendServiceConnectionTrackingPolicy{
+				TrackingMode:                             perSessionTrackingMode,
+				IdleTimeoutSec:                           prolongedIdleTimeout,
+				ConnectionPersistenceOnUnhealthyBackends: "NEVER_PERSIST",
+			},
+			expectUpdate: utils.ResourceUpdate,
+		},
+		{
+			desc:         "remove policy",
+			expectUpdate: utils.ResourceUpdate,
+		},
+		{
+			desc:         "no policy",
+			expectUpdate: utils.ResourceResync,
+		},
+	} {
+		backendParams.ConnectionTrackingPolicy = step.policy
+		bs, updated, err := backendPool.EnsureL4BackendService(backendParams, klog.TODO())
+		if err != nil {
+			t.Fatalf("%s: EnsureL4BackendService() failed: %v", step.desc, err)
+		}
+		if updated != step.expectUpdate {
+			t.Errorf("%s: EnsureL4BackendService() returned update=%v, want %v", step.desc, updated, step.expectUpdate)
+		}
+		if diff := cmp.Diff(step.policy, bs.ConnectionTrackingPolicy); diff != "" {
+			t.Errorf("%s: BackendService.ConnectionTrackingPolicy mismatch (-want +got):\n%s", step.desc, diff)
+		}
+	}
+}
+
 func TestEnsureL4BackendServiceDoesNotDetachBackends(t *testing.T) {
 	// needConnectionTrackingPolicy flag for the function input
 	for _, needConnectionTrackingPolicy := range []bool{false, true} {
@@ -508,9 +581,7 @@ func TestBackendSvcEqual(t *testing.T) {
 			wantEqual: false,
 		},
 		{
-			// ConnectionPersistenceOnUnhealthyBackends change is not supported yet
-			// that's why wantEqual = True. Change this case when the support starts.
-			desc: "The customer's update to ConnectionPersistenceOnUnhealthyBackends will not be overriden",
+			desc: "The customer's update to ConnectionPersistenceOnUnhealthyBackends will not be overriden if connection tracking is not compared",
 			oldBackendService: &composite.BackendService{
 				ConnectionTrackingPolicy: &composite.BackendServiceConnectionTrackingPolicy{
 					EnableStrongAffinity:                     false,
@@ -529,6 +600,39 @@ func TestBackendSvcEqual(t *testing.T) {
 			},
 			wantEqual: true,
 		},
+		{
+			desc:                      "Test with changed ConnectionPersistenceOnUnhealthyBackends",
+			compareConnectionTracking: true,
+			oldBackendService: &composite.BackendService{
+				ConnectionTrackingPolicy: &composite.BackendServiceConnectionTrackingPolicy{
+					TrackingMode:                             perSessionTrackingMode,
+					ConnectionPersistenceOnUnhealthyBackends: "NEVER_PERSIST",
+				},
+			},
+			newBackendService: &composite.BackendService{
+				ConnectionTrackingPolicy: &composite.BackendServiceConnectionTrackingPolicy{
+					TrackingMode:                             perSessionTrackingMode,
+					ConnectionPersistenceOnUnhealthyBackends: "DEFAULT_FOR_PROTOCOL",
+				},
+			},
+			wantEqual: false,
+		},
+		{
+			desc:                      "Test unspecified ConnectionPersistenceOnUnhealthyBackends is equal to the default",
+			compareConnectionTracking: true,
+			oldBackendService: &composite.BackendService{
+				ConnectionTrackingPolicy: &composite.BackendServiceConnectionTrackingPolicy{
+					TrackingMode:                             perSessionTrackingMode,
+					ConnectionPersistenceOnUnhealthyBackends: "DEFAULT_FOR_PROTOCOL",
+				},
+			},
+			newBackendService: &composite.BackendService{
+				ConnectionTrackingPolicy: &composite.BackendServiceConnectionTrackingPolicy{
+					TrackingMode: perSessionTrackingMode,
+				},
+			},
+			wantEqual: true,
+		},
 		{
 			desc:                      "Test with ignoring connection tracking",
 			compareConnectionTracking: false,
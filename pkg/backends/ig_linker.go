@@ -84,11 +84,17 @@ func NewInstanceGroupLinker(instancePool instancegroups.Manager, backendPool Poo
 func (igl *instanceGroupLinker) Link(sp utils.ServicePort, groups []GroupKey) error {
 	var igLinks []string
 	for _, group := range groups {
-		ig, err := igl.instancePool.Get(sp.IGName(), group.Zone)
+		igNames, err := igl.instancePool.BackendInstanceGroups(sp.IGName(), group.Zone, igl.logger)
 		if err != nil {
-			return fmt.Errorf("error retrieving IG for linking with backend %+v: %w", sp, err)
+			return fmt.Errorf("error listing IGs for linking with backend %+v: %w", sp, err)
+		}
+		for _, igName := range igNames {
+			ig, err := igl.instancePool.Get(igName, group.Zone)
+			if err != nil {
+				return fmt.Errorf("error retrieving IG for linking with backend %+v: %w", sp, err)
+			}
+			igLinks = append(igLinks, ig.SelfLink)
 		}
-		igLinks = append(igLinks, ig.SelfLink)
 	}
 
 	// ig_linker only supports L7 HTTP(s) External Load Balancer
@@ -102,12 +108,20 @@ func (igl *instanceGroupLinker) Link(sp utils.ServicePort, groups []GroupKey) er
 		return err
 	}
 
-	addIGs, _, err := getInstanceGroupsToAddAndRemove(be, igLinks, igl.logger)
+	addIGs, removeIGs, err := getInstanceGroupsToAddAndRemove(be, igLinks, igl.logger)
 	if err != nil {
 		return err
 	}
+	// Other instance groups are kept, only the shards of the instance group
+	// which are no longer needed are unlinked.
+	removeShards := sets.NewString()
+	for _, path := range removeIGs.List() {
+		if sp.BackendNamer.IsInstanceGroupShard(path[strings.LastIndex(path, "/")+1:]) {
+			removeShards.Insert(path)
+		}
+	}
 
-	if len(addIGs) == 0 {
+	if len(addIGs) == 0 && removeShards.Len() == 0 {
 		return nil
 	}
 
@@ -115,7 +129,14 @@ func (igl *instanceGroupLinker) Link(sp utils.ServicePort, groups []GroupKey) er
 	for _, backend := range be.Backends {
 		// Backend service is not able to point to NEG and IG at the same time.
 		// Filter IG backends here.
-		if strings.Contains(backend.Group, "instanceGroups") {
+		if !strings.Contains(backend.Group, "instanceGroups") {
+			continue
+		}
+		path, err := utils.RelativeResourceName(backend.Group)
+		if err != nil {
+			return err
+		}
+		if !removeShards.Has(path) {
 			originalIGBackends = append(originalIGBackends, backend)
 		}
 	}
@@ -19,6 +19,7 @@ import (
 	"net/http"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
@@ -96,6 +97,78 @@ func TestLink(t *testing.T) {
 	}
 }
 
+func TestLinkMultipleInstanceGroups(t *testing.T) {
+	fakeIGs := instancegroups.NewFakeInstanceGroups(map[string]instancegroups.IGsToInstances{}, 2)
+	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+
+	nodeInformer := zonegetter.FakeNodeInformer()
+	fakeZoneGetter := zonegetter.NewFakeZoneGetter(nodeInformer, defaultTestSubnetURL, false)
+	zonegetter.AddFakeNodes(fakeZoneGetter, defaultTestZone, "test-instance-1", "test-instance-2", "test-instance-3")
+
+	fakeNodePool := instancegroups.NewManager(&instancegroups.ManagerConfig{
+		Cloud:             fakeIGs,
+		Namer:             defaultNamer,
+		Recorders:         &test.FakeRecorderSource{},
+		BasePath:          utils.GetBasePath(fakeGCE),
+		ZoneGetter:        fakeZoneGetter,
+		MaxIGSize:         2,
+		EnableMultipleIGs: true,
+	})
+	linker := newTestIGLinker(fakeGCE, fakeNodePool)
+
+	sp := utils.ServicePort{NodePort: 8080, Protocol: annotations.ProtocolHTTP, BackendNamer: defaultNamer}
+
+	// Mimic the instance groups being created, 3 nodes need 2 instance groups.
+	if _, err := linker.instancePool.EnsureInstanceGroupsAndPorts(defaultNamer.InstanceGroup(), []int64{sp.NodePort}, klog.TODO()); err != nil {
+		t.Fatalf("Did not expect error when ensuring IG for ServicePort %+v: %v", sp, err)
+	}
+
+	// Mimic the syncer creating the backend.
+	linker.backendPool.Create(sp, "fake-health-check-link", klog.TODO())
+
+	if err := linker.Link(sp, []GroupKey{{Zone: defaultTestZone}}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	be, err := fakeGCE.GetGlobalBackendService(sp.BackendName())
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(be.Backends) != 2 {
+		t.Fatalf("Expected 2 Backends to be created, got %d", len(be.Backends))
+	}
+	for i, backend := range be.Backends {
+		if want := defaultNamer.InstanceGroupShard(i); !strings.HasSuffix(backend.Group, "/"+want) {
+			t.Errorf("Backend %d has group %s, want instance group %s", i, backend.Group, want)
+		}
+	}
+
+	nodes := []string{"test-instance-1", "test-instance-2", "test-instance-3"}
+	if err := fakeNodePool.Sync(nodes, klog.TODO()); err != nil {
+		t.Fatalf("Sync(%v) returned error %v", nodes, err)
+	}
+
+	// After a scale down, the shard which is no longer needed is unlinked
+	// once its nodes are moved to the first one.
+	nodes = []string{"test-instance-1", "test-instance-2"}
+	zonegetter.DeleteFakeNodesInZone(t, defaultTestZone, fakeZoneGetter)
+	zonegetter.AddFakeNodes(fakeZoneGetter, defaultTestZone, nodes...)
+	if err := fakeNodePool.Sync(nodes, klog.TODO()); err != nil {
+		t.Fatalf("Sync(%v) returned error %v", nodes, err)
+	}
+	if err := linker.Link(sp, []GroupKey{{Zone: defaultTestZone}}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	be, err = fakeGCE.GetGlobalBackendService(sp.BackendName())
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(be.Backends) != 1 || !strings.HasSuffix(be.Backends[0].Group, "/"+defaultNamer.InstanceGroup()) {
+		t.Errorf("Backends after scale down = %+v, want only instance group %s", be.Backends, defaultNamer.InstanceGroup())
+	}
+}
+
 func TestLinkWithCreationModeError(t *testing.T) {
 	fakeIGs := instancegroups.NewEmptyFakeInstanceGroups()
 	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
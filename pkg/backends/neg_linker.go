@@ -104,10 +104,20 @@ func (nl *negLinker) getNegSelfLinks(sp utils.ServicePort, groups []GroupKey) ([
 	version := befeatures.VersionFromServicePort(&sp)
 	negName := sp.NEGName()
 	svcNegKey := fmt.Sprintf("%s/%s", sp.ID.Service.Namespace, negName)
+	if sp.IsResourceNEG() {
+		// The ServiceNetworkEndpointGroup declaring a resource NEG is stored
+		// under its own name, which differs from the name of the NEG.
+		svcNegKey = utils.ServiceKeyFunc(sp.ID.Service.Namespace, sp.ID.Service.Name)
+	}
 	urls, ok := getNegUrlsFromSvcneg(svcNegKey, nl.svcNegLister, nl.logger)
 	if ok {
 		return urls, nil
 	}
+	if sp.IsResourceNEG() {
+		// There is no fallback for NEGs declared in a ServiceNetworkEndpointGroup
+		// as their name and scope are only known from the CR.
+		return nil, fmt.Errorf("ServiceNetworkEndpointGroup %s not found", svcNegKey)
+	}
 
 	var negSelfLinks []string
 	// In fail-safe situation, we only link NEGs in the default subnet.
@@ -213,6 +223,10 @@ func backendsForNEGs(negSelfLinks []string, sp *utils.ServicePort) []*composite.
 		newBackend := &composite.Backend{Group: neg}
 
 		switch getNegType(*sp) {
+		case types.ServerlessEndpointType, types.InternetFQDNEndpointType:
+			// Serverless and internet NEGs do not support balancing modes or
+			// capacity settings.
+
 		case types.VmIpEndpointType:
 			// Setting MaxConnectionsPerEndpoint is not supported for L4 ILB
 			// https://cloud.google.com/load-balancing/docs/backend-service#target_capacity
@@ -245,6 +259,9 @@ func backendsForNEGs(negSelfLinks []string, sp *utils.ServicePort) []*composite.
 
 // getNegType returns NEG type based on service port config
 func getNegType(sp utils.ServicePort) types.NetworkEndpointType {
+	if sp.IsResourceNEG() {
+		return types.NetworkEndpointType(sp.ResourceNEGType)
+	}
 	if sp.VMIPNEGEnabled {
 		return types.VmIpEndpointType
 	}
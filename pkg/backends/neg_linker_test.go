@@ -15,6 +15,7 @@ package backends
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -347,6 +348,50 @@ func TestGetNegSelfLinks(t *testing.T) {
 	}
 }
 
+func TestGetNegSelfLinksResourceNEG(t *testing.T) {
+	t.Parallel()
+
+	svc := types.NamespacedName{Namespace: "ns", Name: "serverless-neg"}
+	svcPort := utils.ServicePort{
+		ID:              utils.ServicePortID{Service: svc},
+		Protocol:        annotations.ProtocolHTTP,
+		NEGEnabled:      true,
+		ResourceNEGType: v1beta1.ServerlessEndpointType,
+		BackendNamer:    defaultNamer,
+	}
+	negLink := fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/mock-project/regions/us-central1/networkEndpointGroups/%s", svcPort.NEGName())
+
+	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+	fakeNEG := negtypes.NewFakeNetworkEndpointGroupCloud("test-subnetwork", "test-network")
+	linker := newTestNEGLinker(fakeNEG, fakeGCE)
+
+	if _, err := linker.getNegSelfLinks(svcPort, nil); err == nil {
+		t.Errorf("getNegSelfLinks() without ServiceNetworkEndpointGroup returned nil error, want error")
+	}
+
+	// The ServiceNetworkEndpointGroup is stored under its own name, not the
+	// name of the NEG it declares.
+	svcNeg := &v1beta1.ServiceNetworkEndpointGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      svc.Name,
+			Namespace: svc.Namespace,
+		},
+		Status: v1beta1.ServiceNetworkEndpointGroupStatus{
+			NetworkEndpointGroups: []v1beta1.NegObjectReference{{SelfLink: negLink, State: v1beta1.ActiveState}},
+		},
+	}
+	if err := linker.svcNegLister.Add(svcNeg); err != nil {
+		t.Fatalf("Failed to add svcneg: %v", err)
+	}
+	negLinks, err := linker.getNegSelfLinks(svcPort, nil)
+	if err != nil {
+		t.Fatalf("getNegSelfLinks() returned error %v, want nil", err)
+	}
+	if want := []string{negLink}; !reflect.DeepEqual(negLinks, want) {
+		t.Errorf("getNegSelfLinks() = %v, want %v", negLinks, want)
+	}
+}
+
 func TestMergeBackends(t *testing.T) {
 	t.Parallel()
 
@@ -46,9 +46,15 @@ func (linker *RegionalInstanceGroupLinker) Link(sp utils.ServicePort, projectID
 
 	var igLinks []string
 	for _, zone := range zones {
-		key := meta.ZonalKey(sp.IGName(), zone)
-		igSelfLink := cloudprovider.SelfLink(meta.VersionGA, projectID, "instanceGroups", key)
-		igLinks = append(igLinks, igSelfLink)
+		igNames, err := linker.instancePool.BackendInstanceGroups(sp.IGName(), zone, linker.logger)
+		if err != nil {
+			return err
+		}
+		for _, igName := range igNames {
+			key := meta.ZonalKey(igName, zone)
+			igSelfLink := cloudprovider.SelfLink(meta.VersionGA, projectID, "instanceGroups", key)
+			igLinks = append(igLinks, igSelfLink)
+		}
 	}
 	// TODO(cheungdavid): Create regional ig linker logger that contains backendName,
 	// backendVersion, and backendScope before passing to backendPool.Get().
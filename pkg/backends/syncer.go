@@ -84,10 +84,15 @@ func (s *backendSyncer) ensureBackendService(sp utils.ServicePort, ingLogger klo
 	)
 	be, getErr := s.backendPool.Get(beName, version, scope, beLogger)
 
-	// Ensure health check for backend service exists.
-	hcLink, err := s.ensureHealthCheck(sp, beLogger)
-	if err != nil {
-		return fmt.Errorf("error ensuring health check: %w", err)
+	// Ensure health check for backend service exists. Backend services of
+	// serverless and internet NEGs do not support health checks.
+	var hcLink string
+	if !sp.IsResourceNEG() {
+		var err error
+		hcLink, err = s.ensureHealthCheck(sp, beLogger)
+		if err != nil {
+			return fmt.Errorf("error ensuring health check: %w", err)
+		}
 	}
 
 	// Verify existence of a backend service for the proper port
@@ -98,6 +103,7 @@ func (s *backendSyncer) ensureBackendService(sp utils.ServicePort, ingLogger klo
 		}
 		// Only create the backend service if the error was 404.
 		beLogger.Info("Creating backend service")
+		var err error
 		be, err = s.backendPool.Create(sp, hcLink, beLogger)
 		if err != nil {
 			return err
@@ -105,7 +111,9 @@ func (s *backendSyncer) ensureBackendService(sp utils.ServicePort, ingLogger klo
 	}
 
 	needUpdate := ensureProtocol(be, sp)
-	needUpdate = ensureHealthCheckLink(be, hcLink) || needUpdate
+	if !sp.IsResourceNEG() {
+		needUpdate = ensureHealthCheckLink(be, hcLink) || needUpdate
+	}
 	needUpdate = ensureDescription(be, &sp) || needUpdate
 	if sp.BackendConfig != nil {
 		needUpdate = features.EnsureCDN(sp, be, beLogger) || needUpdate
@@ -115,3 +115,38 @@ func (op *IngressesOperator) ReferencesSvcNeg(negCr *negv1beta1.ServiceNetworkEn
 
 	return op.ReferencesService(svc)
 }
+
+// ReferencesResourceNEG returns the Ingresses that reference the given NEG CR
+// as a resource backend.
+func (op *IngressesOperator) ReferencesResourceNEG(negCr *negv1beta1.ServiceNetworkEndpointGroup) *IngressesOperator {
+	return op.Filter(func(ing *v1.Ingress) bool {
+		return doesIngressReferenceResourceNEG(ing, negCr)
+	})
+}
+
+// doesIngressReferenceResourceNEG returns true if the default backend or any
+// path of the Ingress references the given NEG CR as a resource backend.
+func doesIngressReferenceResourceNEG(ing *v1.Ingress, negCr *negv1beta1.ServiceNetworkEndpointGroup) bool {
+	if ing.Namespace != negCr.Namespace {
+		return false
+	}
+	references := func(be *v1.IngressBackend) bool {
+		return be != nil && be.Resource != nil &&
+			be.Resource.APIGroup != nil && *be.Resource.APIGroup == negv1beta1.SchemeGroupVersion.Group &&
+			be.Resource.Kind == "ServiceNetworkEndpointGroup" && be.Resource.Name == negCr.Name
+	}
+	if references(ing.Spec.DefaultBackend) {
+		return true
+	}
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if references(&path.Backend) {
+				return true
+			}
+		}
+	}
+	return false
+}
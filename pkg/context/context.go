@@ -104,6 +104,7 @@ type ControllerContext struct {
 	IngClassInformer         cache.SharedIndexInformer
 	IngParamsInformer        cache.SharedIndexInformer
 	SAInformer               cache.SharedIndexInformer
+	SAConsumerInformer       cache.SharedIndexInformer
 	FirewallInformer         cache.SharedIndexInformer
 	NetworkInformer          cache.SharedIndexInformer
 	GKENetworkParamsInformer cache.SharedIndexInformer
@@ -143,6 +144,10 @@ type ControllerContextConfig struct {
 	ASMConfigMapNamespace         string
 	ASMConfigMapName              string
 	MaxIGSize                     int
+	ManagedIGNodeLabel            string
+	MaxConcurrentIGZoneSyncs      int
+	IGAddInstancesBatchSize       int
+	EnableMultipleIGs             bool
 	EnableL4ILBDualStack          bool
 	EnableL4NetLBDualStack        bool
 	EnableL4StrongSessionAffinity bool // flag that enables strong session affinity feature
@@ -151,6 +156,7 @@ type ControllerContextConfig struct {
 	EnableWeightedL4ILB           bool
 	EnableWeightedL4NetLB         bool
 	DisableL4LBFirewall           bool
+	EnablePSCConsumer             bool
 }
 
 // NewControllerContext returns a new shared set of informers.
@@ -221,6 +227,9 @@ func NewControllerContext(
 
 	if saClient != nil {
 		context.SAInformer = informerserviceattachment.NewServiceAttachmentInformer(saClient, config.Namespace, config.ResyncPeriod, utils.NewNamespaceIndexer())
+		if config.EnablePSCConsumer {
+			context.SAConsumerInformer = informerserviceattachment.NewServiceAttachmentConsumerInformer(saClient, config.Namespace, config.ResyncPeriod, utils.NewNamespaceIndexer())
+		}
 	}
 
 	if networkClient != nil {
@@ -248,6 +257,7 @@ func NewControllerContext(
 		context.NodeInformer,
 		context.PodInformer,
 		context.EndpointSliceInformer,
+		context.SvcNegInformer,
 		context.KubeClient,
 		context,
 		flags.F.EnableTransparentHealthChecks,
@@ -257,12 +267,16 @@ func NewControllerContext(
 	// The subnet specified in gce.conf is considered as the default subnet.
 	context.ZoneGetter = zonegetter.NewZoneGetter(context.NodeInformer, context.Cloud.SubnetworkURL())
 	context.InstancePool = instancegroups.NewManager(&instancegroups.ManagerConfig{
-		Cloud:      context.Cloud,
-		Namer:      context.ClusterNamer,
-		Recorders:  context,
-		BasePath:   utils.GetBasePath(context.Cloud),
-		ZoneGetter: context.ZoneGetter,
-		MaxIGSize:  config.MaxIGSize,
+		Cloud:                  context.Cloud,
+		Namer:                  context.ClusterNamer,
+		Recorders:              context,
+		BasePath:               utils.GetBasePath(context.Cloud),
+		ZoneGetter:             context.ZoneGetter,
+		MaxIGSize:              config.MaxIGSize,
+		ManagedIGNodeLabel:     config.ManagedIGNodeLabel,
+		MaxConcurrentZoneSyncs: config.MaxConcurrentIGZoneSyncs,
+		AddInstancesBatchSize:  config.IGAddInstancesBatchSize,
+		EnableMultipleIGs:      config.EnableMultipleIGs,
 	})
 
 	return context
@@ -332,6 +346,9 @@ func (ctx *ControllerContext) HasSynced() bool {
 	if ctx.SAInformer != nil {
 		funcs = append(funcs, ctx.SAInformer.HasSynced)
 	}
+	if ctx.SAConsumerInformer != nil {
+		funcs = append(funcs, ctx.SAConsumerInformer.HasSynced)
+	}
 	if ctx.NetworkInformer != nil {
 		funcs = append(funcs, ctx.NetworkInformer.HasSynced)
 	}
@@ -430,6 +447,9 @@ func (ctx *ControllerContext) Start(stopCh <-chan struct{}) {
 	if ctx.SAInformer != nil {
 		go ctx.SAInformer.Run(stopCh)
 	}
+	if ctx.SAConsumerInformer != nil {
+		go ctx.SAConsumerInformer.Run(stopCh)
+	}
 	if ctx.NetworkInformer != nil {
 		go ctx.NetworkInformer.Run(stopCh)
 	}
@@ -327,6 +327,23 @@ func NewLoadBalancerController(
 		})
 	}
 
+	if flags.F.EnableResourceNEGs {
+		// Resync Ingresses using a serverless or internet NEG as a resource
+		// backend once the NEG is created.
+		ctx.SvcNegInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, cur interface{}) {
+				oldSvcNeg := old.(*negv1beta1.ServiceNetworkEndpointGroup)
+				newSvcNeg := cur.(*negv1beta1.ServiceNetworkEndpointGroup)
+
+				if negtypes.ResourceNEGType(newSvcNeg) != "" && !reflect.DeepEqual(oldSvcNeg.Status.NetworkEndpointGroups, newSvcNeg.Status.NetworkEndpointGroups) {
+					logger.Info("Resource svcneg updated", "namespace", newSvcNeg.Namespace, "name", newSvcNeg.Name)
+					ings := operator.Ingresses(ctx.Ingresses().List()).ReferencesResourceNEG(newSvcNeg).AsList()
+					lbc.ingQueue.Enqueue(convert(ings)...)
+				}
+			},
+		})
+	}
+
 	// Register health check on controller context.
 	ctx.AddHealthCheck("ingress", func() error {
 		name := "k8s-ingress-svc-acct-permission-check-probe"
@@ -36,6 +36,7 @@ import (
 
 	"k8s.io/ingress-gce/pkg/annotations"
 	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	negv1beta1 "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1"
 	"k8s.io/ingress-gce/pkg/backendconfig"
 	"k8s.io/ingress-gce/pkg/controller/errors"
 	"k8s.io/ingress-gce/pkg/flags"
@@ -72,6 +73,7 @@ func NewTranslator(serviceInformer cache.SharedIndexInformer,
 	nodeInformer cache.SharedIndexInformer,
 	podInformer cache.SharedIndexInformer,
 	endpointSliceInformer cache.SharedIndexInformer,
+	svcNegInformer cache.SharedIndexInformer,
 	kubeClient kubernetes.Interface,
 	recorderGetter healthchecks.RecorderGetter,
 	enableTHC,
@@ -84,6 +86,7 @@ func NewTranslator(serviceInformer cache.SharedIndexInformer,
 		NodeInformer:          nodeInformer,
 		PodInformer:           podInformer,
 		EndpointSliceInformer: endpointSliceInformer,
+		SvcNegInformer:        svcNegInformer,
 		KubeClient:            kubeClient,
 		enableTHC:             enableTHC,
 		enableL7XLBRegional:   enableL7XLBRegional,
@@ -98,6 +101,7 @@ type Translator struct {
 	NodeInformer          cache.SharedIndexInformer
 	PodInformer           cache.SharedIndexInformer
 	EndpointSliceInformer cache.SharedIndexInformer
+	SvcNegInformer        cache.SharedIndexInformer
 	KubeClient            kubernetes.Interface
 	enableTHC             bool
 	enableL7XLBRegional   bool
@@ -317,6 +321,62 @@ func (t *Translator) getServicePort(id utils.ServicePortID, params *getServicePo
 	return svcPort, nil, flagWarning
 }
 
+// getBackendServicePort returns the ServicePort of the given Ingress backend,
+// which is either a Service or a ServiceNetworkEndpointGroup resource.
+func (t *Translator) getBackendServicePort(be v1.IngressBackend, namespace string, params *getServicePortParams, namer namer_util.BackendNamer) (*utils.ServicePort, error, bool) {
+	if be.Resource != nil && flags.F.EnableResourceNEGs {
+		svcPort, err := t.getResourceNEGServicePort(*be.Resource, namespace, params, namer)
+		return svcPort, err, false
+	}
+	svcPortID, err := utils.BackendToServicePortID(be, namespace)
+	if err != nil {
+		return nil, err, false
+	}
+	return t.getServicePort(svcPortID, params, namer)
+}
+
+// getResourceNEGServicePort returns the ServicePort of a backend referencing a
+// ServiceNetworkEndpointGroup which declares a serverless or internet NEG.
+func (t *Translator) getResourceNEGServicePort(ref api_v1.TypedLocalObjectReference, namespace string, params *getServicePortParams, namer namer_util.BackendNamer) (*utils.ServicePort, error) {
+	if ref.APIGroup == nil || *ref.APIGroup != negv1beta1.SchemeGroupVersion.Group || ref.Kind != "ServiceNetworkEndpointGroup" {
+		return nil, fmt.Errorf("unsupported Ingress resource backend %v, only %s ServiceNetworkEndpointGroup is supported", ref, negv1beta1.SchemeGroupVersion.Group)
+	}
+	obj, exists, err := t.SvcNegInformer.GetIndexer().GetByKey(utils.ServiceKeyFunc(namespace, ref.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving ServiceNetworkEndpointGroup %s/%s: %w", namespace, ref.Name, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("ServiceNetworkEndpointGroup %s/%s not found", namespace, ref.Name)
+	}
+	negCR := obj.(*negv1beta1.ServiceNetworkEndpointGroup)
+
+	svcPort := &utils.ServicePort{
+		ID: utils.ServicePortID{
+			Service: types.NamespacedName{Namespace: namespace, Name: ref.Name},
+		},
+		NEGEnabled:           true,
+		L7ILBEnabled:         params.isL7ILB,
+		L7XLBRegionalEnabled: params.isL7XLBRegional,
+		BackendNamer:         namer,
+		Protocol:             annotations.ProtocolHTTP,
+	}
+	switch {
+	case negCR.Spec.Serverless != nil:
+		svcPort.ResourceNEGType = negv1beta1.ServerlessEndpointType
+	case negCR.Spec.Internet != nil:
+		if params.isL7ILB || params.isL7XLBRegional {
+			return nil, fmt.Errorf("ServiceNetworkEndpointGroup %s/%s declares an internet NEG, which is only supported by global external Ingresses", namespace, ref.Name)
+		}
+		svcPort.ResourceNEGType = negv1beta1.InternetFQDNEndpointType
+		if port := negCR.Spec.Internet.Port; port == 0 || port == 443 {
+			svcPort.Protocol = annotations.ProtocolHTTPS
+		}
+	default:
+		return nil, fmt.Errorf("ServiceNetworkEndpointGroup %s/%s does not declare a serverless or internet NEG", namespace, ref.Name)
+	}
+	return svcPort, nil
+}
+
 // TranslateIngress converts an Ingress into our internal UrlMap representation.
 // The returned bool is for warnings (there is one type of warnings currently possible).
 func (t *Translator) TranslateIngress(ing *v1.Ingress, systemDefaultBackend utils.ServicePortID, namer namer_util.BackendNamer) (*utils.GCEURLMap, []error, bool) {
@@ -332,13 +392,7 @@ func (t *Translator) TranslateIngress(ing *v1.Ingress, systemDefaultBackend util
 
 		pathRules := []utils.PathRule{}
 		for _, p := range rule.HTTP.Paths {
-			svcPortID, err := utils.BackendToServicePortID(p.Backend, ing.Namespace)
-			if err != nil {
-				// Only error possible is Backend is not a Service Backend, so move to next path
-				errs = append(errs, err)
-				continue
-			}
-			svcPort, err, warning := t.getServicePort(svcPortID, params, namer)
+			svcPort, err, warning := t.getBackendServicePort(p.Backend, ing.Namespace, params, namer)
 			warnings = warnings || warning
 			if err != nil {
 				errs = append(errs, err)
@@ -370,12 +424,7 @@ func (t *Translator) TranslateIngress(ing *v1.Ingress, systemDefaultBackend util
 	}
 
 	if ing.Spec.DefaultBackend != nil {
-		svcPortID, err := utils.BackendToServicePortID(*ing.Spec.DefaultBackend, ing.Namespace)
-		if err != nil {
-			errs = append(errs, err)
-			return urlMap, errs, warnings
-		}
-		svcPort, err, warning := t.getServicePort(svcPortID, params, namer)
+		svcPort, err, warning := t.getBackendServicePort(*ing.Spec.DefaultBackend, ing.Namespace, params, namer)
 		warnings = warnings || warning
 		if err == nil {
 			urlMap.DefaultBackend = svcPort
@@ -515,7 +564,8 @@ func (t *Translator) getHTTPProbe(svc api_v1.Service, targetPort intstr.IntOrStr
 func (t *Translator) GatherEndpointPorts(svcPorts []utils.ServicePort) []string {
 	portMap := map[int64]bool{}
 	for _, p := range svcPorts {
-		if p.NEGEnabled {
+		// Serverless and internet NEGs do not have endpoints in the cluster.
+		if p.NEGEnabled && !p.IsResourceNEG() {
 			// For NEG backend, need to open firewall to all endpoint target ports
 			// TODO(mixia): refactor firewall syncing into a separate go routine with different trigger.
 			// With NEG, endpoint changes may cause firewall ports to be different if user specifies inconsistent backends.
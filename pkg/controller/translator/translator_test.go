@@ -42,14 +42,18 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/ingress-gce/pkg/annotations"
 	backendconfig "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	negv1beta1 "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1"
 	backendconfigclient "k8s.io/ingress-gce/pkg/backendconfig/client/clientset/versioned/fake"
 	informerbackendconfig "k8s.io/ingress-gce/pkg/backendconfig/client/informers/externalversions/backendconfig/v1"
 	"k8s.io/ingress-gce/pkg/flags"
 	"k8s.io/ingress-gce/pkg/healthchecks"
+	svcnegfake "k8s.io/ingress-gce/pkg/svcneg/client/clientset/versioned/fake"
+	informersvcneg "k8s.io/ingress-gce/pkg/svcneg/client/informers/externalversions/svcneg/v1beta1"
 	"k8s.io/ingress-gce/pkg/test"
 	"k8s.io/ingress-gce/pkg/utils"
 	"k8s.io/ingress-gce/pkg/utils/endpointslices"
 	namer_util "k8s.io/ingress-gce/pkg/utils/namer"
+	utilpointer "k8s.io/utils/pointer"
 )
 
 var (
@@ -75,6 +79,7 @@ func fakeTranslator() *Translator {
 func configuredFakeTranslator() *Translator {
 	client := fake.NewSimpleClientset()
 	backendConfigClient := backendconfigclient.NewSimpleClientset()
+	svcNegClient := svcnegfake.NewSimpleClientset()
 	namespace := apiv1.NamespaceAll
 	resyncPeriod := 1 * time.Second
 
@@ -84,12 +89,14 @@ func configuredFakeTranslator() *Translator {
 	NodeInformer := informerv1.NewNodeInformer(client, resyncPeriod, utils.NewNamespaceIndexer())
 	EndpointSliceInformer := discoveryinformer.NewEndpointSliceInformer(client, namespace, 0,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc, endpointslices.EndpointSlicesByServiceIndex: endpointslices.EndpointSlicesByServiceFunc})
+	SvcNegInformer := informersvcneg.NewServiceNetworkEndpointGroupInformer(svcNegClient, namespace, resyncPeriod, utils.NewNamespaceIndexer())
 	return NewTranslator(
 		ServiceInformer,
 		BackendConfigInformer,
 		NodeInformer,
 		PodInformer,
 		EndpointSliceInformer,
+		SvcNegInformer,
 		client,
 		healthchecks.NewFakeRecorderGetter(0),
 		false,
@@ -236,6 +243,120 @@ func TestTranslateIngress(t *testing.T) {
 	}
 }
 
+func TestTranslateIngressResourceBackend(t *testing.T) {
+	oldFlag := flags.F.EnableResourceNEGs
+	defer func() {
+		flags.F.EnableResourceNEGs = oldFlag
+	}()
+
+	translator := fakeTranslator()
+	svcNegLister := translator.SvcNegInformer.GetIndexer()
+	svcNegLister.Add(&negv1beta1.ServiceNetworkEndpointGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "serverless-neg", Namespace: "default"},
+		Spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+			Serverless: &negv1beta1.ServerlessNEGSpec{CloudRun: &negv1beta1.CloudRunNEGSpec{Service: "hello"}},
+		},
+	})
+	svcNegLister.Add(&negv1beta1.ServiceNetworkEndpointGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "internet-neg", Namespace: "default"},
+		Spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+			Internet: &negv1beta1.InternetNEGSpec{FQDN: "example.com"},
+		},
+	})
+	svcNegLister.Add(&negv1beta1.ServiceNetworkEndpointGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "service-neg", Namespace: "default"},
+	})
+
+	resourceBackend := func(kind, name string) *v1.IngressBackend {
+		return &v1.IngressBackend{
+			Resource: &apiv1.TypedLocalObjectReference{
+				APIGroup: utilpointer.String(negv1beta1.SchemeGroupVersion.Group),
+				Kind:     kind,
+				Name:     name,
+			},
+		}
+	}
+
+	cases := []struct {
+		desc                string
+		disableResourceNEGs bool
+		annotations         map[string]string
+		backend             *v1.IngressBackend
+		wantErr             bool
+		wantNEGType         negv1beta1.NetworkEndpointType
+		wantProtocol        annotations.AppProtocol
+	}{
+		{
+			desc:         "serverless NEG",
+			backend:      resourceBackend("ServiceNetworkEndpointGroup", "serverless-neg"),
+			wantNEGType:  negv1beta1.ServerlessEndpointType,
+			wantProtocol: annotations.ProtocolHTTP,
+		},
+		{
+			desc:         "internet NEG on default port",
+			backend:      resourceBackend("ServiceNetworkEndpointGroup", "internet-neg"),
+			wantNEGType:  negv1beta1.InternetFQDNEndpointType,
+			wantProtocol: annotations.ProtocolHTTPS,
+		},
+		{
+			desc:        "internet NEG with internal ingress",
+			annotations: map[string]string{annotations.IngressClassKey: annotations.GceL7ILBIngressClass},
+			backend:     resourceBackend("ServiceNetworkEndpointGroup", "internet-neg"),
+			wantErr:     true,
+		},
+		{
+			desc:    "NEG without spec",
+			backend: resourceBackend("ServiceNetworkEndpointGroup", "service-neg"),
+			wantErr: true,
+		},
+		{
+			desc:    "missing NEG",
+			backend: resourceBackend("ServiceNetworkEndpointGroup", "missing-neg"),
+			wantErr: true,
+		},
+		{
+			desc:    "unsupported kind",
+			backend: resourceBackend("Bucket", "serverless-neg"),
+			wantErr: true,
+		},
+		{
+			desc:                "resource NEGs disabled",
+			disableResourceNEGs: true,
+			backend:             resourceBackend("ServiceNetworkEndpointGroup", "serverless-neg"),
+			wantErr:             true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			flags.F.EnableResourceNEGs = !tc.disableResourceNEGs
+			ing := test.NewIngress(types.NamespacedName{Name: "my-ingress", Namespace: "default"}, v1.IngressSpec{DefaultBackend: tc.backend})
+			ing.Annotations = tc.annotations
+
+			urlMap, errs, _ := translator.TranslateIngress(ing, defaultBackend.ID, defaultNamer)
+			if gotErr := len(errs) > 0; gotErr != tc.wantErr {
+				t.Fatalf("TranslateIngress() = _, %v, wantErr %v", errs, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			sp := urlMap.DefaultBackend
+			if sp == nil {
+				t.Fatalf("TranslateIngress() returned no default backend")
+			}
+			if !sp.NEGEnabled || sp.ResourceNEGType != tc.wantNEGType {
+				t.Errorf("Got NEGEnabled %v, ResourceNEGType %q, want true, %q", sp.NEGEnabled, sp.ResourceNEGType, tc.wantNEGType)
+			}
+			if sp.Protocol != tc.wantProtocol {
+				t.Errorf("Got protocol %q, want %q", sp.Protocol, tc.wantProtocol)
+			}
+			if wantNEGName := sp.BackendNamer.ResourceNEG("default", tc.backend.Resource.Name); sp.NEGName() != wantNEGName {
+				t.Errorf("Got NEG name %q, want %q", sp.NEGName(), wantNEGName)
+			}
+		})
+	}
+}
+
 func TestGetServicePort(t *testing.T) {
 	cases := []struct {
 		desc            string
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	gcpfirewallv1 "github.com/GoogleCloudPlatform/gke-networking-api/apis/gcpfirewall/v1"
@@ -55,6 +56,7 @@ var (
 type FirewallController struct {
 	ctx                           *context.ControllerContext
 	firewallPool                  SingleFirewallPool
+	ingressFirewallPool           *IngressFirewallRules
 	queue                         utils.TaskQueue
 	translator                    *translator.Translator
 	zoneGetter                    *zonegetter.ZoneGetter
@@ -106,15 +108,25 @@ func NewFirewallController(
 		firewallCRPool := NewFirewallCRPool(ctx.FirewallClient, ctx.Cloud, ctx.ClusterNamer, gce.L7LoadBalancerSrcRanges(), portRanges, disableFWEnforcement, logger)
 		compositeFirewallPool.pools = append(compositeFirewallPool.pools, firewallCRPool)
 	}
+	var ingressFirewallPool *IngressFirewallRules
 	if !disableFWEnforcement {
-		firewallPool := NewFirewallPool(ctx.Cloud, ctx.ClusterNamer, gce.L7LoadBalancerSrcRanges(), portRanges, logger)
-		compositeFirewallPool.pools = append(compositeFirewallPool.pools, firewallPool)
+		switch {
+		case flags.F.FirewallPolicy != "":
+			firewallPolicyPool := NewFirewallPolicyPool(NewFirewallAdapter(ctx.Cloud), ctx.ClusterNamer, gce.L7LoadBalancerSrcRanges(), portRanges, flags.F.FirewallPolicy, flags.F.FirewallPolicyRulePriority, splitSecureTags(flags.F.FirewallPolicyTargetSecureTags), logger)
+			compositeFirewallPool.pools = append(compositeFirewallPool.pools, firewallPolicyPool)
+		case flags.F.EnablePerIngressFirewall:
+			ingressFirewallPool = NewIngressFirewallPool(ctx.Cloud, NewFirewallAdapter(ctx.Cloud), ctx.ClusterNamer, gce.L7LoadBalancerSrcRanges(), portRanges, logger)
+		default:
+			firewallPool := NewFirewallPool(ctx.Cloud, ctx.ClusterNamer, gce.L7LoadBalancerSrcRanges(), portRanges, logger)
+			compositeFirewallPool.pools = append(compositeFirewallPool.pools, firewallPool)
+		}
 	}
 
 	fwc := &FirewallController{
 		ctx:                           ctx,
 		zoneGetter:                    ctx.ZoneGetter,
 		firewallPool:                  compositeFirewallPool,
+		ingressFirewallPool:           ingressFirewallPool,
 		translator:                    ctx.Translator,
 		hasSynced:                     ctx.HasSynced,
 		enableIngressRegionalExternal: enableRegionalXLB,
@@ -235,6 +247,12 @@ func (fwc *FirewallController) sync(key string) error {
 		return utils.IsGCEIngress(ing)
 	}).AsList()
 
+	if fwc.ingressFirewallPool != nil {
+		if err := fwc.syncIngressFirewalls(gceIngresses); err != nil {
+			return err
+		}
+	}
+
 	// If there are no more ingresses, then delete the firewall rule.
 	if len(gceIngresses) == 0 {
 		if err := fwc.firewallPool.GC(); err != nil {
@@ -243,12 +261,71 @@ func (fwc *FirewallController) sync(key string) error {
 		return nil
 	}
 
-	// gceSvcPorts contains the ServicePorts used by only single-cluster ingress.
-	gceSvcPorts := fwc.ToSvcPorts(gceIngresses)
 	nodes, err := fwc.zoneGetter.ListNodes(zonegetter.CandidateNodesFilter, fwc.logger)
 	if err != nil {
 		return err
 	}
+	additionalPorts, additionalRanges, needNodePort, err := fwc.firewallPortsAndRanges(gceIngresses)
+	if err != nil {
+		return err
+	}
+
+	// Ensure firewall rule for the cluster and pass any NEG endpoint ports.
+	if err := fwc.firewallPool.Sync(utils.GetNodeNames(nodes), additionalPorts, additionalRanges, needNodePort); err != nil {
+		if fwErr, ok := err.(*FirewallXPNError); ok {
+			// XPN: Raise an event on each ingress
+			for _, ing := range gceIngresses {
+				fwc.emitXPNEvent(ing, fwErr)
+			}
+		} else {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncIngressFirewalls ensures the firewall rule of each of the given
+// ingresses, and deletes the firewall rules of ingresses which no longer exist.
+func (fwc *FirewallController) syncIngressFirewalls(gceIngresses []*v1.Ingress) error {
+	var errList []error
+	if len(gceIngresses) > 0 {
+		nodes, err := fwc.zoneGetter.ListNodes(zonegetter.CandidateNodesFilter, fwc.logger)
+		if err != nil {
+			return err
+		}
+		nodeNames := utils.GetNodeNames(nodes)
+
+		for _, ing := range gceIngresses {
+			additionalPorts, additionalRanges, needNodePort, err := fwc.firewallPortsAndRanges([]*v1.Ingress{ing})
+			if err != nil {
+				errList = append(errList, err)
+				continue
+			}
+			if err := fwc.ingressFirewallPool.Sync(ing, nodeNames, additionalPorts, additionalRanges, needNodePort); err != nil {
+				if fwErr, ok := err.(*FirewallXPNError); ok {
+					fwc.emitXPNEvent(ing, fwErr)
+				} else if errors.Is(err, annotations.ErrFirewallOptionsInvalid) {
+					// Retrying won't help until the annotation is fixed, which enqueues the ingress.
+					fwc.ctx.Recorder(ing.Namespace).Eventf(ing, apiv1.EventTypeWarning, "FirewallOptionsInvalid", err.Error())
+				} else {
+					errList = append(errList, err)
+				}
+			}
+		}
+	}
+
+	if err := fwc.ingressFirewallPool.GC(gceIngresses); err != nil {
+		fwc.logger.Error(err, "Could not garbage collect ingress firewall rules, got error")
+	}
+	return utilerrors.NewAggregate(errList)
+}
+
+// firewallPortsAndRanges returns the ports and source ranges to allow in
+// addition to the L7 source ranges for the given ingresses, and whether any
+// of their backends is a node port.
+func (fwc *FirewallController) firewallPortsAndRanges(gceIngresses []*v1.Ingress) ([]string, []string, bool, error) {
+	// gceSvcPorts contains the ServicePorts used by only single-cluster ingress.
+	gceSvcPorts := fwc.ToSvcPorts(gceIngresses)
 	negPorts := fwc.translator.GatherEndpointPorts(gceSvcPorts)
 
 	// check if any nodeport based service backend exists
@@ -265,7 +342,7 @@ func (fwc *FirewallController) sync(key string) error {
 	ilbRange, err := fwc.ilbFirewallSrcRange(gceIngresses)
 	if err != nil {
 		if err != features.ErrSubnetNotFound && err != ErrNoILBIngress {
-			return err
+			return nil, nil, false, err
 		}
 	} else {
 		additionalRanges = append(additionalRanges, ilbRange)
@@ -275,7 +352,7 @@ func (fwc *FirewallController) sync(key string) error {
 		fwc.logger.Info("fwc.rxlbFirewallsSrcRange", "rxlbRange", rxlbRange, "err", err)
 		if err != nil {
 			if err != features.ErrSubnetNotFound && err != ErrNoRXLBIngress {
-				return err
+				return nil, nil, false, err
 			}
 		} else {
 			additionalRanges = append(additionalRanges, rxlbRange)
@@ -287,21 +364,16 @@ func (fwc *FirewallController) sync(key string) error {
 	additionalPorts = append(additionalPorts, hcPorts...)
 	additionalPorts = append(additionalPorts, negPorts...)
 
-	// Ensure firewall rule for the cluster and pass any NEG endpoint ports.
-	if err := fwc.firewallPool.Sync(utils.GetNodeNames(nodes), additionalPorts, additionalRanges, needNodePort); err != nil {
-		if fwErr, ok := err.(*FirewallXPNError); ok {
-			// XPN: Raise an event on each ingress
-			for _, ing := range gceIngresses {
-				if annotations.FromIngress(ing).SuppressFirewallXPNError() {
-					continue
-				}
-				fwc.ctx.Recorder(ing.Namespace).Eventf(ing, apiv1.EventTypeNormal, "XPN", fwErr.Message)
-			}
-		} else {
-			return err
-		}
+	return additionalPorts, additionalRanges, needNodePort, nil
+}
+
+// emitXPNEvent raises an event on the ingress with the firewall change
+// required by the security admin, unless the ingress suppresses it.
+func (fwc *FirewallController) emitXPNEvent(ing *v1.Ingress, fwErr *FirewallXPNError) {
+	if annotations.FromIngress(ing).SuppressFirewallXPNError() {
+		return
 	}
-	return nil
+	fwc.ctx.Recorder(ing.Namespace).Eventf(ing, apiv1.EventTypeNormal, "XPN", fwErr.Message)
 }
 
 func (fwc *FirewallController) ilbFirewallSrcRange(gceIngresses []*v1.Ingress) (string, error) {
@@ -359,3 +431,14 @@ func (fwc *FirewallController) getCustomHealthCheckPorts(svcPorts []utils.Servic
 
 	return result
 }
+
+// splitSecureTags splits the comma separated secure tag values, ignoring empty values.
+func splitSecureTags(tags string) []string {
+	var result []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
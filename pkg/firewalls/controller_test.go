@@ -111,6 +111,74 @@ func TestFirewallCreateDelete(t *testing.T) {
 	}
 }
 
+// TestPerIngressFirewallCreateDelete asserts that `sync` will ensure a firewall
+// rule per ingress when enabled, and delete it when the ingress is deleted.
+func TestPerIngressFirewallCreateDelete(t *testing.T) {
+	// No t.Parallel().
+	oldFlag := flags.F.EnablePerIngressFirewall
+	defer func() { flags.F.EnablePerIngressFirewall = oldFlag }()
+	flags.F.EnablePerIngressFirewall = true
+
+	fwc := newFirewallController()
+
+	// Create the default-backend service.
+	defaultSvc := test.NewService(test.DefaultBeSvcPort.ID.Service, api_v1.ServiceSpec{
+		Type: api_v1.ServiceTypeNodePort,
+		Ports: []api_v1.ServicePort{
+			{
+				Name:     "http",
+				Port:     80,
+				NodePort: 30000,
+			},
+		},
+	})
+	fwc.ctx.KubeClient.CoreV1().Services(defaultSvc.Namespace).Create(context2.TODO(), defaultSvc, meta_v1.CreateOptions{})
+	fwc.ctx.ServiceInformer.GetIndexer().Add(defaultSvc)
+
+	var ings []*networkingv1.Ingress
+	for _, name := range []string{"ing-a", "ing-b"} {
+		ing := test.NewIngress(types.NamespacedName{Name: name, Namespace: "default"}, networkingv1.IngressSpec{})
+		fwc.ctx.KubeClient.NetworkingV1().Ingresses(ing.Namespace).Create(context2.TODO(), ing, meta_v1.CreateOptions{})
+		fwc.ctx.IngressInformer.GetIndexer().Add(ing)
+		ings = append(ings, ing)
+	}
+
+	key, _ := common.KeyFunc(queueKey)
+	if err := fwc.sync(key); err != nil {
+		t.Fatalf("fwc.sync() = %v, want nil", err)
+	}
+
+	// Verify a firewall rule was created for each ingress, and none for the cluster.
+	for _, ing := range ings {
+		name := defaultNamer.IngressFirewallRule(ing.Namespace, ing.Name)
+		fw, err := fwc.ctx.Cloud.GetFirewall(name)
+		if err != nil {
+			t.Fatalf("cloud.GetFirewall(%v) = _, %v, want _, nil", name, err)
+		}
+		if len(fw.Allowed) != 1 || !cmp.Equal(fw.Allowed[0].Ports, []string{"30000-32767"}) {
+			t.Errorf("firewall %v allows %v, want ports of the node port range", name, fw.Allowed)
+		}
+	}
+	if _, err := fwc.ctx.Cloud.GetFirewall(ruleName); !utils.IsNotFoundError(err) {
+		t.Errorf("cloud.GetFirewall(%v) = _, %v, want _, 404 error", ruleName, err)
+	}
+
+	// Delete the first ingress, only its firewall rule should be deleted.
+	fwc.ctx.KubeClient.NetworkingV1().Ingresses(ings[0].Namespace).Delete(context2.TODO(), ings[0].Name, meta_v1.DeleteOptions{})
+	fwc.ctx.IngressInformer.GetIndexer().Delete(ings[0])
+	if err := fwc.sync(key); err != nil {
+		t.Fatalf("fwc.sync() = %v, want nil", err)
+	}
+	deletedRule := defaultNamer.IngressFirewallRule(ings[0].Namespace, ings[0].Name)
+	if _, err := fwc.ctx.Cloud.GetFirewall(deletedRule); !utils.IsNotFoundError(err) {
+		t.Errorf("cloud.GetFirewall(%v) = _, %v, want _, 404 error", deletedRule, err)
+	}
+	remainingRule := defaultNamer.IngressFirewallRule(ings[1].Namespace, ings[1].Name)
+	if _, err := fwc.ctx.Cloud.GetFirewall(remainingRule); err != nil {
+		t.Errorf("cloud.GetFirewall(%v) = _, %v, want _, nil", remainingRule, err)
+	}
+}
+
 func TestGetCustomHealthCheckPorts(t *testing.T) {
 	// No t.Parallel().
 	oldTHC := flags.F.EnableTransparentHealthChecks
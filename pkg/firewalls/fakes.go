@@ -118,6 +118,14 @@ func (ff *fakeFirewallsProvider) UpdateFirewall(f *compute.Firewall) error {
 	return ff.doUpdateFirewall(f)
 }
 
+func (ff *fakeFirewallsProvider) ListFirewalls() ([]*compute.Firewall, error) {
+	var rules []*compute.Firewall
+	for _, rule := range ff.fw {
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
 func (ff *fakeFirewallsProvider) NetworkProjectID() string {
 	return ff.networkProjectID
 }
@@ -145,3 +153,86 @@ func copyFirewall(f *compute.Firewall) (*compute.Firewall, error) {
 	}
 	return &firewall, nil
 }
+
+type fakeFirewallPolicyProvider struct {
+	policies map[string]*compute.FirewallPolicy
+}
+
+// NewFakeFirewallPolicyProvider creates a fake for network firewall policies with the given empty policies.
+func NewFakeFirewallPolicyProvider(policies ...string) *fakeFirewallPolicyProvider {
+	fp := &fakeFirewallPolicyProvider{policies: make(map[string]*compute.FirewallPolicy)}
+	for _, name := range policies {
+		fp.policies[name] = &compute.FirewallPolicy{Name: name}
+	}
+	return fp
+}
+
+func (fp *fakeFirewallPolicyProvider) GetFirewallPolicy(policy string) (*compute.FirewallPolicy, error) {
+	p, exists := fp.policies[policy]
+	if !exists {
+		return nil, test.FakeGoogleAPINotFoundErr()
+	}
+	return p, nil
+}
+
+func (fp *fakeFirewallPolicyProvider) AddFirewallPolicyRule(policy string, rule *compute.FirewallPolicyRule) error {
+	p, err := fp.GetFirewallPolicy(policy)
+	if err != nil {
+		return err
+	}
+	for _, r := range p.Rules {
+		if r.Priority == rule.Priority {
+			return fmt.Errorf("firewall policy %v already has a rule with priority %d", policy, rule.Priority)
+		}
+	}
+	cr, err := copyFirewallPolicyRule(rule)
+	if err != nil {
+		return err
+	}
+	p.Rules = append(p.Rules, cr)
+	return nil
+}
+
+func (fp *fakeFirewallPolicyProvider) PatchFirewallPolicyRule(policy string, rule *compute.FirewallPolicyRule) error {
+	p, err := fp.GetFirewallPolicy(policy)
+	if err != nil {
+		return err
+	}
+	for i, r := range p.Rules {
+		if r.Priority == rule.Priority {
+			cr, err := copyFirewallPolicyRule(rule)
+			if err != nil {
+				return err
+			}
+			p.Rules[i] = cr
+			return nil
+		}
+	}
+	return test.FakeGoogleAPINotFoundErr()
+}
+
+func (fp *fakeFirewallPolicyProvider) RemoveFirewallPolicyRule(policy string, priority int64) error {
+	p, err := fp.GetFirewallPolicy(policy)
+	if err != nil {
+		return err
+	}
+	for i, r := range p.Rules {
+		if r.Priority == priority {
+			p.Rules = append(p.Rules[:i], p.Rules[i+1:]...)
+			return nil
+		}
+	}
+	return test.FakeGoogleAPINotFoundErr()
+}
+
+func copyFirewallPolicyRule(r *compute.FirewallPolicyRule) (*compute.FirewallPolicyRule, error) {
+	enc, err := r.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var rule compute.FirewallPolicyRule
+	if err := json.Unmarshal(enc, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
@@ -14,9 +14,13 @@ limitations under the License.
 package firewalls
 
 import (
+	"context"
+
 	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"k8s.io/cloud-provider-gcp/providers/gce"
 	"k8s.io/ingress-gce/pkg/composite/metrics"
@@ -67,3 +71,71 @@ func (fa *firewallAdapter) PatchFirewall(f *compute.Firewall) error {
 	mc := metrics.NewMetricContext("firewall", "patch", "<n/a>", "<n/a>", "<n/a>")
 	return mc.Observe(fa.gc.Compute().Firewalls().Patch(ctx, meta.GlobalKey(f.Name), f))
 }
+
+// ListFirewalls returns all firewall rules.
+func (fa *firewallAdapter) ListFirewalls() ([]*compute.Firewall, error) {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("firewall", "list", "<n/a>", "<n/a>", "<n/a>")
+	v, err := fa.gc.Compute().Firewalls().List(ctx, filter.None)
+	return v, mc.Observe(err)
+}
+
+// GetFirewallPolicy returns the global network firewall policy by name.
+func (fa *firewallAdapter) GetFirewallPolicy(policy string) (*compute.FirewallPolicy, error) {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("firewall_policy", "get", "<n/a>", "<n/a>", "<n/a>")
+	v, err := fa.gc.ComputeServices().GA.NetworkFirewallPolicies.Get(fa.gc.NetworkProjectID(), policy).Context(ctx).Do()
+	return v, mc.Observe(err)
+}
+
+// AddFirewallPolicyRule adds the rule to the global network firewall policy.
+func (fa *firewallAdapter) AddFirewallPolicyRule(policy string, rule *compute.FirewallPolicyRule) error {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("firewall_policy", "add_rule", "<n/a>", "<n/a>", "<n/a>")
+	op, err := fa.gc.ComputeServices().GA.NetworkFirewallPolicies.AddRule(fa.gc.NetworkProjectID(), policy, rule).Context(ctx).Do()
+	if err != nil {
+		return mc.Observe(err)
+	}
+	return mc.Observe(fa.waitForGlobalOp(ctx, op))
+}
+
+// PatchFirewallPolicyRule patches the rule of the global network firewall policy with the same priority.
+func (fa *firewallAdapter) PatchFirewallPolicyRule(policy string, rule *compute.FirewallPolicyRule) error {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("firewall_policy", "patch_rule", "<n/a>", "<n/a>", "<n/a>")
+	op, err := fa.gc.ComputeServices().GA.NetworkFirewallPolicies.PatchRule(fa.gc.NetworkProjectID(), policy, rule).Priority(rule.Priority).Context(ctx).Do()
+	if err != nil {
+		return mc.Observe(err)
+	}
+	return mc.Observe(fa.waitForGlobalOp(ctx, op))
+}
+
+// RemoveFirewallPolicyRule removes the rule with the given priority from the global network firewall policy.
+func (fa *firewallAdapter) RemoveFirewallPolicyRule(policy string, priority int64) error {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("firewall_policy", "remove_rule", "<n/a>", "<n/a>", "<n/a>")
+	op, err := fa.gc.ComputeServices().GA.NetworkFirewallPolicies.RemoveRule(fa.gc.NetworkProjectID(), policy).Priority(priority).Context(ctx).Do()
+	if err != nil {
+		return mc.Observe(err)
+	}
+	return mc.Observe(fa.waitForGlobalOp(ctx, op))
+}
+
+// waitForGlobalOp waits for the global operation of the network project to complete.
+func (fa *firewallAdapter) waitForGlobalOp(ctx context.Context, op *compute.Operation) error {
+	for op.Status != "DONE" {
+		var err error
+		if op, err = fa.gc.ComputeServices().GA.GlobalOperations.Wait(fa.gc.NetworkProjectID(), op.Name).Context(ctx).Do(); err != nil {
+			return err
+		}
+	}
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		return &googleapi.Error{Code: int(op.HttpErrorStatusCode), Message: op.Error.Errors[0].Message}
+	}
+	return nil
+}
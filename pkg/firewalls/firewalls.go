@@ -24,6 +24,7 @@ import (
 	"google.golang.org/api/compute/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/utils"
 	namer_util "k8s.io/ingress-gce/pkg/utils/namer"
 	"k8s.io/klog/v2"
@@ -34,6 +35,8 @@ const (
 	// DefaultFirewallName is the name to use for firewall rules created
 	// by an L7 controller when --firewall-rule is not used.
 	DefaultFirewallName = ""
+
+	l7FirewallDescription = "GCE L7 firewall rule"
 )
 
 // FirewallRules manages firewall rules.
@@ -68,9 +71,13 @@ func NewFirewallPool(cloud Firewall, namer *namer_util.Namer, l7SrcRanges []stri
 // Sync firewall rules with the cloud.
 func (fr *FirewallRules) Sync(nodeNames, additionalPorts, additionalRanges []string, allowNodePort bool) error {
 	fr.logger.V(4).Info("Sync", "nodeNames", nodeNames)
-	name := fr.namer.FirewallRule()
+	return fr.syncFirewall(fr.namer.FirewallRule(), l7FirewallDescription, nodeNames, additionalPorts, additionalRanges, allowNodePort, nil)
+}
 
-	expectedFirewall, err := fr.buildExpectedFW(nodeNames, additionalPorts, additionalRanges, allowNodePort)
+// syncFirewall ensures the L7 firewall rule with the given name and description.
+// options overrides the logging and priority flags of the rule, if not nil.
+func (fr *FirewallRules) syncFirewall(name, description string, nodeNames, additionalPorts, additionalRanges []string, allowNodePort bool, options *annotations.FirewallOptions) error {
+	expectedFirewall, err := fr.buildExpectedFW(name, description, nodeNames, additionalPorts, additionalRanges, allowNodePort, options)
 	if err != nil {
 		return err
 	}
@@ -87,7 +94,7 @@ func (fr *FirewallRules) Sync(nodeNames, additionalPorts, additionalRanges []str
 
 	// Early return if an update is not required.
 	if equal(expectedFirewall, existingFirewall, fr.logger) {
-		fr.logger.V(4).Info("Firewall does not need update of ports or source ranges")
+		fr.logger.V(4).Info("Firewall does not need update of ports, source ranges, priority or logging")
 		return nil
 	}
 
@@ -95,9 +102,7 @@ func (fr *FirewallRules) Sync(nodeNames, additionalPorts, additionalRanges []str
 	return fr.updateFirewall(expectedFirewall)
 }
 
-func (fr *FirewallRules) buildExpectedFW(nodeNames, additionalPorts, additionalRanges []string, allowNodePort bool) (*compute.Firewall, error) {
-	name := fr.namer.FirewallRule()
-
+func (fr *FirewallRules) buildExpectedFW(name, description string, nodeNames, additionalPorts, additionalRanges []string, allowNodePort bool, options *annotations.FirewallOptions) (*compute.Firewall, error) {
 	// Retrieve list of target tags from node names. This may be configured in
 	// gce.conf or computed by the GCE cloudprovider package.
 	targetTags, err := fr.cloud.GetNodeTags(nodeNames)
@@ -106,33 +111,40 @@ func (fr *FirewallRules) buildExpectedFW(nodeNames, additionalPorts, additionalR
 	}
 	sort.Strings(targetTags)
 
-	// De-dupe ports
-	ports := sets.NewString()
-	if allowNodePort {
-		ports.Insert(fr.nodePortRanges...)
-	}
-	ports.Insert(additionalPorts...)
-
-	// De-dupe srcRanges
-	ranges := sets.NewString(fr.srcRanges...)
-	ranges.Insert(additionalRanges...)
-
+	ports, ranges := expectedPortsAndRanges(fr.nodePortRanges, fr.srcRanges, additionalPorts, additionalRanges, allowNodePort)
 	expectedFirewall := &compute.Firewall{
 		Name:         name,
-		Description:  "GCE L7 firewall rule",
-		SourceRanges: ranges.UnsortedList(),
+		Description:  description,
+		SourceRanges: ranges,
 		Network:      fr.cloud.NetworkURL(),
 		Allowed: []*compute.FirewallAllowed{
 			{
 				IPProtocol: "tcp",
-				Ports:      ports.List(),
+				Ports:      ports,
 			},
 		},
 		TargetTags: targetTags,
 	}
+	setLogConfigAndPriority(expectedFirewall, options)
 	return expectedFirewall, nil
 }
 
+// expectedPortsAndRanges returns the de-duped ports and source ranges allowed by the L7 firewall rule.
+func expectedPortsAndRanges(nodePortRanges, srcRanges, additionalPorts, additionalRanges []string, allowNodePort bool) ([]string, []string) {
+	// De-dupe ports
+	ports := sets.NewString()
+	if allowNodePort {
+		ports.Insert(nodePortRanges...)
+	}
+	ports.Insert(additionalPorts...)
+
+	// De-dupe srcRanges
+	ranges := sets.NewString(srcRanges...)
+	ranges.Insert(additionalRanges...)
+
+	return ports.List(), ranges.UnsortedList()
+}
+
 // GC deletes the firewall rule.
 func (fr *FirewallRules) GC() error {
 	name := fr.namer.FirewallRule()
@@ -214,6 +226,11 @@ func equal(expected *compute.Firewall, existing *compute.Firewall, logger klog.L
 		return false
 	}
 
+	if !logConfigAndPriorityEqual(expected, existing) {
+		logger.V(5).Info("Priority and log config", "expectedPriority", expected.Priority, "actualPriority", existing.Priority, "expectedLogConfig", expected.LogConfig, "actualLogConfig", existing.LogConfig)
+		return false
+	}
+
 	// Ignore other firewall properties as the controller does not set them.
 	return true
 }
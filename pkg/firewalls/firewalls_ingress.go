@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firewalls
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/networking/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/utils/common"
+	namer_util "k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
+)
+
+// IngressFirewallRules manages one L7 firewall rule per Ingress, instead of
+// a single rule shared by all the Ingresses of the cluster.
+type IngressFirewallRules struct {
+	// rules syncs the individual firewall rules.
+	rules  *FirewallRules
+	lister FirewallLister
+	namer  *namer_util.Namer
+
+	logger klog.Logger
+}
+
+// NewIngressFirewallPool creates a new manager of per Ingress firewall rules.
+// cloud: the cloud object implementing Firewall.
+// lister: the cloud object listing the firewall rules, used for garbage collection.
+// namer: cluster namer.
+func NewIngressFirewallPool(cloud Firewall, lister FirewallLister, namer *namer_util.Namer, l7SrcRanges []string, nodePortRanges []string, logger klog.Logger) *IngressFirewallRules {
+	logger = logger.WithName("IngressFirewallRules")
+	return &IngressFirewallRules{
+		rules:  NewFirewallPool(cloud, namer, l7SrcRanges, nodePortRanges, logger).(*FirewallRules),
+		lister: lister,
+		namer:  namer,
+		logger: logger,
+	}
+}
+
+// Sync syncs the firewall rule of the given Ingress with the cloud.
+// The logging and priority of the rule can be overridden by the firewall
+// options annotation of the Ingress, the rule is left untouched if it is invalid.
+func (ifr *IngressFirewallRules) Sync(ing *v1.Ingress, nodeNames, additionalPorts, additionalRanges []string, allowNodePort bool) error {
+	name := ifr.ruleName(ing)
+	ifr.logger.V(4).Info("Sync", "ingress", klog.KObj(ing), "firewallRuleName", name)
+	options, err := annotations.FromIngress(ing).FirewallOptions()
+	if err != nil {
+		return err
+	}
+	description := fmt.Sprintf("%s for Ingress %s", l7FirewallDescription, common.NamespacedName(ing))
+	return ifr.rules.syncFirewall(name, description, nodeNames, additionalPorts, additionalRanges, allowNodePort, options)
+}
+
+// GC deletes the per Ingress firewall rules of the cluster which do not belong
+// to any of the given Ingresses, and the firewall rule shared by all the
+// Ingresses which is replaced by them.
+func (ifr *IngressFirewallRules) GC(ings []*v1.Ingress) error {
+	expected := sets.NewString()
+	for _, ing := range ings {
+		expected.Insert(ifr.ruleName(ing))
+	}
+
+	existing, err := ifr.lister.ListFirewalls()
+	if err != nil {
+		ifr.logger.Error(err, "Failed to list firewalls")
+		return err
+	}
+
+	var errList []error
+	for _, fw := range existing {
+		if fw.Name != ifr.namer.FirewallRule() && (!ifr.namer.IsIngressFirewallRule(fw.Name) || expected.Has(fw.Name)) {
+			continue
+		}
+		ifr.logger.V(3).Info("Deleting firewall", "firewallRuleName", fw.Name)
+		if err := ifr.rules.deleteFirewall(fw.Name); err != nil {
+			errList = append(errList, err)
+		}
+	}
+	return utilerrors.NewAggregate(errList)
+}
+
+func (ifr *IngressFirewallRules) ruleName(ing *v1.Ingress) string {
+	return ifr.namer.IngressFirewallRule(ing.Namespace, ing.Name)
+}
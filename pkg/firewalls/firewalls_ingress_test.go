@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firewalls
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/api/compute/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/flags"
+	"k8s.io/ingress-gce/pkg/test"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+func TestIngressFirewallPoolSync(t *testing.T) {
+	fwp := NewFakeFirewallsProvider(false, false)
+	fp := NewIngressFirewallPool(fwp, fwp, defaultNamer, srcRanges, portRanges(), klog.TODO())
+	nodes := []string{"node-a", "node-b", "node-c"}
+	ingA := test.NewIngress(types.NamespacedName{Name: "ing-a", Namespace: "default"}, networkingv1.IngressSpec{})
+	ingB := test.NewIngress(types.NamespacedName{Name: "ing-b", Namespace: "default"}, networkingv1.IngressSpec{})
+	ruleA := defaultNamer.IngressFirewallRule(ingA.Namespace, ingA.Name)
+	ruleB := defaultNamer.IngressFirewallRule(ingB.Namespace, ingB.Name)
+
+	if err := fp.Sync(ingA, nodes, []string{"8080"}, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := fp.Sync(ingB, nodes, nil, []string{"10.0.0.0/24"}, true); err != nil {
+		t.Fatal(err)
+	}
+	verifyFirewallRule(fwp, ruleA, nodes, srcRanges, []string{"8080"}, t)
+	verifyFirewallRule(fwp, ruleB, nodes, append([]string{"10.0.0.0/24"}, srcRanges...), portRanges(), t)
+
+	// Ports of an ingress are updated without affecting the other rules.
+	if err := fp.Sync(ingA, nodes, []string{"8080", "9090"}, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	verifyFirewallRule(fwp, ruleA, nodes, srcRanges, []string{"8080", "9090"}, t)
+	verifyFirewallRule(fwp, ruleB, nodes, append([]string{"10.0.0.0/24"}, srcRanges...), portRanges(), t)
+}
+
+func TestIngressFirewallPoolGC(t *testing.T) {
+	fwp := NewFakeFirewallsProvider(false, false)
+	fp := NewIngressFirewallPool(fwp, fwp, defaultNamer, srcRanges, portRanges(), klog.TODO())
+	nodes := []string{"node-a", "node-b", "node-c"}
+	ingA := test.NewIngress(types.NamespacedName{Name: "ing-a", Namespace: "default"}, networkingv1.IngressSpec{})
+	ingB := test.NewIngress(types.NamespacedName{Name: "ing-b", Namespace: "default"}, networkingv1.IngressSpec{})
+	ruleA := defaultNamer.IngressFirewallRule(ingA.Namespace, ingA.Name)
+	ruleB := defaultNamer.IngressFirewallRule(ingB.Namespace, ingB.Name)
+
+	// The firewall rule shared by all ingresses, and rules not owned by the cluster.
+	const unmanagedRule = "user-firewall-rule"
+	otherClusterRule := "k8s1-othercls-fw-default-ing-a-12345678"
+	for _, name := range []string{ruleName, unmanagedRule, otherClusterRule} {
+		if err := fwp.CreateFirewall(&compute.Firewall{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, ing := range []*networkingv1.Ingress{ingA, ingB} {
+		if err := fp.Sync(ing, nodes, nil, nil, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Ingress B was deleted.
+	if err := fp.GC([]*networkingv1.Ingress{ingA}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{ruleA, unmanagedRule, otherClusterRule} {
+		if _, err := fwp.GetFirewall(name); err != nil {
+			t.Errorf("GetFirewall(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{ruleB, ruleName} {
+		if _, err := fwp.GetFirewall(name); !utils.IsNotFoundError(err) {
+			t.Errorf("GetFirewall(%q) = %v, want not found error", name, err)
+		}
+	}
+
+	// All ingresses were deleted.
+	if err := fp.GC(nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fwp.GetFirewall(ruleA); !utils.IsNotFoundError(err) {
+		t.Errorf("GetFirewall(%q) = %v, want not found error", ruleA, err)
+	}
+}
+
+// TestIngressFirewallPoolXPNReadOnly tests that the XPN error is returned
+// for the firewall rule of the ingress when the controller does not have
+// permission to create firewall rules.
+func TestIngressFirewallPoolXPNReadOnly(t *testing.T) {
+	fwp := NewFakeFirewallsProvider(true, true)
+	fp := NewIngressFirewallPool(fwp, fwp, defaultNamer, srcRanges, portRanges(), klog.TODO())
+	ing := test.NewIngress(types.NamespacedName{Name: "ing", Namespace: "default"}, networkingv1.IngressSpec{})
+
+	err := fp.Sync(ing, []string{"node-a"}, nil, nil, true)
+	validateXPNError(err, "create", t)
+}
+
+func TestIngressFirewallPoolOptions(t *testing.T) {
+	oldLogging, oldMetadata, oldPriority := flags.F.EnableFirewallLogging, flags.F.FirewallLogMetadata, flags.F.FirewallRulePriority
+	defer func() {
+		flags.F.EnableFirewallLogging, flags.F.FirewallLogMetadata, flags.F.FirewallRulePriority = oldLogging, oldMetadata, oldPriority
+	}()
+	flags.F.EnableFirewallLogging, flags.F.FirewallLogMetadata, flags.F.FirewallRulePriority = true, annotations.FirewallLogMetadataExcludeAll, 1000
+
+	fwp := NewFakeFirewallsProvider(false, false)
+	fp := NewIngressFirewallPool(fwp, fwp, defaultNamer, srcRanges, portRanges(), klog.TODO())
+	nodes := []string{"node-a"}
+	ing := test.NewIngress(types.NamespacedName{Name: "ing", Namespace: "default"}, networkingv1.IngressSpec{})
+	rule := defaultNamer.IngressFirewallRule(ing.Namespace, ing.Name)
+
+	for _, tc := range []struct {
+		desc          string
+		annotation    string
+		wantErr       bool
+		wantPriority  int64
+		wantLogConfig *compute.FirewallLogConfig
+	}{
+		{
+			desc:          "controller flags",
+			wantPriority:  1000,
+			wantLogConfig: &compute.FirewallLogConfig{Enable: true, Metadata: annotations.FirewallLogMetadataExcludeAll},
+		},
+		{
+			desc:          "metadata and priority overrides",
+			annotation:    `{"logMetadata": "INCLUDE_ALL_METADATA", "priority": 900}`,
+			wantPriority:  900,
+			wantLogConfig: &compute.FirewallLogConfig{Enable: true, Metadata: annotations.FirewallLogMetadataIncludeAll},
+		},
+		{
+			desc:          "logging disabled",
+			annotation:    `{"logging": false}`,
+			wantPriority:  1000,
+			wantLogConfig: &compute.FirewallLogConfig{},
+		},
+		{
+			desc:          "invalid annotation leaves the rule untouched",
+			annotation:    `{"priority": 70000}`,
+			wantErr:       true,
+			wantPriority:  1000,
+			wantLogConfig: &compute.FirewallLogConfig{},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ing.Annotations = map[string]string{}
+			if tc.annotation != "" {
+				ing.Annotations[annotations.FirewallOptionsKey] = tc.annotation
+			}
+			err := fp.Sync(ing, nodes, []string{"8080"}, nil, false)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Sync() = %v, want error %v", err, tc.wantErr)
+			}
+			if err != nil && !errors.Is(err, annotations.ErrFirewallOptionsInvalid) {
+				t.Errorf("Sync() = %v, want %v", err, annotations.ErrFirewallOptionsInvalid)
+			}
+
+			fw, err := fwp.GetFirewall(rule)
+			if err != nil {
+				t.Fatalf("GetFirewall(%q) = %v", rule, err)
+			}
+			if fw.Priority != tc.wantPriority {
+				t.Errorf("Priority = %d, want %d", fw.Priority, tc.wantPriority)
+			}
+			if !logConfigEqual(fw.LogConfig, tc.wantLogConfig) {
+				t.Errorf("LogConfig = %+v, want %+v", fw.LogConfig, tc.wantLogConfig)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package firewalls
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
@@ -24,6 +25,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/flags"
 	"k8s.io/ingress-gce/pkg/network"
 	"k8s.io/ingress-gce/pkg/utils"
@@ -39,22 +41,18 @@ type FirewallParams struct {
 	PortRanges        []string
 	NodeNames         []string
 	Protocol          string
-	L4Type            utils.L4LBType
-	Network           network.NetworkInfo
+	// ProtocolPortRanges, if set, replaces Protocol and PortRanges with one
+	// allowed entry per protocol. It is used for Services that mix protocols.
+	ProtocolPortRanges map[string][]string
+	L4Type             utils.L4LBType
+	Network            network.NetworkInfo
+	// Options overrides the logging and priority flags of the rule, if not nil.
+	Options *annotations.FirewallOptions
 }
 
 func EnsureL4FirewallRule(cloud *gce.Cloud, nsName string, params *FirewallParams, sharedRule bool, fwLogger klog.Logger) (utils.ResourceSyncStatus, error) {
 	fwLogger = fwLogger.WithValues("l4Type", params.L4Type.ToString())
 	fa := NewFirewallAdapter(cloud)
-	existingFw, err := fa.GetFirewall(params.Name)
-	if err != nil && !utils.IsNotFoundError(err) {
-		return utils.ResourceResync, err
-	}
-
-	nodeTags, err := cloud.GetNodeTags(params.NodeNames)
-	if err != nil {
-		return utils.ResourceResync, err
-	}
 	fwDesc, err := utils.MakeL4LBFirewallDescription(nsName, params.IP, meta.VersionGA, sharedRule)
 	if err != nil {
 		fwLogger.Info("EnsureL4FirewallRule: failed to generate description for L4 rule", "err", err)
@@ -64,17 +62,27 @@ func EnsureL4FirewallRule(cloud *gce.Cloud, nsName string, params *FirewallParam
 		Description:  fwDesc,
 		Network:      params.Network.NetworkURL,
 		SourceRanges: params.SourceRanges,
-		TargetTags:   nodeTags,
-		Allowed: []*compute.FirewallAllowed{
-			{
-				IPProtocol: strings.ToLower(params.Protocol),
-				Ports:      params.PortRanges,
-			},
-		},
+		Allowed:      params.allowed(),
 	}
 	if flags.F.EnablePinhole {
 		expectedFw.DestinationRanges = params.DestinationRanges
 	}
+	setLogConfigAndPriority(expectedFw, params.Options)
+	if flags.F.FirewallPolicy != "" {
+		// The rule of the firewall policy targets the nodes by secure tags,
+		// its priority is allocated in the policy.
+		return newL4FirewallPolicyRules(fa, fwLogger).ensureL4Rule(expectedFw, sharedRule)
+	}
+
+	existingFw, err := fa.GetFirewall(params.Name)
+	if err != nil && !utils.IsNotFoundError(err) {
+		return utils.ResourceResync, err
+	}
+	nodeTags, err := cloud.GetNodeTags(params.NodeNames)
+	if err != nil {
+		return utils.ResourceResync, err
+	}
+	expectedFw.TargetTags = nodeTags
 	if existingFw == nil {
 		fwLogger.V(2).Info("EnsureL4FirewallRule: creating L4 firewall rule")
 		err = fa.CreateFirewall(expectedFw)
@@ -102,8 +110,37 @@ func EnsureL4FirewallRule(cloud *gce.Cloud, nsName string, params *FirewallParam
 	return utils.ResourceUpdate, err
 }
 
+// allowed returns the allowed protocols and ports of the firewall rule.
+// Entries are sorted by protocol so that comparisons with existing rules are stable.
+func (params *FirewallParams) allowed() []*compute.FirewallAllowed {
+	if len(params.ProtocolPortRanges) == 0 {
+		return []*compute.FirewallAllowed{
+			{
+				IPProtocol: strings.ToLower(params.Protocol),
+				Ports:      params.PortRanges,
+			},
+		}
+	}
+	protocols := make([]string, 0, len(params.ProtocolPortRanges))
+	for protocol := range params.ProtocolPortRanges {
+		protocols = append(protocols, protocol)
+	}
+	sort.Strings(protocols)
+	var allowed []*compute.FirewallAllowed
+	for _, protocol := range protocols {
+		allowed = append(allowed, &compute.FirewallAllowed{
+			IPProtocol: strings.ToLower(protocol),
+			Ports:      params.ProtocolPortRanges[protocol],
+		})
+	}
+	return allowed
+}
+
 func EnsureL4FirewallRuleDeleted(cloud *gce.Cloud, fwName string, fwLogger klog.Logger) error {
 	fa := NewFirewallAdapter(cloud)
+	if flags.F.FirewallPolicy != "" {
+		return newL4FirewallPolicyRules(fa, fwLogger).deleteL4Rule(fwName)
+	}
 	if err := utils.IgnoreHTTPNotFound(fa.DeleteFirewall(fwName)); err != nil {
 		if utils.IsForbiddenError(err) && cloud.OnXPN() {
 			gcloudCmd := gce.FirewallToGCloudDeleteCmd(fwName, cloud.NetworkProjectID())
@@ -137,6 +174,10 @@ func firewallRuleEqual(a, b *compute.Firewall, skipDescription bool) bool {
 		return false
 	}
 
+	if !logConfigAndPriorityEqual(a, b) {
+		return false
+	}
+
 	if !skipDescription && a.Description != b.Description {
 		return false
 	}
@@ -148,7 +189,17 @@ func allowRulesEqual(a *compute.FirewallAllowed, b *compute.FirewallAllowed) boo
 		utils.EqualStringSets(a.Ports, b.Ports)
 }
 
+// ensureFirewall ensures the firewall rule of svc. The logging and priority of
+// rules which are not shared with other Services can be overridden by the
+// firewall options annotation of svc.
 func ensureFirewall(svc *v1.Service, shared bool, params *FirewallParams, cloud *gce.Cloud, recorder record.EventRecorder, fwLogger klog.Logger) (utils.ResourceSyncStatus, error) {
+	if !shared {
+		options, err := annotations.FromService(svc).FirewallOptions()
+		if err != nil {
+			return utils.ResourceResync, utils.NewUserError(err)
+		}
+		params.Options = options
+	}
 	nsName := utils.ServiceKeyFunc(svc.Namespace, svc.Name)
 	updateStatus, err := EnsureL4FirewallRule(cloud, nsName, params, shared, fwLogger)
 	if err != nil {
@@ -6,11 +6,14 @@ import (
 
 	"k8s.io/klog/v2"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	compute "google.golang.org/api/compute/v1"
 	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/network"
 	"k8s.io/ingress-gce/pkg/utils"
 )
@@ -20,6 +23,7 @@ func TestEnsureL4FirewallRule(t *testing.T) {
 	if err != nil {
 		t.Errorf("Failed making the description, err=%v", err)
 	}
+	enableLogging, priority := true, int64(900)
 	tests := []struct {
 		desc         string
 		nsName       string
@@ -56,12 +60,14 @@ func TestEnsureL4FirewallRule(t *testing.T) {
 				},
 				TargetTags:  []string{"k8s-test"},
 				Description: firewallDescription,
+				Priority:    1000,
 				Allowed: []*compute.FirewallAllowed{
 					{
 						IPProtocol: "tcp",
 						Ports:      []string{"8080"},
 					},
 				},
+				LogConfig: &compute.FirewallLogConfig{},
 			},
 			expectUpdate: utils.ResourceUpdate,
 		},
@@ -92,6 +98,7 @@ func TestEnsureL4FirewallRule(t *testing.T) {
 				},
 				TargetTags:  []string{"k8s-test"},
 				Description: firewallDescription,
+				Priority:    1000,
 				Allowed: []*compute.FirewallAllowed{
 					{
 						IPProtocol: "tcp",
@@ -107,6 +114,7 @@ func TestEnsureL4FirewallRule(t *testing.T) {
 				},
 				TargetTags:  []string{"k8s-test"},
 				Description: firewallDescription,
+				Priority:    1000,
 				Allowed: []*compute.FirewallAllowed{
 					{
 						IPProtocol: "tcp",
@@ -143,19 +151,189 @@ func TestEnsureL4FirewallRule(t *testing.T) {
 				},
 				TargetTags:  []string{"k8s-test"},
 				Description: firewallDescription,
+				Priority:    1000,
 				Allowed: []*compute.FirewallAllowed{
 					{
 						IPProtocol: "tcp",
 						Ports:      []string{"8080"},
 					},
 				},
+				LogConfig: &compute.FirewallLogConfig{},
 			},
 			expectUpdate: utils.ResourceUpdate,
 		},
+		{
+			desc:   "mixed protocols",
+			nsName: utils.ServiceKeyFunc("test-ns", "test-name"),
+			params: &FirewallParams{
+				Name: "test-firewall",
+				IP:   "10.0.0.1",
+				SourceRanges: []string{
+					"10.1.2.8/29",
+				},
+				PortRanges: []string{"53"},
+				NodeNames:  []string{"k8s-test-node"},
+				Protocol:   "UDP",
+				ProtocolPortRanges: map[string][]string{
+					"UDP": {"53-54"},
+					"TCP": {"53"},
+				},
+				L4Type:  utils.ILB,
+				Network: network.NetworkInfo{IsDefault: true},
+			},
+			shared: false,
+			want: &compute.Firewall{
+				Name:    "test-firewall",
+				Network: "",
+				SourceRanges: []string{
+					"10.1.2.8/29",
+				},
+				TargetTags:  []string{"k8s-test"},
+				Description: firewallDescription,
+				Priority:    1000,
+				Allowed: []*compute.FirewallAllowed{
+					{
+						IPProtocol: "tcp",
+						Ports:      []string{"53"},
+					},
+					{
+						IPProtocol: "udp",
+						Ports:      []string{"53-54"},
+					},
+				},
+				LogConfig: &compute.FirewallLogConfig{},
+			},
+			expectUpdate: utils.ResourceUpdate,
+		},
+		{
+			desc:   "logging and priority options",
+			nsName: utils.ServiceKeyFunc("test-ns", "test-name"),
+			params: &FirewallParams{
+				Name:         "test-firewall",
+				IP:           "10.0.0.1",
+				SourceRanges: []string{"10.1.2.8/29"},
+				PortRanges:   []string{"8080"},
+				NodeNames:    []string{"k8s-test-node"},
+				Protocol:     "TCP",
+				L4Type:       utils.ILB,
+				Network:      network.NetworkInfo{IsDefault: true},
+				Options: &annotations.FirewallOptions{
+					Logging:     &enableLogging,
+					LogMetadata: annotations.FirewallLogMetadataExcludeAll,
+					Priority:    &priority,
+				},
+			},
+			want: &compute.Firewall{
+				Name:         "test-firewall",
+				SourceRanges: []string{"10.1.2.8/29"},
+				TargetTags:   []string{"k8s-test"},
+				Description:  firewallDescription,
+				Allowed: []*compute.FirewallAllowed{
+					{
+						IPProtocol: "tcp",
+						Ports:      []string{"8080"},
+					},
+				},
+				Priority:  900,
+				LogConfig: &compute.FirewallLogConfig{Enable: true, Metadata: annotations.FirewallLogMetadataExcludeAll},
+			},
+			expectUpdate: utils.ResourceUpdate,
+		},
+		{
+			desc:   "logging disabled",
+			nsName: utils.ServiceKeyFunc("test-ns", "test-name"),
+			params: &FirewallParams{
+				Name:         "test-firewall",
+				IP:           "10.0.0.1",
+				SourceRanges: []string{"10.1.2.8/29"},
+				PortRanges:   []string{"8080"},
+				NodeNames:    []string{"k8s-test-node"},
+				Protocol:     "TCP",
+				L4Type:       utils.ILB,
+				Network:      network.NetworkInfo{IsDefault: true},
+			},
+			existingRule: &compute.Firewall{
+				Name:         "test-firewall",
+				SourceRanges: []string{"10.1.2.8/29"},
+				TargetTags:   []string{"k8s-test"},
+				Description:  firewallDescription,
+				Priority:     1000,
+				Allowed: []*compute.FirewallAllowed{
+					{
+						IPProtocol: "tcp",
+						Ports:      []string{"8080"},
+					},
+				},
+				LogConfig: &compute.FirewallLogConfig{Enable: true},
+			},
+			want: &compute.Firewall{
+				Name:         "test-firewall",
+				SourceRanges: []string{"10.1.2.8/29"},
+				TargetTags:   []string{"k8s-test"},
+				Description:  firewallDescription,
+				Priority:     1000,
+				Allowed: []*compute.FirewallAllowed{
+					{
+						IPProtocol: "tcp",
+						Ports:      []string{"8080"},
+					},
+				},
+				LogConfig: &compute.FirewallLogConfig{},
+			},
+			expectUpdate: utils.ResourceUpdate,
+		},
+		{
+			desc:   "logging with default metadata no update",
+			nsName: utils.ServiceKeyFunc("test-ns", "test-name"),
+			params: &FirewallParams{
+				Name:         "test-firewall",
+				IP:           "10.0.0.1",
+				SourceRanges: []string{"10.1.2.8/29"},
+				PortRanges:   []string{"8080"},
+				NodeNames:    []string{"k8s-test-node"},
+				Protocol:     "TCP",
+				L4Type:       utils.ILB,
+				Network:      network.NetworkInfo{IsDefault: true},
+				Options: &annotations.FirewallOptions{
+					Logging:     &enableLogging,
+					LogMetadata: annotations.FirewallLogMetadataIncludeAll,
+				},
+			},
+			existingRule: &compute.Firewall{
+				Name:         "test-firewall",
+				SourceRanges: []string{"10.1.2.8/29"},
+				TargetTags:   []string{"k8s-test"},
+				Description:  firewallDescription,
+				Priority:     1000,
+				Allowed: []*compute.FirewallAllowed{
+					{
+						IPProtocol: "tcp",
+						Ports:      []string{"8080"},
+					},
+				},
+				LogConfig: &compute.FirewallLogConfig{Enable: true},
+			},
+			want: &compute.Firewall{
+				Name:         "test-firewall",
+				SourceRanges: []string{"10.1.2.8/29"},
+				TargetTags:   []string{"k8s-test"},
+				Description:  firewallDescription,
+				Priority:     1000,
+				Allowed: []*compute.FirewallAllowed{
+					{
+						IPProtocol: "tcp",
+						Ports:      []string{"8080"},
+					},
+				},
+				LogConfig: &compute.FirewallLogConfig{Enable: true},
+			},
+			expectUpdate: utils.ResourceResync,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
 			fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+			(fakeGCE.Compute().(*cloud.MockGCE)).MockFirewalls.PatchHook = mock.UpdateFirewallHook
 			// Add some instance to act as the node so that target tags in the firewall can be resolved.
 			createVMInstanceWithTag(t, fakeGCE, "k8s-test")
 			if tc.existingRule != nil {
@@ -169,7 +347,7 @@ func TestEnsureL4FirewallRule(t *testing.T) {
 			if err != nil {
 				t.Errorf("failed to get firewall err=%v", err)
 			}
-			if diff := cmp.Diff(tc.want, firewall, cmpopts.IgnoreFields(compute.Firewall{}, "SelfLink")); diff != "" {
+			if diff := cmp.Diff(tc.want, firewall, cmpopts.IgnoreFields(compute.Firewall{}, "SelfLink", "ForceSendFields"), cmpopts.IgnoreFields(compute.FirewallLogConfig{}, "ForceSendFields")); diff != "" {
 				t.Errorf("EnsureL4FirewallRule() diff -want +got\n%v\n", diff)
 			}
 			if updateDone != tc.expectUpdate {
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firewalls
+
+import (
+	"google.golang.org/api/compute/v1"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/flags"
+)
+
+// setLogConfigAndPriority sets the log config and the priority of the firewall
+// rule from the controller flags, overridden by options if not nil.
+func setLogConfigAndPriority(fw *compute.Firewall, options *annotations.FirewallOptions) {
+	logging, metadata, priority := flags.F.EnableFirewallLogging, flags.F.FirewallLogMetadata, flags.F.FirewallRulePriority
+	if options != nil {
+		if options.Logging != nil {
+			logging = *options.Logging
+		}
+		if options.LogMetadata != "" {
+			metadata = options.LogMetadata
+		}
+		if options.Priority != nil {
+			priority = *options.Priority
+		}
+	}
+
+	// Disabled logging and the highest priority are zero values, they must be
+	// sent explicitly to be patched.
+	fw.Priority = priority
+	fw.ForceSendFields = append(fw.ForceSendFields, "Priority")
+	fw.LogConfig = &compute.FirewallLogConfig{
+		Enable:          logging,
+		ForceSendFields: []string{"Enable"},
+	}
+	if logging {
+		fw.LogConfig.Metadata = metadata
+	}
+}
+
+// logConfigAndPriorityEqual returns true if the firewall rules have the same
+// priority and log config.
+func logConfigAndPriorityEqual(a, b *compute.Firewall) bool {
+	return a.Priority == b.Priority && logConfigEqual(a.LogConfig, b.LogConfig)
+}
+
+// logConfigEqual returns true if both log configs are disabled, or are enabled
+// with the same metadata mode. A missing config means that logging is disabled.
+func logConfigEqual(a, b *compute.FirewallLogConfig) bool {
+	aEnabled, bEnabled := a != nil && a.Enable, b != nil && b.Enable
+	if aEnabled != bEnabled {
+		return false
+	}
+	return !aEnabled || logMetadata(a) == logMetadata(b)
+}
+
+// logMetadata returns the metadata mode of the log config, GCE includes all
+// metadata if it is not specified.
+func logMetadata(config *compute.FirewallLogConfig) string {
+	if config.Metadata == "" {
+		return annotations.FirewallLogMetadataIncludeAll
+	}
+	return config.Metadata
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firewalls
+
+import (
+	"testing"
+
+	"google.golang.org/api/compute/v1"
+	"k8s.io/ingress-gce/pkg/annotations"
+)
+
+func TestSetLogConfigAndPriority(t *testing.T) {
+	priority := int64(0)
+	for _, tc := range []struct {
+		desc         string
+		options      *annotations.FirewallOptions
+		wantPriority int64
+	}{
+		{
+			desc:         "default priority",
+			wantPriority: 1000,
+		},
+		{
+			desc:         "options without priority",
+			options:      &annotations.FirewallOptions{LogMetadata: annotations.FirewallLogMetadataExcludeAll},
+			wantPriority: 1000,
+		},
+		{
+			desc:         "highest priority",
+			options:      &annotations.FirewallOptions{Priority: &priority},
+			wantPriority: 0,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			fw := &compute.Firewall{}
+			setLogConfigAndPriority(fw, tc.options)
+			if fw.Priority != tc.wantPriority {
+				t.Errorf("setLogConfigAndPriority() set priority %d, want %d", fw.Priority, tc.wantPriority)
+			}
+		})
+	}
+}
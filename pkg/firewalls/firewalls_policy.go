@@ -0,0 +1,364 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firewalls
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	compute "google.golang.org/api/compute/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/ingress-gce/pkg/flags"
+	"k8s.io/ingress-gce/pkg/utils"
+	namer_util "k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
+	netset "k8s.io/utils/net"
+)
+
+const (
+	firewallPolicyActionAllow      = "allow"
+	firewallPolicyDirectionIngress = "INGRESS"
+	firewallPolicyRuleProtocolTCP  = "tcp"
+)
+
+// l4PolicyRulesLock serializes the allocation of priorities to the L4 rules
+// of the firewall policy between the workers of the L4 controllers.
+var l4PolicyRulesLock sync.Mutex
+
+// FirewallPolicyRules manages the L7 and L4 firewall rules in a global network firewall policy.
+// The L7 rule is identified by its priority in the policy. L4 rules are identified by their
+// name, and use the first priorities after the one of the L7 rule which are free when they are
+// created. Rules apply to the instances bound to the target secure tags instead of the network
+// tags of the nodes.
+type FirewallPolicyRules struct {
+	cloud     FirewallPolicy
+	namer     *namer_util.Namer
+	srcRanges []string
+	// TODO(rramkumar): Eliminate this variable. We should just pass in
+	// all the port ranges to open with each call to Sync()
+	nodePortRanges []string
+	// policy is the name of the network firewall policy
+	policy string
+	// priority is the priority of the L7 rule in the policy, L4 rules use
+	// the following priorities
+	priority int64
+	// targetSecureTags are the secure tag values bound to the nodes of the cluster
+	targetSecureTags []string
+
+	logger klog.Logger
+}
+
+// NewFirewallPolicyPool creates a new manager of the L7 firewall rule of a network firewall policy.
+// cloud: the cloud object implementing FirewallPolicy.
+// namer: cluster namer.
+func NewFirewallPolicyPool(cloud FirewallPolicy, namer *namer_util.Namer, l7SrcRanges []string, nodePortRanges []string, policy string, priority int64, targetSecureTags []string, logger klog.Logger) SingleFirewallPool {
+	_, err := netset.ParseIPNets(l7SrcRanges...)
+	if err != nil {
+		klog.Fatalf("Could not parse L7 src ranges %v for firewall rule: %v", l7SrcRanges, err)
+	}
+	if len(targetSecureTags) == 0 {
+		klog.Fatalf("Target secure tags are required for the rule of firewall policy %s", policy)
+	}
+	return &FirewallPolicyRules{
+		cloud:            cloud,
+		namer:            namer,
+		srcRanges:        l7SrcRanges,
+		nodePortRanges:   nodePortRanges,
+		policy:           policy,
+		priority:         priority,
+		targetSecureTags: targetSecureTags,
+		logger:           logger.WithName("FirewallPolicyRules").WithValues("firewallPolicy", policy, "priority", priority),
+	}
+}
+
+// newL4FirewallPolicyRules creates a manager of the L4 firewall rules of the
+// network firewall policy set by the --firewall-policy flag.
+func newL4FirewallPolicyRules(cloud FirewallPolicy, logger klog.Logger) *FirewallPolicyRules {
+	return &FirewallPolicyRules{
+		cloud:            cloud,
+		policy:           flags.F.FirewallPolicy,
+		priority:         flags.F.FirewallPolicyRulePriority,
+		targetSecureTags: splitSecureTags(flags.F.FirewallPolicyTargetSecureTags),
+		logger:           logger.WithValues("firewallPolicy", flags.F.FirewallPolicy),
+	}
+}
+
+// Sync syncs the firewall policy rule with the cloud. The nodes are targeted
+// by secure tags, so nodeNames are not used.
+func (fr *FirewallPolicyRules) Sync(nodeNames, additionalPorts, additionalRanges []string, allowNodePort bool) error {
+	fr.logger.V(4).Info("Sync")
+	expectedRule := fr.buildExpectedRule(additionalPorts, additionalRanges, allowNodePort)
+
+	existingRule, err := fr.getRule()
+	if err != nil {
+		return err
+	}
+	if existingRule == nil {
+		fr.logger.V(3).Info("Firewall policy rule not found, creating rule", "ruleName", expectedRule.RuleName)
+		return fr.cloud.AddFirewallPolicyRule(fr.policy, expectedRule)
+	}
+	if existingRule.RuleName != expectedRule.RuleName {
+		return fmt.Errorf("firewall policy %s already has a rule %q with priority %d", fr.policy, existingRule.RuleName, fr.priority)
+	}
+
+	// Early return if an update is not required.
+	if equalPolicyRules(expectedRule, existingRule, fr.logger) {
+		fr.logger.V(4).Info("Firewall policy rule does not need update of ports, source ranges or logging")
+		return nil
+	}
+
+	fr.logger.V(3).Info("Updating firewall policy rule", "ruleName", expectedRule.RuleName)
+	return fr.cloud.PatchFirewallPolicyRule(fr.policy, expectedRule)
+}
+
+func (fr *FirewallPolicyRules) buildExpectedRule(additionalPorts, additionalRanges []string, allowNodePort bool) *compute.FirewallPolicyRule {
+	ports, ranges := expectedPortsAndRanges(fr.nodePortRanges, fr.srcRanges, additionalPorts, additionalRanges, allowNodePort)
+	sort.Strings(ranges)
+
+	return &compute.FirewallPolicyRule{
+		RuleName:    fr.ruleName(),
+		Description: l7FirewallDescription,
+		Priority:    fr.priority,
+		Action:      firewallPolicyActionAllow,
+		Direction:   firewallPolicyDirectionIngress,
+		Match: &compute.FirewallPolicyRuleMatcher{
+			SrcIpRanges: ranges,
+			Layer4Configs: []*compute.FirewallPolicyRuleMatcherLayer4Config{
+				{
+					IpProtocol: firewallPolicyRuleProtocolTCP,
+					Ports:      ports,
+				},
+			},
+		},
+		TargetSecureTags: fr.ruleTargetSecureTags(),
+		EnableLogging:    flags.F.EnableFirewallLogging,
+		// Disabled logging must be sent explicitly to be patched.
+		ForceSendFields: []string{"EnableLogging"},
+	}
+}
+
+func (fr *FirewallPolicyRules) ruleTargetSecureTags() []*compute.FirewallPolicyRuleSecureTag {
+	var targetSecureTags []*compute.FirewallPolicyRuleSecureTag
+	for _, tag := range fr.targetSecureTags {
+		targetSecureTags = append(targetSecureTags, &compute.FirewallPolicyRuleSecureTag{Name: tag})
+	}
+	return targetSecureTags
+}
+
+// GC removes the firewall policy rule, if it is the rule of the cluster.
+func (fr *FirewallPolicyRules) GC() error {
+	rule, err := fr.getRule()
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			fr.logger.Info("Firewall policy didn't exist when attempting delete of the rule")
+			return nil
+		}
+		return err
+	}
+	if rule == nil || rule.RuleName != fr.ruleName() {
+		return nil
+	}
+	fr.logger.V(3).Info("Removing firewall policy rule", "ruleName", rule.RuleName)
+	err = fr.cloud.RemoveFirewallPolicyRule(fr.policy, fr.priority)
+	if utils.IsNotFoundError(err) {
+		fr.logger.Info("Firewall policy rule didn't exist when attempting delete")
+		return nil
+	}
+	return err
+}
+
+// ensureL4Rule creates or updates the rule of the policy replacing the given
+// L4 VPC firewall rule. The description of shared rules is not compared.
+func (fr *FirewallPolicyRules) ensureL4Rule(fw *compute.Firewall, sharedRule bool) (utils.ResourceSyncStatus, error) {
+	if len(fr.targetSecureTags) == 0 {
+		return utils.ResourceResync, fmt.Errorf("target secure tags are required for the rules of firewall policy %s", fr.policy)
+	}
+	logger := fr.logger.WithValues("ruleName", fw.Name)
+	expectedRule := fr.buildL4Rule(fw)
+
+	l4PolicyRulesLock.Lock()
+	defer l4PolicyRulesLock.Unlock()
+	policy, err := fr.cloud.GetFirewallPolicy(fr.policy)
+	if err != nil {
+		logger.Error(err, "Failed to get firewall policy")
+		return utils.ResourceResync, err
+	}
+	existingRule := findPolicyRule(policy, fw.Name)
+	if existingRule == nil {
+		expectedRule.Priority = fr.freeL4Priority(policy)
+		logger.V(2).Info("Creating L4 firewall policy rule", "priority", expectedRule.Priority)
+		return utils.ResourceUpdate, fr.cloud.AddFirewallPolicyRule(fr.policy, expectedRule)
+	}
+
+	expectedRule.Priority = existingRule.Priority
+	if equalPolicyRules(expectedRule, existingRule, logger) && (sharedRule || expectedRule.Description == existingRule.Description) {
+		return utils.ResourceResync, nil
+	}
+	logger.V(2).Info("Patching L4 firewall policy rule", "priority", expectedRule.Priority)
+	return utils.ResourceUpdate, fr.cloud.PatchFirewallPolicyRule(fr.policy, expectedRule)
+}
+
+// deleteL4Rule removes the rule of the policy replacing the L4 VPC firewall
+// rule with the given name.
+func (fr *FirewallPolicyRules) deleteL4Rule(name string) error {
+	l4PolicyRulesLock.Lock()
+	defer l4PolicyRulesLock.Unlock()
+	policy, err := fr.cloud.GetFirewallPolicy(fr.policy)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			fr.logger.Info("Firewall policy didn't exist when attempting delete of the L4 rule", "ruleName", name)
+			return nil
+		}
+		return err
+	}
+	rule := findPolicyRule(policy, name)
+	if rule == nil {
+		return nil
+	}
+	fr.logger.V(2).Info("Removing L4 firewall policy rule", "ruleName", name, "priority", rule.Priority)
+	return utils.IgnoreHTTPNotFound(fr.cloud.RemoveFirewallPolicyRule(fr.policy, rule.Priority))
+}
+
+// buildL4Rule returns the rule of the policy allowing the same traffic as
+// the given L4 VPC firewall rule, without its priority.
+func (fr *FirewallPolicyRules) buildL4Rule(fw *compute.Firewall) *compute.FirewallPolicyRule {
+	var layer4Configs []*compute.FirewallPolicyRuleMatcherLayer4Config
+	for _, allowed := range fw.Allowed {
+		layer4Configs = append(layer4Configs, &compute.FirewallPolicyRuleMatcherLayer4Config{
+			IpProtocol: allowed.IPProtocol,
+			Ports:      allowed.Ports,
+		})
+	}
+	srcRanges := append([]string{}, fw.SourceRanges...)
+	sort.Strings(srcRanges)
+
+	return &compute.FirewallPolicyRule{
+		RuleName:    fw.Name,
+		Description: fw.Description,
+		Action:      firewallPolicyActionAllow,
+		Direction:   firewallPolicyDirectionIngress,
+		Match: &compute.FirewallPolicyRuleMatcher{
+			SrcIpRanges:   srcRanges,
+			DestIpRanges:  fw.DestinationRanges,
+			Layer4Configs: layer4Configs,
+		},
+		TargetSecureTags: fr.ruleTargetSecureTags(),
+		EnableLogging:    fw.LogConfig != nil && fw.LogConfig.Enable,
+		// Disabled logging must be sent explicitly to be patched.
+		ForceSendFields: []string{"EnableLogging"},
+	}
+}
+
+// freeL4Priority returns the first priority after the one of the L7 rule
+// which is not used by a rule of the policy.
+func (fr *FirewallPolicyRules) freeL4Priority(policy *compute.FirewallPolicy) int64 {
+	used := sets.New[int64]()
+	for _, rule := range policy.Rules {
+		used.Insert(rule.Priority)
+	}
+	priority := fr.priority + 1
+	for used.Has(priority) {
+		priority++
+	}
+	return priority
+}
+
+// findPolicyRule returns the rule of the policy with the given name, nil if there is none.
+func findPolicyRule(policy *compute.FirewallPolicy, name string) *compute.FirewallPolicyRule {
+	for _, rule := range policy.Rules {
+		if rule.RuleName == name {
+			return rule
+		}
+	}
+	return nil
+}
+
+// getRule returns the rule of the policy with the priority of the L7 rule, nil if there is none.
+func (fr *FirewallPolicyRules) getRule() (*compute.FirewallPolicyRule, error) {
+	policy, err := fr.cloud.GetFirewallPolicy(fr.policy)
+	if err != nil {
+		fr.logger.Error(err, "Failed to get firewall policy")
+		return nil, err
+	}
+	for _, rule := range policy.Rules {
+		if rule.Priority == fr.priority {
+			return rule, nil
+		}
+	}
+	return nil, nil
+}
+
+// ruleName returns the name of the rule, which is the name of the L7 VPC firewall rule.
+func (fr *FirewallPolicyRules) ruleName() string {
+	return fr.namer.FirewallRule()
+}
+
+func equalPolicyRules(expected, existing *compute.FirewallPolicyRule, logger klog.Logger) bool {
+	if expected.Action != existing.Action || expected.Direction != existing.Direction || existing.Disabled {
+		logger.V(5).Info("Action", "expectedAction", expected.Action, "actualAction", existing.Action, "actualDirection", existing.Direction, "disabled", existing.Disabled)
+		return false
+	}
+
+	expectedTags, existingTags := sets.NewString(), sets.NewString()
+	for _, tag := range expected.TargetSecureTags {
+		expectedTags.Insert(tag.Name)
+	}
+	for _, tag := range existing.TargetSecureTags {
+		existingTags.Insert(tag.Name)
+	}
+	if !expectedTags.Equal(existingTags) {
+		logger.V(5).Info("Target secure tags", "expectedTags", expectedTags.List(), "actualTags", existingTags.List())
+		return false
+	}
+
+	if expected.EnableLogging != existing.EnableLogging {
+		logger.V(5).Info("Logging", "expectedEnableLogging", expected.EnableLogging, "actualEnableLogging", existing.EnableLogging)
+		return false
+	}
+
+	if existing.Match == nil {
+		return false
+	}
+	expectedL4 := layer4ConfigsToStrings(expected.Match.Layer4Configs)
+	existingL4 := layer4ConfigsToStrings(existing.Match.Layer4Configs)
+	if !sets.NewString(expectedL4...).Equal(sets.NewString(existingL4...)) {
+		logger.V(5).Info("Layer4 configs", "expectedLayer4Configs", expectedL4, "actualLayer4Configs", existingL4)
+		return false
+	}
+
+	if !sets.NewString(expected.Match.SrcIpRanges...).Equal(sets.NewString(existing.Match.SrcIpRanges...)) {
+		logger.V(5).Info("Source ranges", "expectedSourceRanges", expected.Match.SrcIpRanges, "actualSourceRanges", existing.Match.SrcIpRanges)
+		return false
+	}
+
+	if !sets.NewString(expected.Match.DestIpRanges...).Equal(sets.NewString(existing.Match.DestIpRanges...)) {
+		logger.V(5).Info("Destination ranges", "expectedDestinationRanges", expected.Match.DestIpRanges, "actualDestinationRanges", existing.Match.DestIpRanges)
+		return false
+	}
+
+	// Ignore other rule properties as the controller does not set them.
+	return true
+}
+
+func layer4ConfigsToStrings(configs []*compute.FirewallPolicyRuleMatcherLayer4Config) []string {
+	allowed := make([]*compute.FirewallAllowed, 0, len(configs))
+	for _, c := range configs {
+		allowed = append(allowed, &compute.FirewallAllowed{IPProtocol: c.IpProtocol, Ports: c.Ports})
+	}
+	return allowedToStrings(allowed)
+}
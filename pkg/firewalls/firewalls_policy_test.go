@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firewalls
+
+import (
+	"testing"
+
+	"google.golang.org/api/compute/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/ingress-gce/pkg/flags"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+const (
+	testFirewallPolicy = "test-policy"
+	testRulePriority   = 1000
+)
+
+var testSecureTags = []string{"tagValues/123"}
+
+func TestFirewallPolicyPoolSync(t *testing.T) {
+	fpp := NewFakeFirewallPolicyProvider(testFirewallPolicy)
+	fp := NewFirewallPolicyPool(fpp, defaultNamer, srcRanges, portRanges(), testFirewallPolicy, testRulePriority, testSecureTags, klog.TODO())
+	nodes := []string{"node-a", "node-b", "node-c"}
+
+	if err := fp.Sync(nodes, nil, nil, true); err != nil {
+		t.Fatal(err)
+	}
+	verifyFirewallPolicyRule(fpp, srcRanges, portRanges(), testSecureTags, t)
+
+	// Nodes are targeted by secure tags, changing them does not update the rule
+	if err := fp.Sync(nodes[:1], nil, nil, true); err != nil {
+		t.Fatal(err)
+	}
+	verifyFirewallPolicyRule(fpp, srcRanges, portRanges(), testSecureTags, t)
+
+	// Additional ports and ranges update the rule
+	additionalRanges := []string{"10.128.0.0/24"}
+	additionalPorts := []string{"8080", "8443"}
+	if err := fp.Sync(nodes, additionalPorts, additionalRanges, false); err != nil {
+		t.Fatal(err)
+	}
+	verifyFirewallPolicyRule(fpp, append(srcRanges, additionalRanges...), additionalPorts, testSecureTags, t)
+
+	// Changes made outside of the controller are reverted
+	rule := fpp.policies[testFirewallPolicy].Rules[0]
+	rule.Match.SrcIpRanges = []string{"0.0.0.0/0"}
+	if err := fp.Sync(nodes, additionalPorts, additionalRanges, false); err != nil {
+		t.Fatal(err)
+	}
+	verifyFirewallPolicyRule(fpp, append(srcRanges, additionalRanges...), additionalPorts, testSecureTags, t)
+}
+
+func TestFirewallPolicyPoolSyncPriorityConflict(t *testing.T) {
+	fpp := NewFakeFirewallPolicyProvider(testFirewallPolicy)
+	otherRule := &compute.FirewallPolicyRule{RuleName: "other-rule", Priority: testRulePriority, Action: "deny", Direction: "INGRESS"}
+	if err := fpp.AddFirewallPolicyRule(testFirewallPolicy, otherRule); err != nil {
+		t.Fatal(err)
+	}
+	fp := NewFirewallPolicyPool(fpp, defaultNamer, srcRanges, portRanges(), testFirewallPolicy, testRulePriority, testSecureTags, klog.TODO())
+
+	if err := fp.Sync(nil, nil, nil, true); err == nil {
+		t.Errorf("expected an error when the priority of the rule is used by another rule")
+	}
+	if err := fp.GC(); err != nil {
+		t.Fatal(err)
+	}
+	policy, _ := fpp.GetFirewallPolicy(testFirewallPolicy)
+	if len(policy.Rules) != 1 || policy.Rules[0].RuleName != otherRule.RuleName || policy.Rules[0].Action != otherRule.Action {
+		t.Errorf("expected the rule of another owner to be left untouched, got %+v", policy.Rules)
+	}
+}
+
+func TestFirewallPolicyPoolGC(t *testing.T) {
+	fpp := NewFakeFirewallPolicyProvider(testFirewallPolicy)
+	fp := NewFirewallPolicyPool(fpp, defaultNamer, srcRanges, portRanges(), testFirewallPolicy, testRulePriority, testSecureTags, klog.TODO())
+	if err := fp.Sync(nil, nil, nil, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fp.GC(); err != nil {
+		t.Fatal(err)
+	}
+	policy, _ := fpp.GetFirewallPolicy(testFirewallPolicy)
+	if len(policy.Rules) != 0 {
+		t.Errorf("expected the firewall policy rule to be removed, got %+v", policy.Rules)
+	}
+
+	// GC is a noop when the rule or the policy do not exist
+	if err := fp.GC(); err != nil {
+		t.Fatal(err)
+	}
+	missingPolicyPool := NewFirewallPolicyPool(fpp, defaultNamer, srcRanges, portRanges(), "missing-policy", testRulePriority, testSecureTags, klog.TODO())
+	if err := missingPolicyPool.GC(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSplitSecureTags(t *testing.T) {
+	for _, tc := range []struct {
+		tags string
+		want []string
+	}{
+		{tags: "", want: nil},
+		{tags: "tagValues/1", want: []string{"tagValues/1"}},
+		{tags: "tagValues/1, tagValues/2,", want: []string{"tagValues/1", "tagValues/2"}},
+	} {
+		got := splitSecureTags(tc.tags)
+		if !sets.NewString(got...).Equal(sets.NewString(tc.want...)) || len(got) != len(tc.want) {
+			t.Errorf("splitSecureTags(%q) = %v, want %v", tc.tags, got, tc.want)
+		}
+	}
+}
+
+func verifyFirewallPolicyRule(fpp *fakeFirewallPolicyProvider, expectedSourceRanges, expectedPorts, expectedTags []string, t *testing.T) {
+	t.Helper()
+	policy, err := fpp.GetFirewallPolicy(testFirewallPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policy.Rules) != 1 {
+		t.Fatalf("expected a single firewall policy rule, got %d", len(policy.Rules))
+	}
+	rule := policy.Rules[0]
+	if rule.RuleName != ruleName || rule.Priority != testRulePriority {
+		t.Errorf("got rule %q with priority %d, want rule %q with priority %d", rule.RuleName, rule.Priority, ruleName, testRulePriority)
+	}
+	if rule.Action != "allow" || rule.Direction != "INGRESS" {
+		t.Errorf("got action %q and direction %q, want allow and INGRESS", rule.Action, rule.Direction)
+	}
+	if !sets.NewString(rule.Match.SrcIpRanges...).Equal(sets.NewString(expectedSourceRanges...)) {
+		t.Errorf("got source ranges %v, want %v", rule.Match.SrcIpRanges, expectedSourceRanges)
+	}
+	if len(rule.Match.Layer4Configs) != 1 || rule.Match.Layer4Configs[0].IpProtocol != "tcp" || !sets.NewString(rule.Match.Layer4Configs[0].Ports...).Equal(sets.NewString(expectedPorts...)) {
+		t.Errorf("got layer4 configs %+v, want tcp ports %v", rule.Match.Layer4Configs, expectedPorts)
+	}
+	var tags []string
+	for _, tag := range rule.TargetSecureTags {
+		tags = append(tags, tag.Name)
+	}
+	if !sets.NewString(tags...).Equal(sets.NewString(expectedTags...)) {
+		t.Errorf("got target secure tags %v, want %v", tags, expectedTags)
+	}
+}
+
+func TestFirewallPolicyL4Rules(t *testing.T) {
+	oldPolicy, oldPriority, oldTags := flags.F.FirewallPolicy, flags.F.FirewallPolicyRulePriority, flags.F.FirewallPolicyTargetSecureTags
+	defer func() {
+		flags.F.FirewallPolicy, flags.F.FirewallPolicyRulePriority, flags.F.FirewallPolicyTargetSecureTags = oldPolicy, oldPriority, oldTags
+	}()
+	flags.F.FirewallPolicy = testFirewallPolicy
+	flags.F.FirewallPolicyRulePriority = testRulePriority
+	flags.F.FirewallPolicyTargetSecureTags = testSecureTags[0]
+
+	fpp := NewFakeFirewallPolicyProvider(testFirewallPolicy)
+	fp := newL4FirewallPolicyRules(fpp, klog.TODO())
+	// The priority after the one of the L7 rule is already used.
+	otherRule := &compute.FirewallPolicyRule{RuleName: "other-rule", Priority: testRulePriority + 1, Action: "deny", Direction: "INGRESS"}
+	if err := fpp.AddFirewallPolicyRule(testFirewallPolicy, otherRule); err != nil {
+		t.Fatal(err)
+	}
+
+	fw := &compute.Firewall{
+		Name:         "k8s2-l4-fw",
+		Description:  "l4 rule",
+		SourceRanges: []string{"10.0.0.0/8", "0.0.0.0/0"},
+		Allowed:      []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"80"}}},
+	}
+	otherFw := &compute.Firewall{
+		Name:         "k8s2-l4-other-fw",
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed:      []*compute.FirewallAllowed{{IPProtocol: "udp", Ports: []string{"53"}}},
+		LogConfig:    &compute.FirewallLogConfig{Enable: true},
+	}
+	for _, step := range []struct {
+		desc         string
+		fw           *compute.Firewall
+		wantStatus   utils.ResourceSyncStatus
+		wantPriority int64
+	}{
+		{desc: "create rule", fw: fw, wantStatus: utils.ResourceUpdate, wantPriority: testRulePriority + 2},
+		{desc: "rule up to date", fw: fw, wantStatus: utils.ResourceResync, wantPriority: testRulePriority + 2},
+		{desc: "create other rule", fw: otherFw, wantStatus: utils.ResourceUpdate, wantPriority: testRulePriority + 3},
+	} {
+		status, err := fp.ensureL4Rule(step.fw, false)
+		if err != nil {
+			t.Fatalf("%s: ensureL4Rule() returned error %v", step.desc, err)
+		}
+		if status != step.wantStatus {
+			t.Errorf("%s: ensureL4Rule() returned status %v, want %v", step.desc, status, step.wantStatus)
+		}
+		rule := findPolicyRule(fpp.policies[testFirewallPolicy], step.fw.Name)
+		if rule == nil || rule.Priority != step.wantPriority {
+			t.Fatalf("%s: got rule %+v, want priority %d", step.desc, rule, step.wantPriority)
+		}
+	}
+	if rule := findPolicyRule(fpp.policies[testFirewallPolicy], otherFw.Name); !rule.EnableLogging || rule.TargetSecureTags[0].Name != testSecureTags[0] {
+		t.Errorf("Got rule %+v, want logging enabled and target secure tag %s", rule, testSecureTags[0])
+	}
+
+	// Changes of the allowed ports update the rule, which keeps its priority.
+	fw.Allowed = []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"80", "443"}}}
+	if status, err := fp.ensureL4Rule(fw, false); err != nil || status != utils.ResourceUpdate {
+		t.Fatalf("ensureL4Rule() = %v, %v, want %v, nil", status, err, utils.ResourceUpdate)
+	}
+	rule := findPolicyRule(fpp.policies[testFirewallPolicy], fw.Name)
+	if rule.Priority != testRulePriority+2 || !sets.NewString(layer4ConfigsToStrings(rule.Match.Layer4Configs)...).Equal(sets.NewString(allowedToStrings(fw.Allowed)...)) {
+		t.Errorf("Got rule %+v after update, want priority %d and allowed %v", rule, testRulePriority+2, allowedToStrings(fw.Allowed))
+	}
+
+	// The description of shared rules is not compared.
+	fw.Description = "shared l4 rule"
+	if status, err := fp.ensureL4Rule(fw, true); err != nil || status != utils.ResourceResync {
+		t.Errorf("ensureL4Rule() of shared rule = %v, %v, want %v, nil", status, err, utils.ResourceResync)
+	}
+
+	// The priority of a deleted rule is reused.
+	for i := 0; i < 2; i++ {
+		if err := fp.deleteL4Rule(fw.Name); err != nil {
+			t.Fatalf("deleteL4Rule() returned error %v", err)
+		}
+	}
+	if rule := findPolicyRule(fpp.policies[testFirewallPolicy], fw.Name); rule != nil {
+		t.Errorf("deleteL4Rule() left rule %+v", rule)
+	}
+	newFw := &compute.Firewall{Name: "k8s2-l4-new-fw", SourceRanges: []string{"0.0.0.0/0"}, Allowed: fw.Allowed}
+	if _, err := fp.ensureL4Rule(newFw, false); err != nil {
+		t.Fatalf("ensureL4Rule() returned error %v", err)
+	}
+	if rule := findPolicyRule(fpp.policies[testFirewallPolicy], newFw.Name); rule.Priority != testRulePriority+2 {
+		t.Errorf("Got rule priority %d, want %d", rule.Priority, testRulePriority+2)
+	}
+
+	flags.F.FirewallPolicyTargetSecureTags = ""
+	if _, err := newL4FirewallPolicyRules(fpp, klog.TODO()).ensureL4Rule(fw, false); err == nil {
+		t.Errorf("ensureL4Rule() without target secure tags returned nil, want error")
+	}
+}
@@ -324,6 +324,7 @@ func TestSyncXPNReadOnly(t *testing.T) {
 			},
 		},
 		TargetTags: nodes,
+		Priority:   1000,
 	}
 	if err = fwp.doCreateFirewall(expectedFirewall); err != nil {
 		t.Errorf("unexpected err when creating firewall, err: %v", err)
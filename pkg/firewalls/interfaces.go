@@ -42,3 +42,17 @@ type Firewall interface {
 	// OnXPN returns true if the GCE NetworkProjectID != ProjectID.
 	OnXPN() bool
 }
+
+// FirewallLister lists the GCE firewall rules of the network project.
+type FirewallLister interface {
+	ListFirewalls() ([]*compute.Firewall, error)
+}
+
+// FirewallPolicy interfaces with the GCE network firewall policy api.
+// Rules of a firewall policy are identified by their priority.
+type FirewallPolicy interface {
+	GetFirewallPolicy(policy string) (*compute.FirewallPolicy, error)
+	AddFirewallPolicyRule(policy string, rule *compute.FirewallPolicyRule) error
+	PatchFirewallPolicyRule(policy string, rule *compute.FirewallPolicyRule) error
+	RemoveFirewallPolicyRule(policy string, priority int64) error
+}
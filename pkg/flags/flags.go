@@ -51,6 +51,9 @@ const (
 
 	// DefaultLockObjectName is the object name of the lock object.
 	DefaultLockObjectName = "ingress-gce-lock"
+
+	// DefaultFirewallRulePriority is the default priority of GCE firewall rules.
+	DefaultFirewallRulePriority = 1000
 )
 
 var (
@@ -59,6 +62,11 @@ var (
 		APIServerHost                    string
 		ASMConfigMapBasedConfigCMName    string
 		ASMConfigMapBasedConfigNamespace string
+		LabelPropagationConfigMapName    string
+		LabelPropagationConfigMapNS      string
+		LabelPropagationWebhookPort      int
+		LabelPropagationWebhookCertFile  string
+		LabelPropagationWebhookKeyFile   string
 		ClusterName                      string
 		ConfigFilePath                   string
 		DefaultSvc                       string
@@ -78,9 +86,16 @@ var (
 		KubeConfigFile                   string
 		NegGCPeriod                      time.Duration
 		NumNegGCWorkers                  int
+		MaxConcurrentNegSyncs            int
+		NegReadinessMaxConcurrentPolls   int
+		NegReadinessPollQPS              float32
+		NegReadinessPollBurst            int
 		NodePortRanges                   PortRanges
 		ResyncPeriod                     time.Duration
 		L4NetLBProvisionDeadline         time.Duration
+		L4ForwardingRuleSoakPeriod       time.Duration
+		L4OrphanScanPeriod               time.Duration
+		L4OrphanScanDryRun               bool
 		NumL4Workers                     int
 		NumL4NetLBWorkers                int
 		NumIngressWorkers                int
@@ -106,6 +121,8 @@ var (
 		FinalizerAdd                             bool // Should have been named Enablexxx.
 		FinalizerRemove                          bool // Should have been named Enablexxx.
 		EnablePSC                                bool
+		PSCReconcilePeriod                       time.Duration
+		EnablePSCConsumer                        bool
 		EnableIngressGAFields                    bool
 		EnableTrafficScaling                     bool
 		EnableRecalculateUHCOnBCRemoval          bool
@@ -122,12 +139,22 @@ var (
 		EnableNEGLabelPropagation                bool
 		EnableMultiNetworking                    bool
 		MaxIGSize                                int
+		ManagedInstanceGroupsNodeLabel           string
+		MaxConcurrentIGZoneSyncs                 int
+		IGAddInstancesBatchSize                  int
 		EnableDegradedMode                       bool
 		EnableDegradedModeMetrics                bool
 		EnableDualStackNEG                       bool
 		EnableFirewallCR                         bool
 		DisableFWEnforcement                     bool
 		DisableL4LBFirewall                      bool
+		FirewallPolicy                           string
+		FirewallPolicyRulePriority               int64
+		FirewallPolicyTargetSecureTags           string
+		EnablePerIngressFirewall                 bool
+		EnableFirewallLogging                    bool
+		FirewallLogMetadata                      string
+		FirewallRulePriority                     int64
 		EnableIngressRegionalExternal            bool
 		EnableIngressGlobalExternal              bool
 		OverrideComputeAPIEndpoint               string
@@ -138,8 +165,11 @@ var (
 		EnableWeightedL4NetLB                    bool
 		EnableDiscretePortForwarding             bool
 		EnableMultiProjectMode                   bool
+		EnableResourceNEGs                       bool
+		EnableL4OrphanScanner                    bool
 	}{
-		GCERateLimitScale: 1.0,
+		GCERateLimitScale:    1.0,
+		FirewallRulePriority: DefaultFirewallRulePriority,
 	}
 )
 
@@ -244,6 +274,14 @@ the pod secrets for creating a Kubernetes client.`)
 		`Relist and confirm cloud resources this often.`)
 	flag.DurationVar(&F.L4NetLBProvisionDeadline, "l4-netlb-provision-deadline", 20*time.Minute,
 		`Deadline latency for L4 NetLB provisioning.`)
+	flag.DurationVar(&F.L4ForwardingRuleSoakPeriod, "l4-forwarding-rule-soak-period", time.Hour,
+		`Time a forwarding rule replaced in a make-before-break recreation keeps serving traffic before it is removed.`)
+	flag.BoolVar(&F.EnableL4OrphanScanner, "enable-l4-orphan-scanner", false,
+		`Enable the periodic scan for L4 load balancer resources of the cluster that no longer belong to a LoadBalancer Service.`)
+	flag.DurationVar(&F.L4OrphanScanPeriod, "l4-orphan-scan-period", time.Hour,
+		`Time between two scans for orphaned L4 load balancer resources.`)
+	flag.BoolVar(&F.L4OrphanScanDryRun, "l4-orphan-scan-dry-run", true,
+		`If enabled, orphaned L4 load balancer resources are only reported. Disable to delete them.`)
 	flag.IntVar(&F.NumL4Workers, "num-l4-workers", 5,
 		`Number of parallel L4 Internal Load Balancer Service worker goroutines.`)
 	flag.IntVar(&F.NumL4NetLBWorkers, "num-l4-net-workers", 5,
@@ -265,7 +303,11 @@ L7 load balancing. CSV values accepted. Example: -node-port-ranges=80,8080,400-5
 	flag.DurationVar(&F.NegGCPeriod, "neg-gc-period", 120*time.Second,
 		`Relist and garbage collect NEGs this often.`)
 	flag.IntVar(&F.NumNegGCWorkers, "num-neg-gc-workers", 10, "Number of goroutines created by NEG garbage collector. This value controls the maximum number of concurrent calls made to the GCE NEG Delete API.")
+	flag.IntVar(&F.MaxConcurrentNegSyncs, "max-concurrent-neg-syncs", 0, "Maximum number of NEG syncs and endpoint attach and detach operations running concurrently. Syncers waiting for a slot are scheduled by the networking.gke.io/neg-sync-priority annotation of their Service, with weighted fair sharing among priorities. Non-positive means unlimited.")
 	flag.BoolVar(&F.EnableReadinessReflector, "enable-readiness-reflector", true, "Enable NEG Readiness Reflector")
+	flag.IntVar(&F.NegReadinessMaxConcurrentPolls, "neg-readiness-max-concurrent-polls", 20, "Maximum number of NEGs the readiness reflector polls for health status concurrently. NEGs with the oldest unready pods are polled first. Non-positive means unlimited.")
+	flag.Float32Var(&F.NegReadinessPollQPS, "neg-readiness-poll-qps", 5, "Rate of health status polls (ListNetworkEndpoints calls) shared by all NEGs in the readiness reflector. Non-positive means unlimited.")
+	flag.IntVar(&F.NegReadinessPollBurst, "neg-readiness-poll-burst", 10, "Burst of health status polls shared by all NEGs in the readiness reflector.")
 	flag.BoolVar(&F.FinalizerAdd, "enable-finalizer-add",
 		F.FinalizerAdd, "Enable adding Finalizer to Ingress.")
 	flag.BoolVar(&F.FinalizerRemove, "enable-finalizer-remove",
@@ -284,6 +326,8 @@ L7 load balancing. CSV values accepted. Example: -node-port-ranges=80,8080,400-5
 	flag.BoolVar(&F.GateNEGByLock, "gate-neg-by-lock", false, "If enabled then the NEG controller will be run via leader election with NEG resource lock")
 	flag.BoolVar(&F.EnableIGController, "enable-ig-controller", true, `Optional, if enabled then the IG controller will be run.`)
 	flag.BoolVar(&F.EnablePSC, "enable-psc", false, "Enable PSC controller")
+	flag.DurationVar(&F.PSCReconcilePeriod, "psc-reconcile-period", 10*time.Minute, "Time between two reconciliations of all the ServiceAttachments with their GCE Service Attachments, which reverts changes made outside of the PSC controller. Disabled if zero.")
+	flag.BoolVar(&F.EnablePSCConsumer, "enable-psc-consumer", false, "Enable the PSC consumer controller, which creates Private Service Connect endpoints for ServiceAttachmentConsumer resources. Requires --enable-psc.")
 	flag.BoolVar(&F.EnableIngressGAFields, "enable-ingress-ga-fields", false, "Enable using Ingress Class GA features")
 	flag.StringVar(&F.GKEClusterName, "gke-cluster-name", "", "The name of the GKE cluster this Ingress Controller will be interacting with")
 	flag.StringVar(&F.GKEClusterHash, "gke-cluster-hash", "", "The cluster hash of the GKE cluster this Ingress Controller will be interacting with")
@@ -300,18 +344,33 @@ L7 load balancing. CSV values accepted. Example: -node-port-ranges=80,8080,400-5
 	// allow-listed projects only. If you need access to this feature for your
 	// External L4 Load Balancer, please contact Google Cloud support team.
 	flag.BoolVar(&F.EnableL4StrongSessionAffinity, "enable-l4lb-strong-sa", false, "Enable Strong Session Affinity for L4 External Load Balancers. The feature is restricted for allow-listed clusters only.")
-	flag.BoolVar(&F.EnableMultipleIGs, "enable-multiple-igs", false, "Enable using multiple unmanaged instance groups")
+	flag.BoolVar(&F.EnableMultipleIGs, "enable-multiple-igs", false, "Enable using multiple unmanaged instance groups per zone, to load balance zones with more than --max-ig-size nodes.")
 	flag.BoolVar(&F.EnableMultiNetworking, "enable-multi-networking", false, "Enable support for multi-networking L4 load balancers.")
 	flag.IntVar(&F.MaxIGSize, "max-ig-size", 1000, "Max number of instances in Instance Group")
+	flag.StringVar(&F.ManagedInstanceGroupsNodeLabel, "managed-instance-groups-node-label", "", "Label of the nodes whose value is the name of their managed instance group. If set, the managed instance groups of the node pools are used as backends instead of unmanaged instance groups.")
+	flag.IntVar(&F.MaxConcurrentIGZoneSyncs, "max-concurrent-ig-zone-syncs", 4, "Maximum number of zones whose instance group members are synced concurrently. Non-positive means unlimited.")
+	flag.IntVar(&F.IGAddInstancesBatchSize, "ig-add-instances-batch-size", 500, "Maximum number of instances added to an instance group in a single call. Chunks that fail are retried without adding the other chunks again. Non-positive means unlimited.")
 	flag.DurationVar(&F.MetricsExportInterval, "metrics-export-interval", 10*time.Minute, `Period for calculating and exporting metrics related to state of managed objects.`)
 	flag.DurationVar(&F.NegMetricsExportInterval, "neg-metrics-export-interval", 5*time.Second, `Period for calculating and exporting internal neg controller metrics, not usage.`)
 	flag.BoolVar(&F.EnableDegradedMode, "enable-degraded-mode", false, `Enable degraded mode endpoint calculation and use results when error state is triggered. enabledDegradedMode also enables degrade mode correctness metrics with or without enabledDegradedModeMetrics.`)
 	flag.BoolVar(&F.EnableDegradedModeMetrics, "enable-degraded-mode-metrics", false, `Enable metrics collection for degraded mode, but uses normal mode calculation result when error state is triggered.`)
-	flag.BoolVar(&F.EnableNEGLabelPropagation, "enable-label-propagation", false, "Enable NEG endpoint label propagation")
+	flag.BoolVar(&F.EnableNEGLabelPropagation, "enable-label-propagation", false, "Enable NEG endpoint label propagation. Pods whose labels will be truncated get a Warning event when the NEG controller observes them before they are scheduled, and a warning at admission time if --label-propagation-webhook-port is set.")
+	flag.IntVar(&F.LabelPropagationWebhookPort, "label-propagation-webhook-port", 0, "Port of the HTTPS server of the validating admission webhook returning a warning when a label of a created or updated pod will be truncated by label propagation, on the path /validate-pod-label-propagation. The webhook always admits pods, its ValidatingWebhookConfiguration should use the Ignore failure policy. Disabled if 0. Requires --enable-label-propagation, --label-propagation-webhook-cert-file and --label-propagation-webhook-key-file.")
+	flag.StringVar(&F.LabelPropagationWebhookCertFile, "label-propagation-webhook-cert-file", "", "Path to the TLS certificate of the label propagation admission webhook server.")
+	flag.StringVar(&F.LabelPropagationWebhookKeyFile, "label-propagation-webhook-key-file", "", "Path to the TLS private key of the label propagation admission webhook server.")
+	flag.StringVar(&F.LabelPropagationConfigMapNS, "label-propagation-configmap-namespace", "kube-system", "Namespace of the ConfigMap holding the label propagation config.")
+	flag.StringVar(&F.LabelPropagationConfigMapName, "label-propagation-configmap-name", "", "Name of the ConfigMap holding the label propagation config under the key \"config\". If set, the config is reloaded whenever the ConfigMap changes and takes precedence over the LABEL_PROPAGATION_CONFIG environment variable. Requires --enable-label-propagation.")
 	flag.BoolVar(&F.EnableDualStackNEG, "enable-dual-stack-neg", false, `Enable support for Dual-Stack NEGs within the NEG Controller`)
 	flag.BoolVar(&F.EnableFirewallCR, "enable-firewall-cr", false, "Enable generating firewall CR")
 	flag.BoolVar(&F.DisableFWEnforcement, "disable-fw-enforcement", false, "Disable Ingress controller to enforce the firewall rules. If set to true, Ingress Controller stops creating GCE firewall rules. We can only enable this if enable-firewall-cr sets to true.")
-	flag.BoolVar(&F.DisableL4LBFirewall, "disable-l4-lb-fw", false, "Disable enforcement of L4 ILB and L4 NetLB VPC firewall rules. Required for firewall policies.")
+	flag.BoolVar(&F.DisableL4LBFirewall, "disable-l4-lb-fw", false, "Disable enforcement of L4 ILB and L4 NetLB VPC firewall rules.")
+	flag.StringVar(&F.FirewallPolicy, "firewall-policy", "", "Name of the global network firewall policy to manage the L7 and L4 load balancer firewall rules in, instead of VPC firewall rules. The policy must be associated with the network of the cluster.")
+	flag.Int64Var(&F.FirewallPolicyRulePriority, "firewall-policy-rule-priority", 1000, "Priority of the L7 firewall rule in the network firewall policy set by --firewall-policy. The priority identifies the rule in the policy and must not be used by other rules. L4 firewall rules use the first free priorities after it.")
+	flag.StringVar(&F.FirewallPolicyTargetSecureTags, "firewall-policy-target-secure-tags", "", "Comma separated secure tag values (tagValues/ID) bound to the nodes of the cluster, targeted by the firewall rules of the network firewall policy set by --firewall-policy.")
+	flag.BoolVar(&F.EnablePerIngressFirewall, "enable-per-ingress-firewall", false, "Enable one L7 VPC firewall rule per Ingress, allowing only the ports and source ranges of its load balancer, instead of a single rule for all the Ingresses of the cluster. Not supported with --firewall-policy.")
+	flag.BoolVar(&F.EnableFirewallLogging, "enable-firewall-logging", false, "Enable logging of the VPC firewall rules managed by the L7 and L4 controllers, and of the firewall rules of the network firewall policy set by --firewall-policy. Can be overridden per Service and per Ingress with the networking.gke.io/firewall-options annotation.")
+	flag.StringVar(&F.FirewallLogMetadata, "firewall-log-metadata", "INCLUDE_ALL_METADATA", "Metadata mode of the logs of the VPC firewall rules when logging is enabled, one of INCLUDE_ALL_METADATA, EXCLUDE_ALL_METADATA.")
+	flag.Int64Var(&F.FirewallRulePriority, "firewall-rule-priority", DefaultFirewallRulePriority, "Priority of the VPC firewall rules managed by the L7 and L4 controllers, from 0 (highest) to 65535. Can be overridden per Service and per Ingress with the networking.gke.io/firewall-options annotation.")
 	flag.BoolVar(&F.EnableIngressRegionalExternal, "enable-ingress-regional-external", false, "Enable L7 Ingress Regional External.")
 	flag.BoolVar(&F.EnableIngressGlobalExternal, "enable-ingress-global-external", true, "Enable L7 Ingress Global External. Should be disabled when Regional External is enabled.")
 	flag.StringVar(&F.OverrideComputeAPIEndpoint, "override-compute-api-endpoint", "", "Override endpoint that is used to communicate to GCP compute APIs.")
@@ -324,6 +383,7 @@ L7 load balancing. CSV values accepted. Example: -node-port-ranges=80,8080,400-5
 	flag.IntVar(&F.KubeClientBurst, "kube-client-burst", 0, "The burst QPS that the controllers' kube client should adhere to through client side throttling. If zero, client will be created with default settings.")
 	flag.BoolVar(&F.EnableDiscretePortForwarding, "enable-discrete-port-forwarding", false, "Enable forwarding of individual ports instead of port ranges.")
 	flag.BoolVar(&F.EnableMultiProjectMode, "enable-multi-project-mode", false, "Enable running in multi-project mode.")
+	flag.BoolVar(&F.EnableResourceNEGs, "enable-resource-negs", false, "Enable serverless and internet NEGs declared in ServiceNetworkEndpointGroups and referenced as Ingress resource backends.")
 }
 
 func Validate() {
@@ -335,6 +395,13 @@ func Validate() {
 	if F.THCPort != 7877 && !F.EnableTransparentHealthChecks {
 		klog.Fatalf("The flag --transparent-health-checks-port cannot be used without --enable-transparent-health-checks.")
 	}
+
+	if F.FirewallLogMetadata != "INCLUDE_ALL_METADATA" && F.FirewallLogMetadata != "EXCLUDE_ALL_METADATA" {
+		klog.Fatalf("The flag --firewall-log-metadata must be one of INCLUDE_ALL_METADATA, EXCLUDE_ALL_METADATA, got %q.", F.FirewallLogMetadata)
+	}
+	if F.FirewallRulePriority < 0 || F.FirewallRulePriority > 65535 {
+		klog.Fatalf("The flag --firewall-rule-priority must be in the range [0, 65535], got %d.", F.FirewallRulePriority)
+	}
 }
 
 type RateLimitSpecs struct {
@@ -3,7 +3,9 @@ package forwardingrules
 import (
 	"fmt"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	compute "google.golang.org/api/compute/v1"
 	"k8s.io/cloud-provider-gcp/providers/gce"
 	"k8s.io/ingress-gce/pkg/composite"
 	"k8s.io/ingress-gce/pkg/utils"
@@ -69,6 +71,25 @@ func (frc *ForwardingRules) Delete(name string) error {
 	return nil
 }
 
+// SetLabels replaces the labels of the regional forwarding rule with the given name.
+// labelFingerprint must be the fingerprint of the current labels of the forwarding rule.
+func (frc *ForwardingRules) SetLabels(name string, labels map[string]string, labelFingerprint string) error {
+	if frc.scope != meta.Regional {
+		return fmt.Errorf("Failed to set labels of forwarding rule %s, unsupported scope %s", name, frc.scope)
+	}
+	key, err := frc.createKey(name)
+	if err != nil {
+		return fmt.Errorf("Failed to create key for setting labels of forwarding rule %s, err: %w", name, err)
+	}
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	req := &compute.RegionSetLabelsRequest{Labels: labels, LabelFingerprint: labelFingerprint}
+	if err := frc.cloud.Compute().ForwardingRules().SetLabels(ctx, key, req); err != nil {
+		return fmt.Errorf("Failed to set labels of forwarding rule %s, err: %w", name, err)
+	}
+	return nil
+}
+
 func (frc *ForwardingRules) createKey(name string) (*meta.Key, error) {
 	return composite.CreateKey(frc.cloud, name, frc.scope)
 }
@@ -1,6 +1,7 @@
 package forwardingrules
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	compute "google.golang.org/api/compute/v1"
 	"k8s.io/cloud-provider-gcp/providers/gce"
 	"k8s.io/ingress-gce/pkg/composite"
 	"k8s.io/ingress-gce/pkg/utils"
@@ -180,6 +182,35 @@ func TestDeleteForwardingRule(t *testing.T) {
 	}
 }
 
+func TestSetLabels(t *testing.T) {
+	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+	var gotKey *meta.Key
+	var gotReq *compute.RegionSetLabelsRequest
+	fakeGCE.Compute().(*cloud.MockGCE).MockForwardingRules.SetLabelsHook = func(_ context.Context, key *meta.Key, req *compute.RegionSetLabelsRequest, _ *cloud.MockForwardingRules, _ ...cloud.Option) error {
+		gotKey, gotReq = key, req
+		return nil
+	}
+	frc := New(fakeGCE, meta.VersionGA, meta.Regional, klog.TODO())
+
+	labels := map[string]string{"team": "dns"}
+	if err := frc.SetLabels("ILB", labels, "fingerprint"); err != nil {
+		t.Fatalf("frc.SetLabels(ILB, %v, fingerprint) returned error %v, want nil", labels, err)
+	}
+	wantKey := meta.RegionalKey("ILB", fakeGCE.Region())
+	if diff := cmp.Diff(wantKey, gotKey); diff != "" {
+		t.Errorf("SetLabels key mismatch (-want +got):\n%s", diff)
+	}
+	wantReq := &compute.RegionSetLabelsRequest{Labels: labels, LabelFingerprint: "fingerprint"}
+	if diff := cmp.Diff(wantReq, gotReq); diff != "" {
+		t.Errorf("SetLabels request mismatch (-want +got):\n%s", diff)
+	}
+
+	globalFrc := New(fakeGCE, meta.VersionGA, meta.Global, klog.TODO())
+	if err := globalFrc.SetLabels("ILB", labels, "fingerprint"); err == nil {
+		t.Errorf("globalFrc.SetLabels(ILB, %v, fingerprint) returned nil error, want error for global scope", labels)
+	}
+}
+
 func verifyForwardingRuleExists(cloud *gce.Cloud, name string) error {
 	key, err := composite.CreateKey(cloud, name, meta.Regional)
 	if err != nil {
@@ -49,6 +49,10 @@ const (
 	gceLocalHcUnhealthyThreshold  = int64(2) // 2  * 3 = 6 seconds before the LB will steer traffic away
 	L4ILBIPv6HCRange              = "2600:2d00:1:b029::/64"
 	L4NetLBIPv6HCRange            = "2600:1901:8001::/48"
+	// l4HealthCheckOptionsDesc is recorded in the description of health checks with parameters
+	// overridden by the L4HealthCheckKey annotation, so that they are reset to the defaults
+	// once the annotation is removed.
+	l4HealthCheckOptionsDesc = "Parameters overridden by the " + annotations.L4HealthCheckKey + " annotation"
 )
 
 var (
@@ -120,20 +124,45 @@ func (l4hc *l4HealthChecks) EnsureHealthCheckWithFirewall(svc *corev1.Service, n
 func (l4hc *l4HealthChecks) EnsureHealthCheckWithDualStackFirewalls(svc *corev1.Service, namer namer.L4ResourcesNamer, sharedHC bool, scope meta.KeyType, l4Type utils.L4LBType, nodeNames []string, needsIPv4 bool, needsIPv6 bool, svcNetwork network.NetworkInfo, svcLogger klog.Logger) *EnsureHealthCheckResult {
 	namespacedName := types.NamespacedName{Name: svc.Name, Namespace: svc.Namespace}
 
-	hcName := namer.L4HealthCheck(svc.Namespace, svc.Name, sharedHC)
+	hcOptions, err := healthCheckOptions(svc, sharedHC)
+	if err != nil {
+		svcLogger.Error(err, "Invalid health check options")
+		return &EnsureHealthCheckResult{
+			GceResourceInError: annotations.HealthcheckResource,
+			Err:                utils.NewUserError(err),
+			WasUpdated:         utils.ResourceResync,
+		}
+	}
 	hcPath, hcPort := helpers.GetServiceHealthCheckPathPort(svc)
+	if sharedHC {
+		hcPath, hcPort = gce.GetNodesHealthCheckPath(), gce.GetNodesHealthCheckPort()
+		if hcOptions != nil {
+			// The shared health check can't be customized without affecting all the other Services
+			// using it, the Service gets a dedicated health check of kube-proxy instead.
+			sharedHC = false
+		}
+	}
+	if hcOptions != nil {
+		if hcOptions.Port != 0 {
+			hcPort = hcOptions.Port
+		}
+		if hcOptions.RequestPath != "" {
+			hcPath = hcOptions.RequestPath
+		}
+	}
+
+	hcName := namer.L4HealthCheck(svc.Namespace, svc.Name, sharedHC)
 	hcLogger := svcLogger.WithValues("healthcheckName", hcName)
 	hcLogger.V(3).Info("Ensuring L4 healthcheck with firewalls for service", "shared", sharedHC)
 
 	if sharedHC {
-		hcPath, hcPort = gce.GetNodesHealthCheckPath(), gce.GetNodesHealthCheckPort()
 		// We need to acquire a controller-wide mutex to ensure that in the case of a healthcheck shared between loadbalancers that the sync of the GCE resources is not performed in parallel.
 		l4hc.sharedResourcesLock.Lock()
 		defer l4hc.sharedResourcesLock.Unlock()
 	}
-	hcLogger.V(3).Info("L4 Healthcheck", "expectedPath", hcPath, "expectedPort", hcPort)
+	hcLogger.V(3).Info("L4 Healthcheck", "expectedPath", hcPath, "expectedPort", hcPort, "options", hcOptions)
 
-	hcLink, wasUpdate, err := l4hc.ensureHealthCheck(hcName, namespacedName, sharedHC, hcPath, hcPort, scope, l4Type, hcLogger)
+	hcLink, wasUpdate, err := l4hc.ensureHealthCheck(hcName, namespacedName, sharedHC, hcPath, hcPort, hcOptions, scope, l4Type, hcLogger)
 	if err != nil {
 		hcLogger.Error(err, "Error while ensuring hc")
 		return &EnsureHealthCheckResult{
@@ -162,7 +191,20 @@ func (l4hc *l4HealthChecks) EnsureHealthCheckWithDualStackFirewalls(svc *corev1.
 	return hcResult
 }
 
-func (l4hc *l4HealthChecks) ensureHealthCheck(hcName string, svcName types.NamespacedName, shared bool, path string, port int32, scope meta.KeyType, l4Type utils.L4LBType, hcLogger klog.Logger) (string, utils.ResourceSyncStatus, error) {
+// healthCheckOptions returns the health check overrides of the Service, or nil if there are none.
+func healthCheckOptions(svc *corev1.Service, sharedHC bool) (*annotations.L4HealthCheckOptions, error) {
+	options, err := annotations.FromService(svc).L4HealthCheckOptions()
+	if err != nil || options == nil {
+		return nil, err
+	}
+	if sharedHC && (options.Port != 0 || options.RequestPath != "") {
+		return nil, fmt.Errorf("%w: port and requestPath can only be overridden for Services with the %s external traffic policy",
+			annotations.ErrL4HealthCheckOptionsInvalid, corev1.ServiceExternalTrafficPolicyLocal)
+	}
+	return options, nil
+}
+
+func (l4hc *l4HealthChecks) ensureHealthCheck(hcName string, svcName types.NamespacedName, shared bool, path string, port int32, options *annotations.L4HealthCheckOptions, scope meta.KeyType, l4Type utils.L4LBType, hcLogger klog.Logger) (string, utils.ResourceSyncStatus, error) {
 	start := time.Now()
 	hcLogger.V(2).Info("Ensuring healthcheck for service", "shared", shared, "path", path, "port", port, "scope", scope, "l4Type", l4Type.ToString())
 	defer func() {
@@ -179,6 +221,11 @@ func (l4hc *l4HealthChecks) ensureHealthCheck(hcName string, svcName types.Names
 		region = l4hc.cloud.Region()
 	}
 	expectedHC := newL4HealthCheck(hcName, svcName, shared, path, port, l4Type, scope, region, hcLogger)
+	applyHealthCheckOptions(expectedHC, options, hcLogger)
+	if expectedHC.TimeoutSec > expectedHC.CheckIntervalSec {
+		return "", utils.ResourceResync, utils.NewUserError(fmt.Errorf("%w: timeout of %ds is longer than the check interval of %ds",
+			annotations.ErrL4HealthCheckOptionsInvalid, expectedHC.TimeoutSec, expectedHC.CheckIntervalSec))
+	}
 
 	if hc == nil {
 		// Create the healthcheck
@@ -194,12 +241,20 @@ func (l4hc *l4HealthChecks) ensureHealthCheck(hcName string, svcName types.Names
 		return selfLink, utils.ResourceUpdate, nil
 	}
 	selfLink := hc.SelfLink
-	if !needToUpdateHealthChecks(hc, expectedHC) {
+	if !needToUpdateHealthChecks(hc, expectedHC) && !healthCheckOptionsChanged(hc, options) {
 		// nothing to do
 		hcLogger.V(3).Info("Healthcheck already exists and does not require update")
 		return selfLink, utils.ResourceResync, nil
 	}
-	mergeHealthChecks(hc, expectedHC)
+	if options == nil && hasHealthCheckOptions(hc) {
+		// The annotation was removed, the overridden values are reset to the defaults
+		// instead of being kept as larger than them.
+		hcLogger.V(2).Info("Resetting healthcheck parameters overridden by the removed annotation")
+	} else {
+		mergeHealthChecks(hc, expectedHC)
+	}
+	// Overridden values are applied as they are, even if they are smaller than the existing ones.
+	applyHealthCheckOptions(expectedHC, options, hcLogger)
 	hcLogger.V(2).Info("Updating healthcheck for service", "updatedHealthcheck", expectedHC)
 	err = l4hc.hcProvider.Update(expectedHC.Name, scope, expectedHC)
 	if err != nil {
@@ -281,6 +336,24 @@ func (l4hc *l4HealthChecks) DeleteHealthCheckWithDualStackFirewalls(svc *corev1.
 	return l4hc.deleteHealthCheckWithDualStackFirewalls(svc, namer, sharedHC, scope, l4Type /* delete ipv6 */, true, svcLogger)
 }
 
+// DeleteOverriddenHealthCheckWithFirewalls deletes the dedicated health check and firewall rules that a
+// Service with the Cluster external traffic policy used while its health check parameters were overridden
+// by the annotation. It must be called once the backend service uses the shared health check.
+// Dedicated health checks of Services with the Local external traffic policy are kept.
+func (l4hc *l4HealthChecks) DeleteOverriddenHealthCheckWithFirewalls(svc *corev1.Service, namer namer.L4ResourcesNamer, scope meta.KeyType, l4Type utils.L4LBType, deleteIPv6 bool, svcLogger klog.Logger) (string, error) {
+	hcName := namer.L4HealthCheck(svc.Namespace, svc.Name, false)
+	hc, err := l4hc.hcProvider.Get(hcName, scope)
+	if err != nil {
+		svcLogger.Error(err, "Failed to get dedicated healthcheck for service", "healthcheckName", hcName)
+		return annotations.HealthcheckResource, err
+	}
+	if hc == nil || !hasHealthCheckOptions(hc) {
+		return "", nil
+	}
+	svcLogger.V(2).Info("Deleting dedicated healthcheck replaced by the shared one", "healthcheckName", hcName)
+	return l4hc.deleteHealthCheckWithDualStackFirewalls(svc, namer, false, scope, l4Type, deleteIPv6, svcLogger)
+}
+
 // deleteHealthCheckWithDualStackFirewalls deletes health check, ipv4  firewall rule
 // and ipv6 firewall if running in dual-stack mode for l4 service.
 // Checks if shared resources are safe to delete.
@@ -444,6 +517,66 @@ func newL4HealthCheck(name string, svcName types.NamespacedName, shared bool, pa
 	}
 }
 
+// applyHealthCheckOptions overrides the health check parameters set in the options, and
+// records it in the description. The port and request path are handled by the caller.
+func applyHealthCheckOptions(hc *composite.HealthCheck, options *annotations.L4HealthCheckOptions, hcLogger klog.Logger) {
+	if options == nil {
+		return
+	}
+	setHealthCheckOptionsDescription(hc, hcLogger)
+	if options.CheckIntervalSec != 0 {
+		hc.CheckIntervalSec = options.CheckIntervalSec
+	}
+	if options.TimeoutSec != 0 {
+		hc.TimeoutSec = options.TimeoutSec
+	}
+	if options.HealthyThreshold != 0 {
+		hc.HealthyThreshold = options.HealthyThreshold
+	}
+	if options.UnhealthyThreshold != 0 {
+		hc.UnhealthyThreshold = options.UnhealthyThreshold
+	}
+}
+
+// setHealthCheckOptionsDescription marks the description of the health check as having
+// parameters overridden by the annotation.
+func setHealthCheckOptionsDescription(hc *composite.HealthCheck, hcLogger klog.Logger) {
+	var desc utils.L4LBResourceDescription
+	if err := desc.Unmarshal(hc.Description); err != nil {
+		hcLogger.Info("Failed to parse description of L4HealthCheck", "err", err)
+		return
+	}
+	desc.ResourceDescription = l4HealthCheckOptionsDesc
+	out, err := desc.Marshal()
+	if err != nil {
+		hcLogger.Info("Failed to generate description for L4HealthCheck", "err", err)
+		return
+	}
+	hc.Description = out
+}
+
+// hasHealthCheckOptions checks whether the health check was created with parameters
+// overridden by the annotation.
+func hasHealthCheckOptions(hc *composite.HealthCheck) bool {
+	var desc utils.L4LBResourceDescription
+	if err := desc.Unmarshal(hc.Description); err != nil {
+		return false
+	}
+	return desc.ResourceDescription == l4HealthCheckOptionsDesc
+}
+
+// healthCheckOptionsChanged checks whether the health check differs from the overridden parameters.
+// Unlike the defaults, overridden values must match exactly.
+func healthCheckOptionsChanged(hc *composite.HealthCheck, options *annotations.L4HealthCheckOptions) bool {
+	if options == nil {
+		return false
+	}
+	return (options.CheckIntervalSec != 0 && hc.CheckIntervalSec != options.CheckIntervalSec) ||
+		(options.TimeoutSec != 0 && hc.TimeoutSec != options.TimeoutSec) ||
+		(options.HealthyThreshold != 0 && hc.HealthyThreshold != options.HealthyThreshold) ||
+		(options.UnhealthyThreshold != 0 && hc.UnhealthyThreshold != options.UnhealthyThreshold)
+}
+
 // mergeHealthChecks reconciles HealthCheck config to be no smaller than
 // the default values. newHC is assumed to have defaults,
 // since it is created by the newL4HealthCheck call.
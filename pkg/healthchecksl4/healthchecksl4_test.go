@@ -18,6 +18,7 @@ package healthchecksl4
 
 import (
 	"context"
+	"strconv"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -26,13 +27,16 @@ import (
 	"k8s.io/ingress-gce/pkg/utils/namer"
 	"k8s.io/klog/v2"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"google.golang.org/api/compute/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/composite"
 	"k8s.io/ingress-gce/pkg/utils"
 )
@@ -512,7 +516,7 @@ func TestEnsureHealthCheck(t *testing.T) {
 				}
 			}
 
-			_, updated, err := hcs.ensureHealthCheck(hcName, namespacedName, tc.shared, hcDefaultPath, tc.port, tc.scope, tc.l4Type, klog.TODO())
+			_, updated, err := hcs.ensureHealthCheck(hcName, namespacedName, tc.shared, hcDefaultPath, tc.port, nil, tc.scope, tc.l4Type, klog.TODO())
 			if err != nil {
 				t.Errorf("ensureHealthCheck() err=%v", err)
 			}
@@ -559,6 +563,7 @@ func TestEnsureHealthCheckWithDualStackFirewalls(t *testing.T) {
 		Network:      testClusterValues.NetworkURL,
 		SourceRanges: gce.L4LoadBalancerSrcRanges(),
 		TargetTags:   []string{"k8s-test"},
+		Priority:     1000,
 		Allowed: []*compute.FirewallAllowed{
 			{
 				IPProtocol: "tcp",
@@ -572,6 +577,7 @@ func TestEnsureHealthCheckWithDualStackFirewalls(t *testing.T) {
 		Network:      testClusterValues.NetworkURL,
 		SourceRanges: []string{"10.0.0.0/16"},
 		TargetTags:   []string{"k8s-test"},
+		Priority:     1000,
 		Allowed: []*compute.FirewallAllowed{
 			{
 				IPProtocol: "tcp",
@@ -670,13 +676,202 @@ func TestEnsureHealthCheckWithDualStackFirewalls(t *testing.T) {
 			if err != nil {
 				t.Errorf("GetFirewall() err=-%v", err)
 			}
-			if diff := cmp.Diff(tc.wantFirewall, firewall, cmpopts.IgnoreFields(compute.Firewall{}, "SelfLink", "SourceRanges")); diff != "" {
+			if diff := cmp.Diff(tc.wantFirewall, firewall, cmpopts.IgnoreFields(compute.Firewall{}, "SelfLink", "SourceRanges", "LogConfig", "ForceSendFields")); diff != "" {
 				t.Errorf("created Firewall differs: diff -want +got\n%v\n", diff)
 			}
 		})
 	}
 }
 
+func TestEnsureHealthCheckWithOptions(t *testing.T) {
+	l4Namer := namer.NewL4Namer("test", namer.NewNamer("testCluster", "testFirewall", klog.TODO()))
+	testClusterValues := gce.DefaultTestClusterValues()
+	newService := func(trafficPolicy corev1.ServiceExternalTrafficPolicyType, hcAnnotation string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "serviceName",
+				Namespace:   "serviceNamespace",
+				UID:         types.UID("1"),
+				Annotations: map[string]string{annotations.L4HealthCheckKey: hcAnnotation},
+			},
+			Spec: corev1.ServiceSpec{
+				Ports:                 []corev1.ServicePort{{Port: 8080, Protocol: corev1.ProtocolTCP}},
+				Type:                  "LoadBalancer",
+				ExternalTrafficPolicy: trafficPolicy,
+				HealthCheckNodePort:   1234,
+			},
+		}
+	}
+	namespacedName := types.NamespacedName{Name: "serviceName", Namespace: "serviceNamespace"}
+	dedicatedHCName := l4Namer.L4HealthCheck(namespacedName.Namespace, namespacedName.Name, false)
+
+	testCases := []struct {
+		desc         string
+		svc          *corev1.Service
+		existingHC   *composite.HealthCheck
+		wantHC       func(hc *composite.HealthCheck)
+		wantPath     string
+		wantPort     int32
+		wantFirewall string
+		wantErr      bool
+	}{
+		{
+			desc:     "local traffic policy with all overrides",
+			svc:      newService(corev1.ServiceExternalTrafficPolicyLocal, `{"checkIntervalSec": 10, "timeoutSec": 5, "healthyThreshold": 2, "unhealthyThreshold": 4, "port": 8081, "requestPath": "/ready"}`),
+			wantPath: "/ready",
+			wantPort: 8081,
+			wantHC: func(hc *composite.HealthCheck) {
+				hc.CheckIntervalSec, hc.TimeoutSec, hc.HealthyThreshold, hc.UnhealthyThreshold = 10, 5, 2, 4
+			},
+			wantFirewall: l4Namer.L4HealthCheckFirewall(namespacedName.Namespace, namespacedName.Name, false),
+		},
+		{
+			desc:       "overrides smaller than the existing health check are applied",
+			svc:        newService(corev1.ServiceExternalTrafficPolicyLocal, `{"checkIntervalSec": 2}`),
+			existingHC: newL4HealthCheck(dedicatedHCName, namespacedName, false, "/healthz", 1234, utils.XLB, meta.Global, testClusterValues.Region, klog.TODO()),
+			wantPath:   "/healthz",
+			wantPort:   1234,
+			wantHC: func(hc *composite.HealthCheck) {
+				hc.CheckIntervalSec = 2
+			},
+			wantFirewall: l4Namer.L4HealthCheckFirewall(namespacedName.Namespace, namespacedName.Name, false),
+		},
+		{
+			desc:     "cluster traffic policy gets a dedicated health check",
+			svc:      newService(corev1.ServiceExternalTrafficPolicyCluster, `{"unhealthyThreshold": 5}`),
+			wantPath: gce.GetNodesHealthCheckPath(),
+			wantPort: gce.GetNodesHealthCheckPort(),
+			wantHC: func(hc *composite.HealthCheck) {
+				hc.UnhealthyThreshold = 5
+			},
+			wantFirewall: l4Namer.L4HealthCheckFirewall(namespacedName.Namespace, namespacedName.Name, false),
+		},
+		{
+			desc:    "port can't be overridden for the cluster traffic policy",
+			svc:     newService(corev1.ServiceExternalTrafficPolicyCluster, `{"port": 8081}`),
+			wantErr: true,
+		},
+		{
+			desc:    "timeout longer than the default interval",
+			svc:     newService(corev1.ServiceExternalTrafficPolicyLocal, `{"timeoutSec": 5}`),
+			wantErr: true,
+		},
+		{
+			desc:    "invalid annotation",
+			svc:     newService(corev1.ServiceExternalTrafficPolicyLocal, `{"checkIntervalSec": "5"}`),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fakeGCE := gce.NewFakeGCECloud(testClusterValues)
+			nodeNames := []string{"k8s-test-node"}
+			createVMInstanceWithTag(t, fakeGCE, "k8s-test")
+			defaultNetwork := network.DefaultNetwork(fakeGCE)
+			(fakeGCE.Compute().(*cloud.MockGCE)).MockHealthChecks.UpdateHook = mock.UpdateHealthCheckHook
+			hcs := NewL4HealthChecks(fakeGCE, &record.FakeRecorder{}, klog.TODO())
+			if tc.existingHC != nil {
+				if err := hcs.hcProvider.Create(tc.existingHC); err != nil {
+					t.Fatalf("hcProvider.Create() err=%v", err)
+				}
+			}
+
+			sharedHC := tc.svc.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyCluster
+			result := hcs.EnsureHealthCheckWithDualStackFirewalls(tc.svc, l4Namer, sharedHC, meta.Global, utils.XLB, nodeNames, true, false, *defaultNetwork, klog.TODO())
+			if tc.wantErr {
+				if result.Err == nil || !utils.IsUserError(result.Err) {
+					t.Fatalf("hcs.EnsureHealthCheckWithDualStackFirewalls() err=%v, want user error", result.Err)
+				}
+				if result.GceResourceInError != annotations.HealthcheckResource {
+					t.Errorf("result.GceResourceInError = %q, want %q", result.GceResourceInError, annotations.HealthcheckResource)
+				}
+				return
+			}
+			if result.Err != nil {
+				t.Fatalf("hcs.EnsureHealthCheckWithDualStackFirewalls() err=%v", result.Err)
+			}
+			if result.HCName != dedicatedHCName {
+				t.Errorf("result.HCName = %q, want %q", result.HCName, dedicatedHCName)
+			}
+			wantHC := newL4HealthCheck(dedicatedHCName, namespacedName, false, tc.wantPath, tc.wantPort, utils.XLB, meta.Global, testClusterValues.Region, klog.TODO())
+			tc.wantHC(wantHC)
+			setHealthCheckOptionsDescription(wantHC, klog.TODO())
+			resultHC, err := hcs.hcProvider.Get(result.HCName, meta.Global)
+			if err != nil {
+				t.Fatalf("hcProvider.Get() err=%v", err)
+			}
+			if diff := cmp.Diff(wantHC, resultHC, cmpopts.IgnoreFields(composite.HealthCheck{}, "SelfLink", "Region", "Scope", "Version")); diff != "" {
+				t.Errorf("created HC differs: diff -want +got\n%v\n", diff)
+			}
+			if sharedHC {
+				sharedHCName := l4Namer.L4HealthCheck(namespacedName.Namespace, namespacedName.Name, true)
+				if hc, err := hcs.hcProvider.Get(sharedHCName, meta.Global); err != nil || hc != nil {
+					t.Errorf("hcProvider.Get(%s) = %v, %v, want the shared health check to not be created", sharedHCName, hc, err)
+				}
+			}
+			if result.HCFirewallRuleName != tc.wantFirewall {
+				t.Errorf("result.HCFirewallRuleName = %q, want %q", result.HCFirewallRuleName, tc.wantFirewall)
+			}
+			firewall, err := fakeGCE.GetFirewall(result.HCFirewallRuleName)
+			if err != nil {
+				t.Fatalf("GetFirewall() err=%v", err)
+			}
+			wantAllowed := []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{strconv.Itoa(int(tc.wantPort))}}}
+			if diff := cmp.Diff(wantAllowed, firewall.Allowed); diff != "" {
+				t.Errorf("health check firewall allows unexpected ports: diff -want +got\n%v\n", diff)
+			}
+		})
+	}
+}
+
+func TestEnsureHealthCheckOptionsRemoved(t *testing.T) {
+	l4Namer := namer.NewL4Namer("test", namer.NewNamer("testCluster", "testFirewall", klog.TODO()))
+	testClusterValues := gce.DefaultTestClusterValues()
+	fakeGCE := gce.NewFakeGCECloud(testClusterValues)
+	createVMInstanceWithTag(t, fakeGCE, "k8s-test")
+	(fakeGCE.Compute().(*cloud.MockGCE)).MockHealthChecks.UpdateHook = mock.UpdateHealthCheckHook
+	hcs := NewL4HealthChecks(fakeGCE, &record.FakeRecorder{}, klog.TODO())
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "serviceName",
+			Namespace:   "serviceNamespace",
+			Annotations: map[string]string{annotations.L4HealthCheckKey: `{"checkIntervalSec": 20, "unhealthyThreshold": 5}`},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports:                 []corev1.ServicePort{{Port: 8080, Protocol: corev1.ProtocolTCP}},
+			Type:                  "LoadBalancer",
+			ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+			HealthCheckNodePort:   1234,
+		},
+	}
+	namespacedName := types.NamespacedName{Name: svc.Name, Namespace: svc.Namespace}
+	hcName := l4Namer.L4HealthCheck(svc.Namespace, svc.Name, false)
+	ensure := func() *composite.HealthCheck {
+		t.Helper()
+		result := hcs.EnsureHealthCheckWithFirewall(svc, l4Namer, false, meta.Global, utils.XLB, []string{"k8s-test-node"}, *network.DefaultNetwork(fakeGCE), klog.TODO())
+		if result.Err != nil {
+			t.Fatalf("hcs.EnsureHealthCheckWithFirewall() err=%v", result.Err)
+		}
+		hc, err := hcs.hcProvider.Get(hcName, meta.Global)
+		if err != nil {
+			t.Fatalf("hcProvider.Get() err=%v", err)
+		}
+		return hc
+	}
+
+	if hc := ensure(); hc.CheckIntervalSec != 20 || hc.UnhealthyThreshold != 5 {
+		t.Errorf("Health check has checkIntervalSec=%d, unhealthyThreshold=%d, want 20, 5", hc.CheckIntervalSec, hc.UnhealthyThreshold)
+	}
+
+	delete(svc.Annotations, annotations.L4HealthCheckKey)
+	wantHC := newL4HealthCheck(hcName, namespacedName, false, "/healthz", 1234, utils.XLB, meta.Global, testClusterValues.Region, klog.TODO())
+	if diff := cmp.Diff(wantHC, ensure(), cmpopts.IgnoreFields(composite.HealthCheck{}, "SelfLink", "Region", "Scope", "Version")); diff != "" {
+		t.Errorf("Health check was not reset to the defaults: diff -want +got\n%v\n", diff)
+	}
+}
+
 func createVMInstanceWithTag(t *testing.T, fakeGCE *gce.Cloud, tag string) {
 	err := fakeGCE.Compute().Instances().Insert(context.Background(),
 		meta.ZonalKey("k8s-test-node", fakeGCE.LocalZone()),
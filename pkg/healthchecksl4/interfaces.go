@@ -20,6 +20,9 @@ type L4HealthChecks interface {
 	DeleteHealthCheckWithFirewall(svc *v1.Service, namer namer.L4ResourcesNamer, sharedHC bool, scope meta.KeyType, l4Type utils.L4LBType, svcLogger klog.Logger) (string, error)
 	// DeleteHealthCheckWithDualStackFirewalls deletes health check (and firewall rule) for l4 service, deletes IPv6 firewalls if asked.
 	DeleteHealthCheckWithDualStackFirewalls(svc *v1.Service, namer namer.L4ResourcesNamer, sharedHC bool, scope meta.KeyType, l4Type utils.L4LBType, svcLogger klog.Logger) (string, error)
+	// DeleteOverriddenHealthCheckWithFirewalls deletes the dedicated health check (and firewall rules) of a l4 service
+	// using the shared health check, if it was created for overridden health check parameters.
+	DeleteOverriddenHealthCheckWithFirewalls(svc *v1.Service, namer namer.L4ResourcesNamer, scope meta.KeyType, l4Type utils.L4LBType, deleteIPv6 bool, svcLogger klog.Logger) (string, error)
 }
 
 type EnsureHealthCheckResult struct {
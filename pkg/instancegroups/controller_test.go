@@ -171,3 +171,7 @@ func (igmf *IGManagerFake) Get(name, zone string) (*compute.InstanceGroup, error
 func (igmf *IGManagerFake) List(logger klog.Logger) ([]string, error) {
 	return []string{}, nil
 }
+
+func (igmf *IGManagerFake) BackendInstanceGroups(name, zone string, logger klog.Logger) ([]string, error) {
+	return []string{name}, nil
+}
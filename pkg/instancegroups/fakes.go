@@ -19,6 +19,7 @@ package instancegroups
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
@@ -47,6 +48,9 @@ type IGsToInstances map[*compute.InstanceGroup]sets.String
 
 // FakeInstanceGroups fakes out the instance groups api.
 type FakeInstanceGroups struct {
+	// mu protects the fields below, the instance groups of different zones
+	// are synced concurrently.
+	mu                    sync.Mutex
 	calls                 []int
 	zonesToIGsToInstances map[string]IGsToInstances
 	maxIGSize             int
@@ -65,12 +69,16 @@ func (f *FakeInstanceGroups) getInstanceGroup(name, zone string) (*compute.Insta
 
 // GetInstanceGroup fakes getting an instance group from the cloud.
 func (f *FakeInstanceGroups) GetInstanceGroup(name, zone string) (*compute.InstanceGroup, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.calls = append(f.calls, utils.Get)
 	return f.getInstanceGroup(name, zone)
 }
 
 // CreateInstanceGroup fakes instance group creation.
 func (f *FakeInstanceGroups) CreateInstanceGroup(ig *compute.InstanceGroup, zone string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	if _, ok := f.zonesToIGsToInstances[zone]; !ok {
 		f.zonesToIGsToInstances[zone] = map[*compute.InstanceGroup]sets.String{}
 	}
@@ -86,6 +94,8 @@ func (f *FakeInstanceGroups) CreateInstanceGroup(ig *compute.InstanceGroup, zone
 
 // DeleteInstanceGroup fakes instance group deletion.
 func (f *FakeInstanceGroups) DeleteInstanceGroup(name, zone string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	ig, err := f.getInstanceGroup(name, zone)
 	if err != nil {
 		return err
@@ -96,6 +106,8 @@ func (f *FakeInstanceGroups) DeleteInstanceGroup(name, zone string) error {
 
 // ListInstancesInInstanceGroup fakes listing instances in an instance group.
 func (f *FakeInstanceGroups) ListInstancesInInstanceGroup(name, zone string, state string) ([]*compute.InstanceWithNamedPorts, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	ig, err := f.getInstanceGroup(name, zone)
 	if err != nil {
 		return nil, err
@@ -105,6 +117,8 @@ func (f *FakeInstanceGroups) ListInstancesInInstanceGroup(name, zone string, sta
 
 // ListInstanceGroups fakes listing instance groups in a zone
 func (f *FakeInstanceGroups) ListInstanceGroups(zone string) ([]*compute.InstanceGroup, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	igs := []*compute.InstanceGroup{}
 	for ig := range f.zonesToIGsToInstances[zone] {
 		igs = append(igs, ig)
@@ -114,6 +128,8 @@ func (f *FakeInstanceGroups) ListInstanceGroups(zone string) ([]*compute.Instanc
 
 // AddInstancesToInstanceGroup fakes adding instances to an instance group.
 func (f *FakeInstanceGroups) AddInstancesToInstanceGroup(name, zone string, instanceRefs []*compute.InstanceReference) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	instanceNames := toInstanceNames(instanceRefs)
 	f.calls = append(f.calls, utils.AddInstances)
 	ig, err := f.getInstanceGroup(name, zone)
@@ -121,6 +137,14 @@ func (f *FakeInstanceGroups) AddInstancesToInstanceGroup(name, zone string, inst
 		return err
 	}
 
+	// Like load balanced instance groups, an instance can only be a member
+	// of a single instance group.
+	for otherIG, instances := range f.zonesToIGsToInstances[zone] {
+		if otherIG != ig && instances.HasAny(instanceNames...) {
+			return test.FakeGoogleAPIMemberAlreadyInAnotherInstanceGroupError()
+		}
+	}
+
 	newValue := sets.NewString(f.zonesToIGsToInstances[zone][ig].List()...)
 	newValue.Insert(instanceNames...)
 
@@ -134,6 +158,8 @@ func (f *FakeInstanceGroups) AddInstancesToInstanceGroup(name, zone string, inst
 
 // RemoveInstancesFromInstanceGroup fakes removing instances from an instance group.
 func (f *FakeInstanceGroups) RemoveInstancesFromInstanceGroup(name, zone string, instanceRefs []*compute.InstanceReference) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	instanceNames := toInstanceNames(instanceRefs)
 	f.calls = append(f.calls, utils.RemoveInstances)
 	ig, err := f.getInstanceGroup(name, zone)
@@ -145,6 +171,8 @@ func (f *FakeInstanceGroups) RemoveInstancesFromInstanceGroup(name, zone string,
 }
 
 func (f *FakeInstanceGroups) SetNamedPortsOfInstanceGroup(igName, zone string, namedPorts []*compute.NamedPort) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	ig, err := f.getInstanceGroup(igName, zone)
 	if err != nil {
 		return err
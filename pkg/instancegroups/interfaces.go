@@ -29,6 +29,10 @@ type Manager interface {
 
 	Get(name, zone string) (*compute.InstanceGroup, error)
 	List(logger klog.Logger) ([]string, error)
+	// BackendInstanceGroups returns the names of the instance groups in the
+	// zone to link to backend services, given the name of the instance group
+	// of the controller.
+	BackendInstanceGroups(name, zone string, logger klog.Logger) ([]string, error)
 
 	Sync(nodeNames []string, logger klog.Logger) error
 }
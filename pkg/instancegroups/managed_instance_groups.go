@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"fmt"
+	"net/http"
+
+	"google.golang.org/api/compute/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/ingress-gce/pkg/events"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/zonegetter"
+	"k8s.io/klog/v2"
+)
+
+// managedInstanceGroups returns the sorted names of the managed instance
+// groups of the nodes, by zone. The managed instance group of a node is the
+// value of its m.migNodeLabel label, nodes without the label are skipped.
+func (m *manager) managedInstanceGroups(logger klog.Logger) (map[string][]string, error) {
+	nodes, err := m.ZoneGetter.ListNodes(zonegetter.AllNodesFilter, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	migs := make(map[string]sets.String)
+	var unlabeled []string
+	for _, node := range nodes {
+		migName, ok := node.Labels[m.migNodeLabel]
+		if !ok || migName == "" {
+			unlabeled = append(unlabeled, node.Name)
+			continue
+		}
+		zone, err := m.ZoneGetter.ZoneForNode(node.Name, logger)
+		if err != nil {
+			logger.Error(err, "Failed to get zone for node, skipping", "node", node.Name)
+			continue
+		}
+		if _, ok := migs[zone]; !ok {
+			migs[zone] = sets.NewString()
+		}
+		migs[zone].Insert(migName)
+	}
+	if len(unlabeled) != 0 {
+		logger.Info("Nodes without managed instance group label are not load balanced", "label", m.migNodeLabel, "nodes", events.TruncatedStringList(unlabeled))
+	}
+
+	migsByZone := make(map[string][]string, len(migs))
+	for zone, names := range migs {
+		migsByZone[zone] = names.List()
+	}
+	return migsByZone, nil
+}
+
+// ensureManagedInstanceGroupsPorts ensures that the managed instance groups of
+// the nodes have the named ports of the given ports. Managed instance groups
+// are owned by the node pools, they are never created by the controller.
+func (m *manager) ensureManagedInstanceGroupsPorts(ports []int64, logger klog.Logger) ([]*compute.InstanceGroup, error) {
+	migsByZone, err := m.managedInstanceGroups(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var igs []*compute.InstanceGroup
+	for zone, migNames := range migsByZone {
+		for _, migName := range migNames {
+			ig, err := m.Get(migName, zone)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get managed instance group %s/%s: %w", zone, migName, err)
+			}
+			if err := m.ensureNamedPorts(ig, zone, ports, logger); err != nil {
+				return nil, err
+			}
+			igs = append(igs, ig)
+		}
+	}
+	return igs, nil
+}
+
+// emptyInstanceGroups removes all instances from the unmanaged instance group
+// of the controller, since instances of the managed instance groups can only
+// be load balanced through a single instance group.
+func (m *manager) emptyInstanceGroups(logger klog.Logger) error {
+	zones, err := m.ZoneGetter.ListZones(zonegetter.AllNodesFilter, logger)
+	if err != nil {
+		return err
+	}
+
+	igName := m.namer.InstanceGroup()
+	for _, zone := range zones {
+		instances, err := m.cloud.ListInstancesInInstanceGroup(igName, zone, allInstances)
+		if err != nil {
+			if utils.IsHTTPErrorCode(err, http.StatusNotFound) {
+				continue
+			}
+			logger.Error(err, "Failed to list instance from instance group", "zone", zone, "igName", igName)
+			return err
+		}
+		var removeNodes []string
+		for _, ins := range instances {
+			instance, err := utils.KeyName(ins.Instance)
+			if err != nil {
+				logger.Error(err, "Failed to read instance name from ULR, skipping single instance", "Instance URL", ins.Instance)
+				continue
+			}
+			removeNodes = append(removeNodes, instance)
+		}
+		if len(removeNodes) == 0 {
+			continue
+		}
+		if err := m.remove(igName, removeNodes, zone, logger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
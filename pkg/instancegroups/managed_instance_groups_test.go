@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/compute/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/zonegetter"
+	"k8s.io/klog/v2"
+)
+
+const testMIGNodeLabel = "cloud.google.com/gke-nodepool-mig"
+
+func newManagedNodePool(f Provider) *manager {
+	pool := newNodePool(f, 1000).(*manager)
+	pool.migNodeLabel = testMIGNodeLabel
+	return pool
+}
+
+func addFakeMIGNode(t *testing.T, zoneGetter *zonegetter.ZoneGetter, name, zone, migName string) {
+	t.Helper()
+	labels := map[string]string{utils.LabelNodeSubnet: "default"}
+	if migName != "" {
+		labels[testMIGNodeLabel] = migName
+	}
+	if err := zonegetter.AddFakeNode(zoneGetter, &apiv1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec:       apiv1.NodeSpec{ProviderID: fmt.Sprintf("gce://foo-project/%s/%s", zone, name)},
+		Status: apiv1.NodeStatus{
+			Conditions: []apiv1.NodeCondition{{Type: apiv1.NodeReady, Status: apiv1.ConditionTrue}},
+		},
+	}); err != nil {
+		t.Fatalf("AddFakeNode(%s) returned error %v", name, err)
+	}
+}
+
+func TestManagedInstanceGroups(t *testing.T) {
+	igName := defaultNamer.InstanceGroup()
+	migA1, migA2, migB := &compute.InstanceGroup{Name: "mig-a1"}, &compute.InstanceGroup{Name: "mig-a2"}, &compute.InstanceGroup{Name: "mig-b"}
+	fakeIGs := NewFakeInstanceGroups(map[string]IGsToInstances{
+		testZoneA: {
+			migA1:                                sets.NewString("n1"),
+			migA2:                                sets.NewString("n2"),
+			&compute.InstanceGroup{Name: igName}: sets.NewString("n1", "n2"),
+		},
+		testZoneB: {
+			migB: sets.NewString("n3"),
+		},
+	}, 1000)
+	pool := newManagedNodePool(fakeIGs)
+	addFakeMIGNode(t, pool.ZoneGetter, "n1", testZoneA, "mig-a1")
+	addFakeMIGNode(t, pool.ZoneGetter, "n2", testZoneA, "mig-a2")
+	addFakeMIGNode(t, pool.ZoneGetter, "n3", testZoneB, "mig-b")
+	addFakeMIGNode(t, pool.ZoneGetter, "n4", testZoneB, "")
+
+	igs, err := pool.EnsureInstanceGroupsAndPorts(igName, []int64{80, 81}, klog.TODO())
+	if err != nil {
+		t.Fatalf("pool.EnsureInstanceGroupsAndPorts() returned error %v, want nil", err)
+	}
+	gotIGs := sets.NewString()
+	for _, ig := range igs {
+		gotIGs.Insert(ig.Name)
+	}
+	if want := sets.NewString("mig-a1", "mig-a2", "mig-b"); !gotIGs.Equal(want) {
+		t.Errorf("pool.EnsureInstanceGroupsAndPorts() returned %v, want %v", gotIGs.List(), want.List())
+	}
+	for _, mig := range []*compute.InstanceGroup{migA1, migA2, migB} {
+		var ports []int64
+		for _, np := range mig.NamedPorts {
+			ports = append(ports, np.Port)
+		}
+		if want := []int64{80, 81}; !reflect.DeepEqual(ports, want) {
+			t.Errorf("Named ports of %s = %v, want %v", mig.Name, ports, want)
+		}
+	}
+	if _, err := fakeIGs.GetInstanceGroup(igName, testZoneB); !utils.IsNotFoundError(err) {
+		t.Errorf("GetInstanceGroup(%s, %s) returned error %v, want not found", igName, testZoneB, err)
+	}
+
+	for zone, want := range map[string][]string{
+		testZoneA: {"mig-a1", "mig-a2"},
+		testZoneB: {"mig-b"},
+		testZoneC: nil,
+	} {
+		got, err := pool.BackendInstanceGroups(igName, zone, klog.TODO())
+		if err != nil {
+			t.Errorf("pool.BackendInstanceGroups(%s, %s) returned error %v, want nil", igName, zone, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("pool.BackendInstanceGroups(%s, %s) = %v, want %v", igName, zone, got, want)
+		}
+	}
+
+	fakeIGs.calls = nil
+	if err := pool.Sync([]string{"n1", "n2", "n3", "n4"}, klog.TODO()); err != nil {
+		t.Fatalf("pool.Sync() returned error %v, want nil", err)
+	}
+	if want := []int{utils.RemoveInstances}; !reflect.DeepEqual(fakeIGs.calls, want) {
+		t.Errorf("pool.Sync() made calls %v, want %v", fakeIGs.calls, want)
+	}
+	instances, err := fakeIGs.ListInstancesInInstanceGroup(igName, testZoneA, allInstances)
+	if err != nil {
+		t.Fatalf("ListInstancesInInstanceGroup(%s, %s) returned error %v", igName, testZoneA, err)
+	}
+	if len(instances) != 0 {
+		t.Errorf("Instance group %s has instances %v, want none", igName, instances)
+	}
+	for mig, want := range map[*compute.InstanceGroup]sets.String{
+		migA1: sets.NewString("n1"),
+		migA2: sets.NewString("n2"),
+		migB:  sets.NewString("n3"),
+	} {
+		zone := testZoneA
+		if mig == migB {
+			zone = testZoneB
+		}
+		if got := fakeIGs.zonesToIGsToInstances[zone][mig]; !got.Equal(want) {
+			t.Errorf("Instances of %s = %v, want %v", mig.Name, got.List(), want.List())
+		}
+	}
+}
+
+func TestManagedInstanceGroupsNotFound(t *testing.T) {
+	fakeIGs := NewFakeInstanceGroups(map[string]IGsToInstances{}, 1000)
+	pool := newManagedNodePool(fakeIGs)
+	addFakeMIGNode(t, pool.ZoneGetter, "n1", testZoneA, "mig-a")
+
+	igName := defaultNamer.InstanceGroup()
+	if _, err := pool.EnsureInstanceGroupsAndPorts(igName, []int64{80}, klog.TODO()); !utils.IsNotFoundError(err) {
+		t.Errorf("pool.EnsureInstanceGroupsAndPorts() returned error %v, want not found", err)
+	}
+	if igs, _ := fakeIGs.ListInstanceGroups(testZoneA); len(igs) != 0 {
+		t.Errorf("pool.EnsureInstanceGroupsAndPorts() created instance groups %v, want none", igs)
+	}
+}
+
+func TestBackendInstanceGroupsUnmanaged(t *testing.T) {
+	pool := newNodePool(NewFakeInstanceGroups(map[string]IGsToInstances{}, 1000), 1000)
+	got, err := pool.BackendInstanceGroups("ig", testZoneA, klog.TODO())
+	if err != nil {
+		t.Fatalf("pool.BackendInstanceGroups() returned error %v, want nil", err)
+	}
+	if want := []string{"ig"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pool.BackendInstanceGroups() = %v, want %v", got, want)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	metrics "k8s.io/ingress-gce/pkg/instancegroups/metrics"
@@ -32,6 +33,7 @@ import (
 	"k8s.io/klog/v2"
 
 	core "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"k8s.io/ingress-gce/pkg/utils"
@@ -40,6 +42,10 @@ import (
 const (
 	// State string required by gce library to list all instances.
 	allInstances = "ALL"
+
+	// maxAddChunkRetries is the number of times a chunk of nodes that failed
+	// to be added to an instance group is retried during a sync.
+	maxAddChunkRetries = 2
 )
 
 // manager implements Manager.
@@ -50,6 +56,19 @@ type manager struct {
 	recorder           record.EventRecorder
 	instanceLinkFormat string
 	maxIGSize          int
+	// migNodeLabel is the label of the nodes whose value is the name of
+	// their managed instance group. If set, managed instance groups are used
+	// as backends instead of the unmanaged instance group of the controller.
+	migNodeLabel string
+	// maxConcurrentZoneSyncs is the maximum number of zones synced
+	// concurrently. Non-positive means unlimited.
+	maxConcurrentZoneSyncs int
+	// addInstancesBatchSize is the maximum number of nodes added to an
+	// instance group in a single call. Non-positive means unlimited.
+	addInstancesBatchSize int
+	// enableMultipleIGs splits the nodes of a zone between multiple
+	// instance groups of at most maxIGSize nodes.
+	enableMultipleIGs bool
 }
 
 type recorderSource interface {
@@ -65,17 +84,34 @@ type ManagerConfig struct {
 	BasePath   string
 	ZoneGetter *zonegetter.ZoneGetter
 	MaxIGSize  int
+	// ManagedIGNodeLabel is the label of the nodes whose value is the name
+	// of their managed instance group. If set, the managed instance groups of
+	// the nodes are used as backends instead of an unmanaged instance group.
+	ManagedIGNodeLabel string
+	// MaxConcurrentZoneSyncs is the maximum number of zones whose instance
+	// groups are synced concurrently. Non-positive means unlimited.
+	MaxConcurrentZoneSyncs int
+	// AddInstancesBatchSize is the maximum number of nodes added to an
+	// instance group in a single call. Non-positive means unlimited.
+	AddInstancesBatchSize int
+	// EnableMultipleIGs splits the nodes of a zone between as many instance
+	// groups of at most MaxIGSize nodes as needed, instead of truncating them.
+	EnableMultipleIGs bool
 }
 
 // NewManager creates a new node pool using ManagerConfig.
 func NewManager(config *ManagerConfig) Manager {
 	return &manager{
-		cloud:              config.Cloud,
-		namer:              config.Namer,
-		recorder:           config.Recorders.Recorder(""), // No namespace
-		instanceLinkFormat: config.BasePath + "zones/%s/instances/%s",
-		ZoneGetter:         config.ZoneGetter,
-		maxIGSize:          config.MaxIGSize,
+		cloud:                  config.Cloud,
+		namer:                  config.Namer,
+		recorder:               config.Recorders.Recorder(""), // No namespace
+		instanceLinkFormat:     config.BasePath + "zones/%s/instances/%s",
+		ZoneGetter:             config.ZoneGetter,
+		maxIGSize:              config.MaxIGSize,
+		migNodeLabel:           config.ManagedIGNodeLabel,
+		maxConcurrentZoneSyncs: config.MaxConcurrentZoneSyncs,
+		addInstancesBatchSize:  config.AddInstancesBatchSize,
+		enableMultipleIGs:      config.EnableMultipleIGs,
 	}
 }
 
@@ -84,6 +120,12 @@ func NewManager(config *ManagerConfig) Manager {
 // all of which have the exact same named ports.
 func (m *manager) EnsureInstanceGroupsAndPorts(name string, ports []int64, logger klog.Logger) (igs []*compute.InstanceGroup, err error) {
 	iglogger := logger.WithName("InstanceGroupsManager")
+	if m.migNodeLabel != "" {
+		return m.ensureManagedInstanceGroupsPorts(ports, iglogger)
+	}
+	if m.usesShards(name) {
+		return m.ensureInstanceGroupShardsAndPorts(ports, iglogger)
+	}
 	// Instance groups need to be created in all zones that nodes are in.
 	zones, err := m.ZoneGetter.ListZones(zonegetter.AllNodesFilter, iglogger)
 	if err != nil {
@@ -131,6 +173,15 @@ func (m *manager) ensureInstanceGroupAndPorts(name, zone string, ports []int64,
 		logger.V(2).Info("Instance group already exists", "key", klog.KRef(zone, name))
 	}
 
+	if err := m.ensureNamedPorts(ig, zone, ports, logger); err != nil {
+		return nil, err
+	}
+	return ig, nil
+}
+
+// ensureNamedPorts adds the named ports of the given ports which are missing
+// from the instance group.
+func (m *manager) ensureNamedPorts(ig *compute.InstanceGroup, zone string, ports []int64, logger klog.Logger) error {
 	// Build map of existing ports
 	existingPorts := map[int64]bool{}
 	for _, np := range ig.NamedPorts {
@@ -154,13 +205,12 @@ func (m *manager) ensureInstanceGroupAndPorts(name, zone string, ports []int64,
 	}
 
 	if len(newNamedPorts) > 0 {
-		logger.V(3).Info("Instance group does not have ports, adding them now", "key", klog.KRef(zone, name), "ports", fmt.Sprintf("%+v", newPorts))
+		logger.V(3).Info("Instance group does not have ports, adding them now", "key", klog.KRef(zone, ig.Name), "ports", fmt.Sprintf("%+v", newPorts))
 		if err := m.cloud.SetNamedPortsOfInstanceGroup(ig.Name, zone, append(ig.NamedPorts, newNamedPorts...)); err != nil {
-			return nil, err
+			return err
 		}
 	}
-
-	return ig, nil
+	return nil
 }
 
 // DeleteInstanceGroup deletes the given IG by name, from all zones.
@@ -173,16 +223,28 @@ func (m *manager) DeleteInstanceGroup(name string, logger klog.Logger) error {
 		return err
 	}
 	for _, zone := range zones {
-		if err := m.cloud.DeleteInstanceGroup(name, zone); err != nil {
-			if utils.IsNotFoundError(err) {
-				logger.V(3).Info("Instance group in zone did not exist", "name", name, "zone", zone)
-			} else if utils.IsInUsedByError(err) {
-				logger.V(3).Info("Could not delete instance group in zone because it's still in use. Ignoring", "name", name, "zone", zone, "err", err)
-			} else {
+		names := []string{name}
+		if m.usesShards(name) {
+			shards, err := m.listShards(zone)
+			if err != nil {
 				errs = append(errs, err)
+				continue
+			}
+			// The first shard is deleted even if it is not listed.
+			names = sets.NewString(shards...).Insert(name).List()
+		}
+		for _, igName := range names {
+			if err := m.cloud.DeleteInstanceGroup(igName, zone); err != nil {
+				if utils.IsNotFoundError(err) {
+					logger.V(3).Info("Instance group in zone did not exist", "name", igName, "zone", zone)
+				} else if utils.IsInUsedByError(err) {
+					logger.V(3).Info("Could not delete instance group in zone because it's still in use. Ignoring", "name", igName, "zone", zone, "err", err)
+				} else {
+					errs = append(errs, err)
+				}
+			} else {
+				logger.V(3).Info("Deleted instance group in zone", "name", igName, "zone", zone)
 			}
-		} else {
-			logger.V(3).Info("Deleted instance group in zone", "name", name, "zone", zone)
 		}
 	}
 	if len(errs) == 0 {
@@ -235,6 +297,23 @@ func (m *manager) List(logger klog.Logger) ([]string, error) {
 	return names.UnsortedList(), nil
 }
 
+// BackendInstanceGroups returns the names of the instance groups of the zone
+// which are the backends of instance group based load balancers. name is the
+// name of the instance group of the controller.
+func (m *manager) BackendInstanceGroups(name, zone string, logger klog.Logger) ([]string, error) {
+	if m.migNodeLabel == "" {
+		if m.usesShards(name) {
+			return m.backendShards(zone, logger)
+		}
+		return []string{name}, nil
+	}
+	migsByZone, err := m.managedInstanceGroups(logger.WithName("InstanceGroupsManager"))
+	if err != nil {
+		return nil, err
+	}
+	return migsByZone[zone], nil
+}
+
 // splitNodesByZones takes a list of node names and returns a map of zone:node names.
 // It figures out the zones by asking the zoneLister.
 func (m *manager) splitNodesByZone(names []string, logger klog.Logger) map[string][]string {
@@ -304,66 +383,168 @@ func (m *manager) Sync(nodes []string, logger klog.Logger) (err error) {
 		}
 	}()
 
+	if m.migNodeLabel != "" {
+		// Nodes are members of their managed instance groups.
+		return m.emptyInstanceGroups(iglogger)
+	}
+
+	zonedNodes := m.splitNodesByZone(nodes, iglogger)
+	return m.syncZones(zonedNodes, iglogger)
+}
+
+// syncZones syncs the instance groups of the zones with their nodes, with at
+// most m.maxConcurrentZoneSyncs zones synced concurrently.
+func (m *manager) syncZones(zonedNodes map[string][]string, logger klog.Logger) error {
+	zones := make(chan string, len(zonedNodes))
+	for zone := range zonedNodes {
+		zones <- zone
+	}
+	close(zones)
+
+	numWorkers := len(zonedNodes)
+	if m.maxConcurrentZoneSyncs > 0 && m.maxConcurrentZoneSyncs < numWorkers {
+		numWorkers = m.maxConcurrentZoneSyncs
+	}
+
+	// errListMutex protects writes to errList from the workers.
+	var errList []error
+	var errListMutex sync.Mutex
+	wg := sync.WaitGroup{}
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for zone := range zones {
+				start := time.Now()
+				err := m.syncZone(zone, zonedNodes[zone], logger)
+				metrics.PublishInstanceGroupZoneSync(zone, err, start)
+				if utils.IsHTTPErrorCode(err, http.StatusNotFound) {
+					logger.Info("Node pool encountered a 404, ignoring", "zone", zone, "err", err)
+					err = nil
+				}
+				if err != nil {
+					errListMutex.Lock()
+					errList = append(errList, err)
+					errListMutex.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return utilerrors.NewAggregate(errList)
+}
+
+// syncZone syncs the instances of the instance group of the zone with the
+// given nodes of the zone.
+func (m *manager) syncZone(zone string, kubeNodesFromZone []string, iglogger klog.Logger) error {
+	if m.enableMultipleIGs {
+		return m.syncZoneShards(zone, kubeNodesFromZone, iglogger)
+	}
+	igName := m.namer.InstanceGroup()
 	// For each zone add up to #m.maxIGSize number of nodes to the instance group
 	// If there is more then truncate last nodes (in alphabetical order)
 	// the logic should be consistent with cloud-provider-gcp's Legacy L4 ILB Controller:
 	// https://github.com/kubernetes/cloud-provider-gcp/blob/fca628cb3bf9267def0abb509eaae87d2d4040f3/providers/gce/gce_loadbalancer_internal.go#L606C1-L675C1
 	// the m.maxIGSize should be set to 1000 as is in the cloud-provider-gcp.
-	zonedNodes := m.splitNodesByZone(nodes, iglogger)
-	for zone, kubeNodesFromZone := range zonedNodes {
-		igName := m.namer.InstanceGroup()
-		if len(kubeNodesFromZone) > m.maxIGSize {
-			sortedKubeNodesFromZone := sets.NewString(kubeNodesFromZone...).List()
-			loggableNodeList := events.TruncatedStringList(sortedKubeNodesFromZone[m.maxIGSize:])
-			iglogger.Info(fmt.Sprintf("Total number of kubeNodes: %d, truncating to maximum Instance Group size = %d. zone: %s. First truncated instances: %v", len(kubeNodesFromZone), m.maxIGSize, zone, loggableNodeList))
-			kubeNodesFromZone = sortedKubeNodesFromZone[:m.maxIGSize]
-		}
+	if len(kubeNodesFromZone) > m.maxIGSize {
+		sortedKubeNodesFromZone := sets.NewString(kubeNodesFromZone...).List()
+		loggableNodeList := events.TruncatedStringList(sortedKubeNodesFromZone[m.maxIGSize:])
+		iglogger.Info(fmt.Sprintf("Total number of kubeNodes: %d, truncating to maximum Instance Group size = %d. zone: %s. First truncated instances: %v", len(kubeNodesFromZone), m.maxIGSize, zone, loggableNodeList))
+		kubeNodesFromZone = sortedKubeNodesFromZone[:m.maxIGSize]
+	}
 
-		kubeNodes := sets.NewString(kubeNodesFromZone...)
+	kubeNodes := sets.NewString(kubeNodesFromZone...)
 
-		gceNodes := sets.NewString()
-		instances, err := m.cloud.ListInstancesInInstanceGroup(igName, zone, allInstances)
+	gceNodes := sets.NewString()
+	instances, err := m.cloud.ListInstancesInInstanceGroup(igName, zone, allInstances)
+	if err != nil {
+		iglogger.Error(err, "Failed to list instance from instance group", "zone", zone, "igName", igName)
+		return err
+	}
+	for _, ins := range instances {
+		instance, err := utils.KeyName(ins.Instance)
 		if err != nil {
-			iglogger.Error(err, "Failed to list instance from instance group", "zone", zone, "igName", igName)
-			return err
-		}
-		for _, ins := range instances {
-			instance, err := utils.KeyName(ins.Instance)
-			if err != nil {
-				iglogger.Error(err, "Failed to read instance name from ULR, skipping single instance", "Instance URL", ins.Instance)
-			}
-			gceNodes.Insert(instance)
+			iglogger.Error(err, "Failed to read instance name from ULR, skipping single instance", "Instance URL", ins.Instance)
 		}
+		gceNodes.Insert(instance)
+	}
 
-		removeNodes := gceNodes.Difference(kubeNodes).List()
-		addNodes := kubeNodes.Difference(gceNodes).List()
+	removeNodes := gceNodes.Difference(kubeNodes).List()
+	addNodes := kubeNodes.Difference(gceNodes).List()
 
-		iglogger.V(2).Info("Removing nodes", "removeNodes", events.TruncatedStringList(removeNodes))
-		iglogger.V(2).Info("Adding nodes", "addNodes", events.TruncatedStringList(addNodes))
+	iglogger.V(2).Info("Removing nodes", "zone", zone, "removeNodes", events.TruncatedStringList(removeNodes))
+	iglogger.V(2).Info("Adding nodes", "zone", zone, "addNodes", events.TruncatedStringList(addNodes))
 
-		start := time.Now()
-		if len(removeNodes) != 0 {
-			metrics.PublishInstanceGroupRemove(len(removeNodes))
-			err = m.remove(igName, removeNodes, zone, iglogger)
-			iglogger.V(2).Info("Remove finished", "name", igName, "err", err, "timeTaken", time.Now().Sub(start), "removeNodes", events.TruncatedStringList(removeNodes))
-			if err != nil {
-				return err
-			}
+	start := time.Now()
+	if len(removeNodes) != 0 {
+		metrics.PublishInstanceGroupRemove(len(removeNodes))
+		err = m.remove(igName, removeNodes, zone, iglogger)
+		iglogger.V(2).Info("Remove finished", "name", igName, "zone", zone, "err", err, "timeTaken", time.Now().Sub(start), "removeNodes", events.TruncatedStringList(removeNodes))
+		if err != nil {
+			return err
 		}
+	}
 
-		start = time.Now()
-		if len(addNodes) != 0 {
-			metrics.PublishInstanceGroupAdd(len(addNodes))
-			err = m.add(igName, addNodes, zone, iglogger)
-			iglogger.V(2).Info("Add finished", "name", igName, "err", err, "timeTaken", time.Now().Sub(start), "addNodes", events.TruncatedStringList(addNodes))
-			if err != nil {
-				return err
+	start = time.Now()
+	if len(addNodes) != 0 {
+		metrics.PublishInstanceGroupAdd(len(addNodes))
+		err = m.addInChunks(igName, addNodes, zone, iglogger)
+		iglogger.V(2).Info("Add finished", "name", igName, "zone", zone, "err", err, "timeTaken", time.Now().Sub(start), "addNodes", events.TruncatedStringList(addNodes))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addInChunks adds the nodes to the instance group in chunks of at most
+// m.addInstancesBatchSize nodes. Only the chunks which failed to be added are
+// retried, up to maxAddChunkRetries times.
+func (m *manager) addInChunks(groupName string, nodeNames []string, zone string, logger klog.Logger) error {
+	chunks := chunkNodes(nodeNames, m.addInstancesBatchSize)
+	var err error
+	for attempt := 0; attempt <= maxAddChunkRetries && len(chunks) != 0; attempt++ {
+		if attempt > 0 {
+			logger.Info("Retrying to add failed chunks of nodes to instance group", "name", groupName, "zone", zone, "chunkCount", len(chunks), "attempt", attempt)
+		}
+		var failedChunks [][]string
+		for _, chunk := range chunks {
+			if chunkErr := m.add(groupName, chunk, zone, logger); chunkErr != nil {
+				// Retrying won't help if the instance group does not exist.
+				if utils.IsHTTPErrorCode(chunkErr, http.StatusNotFound) {
+					return chunkErr
+				}
+				err = chunkErr
+				failedChunks = append(failedChunks, chunk)
 			}
 		}
+		chunks = failedChunks
+	}
+	if len(chunks) != 0 {
+		var failedNodes []string
+		for _, chunk := range chunks {
+			failedNodes = append(failedNodes, chunk...)
+		}
+		logger.Error(err, "Failed to add nodes to instance group", "name", groupName, "zone", zone, "failedNodeCount", len(failedNodes), "nodeCount", len(nodeNames), "failedNodes", events.TruncatedStringList(failedNodes))
+		return err
 	}
 	return nil
 }
 
+// chunkNodes splits the nodes into chunks of at most size nodes. Non-positive
+// size means a single chunk.
+func chunkNodes(nodeNames []string, size int) [][]string {
+	if size <= 0 || len(nodeNames) <= size {
+		return [][]string{nodeNames}
+	}
+	var chunks [][]string
+	for size < len(nodeNames) {
+		nodeNames, chunks = nodeNames[size:], append(chunks, nodeNames[0:size:size])
+	}
+	return append(chunks, nodeNames)
+}
+
 // canonicalizeInstanceName take a GCE instance 'hostname' and break it down
 // to something that can be fed to the GCE API client library.  Basically
 // this means reducing 'kubernetes-node-2.c.my-proj.internal' to
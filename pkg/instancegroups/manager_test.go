@@ -22,7 +22,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/ingress-gce/pkg/utils"
 	"net/http"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 
 	"google.golang.org/api/googleapi"
@@ -496,3 +498,138 @@ func TestGetInstanceReferences(t *testing.T) {
 		}
 	}
 }
+
+func TestChunkNodes(t *testing.T) {
+	nodes := []string{"n1", "n2", "n3", "n4", "n5"}
+	for _, tc := range []struct {
+		size int
+		want [][]string
+	}{
+		{size: 0, want: [][]string{nodes}},
+		{size: 5, want: [][]string{nodes}},
+		{size: 10, want: [][]string{nodes}},
+		{size: 2, want: [][]string{{"n1", "n2"}, {"n3", "n4"}, {"n5"}}},
+		{size: 1, want: [][]string{{"n1"}, {"n2"}, {"n3"}, {"n4"}, {"n5"}}},
+	} {
+		if got := chunkNodes(nodes, tc.size); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("chunkNodes(%v, %d) = %v, want %v", nodes, tc.size, got, tc.want)
+		}
+	}
+}
+
+// fakeIGAddFailures fails adding a chunk of instances to an instance group if
+// it contains an instance with remaining failures.
+type fakeIGAddFailures struct {
+	*FakeInstanceGroups
+
+	mu       sync.Mutex
+	failures map[string]int
+	addCalls [][]string
+}
+
+func (fakeIG *fakeIGAddFailures) AddInstancesToInstanceGroup(name, zone string, instanceRefs []*compute.InstanceReference) error {
+	fakeIG.mu.Lock()
+	instanceNames := toInstanceNames(instanceRefs)
+	fakeIG.addCalls = append(fakeIG.addCalls, instanceNames)
+	for _, instance := range instanceNames {
+		if fakeIG.failures[instance] > 0 {
+			fakeIG.failures[instance]--
+			fakeIG.mu.Unlock()
+			return test.FakeGoogleAPIRequestServerError()
+		}
+	}
+	fakeIG.mu.Unlock()
+	return fakeIG.FakeInstanceGroups.AddInstancesToInstanceGroup(name, zone, instanceRefs)
+}
+
+func TestSyncAddsNodesInChunks(t *testing.T) {
+	nodes := []string{"n1", "n2", "n3", "n4", "n5"}
+	for _, tc := range []struct {
+		desc          string
+		failures      map[string]int
+		wantAddCalls  [][]string
+		wantInstances sets.String
+		wantErr       bool
+	}{
+		{
+			desc:          "all chunks added",
+			wantAddCalls:  [][]string{{"n1", "n2"}, {"n3", "n4"}, {"n5"}},
+			wantInstances: sets.NewString(nodes...),
+		},
+		{
+			desc:          "only failed chunk retried",
+			failures:      map[string]int{"n3": 1},
+			wantAddCalls:  [][]string{{"n1", "n2"}, {"n3", "n4"}, {"n5"}, {"n3", "n4"}},
+			wantInstances: sets.NewString(nodes...),
+		},
+		{
+			desc:          "chunk fails all retries",
+			failures:      map[string]int{"n5": maxAddChunkRetries + 1},
+			wantAddCalls:  [][]string{{"n1", "n2"}, {"n3", "n4"}, {"n5"}, {"n5"}, {"n5"}},
+			wantInstances: sets.NewString("n1", "n2", "n3", "n4"),
+			wantErr:       true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ig := &compute.InstanceGroup{Name: defaultNamer.InstanceGroup()}
+			fakeIGs := &fakeIGAddFailures{
+				FakeInstanceGroups: NewFakeInstanceGroups(map[string]IGsToInstances{testZoneA: {ig: sets.NewString()}}, 1000),
+				failures:           tc.failures,
+			}
+			pool := newNodePool(fakeIGs, 1000).(*manager)
+			pool.addInstancesBatchSize = 2
+			zonegetter.AddFakeNodes(pool.ZoneGetter, testZoneA, nodes...)
+
+			err := pool.Sync(nodes, klog.TODO())
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("pool.Sync() returned error %v, want error %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(fakeIGs.addCalls, tc.wantAddCalls) {
+				t.Errorf("pool.Sync() added chunks %v, want %v", fakeIGs.addCalls, tc.wantAddCalls)
+			}
+			if got := fakeIGs.zonesToIGsToInstances[testZoneA][ig]; !got.Equal(tc.wantInstances) {
+				t.Errorf("Instances of %s = %v, want %v", ig.Name, got.List(), tc.wantInstances.List())
+			}
+		})
+	}
+}
+
+func TestSyncZonesConcurrently(t *testing.T) {
+	zoneNodes := map[string][]string{
+		testZoneA:       {"a1", "a2"},
+		testZoneB:       {"b1"},
+		testZoneC:       {"c1", "c2", "c3"},
+		defaultTestZone: {"d1"},
+	}
+	igs := map[string]*compute.InstanceGroup{}
+	zonesToIGs := map[string]IGsToInstances{}
+	for zone := range zoneNodes {
+		igs[zone] = &compute.InstanceGroup{Name: defaultNamer.InstanceGroup()}
+		zonesToIGs[zone] = IGsToInstances{igs[zone]: sets.NewString()}
+	}
+	fakeIGs := &fakeIGAddFailures{
+		FakeInstanceGroups: NewFakeInstanceGroups(zonesToIGs, 1000),
+		// The failure of a zone does not prevent the other zones from syncing.
+		failures: map[string]int{"b1": maxAddChunkRetries + 1},
+	}
+	pool := newNodePool(fakeIGs, 1000).(*manager)
+	pool.maxConcurrentZoneSyncs = 2
+	var allNodes []string
+	for zone, nodes := range zoneNodes {
+		zonegetter.AddFakeNodes(pool.ZoneGetter, zone, nodes...)
+		allNodes = append(allNodes, nodes...)
+	}
+
+	if err := pool.Sync(allNodes, klog.TODO()); err == nil {
+		t.Errorf("pool.Sync() returned nil, want error of zone %s", testZoneB)
+	}
+	for zone, nodes := range zoneNodes {
+		want := sets.NewString(nodes...)
+		if zone == testZoneB {
+			want = sets.NewString()
+		}
+		if got := fakeIGs.zonesToIGsToInstances[zone][igs[zone]]; !got.Equal(want) {
+			t.Errorf("Instances of instance group in zone %s = %v, want %v", zone, got.List(), want.List())
+		}
+	}
+}
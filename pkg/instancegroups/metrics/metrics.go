@@ -17,6 +17,8 @@ limitations under the License.
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/klog/v2"
 )
@@ -24,6 +26,9 @@ import (
 const (
 	AddOperationTypeLabel    = "Add"
 	RemoveOperationTypeLabel = "Remove"
+
+	resultSuccess = "success"
+	resultError   = "error"
 )
 
 var (
@@ -42,6 +47,18 @@ var (
 		},
 		[]string{"operation_type"},
 	)
+	instanceGroupZoneSyncLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "instance_group_zone_sync_duration_seconds",
+			Help: "Latency of syncing the nodes of a zone with its instance group",
+			// custom buckets - [0.5s, 1s, 2s, 4s, 8s, 16s, 32s, 64s, 128s, 256s(~4min), 512s(~8min), +Inf]
+			Buckets: prometheus.ExponentialBuckets(0.5, 2, 11),
+		},
+		[]string{
+			"zone",   // zone of the instance group
+			"result", // result of the sync
+		},
+	)
 )
 
 // init metrics.
@@ -50,6 +67,8 @@ func init() {
 	prometheus.MustRegister(instanceGroupEventSize)
 	klog.V(3).Infof("Registering Instance Group event count metric: %v", instanceGroupEventCount)
 	prometheus.MustRegister(instanceGroupEventCount)
+	klog.V(3).Infof("Registering Instance Group zone sync latency metric: %v", instanceGroupZoneSyncLatency)
+	prometheus.MustRegister(instanceGroupZoneSyncLatency)
 }
 
 // PublishInstanceGroupAdd counts how many times with attempt to add nodes to an instance group and the number of nodes present in each attempt.
@@ -63,3 +82,12 @@ func PublishInstanceGroupRemove(count int) {
 	instanceGroupEventSize.WithLabelValues(RemoveOperationTypeLabel).Observe((float64(count)))
 	instanceGroupEventCount.WithLabelValues(RemoveOperationTypeLabel).Inc()
 }
+
+// PublishInstanceGroupZoneSync publishes the latency of syncing the instance group of a zone.
+func PublishInstanceGroupZoneSync(zone string, err error, start time.Time) {
+	result := resultSuccess
+	if err != nil {
+		result = resultError
+	}
+	instanceGroupZoneSyncLatency.WithLabelValues(zone, result).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/api/compute/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/ingress-gce/pkg/events"
+	metrics "k8s.io/ingress-gce/pkg/instancegroups/metrics"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/zonegetter"
+	"k8s.io/klog/v2"
+)
+
+// usesShards returns true if the nodes of a zone are split between multiple
+// shards of the instance group with the given name. Only the instance group
+// of the controller is sharded.
+func (m *manager) usesShards(name string) bool {
+	return m.enableMultipleIGs && name == m.namer.InstanceGroup()
+}
+
+// numShards returns the number of instance groups needed for the given
+// number of nodes of a zone.
+func (m *manager) numShards(nodeCount int) int {
+	if m.maxIGSize <= 0 || nodeCount <= m.maxIGSize {
+		return 1
+	}
+	return (nodeCount + m.maxIGSize - 1) / m.maxIGSize
+}
+
+// ensureInstanceGroupShardsAndPorts creates the shards of the instance group
+// of the controller needed for the nodes of each zone, and adds the given
+// ports to them. Shards which are no longer needed are kept, they are emptied
+// and deleted by Sync.
+func (m *manager) ensureInstanceGroupShardsAndPorts(ports []int64, logger klog.Logger) ([]*compute.InstanceGroup, error) {
+	zones, err := m.ZoneGetter.ListZones(zonegetter.AllNodesFilter, logger)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := m.ZoneGetter.ListNodes(zonegetter.AllNodesFilter, logger)
+	if err != nil {
+		return nil, err
+	}
+	zonedNodes := m.splitNodesByZone(utils.GetNodeNames(nodes), logger)
+
+	var igs []*compute.InstanceGroup
+	for _, zone := range zones {
+		for shard := 0; shard < m.numShards(len(zonedNodes[zone])); shard++ {
+			ig, err := m.ensureInstanceGroupAndPorts(m.namer.InstanceGroupShard(shard), zone, ports, logger)
+			if err != nil {
+				return nil, err
+			}
+			igs = append(igs, ig)
+		}
+	}
+	return igs, nil
+}
+
+// listShards returns the names of the existing shards of the instance group
+// of the controller in the zone, ordered by index. Shards are always created
+// in order, so the listing stops at the first missing index.
+func (m *manager) listShards(zone string) ([]string, error) {
+	igs, err := m.cloud.ListInstanceGroups(zone)
+	if err != nil {
+		return nil, err
+	}
+	names := sets.NewString()
+	for _, ig := range igs {
+		names.Insert(ig.Name)
+	}
+
+	var shards []string
+	for shard := 0; names.Has(m.namer.InstanceGroupShard(shard)); shard++ {
+		shards = append(shards, m.namer.InstanceGroupShard(shard))
+	}
+	return shards, nil
+}
+
+// backendShards returns the shards of the zone which are backends of the load
+// balancers: the shards needed for the nodes of the zone, and the shards which
+// are no longer needed but still have instances. Once Sync has moved their
+// nodes to the other shards, surplus shards are unlinked from the backend
+// services, so that Sync can delete them.
+func (m *manager) backendShards(zone string, logger klog.Logger) ([]string, error) {
+	shards, err := m.listShards(zone)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := m.ZoneGetter.ListNodes(zonegetter.AllNodesFilter, logger)
+	if err != nil {
+		return nil, err
+	}
+	numShards := m.numShards(len(m.splitNodesByZone(utils.GetNodeNames(nodes), logger)[zone]))
+	if numShards >= len(shards) {
+		return shards, nil
+	}
+
+	backends := shards[:numShards]
+	for _, igName := range shards[numShards:] {
+		instances, err := m.cloud.ListInstancesInInstanceGroup(igName, zone, allInstances)
+		if err != nil {
+			return nil, err
+		}
+		if len(instances) != 0 {
+			backends = append(backends, igName)
+		}
+	}
+	return backends, nil
+}
+
+// syncZoneShards syncs the instances of the shards of the instance group of
+// the zone with the given nodes of the zone. Nodes stay in their shard as long
+// as it is needed and not full, other nodes are assigned to the shards with
+// the least nodes. Shards which are no longer needed are deleted once empty.
+func (m *manager) syncZoneShards(zone string, kubeNodesFromZone []string, logger klog.Logger) error {
+	shards, err := m.listShards(zone)
+	if err != nil {
+		logger.Error(err, "Failed to list instance groups", "zone", zone)
+		return err
+	}
+	if len(shards) == 0 {
+		// Same as a single instance group not found, wait for
+		// EnsureInstanceGroupsAndPorts to create it.
+		logger.Info("Instance group not found in zone, skipping", "zone", zone, "igName", m.namer.InstanceGroup())
+		return nil
+	}
+
+	members := make([]sets.String, len(shards))
+	for i, igName := range shards {
+		instances, err := m.cloud.ListInstancesInInstanceGroup(igName, zone, allInstances)
+		if err != nil {
+			logger.Error(err, "Failed to list instance from instance group", "zone", zone, "igName", igName)
+			return err
+		}
+		members[i] = sets.NewString()
+		for _, ins := range instances {
+			instance, err := utils.KeyName(ins.Instance)
+			if err != nil {
+				logger.Error(err, "Failed to read instance name from ULR, skipping single instance", "Instance URL", ins.Instance)
+				continue
+			}
+			members[i].Insert(instance)
+		}
+	}
+
+	numShards := m.numShards(len(kubeNodesFromZone))
+	if numShards > len(shards) {
+		// Missing shards are created by EnsureInstanceGroupsAndPorts, the
+		// nodes which don't fit in the existing ones are added afterwards.
+		logger.Info("Not all instance groups of zone exist yet", "zone", zone, "wantCount", numShards, "count", len(shards))
+		numShards = len(shards)
+	}
+	assignment, truncated := assignNodesToShards(kubeNodesFromZone, members, numShards, m.maxIGSize)
+	if len(truncated) != 0 {
+		logger.Info(fmt.Sprintf("Total number of kubeNodes: %d, truncating to maximum size of %d Instance Groups = %d. zone: %s. First truncated instances: %v", len(kubeNodesFromZone), numShards, m.maxIGSize, zone, events.TruncatedStringList(truncated)))
+	}
+
+	// An instance can only be a member of a single load balanced instance
+	// group, so nodes moved between shards are removed from their previous
+	// shard before being added to the new one. They are not backends of the
+	// load balancers in between.
+	for i, igName := range shards {
+		want := sets.NewString()
+		if i < numShards {
+			want = assignment[i]
+		}
+		if err := m.removeFromShard(igName, members[i].Difference(want).List(), zone, logger); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < numShards; i++ {
+		addNodes := assignment[i].Difference(members[i]).List()
+		if len(addNodes) == 0 {
+			continue
+		}
+		start := time.Now()
+		metrics.PublishInstanceGroupAdd(len(addNodes))
+		err = m.addInChunks(shards[i], addNodes, zone, logger)
+		logger.V(2).Info("Add finished", "name", shards[i], "zone", zone, "err", err, "timeTaken", time.Since(start), "addNodes", events.TruncatedStringList(addNodes))
+		if err != nil {
+			return err
+		}
+	}
+	return m.deleteSurplusShards(shards[numShards:], zone, logger)
+}
+
+// removeFromShard removes the given nodes from the shard.
+func (m *manager) removeFromShard(igName string, removeNodes []string, zone string, logger klog.Logger) error {
+	if len(removeNodes) == 0 {
+		return nil
+	}
+	start := time.Now()
+	metrics.PublishInstanceGroupRemove(len(removeNodes))
+	err := m.remove(igName, removeNodes, zone, logger)
+	logger.V(2).Info("Remove finished", "name", igName, "zone", zone, "err", err, "timeTaken", time.Since(start), "removeNodes", events.TruncatedStringList(removeNodes))
+	return err
+}
+
+// deleteSurplusShards deletes the given shards, which are no longer needed,
+// starting from the last one so that the remaining shards keep consecutive
+// indexes. Shards still used by backend services are kept until they are
+// unlinked.
+func (m *manager) deleteSurplusShards(shards []string, zone string, logger klog.Logger) error {
+	for i := len(shards) - 1; i >= 0; i-- {
+		igName := shards[i]
+		err := m.cloud.DeleteInstanceGroup(igName, zone)
+		switch {
+		case err == nil:
+			logger.V(2).Info("Deleted instance group which is no longer needed", "name", igName, "zone", zone)
+		case utils.IsNotFoundError(err):
+			logger.V(3).Info("Instance group in zone did not exist", "name", igName, "zone", zone)
+		case utils.IsInUsedByError(err):
+			logger.V(2).Info("Could not delete instance group which is no longer needed because it's still in use, will retry", "name", igName, "zone", zone, "err", err)
+			return nil
+		default:
+			logger.Error(err, "Failed to delete instance group which is no longer needed", "name", igName, "zone", zone)
+			return err
+		}
+	}
+	return nil
+}
+
+// assignNodesToShards assigns the nodes to numShards shards of at most
+// maxSize nodes, given the current members of the shards. Nodes keep their
+// current shard if it is one of the numShards first ones and is not full, the
+// other nodes are assigned in alphabetical order to the shard with the least
+// nodes. It returns the nodes of each shard and the nodes which didn't fit.
+func assignNodesToShards(nodes []string, members []sets.String, numShards, maxSize int) ([]sets.String, []string) {
+	assignment := make([]sets.String, numShards)
+	for i := range assignment {
+		assignment[i] = sets.NewString()
+	}
+
+	var unassigned []string
+	for _, node := range sets.NewString(nodes...).List() {
+		shard := -1
+		for i := 0; i < numShards && i < len(members); i++ {
+			if members[i].Has(node) {
+				shard = i
+				break
+			}
+		}
+		if shard == -1 || assignment[shard].Len() >= maxSize {
+			unassigned = append(unassigned, node)
+			continue
+		}
+		assignment[shard].Insert(node)
+	}
+
+	for i, node := range unassigned {
+		smallest := 0
+		for shard := 1; shard < numShards; shard++ {
+			if assignment[shard].Len() < assignment[smallest].Len() {
+				smallest = shard
+			}
+		}
+		if assignment[smallest].Len() >= maxSize {
+			return assignment, unassigned[i:]
+		}
+		assignment[smallest].Insert(node)
+	}
+	return assignment, nil
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/ingress-gce/pkg/test"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/zonegetter"
+	"k8s.io/klog/v2"
+)
+
+func TestAssignNodesToShards(t *testing.T) {
+	for _, tc := range []struct {
+		desc          string
+		nodes         []string
+		members       []sets.String
+		numShards     int
+		want          []sets.String
+		wantTruncated []string
+	}{
+		{
+			desc:      "empty shards",
+			nodes:     []string{"n1", "n2", "n3", "n4", "n5"},
+			members:   []sets.String{sets.NewString(), sets.NewString(), sets.NewString()},
+			numShards: 3,
+			want:      []sets.String{sets.NewString("n1", "n4"), sets.NewString("n2", "n5"), sets.NewString("n3")},
+		},
+		{
+			desc:      "nodes keep their shard",
+			nodes:     []string{"n1", "n2", "n3", "n4"},
+			members:   []sets.String{sets.NewString("n3", "n4"), sets.NewString("n1")},
+			numShards: 2,
+			want:      []sets.String{sets.NewString("n3", "n4"), sets.NewString("n1", "n2")},
+		},
+		{
+			desc:      "new nodes go to the new shard",
+			nodes:     []string{"n1", "n2", "n3", "n4", "n5"},
+			members:   []sets.String{sets.NewString("n1", "n2"), sets.NewString("n3", "n4")},
+			numShards: 3,
+			want:      []sets.String{sets.NewString("n1", "n2"), sets.NewString("n3", "n4"), sets.NewString("n5")},
+		},
+		{
+			desc:      "nodes of unused shards are rebalanced",
+			nodes:     []string{"n1", "n3", "n5"},
+			members:   []sets.String{sets.NewString("n1"), sets.NewString("n3"), sets.NewString("n5")},
+			numShards: 2,
+			want:      []sets.String{sets.NewString("n1", "n5"), sets.NewString("n3")},
+		},
+		{
+			desc:      "deleted nodes are removed",
+			nodes:     []string{"n2"},
+			members:   []sets.String{sets.NewString("n1", "n2")},
+			numShards: 1,
+			want:      []sets.String{sets.NewString("n2")},
+		},
+		{
+			desc:          "nodes above capacity are truncated",
+			nodes:         []string{"n1", "n2", "n3", "n4"},
+			members:       []sets.String{sets.NewString("n4")},
+			numShards:     1,
+			want:          []sets.String{sets.NewString("n4", "n1")},
+			wantTruncated: []string{"n2", "n3"},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotTruncated := assignNodesToShards(tc.nodes, tc.members, tc.numShards, 2)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("assignNodesToShards() = %v, want %v", got, tc.want)
+			}
+			if !reflect.DeepEqual(gotTruncated, tc.wantTruncated) {
+				t.Errorf("assignNodesToShards() truncated %v, want %v", gotTruncated, tc.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestMultipleInstanceGroups(t *testing.T) {
+	maxIGSize := 2
+	fakeIGs := NewFakeInstanceGroups(map[string]IGsToInstances{}, maxIGSize)
+	pool := newNodePool(fakeIGs, maxIGSize).(*manager)
+	pool.enableMultipleIGs = true
+	igName := defaultNamer.InstanceGroup()
+	shardNames := []string{igName, defaultNamer.InstanceGroupShard(1), defaultNamer.InstanceGroupShard(2)}
+
+	nodes := []string{"n1", "n2", "n3", "n4", "n5"}
+	zonegetter.AddFakeNodes(pool.ZoneGetter, testZoneA, nodes...)
+	igs, err := pool.EnsureInstanceGroupsAndPorts(igName, []int64{80}, klog.TODO())
+	if err != nil {
+		t.Fatalf("pool.EnsureInstanceGroupsAndPorts() returned error %v, want nil", err)
+	}
+	if len(igs) != len(shardNames) {
+		t.Errorf("pool.EnsureInstanceGroupsAndPorts() returned %d instance groups, want %d", len(igs), len(shardNames))
+	}
+	got, err := pool.BackendInstanceGroups(igName, testZoneA, klog.TODO())
+	if err != nil {
+		t.Fatalf("pool.BackendInstanceGroups() returned error %v, want nil", err)
+	}
+	if !reflect.DeepEqual(got, shardNames) {
+		t.Errorf("pool.BackendInstanceGroups() = %v, want %v", got, shardNames)
+	}
+
+	shardMembers := func(shardNames []string) []sets.String {
+		var members []sets.String
+		for _, name := range shardNames {
+			instances, err := fakeIGs.ListInstancesInInstanceGroup(name, testZoneA, allInstances)
+			if err != nil {
+				t.Fatalf("ListInstancesInInstanceGroup(%s) returned error %v", name, err)
+			}
+			names := sets.NewString()
+			for _, instance := range instances {
+				name, err := utils.KeyName(instance.Instance)
+				if err != nil {
+					t.Fatalf("KeyName(%s) returned error %v", instance.Instance, err)
+				}
+				names.Insert(name)
+			}
+			members = append(members, names)
+		}
+		return members
+	}
+
+	if err := pool.Sync(nodes, klog.TODO()); err != nil {
+		t.Fatalf("pool.Sync() returned error %v, want nil", err)
+	}
+	want := []sets.String{sets.NewString("n1", "n4"), sets.NewString("n2", "n5"), sets.NewString("n3")}
+	if got := shardMembers(shardNames); !reflect.DeepEqual(got, want) {
+		t.Errorf("Instances of shards after sync = %v, want %v", got, want)
+	}
+
+	// The new node fills the shard with a single node, the other nodes stay
+	// in their shard.
+	zonegetter.AddFakeNodes(pool.ZoneGetter, testZoneA, "n6")
+	nodes = append(nodes, "n6")
+	if err := pool.Sync(nodes, klog.TODO()); err != nil {
+		t.Fatalf("pool.Sync() returned error %v, want nil", err)
+	}
+	want = []sets.String{sets.NewString("n1", "n4"), sets.NewString("n2", "n5"), sets.NewString("n3", "n6")}
+	if got := shardMembers(shardNames); !reflect.DeepEqual(got, want) {
+		t.Errorf("Instances of shards after adding a node = %v, want %v", got, want)
+	}
+
+	// After a scale down, the shard which is no longer needed stays a backend
+	// until its nodes are moved to the first ones, and is then deleted.
+	nodes = []string{"n1", "n3", "n5"}
+	zonegetter.DeleteFakeNodesInZone(t, testZoneA, pool.ZoneGetter)
+	zonegetter.AddFakeNodes(pool.ZoneGetter, testZoneA, nodes...)
+	got, err = pool.BackendInstanceGroups(igName, testZoneA, klog.TODO())
+	if err != nil {
+		t.Fatalf("pool.BackendInstanceGroups() returned error %v, want nil", err)
+	}
+	if !reflect.DeepEqual(got, shardNames) {
+		t.Errorf("pool.BackendInstanceGroups() before scale down sync = %v, want %v", got, shardNames)
+	}
+	if err := pool.Sync(nodes, klog.TODO()); err != nil {
+		t.Fatalf("pool.Sync() returned error %v, want nil", err)
+	}
+	want = []sets.String{sets.NewString("n1", "n3"), sets.NewString("n5")}
+	if got := shardMembers(shardNames[:2]); !reflect.DeepEqual(got, want) {
+		t.Errorf("Instances of shards after scale down = %v, want %v", got, want)
+	}
+	if _, err := fakeIGs.GetInstanceGroup(shardNames[2], testZoneA); !utils.IsNotFoundError(err) {
+		t.Errorf("GetInstanceGroup(%s) after scale down returned error %v, want not found", shardNames[2], err)
+	}
+	got, err = pool.BackendInstanceGroups(igName, testZoneA, klog.TODO())
+	if err != nil {
+		t.Fatalf("pool.BackendInstanceGroups() returned error %v, want nil", err)
+	}
+	if !reflect.DeepEqual(got, shardNames[:2]) {
+		t.Errorf("pool.BackendInstanceGroups() after scale down = %v, want %v", got, shardNames[:2])
+	}
+
+	if err := pool.DeleteInstanceGroup(igName, klog.TODO()); err != nil {
+		t.Fatalf("pool.DeleteInstanceGroup() returned error %v, want nil", err)
+	}
+	if igs, _ := fakeIGs.ListInstanceGroups(testZoneA); len(igs) != 0 {
+		t.Errorf("pool.DeleteInstanceGroup() left instance groups %v, want none", igs)
+	}
+}
+
+func TestMultipleInstanceGroupsScaleDownToSingleInstanceGroup(t *testing.T) {
+	maxIGSize := 2
+	fakeIGs := NewFakeInstanceGroups(map[string]IGsToInstances{}, maxIGSize)
+	pool := newNodePool(fakeIGs, maxIGSize).(*manager)
+	pool.enableMultipleIGs = true
+	igName := defaultNamer.InstanceGroup()
+
+	nodes := []string{"n1", "n2", "n3"}
+	zonegetter.AddFakeNodes(pool.ZoneGetter, testZoneA, nodes...)
+	if _, err := pool.EnsureInstanceGroupsAndPorts(igName, []int64{80}, klog.TODO()); err != nil {
+		t.Fatalf("pool.EnsureInstanceGroupsAndPorts() returned error %v, want nil", err)
+	}
+	if err := pool.Sync(nodes, klog.TODO()); err != nil {
+		t.Fatalf("pool.Sync() returned error %v, want nil", err)
+	}
+
+	// n2 moves from the second shard, which is no longer needed, to the
+	// first one. It can't be a member of both shards at the same time.
+	nodes = []string{"n1", "n2"}
+	zonegetter.DeleteFakeNodesInZone(t, testZoneA, pool.ZoneGetter)
+	zonegetter.AddFakeNodes(pool.ZoneGetter, testZoneA, nodes...)
+	if err := pool.Sync(nodes, klog.TODO()); err != nil {
+		t.Fatalf("pool.Sync() returned error %v, want nil", err)
+	}
+	instances, err := fakeIGs.ListInstancesInInstanceGroup(igName, testZoneA, allInstances)
+	if err != nil {
+		t.Fatalf("ListInstancesInInstanceGroup(%s) returned error %v", igName, err)
+	}
+	got, err := test.InstancesListToNameSet(instances)
+	if err != nil {
+		t.Fatalf("InstancesListToNameSet() returned error %v", err)
+	}
+	if want := sets.NewString(nodes...); !got.Equal(want) {
+		t.Errorf("Instances of %s after scale down = %v, want %v", igName, got.List(), want.List())
+	}
+	if shards, err := pool.listShards(testZoneA); err != nil || !reflect.DeepEqual(shards, []string{igName}) {
+		t.Errorf("pool.listShards() = %v, %v, want %v, nil", shards, err, []string{igName})
+	}
+}
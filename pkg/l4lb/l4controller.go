@@ -226,8 +226,12 @@ func (l4c *L4Controller) shutdown() {
 // the subsetting controller will not process it. Processing it will fail forwarding rule creation with the same IP anyway.
 // This check prevents processing of v1-implemented services whose finalizer field got wiped out.
 func (l4c *L4Controller) shouldProcessService(service *v1.Service, svcLogger klog.Logger) bool {
-	// Ignore services with LoadBalancerClass set. LoadBalancerClass can't be updated (see the field API doc) so we don't need to worry about cleaning up services that changed the class.
+	// Ignore services with LoadBalancerClass set, unless it is the class handled by this controller. LoadBalancerClass can't be updated (see the field API doc) so we don't need to worry about cleaning up services that changed the class.
 	if service.Spec.LoadBalancerClass != nil {
+		if annotations.HasLoadBalancerClass(service, annotations.RegionalInternalLoadBalancerClass) {
+			// The legacy service controller does not handle services with a LoadBalancerClass, so ownership is unambiguous.
+			return true
+		}
 		svcLogger.Info("Ignoring service managed by another controller", "serviceLoadBalancerClass", *service.Spec.LoadBalancerClass)
 		return false
 	}
@@ -469,6 +473,11 @@ func (l4c *L4Controller) sync(key string, svcLogger klog.Logger) error {
 		}
 		l4c.serviceVersions.Delete(key)
 		l4c.publishMetrics(result, namespacedName, false, svcLogger)
+		if result.Error == nil {
+			if err := removeL4SyncConditions(l4c.ctx, svc, svcLogger); err != nil {
+				svcLogger.Error(err, "Failed to remove L4 conditions of service")
+			}
+		}
 		return skipUserError(result.Error, svcLogger)
 	}
 	// Check again here, to avoid time-of check, time-of-use race. A service queued by informer could have changed, no
@@ -480,8 +489,15 @@ func (l4c *L4Controller) sync(key string, svcLogger klog.Logger) error {
 			// result will be nil if the service was ignored(due to presence of service controller finalizer).
 			return nil
 		}
+		conditionsErr := updateL4SyncConditions(l4c.ctx, svc, result.Error, result.GCEResourceInError, l4c.enableDualStack, svcLogger)
+		if conditionsErr != nil {
+			svcLogger.Error(conditionsErr, "Failed to update L4 conditions of service")
+		}
 		l4c.publishMetrics(result, namespacedName, isResync, svcLogger)
 		l4c.serviceVersions.SetProcessed(key, svc.ResourceVersion, result.Error == nil, isResync, svcLogger)
+		if result.Error == nil && conditionsErr != nil {
+			return conditionsErr
+		}
 		return skipUserError(result.Error, svcLogger)
 	}
 	svcLogger.V(3).Info("Ignoring sync of service, neither delete nor ensure needed.")
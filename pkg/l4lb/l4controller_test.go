@@ -35,6 +35,7 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
 	api_v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -838,6 +839,26 @@ func TestProcessCreateServiceWithLoadBalancerClass(t *testing.T) {
 	verifyILBServiceNotProvisioned(t, svc)
 }
 
+func TestProcessCreateServiceWithGKELoadBalancerClass(t *testing.T) {
+	l4c := newServiceController(t, newFakeGCE())
+	newSvc := test.NewL4ILBService(false, 8080)
+	// The class selects the ILB path without the internal load balancer type annotation.
+	newSvc.Annotations = nil
+	lbClass := annotations.RegionalInternalLoadBalancerClass
+	newSvc.Spec.LoadBalancerClass = &lbClass
+	addILBService(l4c, newSvc)
+	addNEGAndSvcNegL4Controller(l4c, newSvc)
+	err := l4c.sync(getKeyForSvc(newSvc, t), klog.TODO())
+	if err != nil {
+		t.Errorf("Failed to sync newly added service %s, err %v", newSvc.Name, err)
+	}
+	svc, err := l4c.client.CoreV1().Services(newSvc.Namespace).Get(context2.TODO(), newSvc.Name, v1.GetOptions{})
+	if err != nil {
+		t.Errorf("Failed to lookup service %s, err: %v", newSvc.Name, err)
+	}
+	verifyILBServiceProvisioned(t, svc)
+}
+
 func newServiceController(t *testing.T, fakeGCE *gce.Cloud) *L4Controller {
 	kubeClient := fake.NewSimpleClientset()
 	svcNegClient := svcnegclient.NewSimpleClientset()
@@ -962,6 +983,9 @@ func verifyILBServiceProvisioned(t *testing.T, svc *api_v1.Service) {
 			t.Errorf("Ingress VIP not assigned to service")
 		}
 	}
+	if !apimeta.IsStatusConditionTrue(svc.Status.Conditions, LoadBalancerProvisionedConditionType) {
+		t.Errorf("Service condition %s is not true, got conditions %+v", LoadBalancerProvisionedConditionType, svc.Status.Conditions)
+	}
 
 	expectedAnnotationsKeys := calculateExpectedAnnotationsKeys(svc)
 	var missingKeys []string
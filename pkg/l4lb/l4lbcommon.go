@@ -19,12 +19,15 @@ package l4lb
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cloud-provider-gcp/providers/gce"
 	"k8s.io/cloud-provider/service/helpers"
+	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/composite"
 	"k8s.io/ingress-gce/pkg/context"
 	l4metrics "k8s.io/ingress-gce/pkg/l4lb/metrics"
@@ -35,6 +38,28 @@ import (
 	"k8s.io/klog/v2"
 )
 
+const (
+	// LoadBalancerProvisionedConditionType is the type of the Service condition that reports
+	// whether the last sync of the L4 load balancer succeeded.
+	LoadBalancerProvisionedConditionType = "LoadBalancerProvisioned"
+	// FirewallReadyConditionType is the type of the Service condition that reports
+	// whether the firewall rules of the L4 load balancer are provisioned.
+	FirewallReadyConditionType = "FirewallReady"
+	// HealthCheckReadyConditionType is the type of the Service condition that reports
+	// whether the health checks of the L4 load balancer are provisioned.
+	HealthCheckReadyConditionType = "HealthCheckReady"
+	// DualStackReadyConditionType is the type of the Service condition that reports
+	// whether both the IPv4 and IPv6 resources of a dual-stack L4 load balancer are provisioned.
+	DualStackReadyConditionType = "DualStackReady"
+
+	L4SyncSucceededReason = "SyncSucceeded"
+	L4SyncFailedReason    = "SyncFailed"
+	L4UserErrorReason     = "UserError"
+)
+
+// L4SyncConditionTypes are the types of the Service conditions set from the L4 load balancer sync results.
+var L4SyncConditionTypes = []string{LoadBalancerProvisionedConditionType, FirewallReadyConditionType, HealthCheckReadyConditionType, DualStackReadyConditionType}
+
 // computeNewAnnotationsIfNeeded checks if new annotations should be added to service.
 // If needed creates new service meta object.
 // This function is used by External and Internal L4 LB controllers.
@@ -108,6 +133,88 @@ func updateServiceStatus(ctx *context.ControllerContext, svc *v1.Service, newSta
 	return patch.PatchServiceLoadBalancerStatus(ctx.KubeClient.CoreV1(), svc, *newStatus)
 }
 
+// updateServiceConditions sets the given conditions in the service status, removes the conditions
+// of the given types, and patches the service if the conditions changed.
+func updateServiceConditions(ctx *context.ControllerContext, svc *v1.Service, conditionsToSet []metav1.Condition, typesToRemove []string, svcLogger klog.Logger) error {
+	conditions := append([]metav1.Condition(nil), svc.Status.Conditions...)
+	changed := false
+	for _, condition := range conditionsToSet {
+		changed = apimeta.SetStatusCondition(&conditions, condition) || changed
+	}
+	for _, conditionType := range typesToRemove {
+		changed = apimeta.RemoveStatusCondition(&conditions, conditionType) || changed
+	}
+	if !changed {
+		return nil
+	}
+	svcLogger.V(2).Info("Updating service conditions", "conditions", fmt.Sprintf("%+v", conditionsToSet), "removedConditionTypes", typesToRemove)
+	if err := patch.PatchServiceConditions(ctx.KubeClient.CoreV1(), svc, conditions); err != nil {
+		return err
+	}
+	// update current object conditions, so the following patches are computed against them
+	svc.Status.Conditions = conditions
+	return nil
+}
+
+// updateL4SyncConditions reports the result of an L4 load balancer sync in the service conditions.
+// A sync stops at the first resource in error, the conditions of the resources that were not
+// reached keep their previous value.
+func updateL4SyncConditions(ctx *context.ControllerContext, svc *v1.Service, syncErr error, resourceInError string, dualStackEnabled bool, svcLogger klog.Logger) error {
+	newCondition := func(conditionType, message string, err error) metav1.Condition {
+		condition := metav1.Condition{
+			Type:               conditionType,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: svc.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             L4SyncSucceededReason,
+			Message:            message,
+		}
+		if err != nil {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = L4SyncFailedReason
+			if utils.IsUserError(err) {
+				condition.Reason = L4UserErrorReason
+			}
+			condition.Message = err.Error()
+		}
+		return condition
+	}
+	isDualStack := dualStackEnabled && utils.NeedsIPv4(svc) && utils.NeedsIPv6(svc)
+
+	conditions := []metav1.Condition{newCondition(LoadBalancerProvisionedConditionType, "Load balancer resources are provisioned", syncErr)}
+	var typesToRemove []string
+	if syncErr == nil {
+		conditions = append(conditions,
+			newCondition(FirewallReadyConditionType, "Firewall rules are provisioned", nil),
+			newCondition(HealthCheckReadyConditionType, "Health checks are provisioned", nil))
+		if isDualStack {
+			conditions = append(conditions, newCondition(DualStackReadyConditionType, "IPv4 and IPv6 load balancer resources are provisioned", nil))
+		} else {
+			typesToRemove = append(typesToRemove, DualStackReadyConditionType)
+		}
+	} else {
+		switch resourceInError {
+		case annotations.FirewallRuleResource, annotations.FirewallRuleIPv6Resource, annotations.FirewallForHealthcheckResource, annotations.FirewallForHealthcheckIPv6Resource:
+			conditions = append(conditions, newCondition(FirewallReadyConditionType, "", syncErr))
+		case annotations.HealthcheckResource:
+			conditions = append(conditions, newCondition(HealthCheckReadyConditionType, "", syncErr))
+		}
+		if isDualStack && strings.HasSuffix(resourceInError, annotations.IPv6Suffix) {
+			conditions = append(conditions, newCondition(DualStackReadyConditionType, "", syncErr))
+		}
+	}
+	return updateServiceConditions(ctx, svc, conditions, typesToRemove, svcLogger)
+}
+
+// removeL4SyncConditions removes the conditions set by updateL4SyncConditions from a service
+// that is not a LoadBalancer service anymore.
+func removeL4SyncConditions(ctx *context.ControllerContext, svc *v1.Service, svcLogger klog.Logger) error {
+	if svc.DeletionTimestamp != nil || utils.IsLoadBalancerServiceType(svc) {
+		return nil
+	}
+	return updateServiceConditions(ctx, svc, nil, L4SyncConditionTypes, svcLogger)
+}
+
 // isHealthCheckDeleted checks if given health check exists in GCE
 func isHealthCheckDeleted(cloud *gce.Cloud, hcName string, logger klog.Logger) bool {
 	_, err := composite.GetHealthCheck(cloud, meta.GlobalKey(hcName), meta.VersionGA, logger)
@@ -1,12 +1,22 @@
 package l4lb
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/ingress-gce/pkg/annotations"
+	ingctx "k8s.io/ingress-gce/pkg/context"
+	"k8s.io/ingress-gce/pkg/loadbalancers"
+	"k8s.io/ingress-gce/pkg/utils"
 	"k8s.io/ingress-gce/pkg/utils/common"
+	"k8s.io/klog/v2"
 )
 
 func TestFinalizerWasRemovedUnexpectedly(t *testing.T) {
@@ -141,3 +151,138 @@ func TestFinalizerWasRemovedUnexpectedly(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeNewAnnotationsConsumesPreviousForwardingRuleRemoval(t *testing.T) {
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				annotations.MakeBeforeBreakAnnotationKey:              annotations.MakeBeforeBreakEnabled,
+				annotations.RemovePreviousForwardingRuleAnnotationKey: "true",
+				annotations.TCPForwardingRuleKey:                      "fr-alt",
+			},
+		},
+	}
+	newObjectMeta := computeNewAnnotationsIfNeeded(svc, map[string]string{annotations.TCPForwardingRuleKey: "fr-alt"}, loadbalancers.L4ResourceAnnotationKeys)
+	if newObjectMeta == nil {
+		t.Fatalf("computeNewAnnotationsIfNeeded() = nil, want annotations without %s", annotations.RemovePreviousForwardingRuleAnnotationKey)
+	}
+	want := map[string]string{
+		annotations.MakeBeforeBreakAnnotationKey: annotations.MakeBeforeBreakEnabled,
+		annotations.TCPForwardingRuleKey:         "fr-alt",
+	}
+	if diff := cmp.Diff(want, newObjectMeta.Annotations); diff != "" {
+		t.Errorf("computeNewAnnotationsIfNeeded() returned unexpected annotations (-want +got):\n%s", diff)
+	}
+}
+
+func TestUpdateL4SyncConditions(t *testing.T) {
+	t.Parallel()
+
+	previousConditions := []metav1.Condition{
+		{Type: FirewallReadyConditionType, Status: metav1.ConditionTrue, Reason: L4SyncSucceededReason},
+		{Type: HealthCheckReadyConditionType, Status: metav1.ConditionTrue, Reason: L4SyncSucceededReason},
+		{Type: DualStackReadyConditionType, Status: metav1.ConditionTrue, Reason: L4SyncSucceededReason},
+	}
+	syncErr := errors.New("sync failed")
+
+	testCases := []struct {
+		desc             string
+		ipFamilies       []v1.IPFamily
+		dualStackEnabled bool
+		syncErr          error
+		resourceInError  string
+		// wantConditions maps the condition type to its status and reason.
+		wantConditions map[string]string
+	}{
+		{
+			desc:       "successful sync of single stack service",
+			ipFamilies: []v1.IPFamily{v1.IPv4Protocol},
+			wantConditions: map[string]string{
+				LoadBalancerProvisionedConditionType: "True/" + L4SyncSucceededReason,
+				FirewallReadyConditionType:           "True/" + L4SyncSucceededReason,
+				HealthCheckReadyConditionType:        "True/" + L4SyncSucceededReason,
+			},
+		},
+		{
+			desc:             "successful sync of dual stack service",
+			ipFamilies:       []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol},
+			dualStackEnabled: true,
+			wantConditions: map[string]string{
+				LoadBalancerProvisionedConditionType: "True/" + L4SyncSucceededReason,
+				FirewallReadyConditionType:           "True/" + L4SyncSucceededReason,
+				HealthCheckReadyConditionType:        "True/" + L4SyncSucceededReason,
+				DualStackReadyConditionType:          "True/" + L4SyncSucceededReason,
+			},
+		},
+		{
+			desc:            "firewall error",
+			ipFamilies:      []v1.IPFamily{v1.IPv4Protocol},
+			syncErr:         syncErr,
+			resourceInError: annotations.FirewallRuleResource,
+			wantConditions: map[string]string{
+				LoadBalancerProvisionedConditionType: "False/" + L4SyncFailedReason,
+				FirewallReadyConditionType:           "False/" + L4SyncFailedReason,
+				HealthCheckReadyConditionType:        "True/" + L4SyncSucceededReason,
+				DualStackReadyConditionType:          "True/" + L4SyncSucceededReason,
+			},
+		},
+		{
+			desc:            "health check user error",
+			ipFamilies:      []v1.IPFamily{v1.IPv4Protocol},
+			syncErr:         utils.NewUserError(syncErr),
+			resourceInError: annotations.HealthcheckResource,
+			wantConditions: map[string]string{
+				LoadBalancerProvisionedConditionType: "False/" + L4UserErrorReason,
+				FirewallReadyConditionType:           "True/" + L4SyncSucceededReason,
+				HealthCheckReadyConditionType:        "False/" + L4UserErrorReason,
+				DualStackReadyConditionType:          "True/" + L4SyncSucceededReason,
+			},
+		},
+		{
+			desc:             "IPv6 forwarding rule error of dual stack service",
+			ipFamilies:       []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol},
+			dualStackEnabled: true,
+			syncErr:          syncErr,
+			resourceInError:  annotations.ForwardingRuleIPv6Resource,
+			wantConditions: map[string]string{
+				LoadBalancerProvisionedConditionType: "False/" + L4SyncFailedReason,
+				FirewallReadyConditionType:           "True/" + L4SyncSucceededReason,
+				HealthCheckReadyConditionType:        "True/" + L4SyncSucceededReason,
+				DualStackReadyConditionType:          "False/" + L4SyncFailedReason,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", Generation: 3},
+				Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, IPFamilies: tc.ipFamilies},
+				Status:     v1.ServiceStatus{Conditions: append([]metav1.Condition(nil), previousConditions...)},
+			}
+			kubeClient := fake.NewSimpleClientset(svc)
+			ctx := &ingctx.ControllerContext{KubeClient: kubeClient}
+
+			if err := updateL4SyncConditions(ctx, svc, tc.syncErr, tc.resourceInError, tc.dualStackEnabled, klog.TODO()); err != nil {
+				t.Fatalf("updateL4SyncConditions() returned error %v, want nil", err)
+			}
+			gotSvc, err := kubeClient.CoreV1().Services(svc.Namespace).Get(context.TODO(), svc.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Failed to get service: %v", err)
+			}
+			gotConditions := make(map[string]string)
+			for _, condition := range gotSvc.Status.Conditions {
+				gotConditions[condition.Type] = string(condition.Status) + "/" + condition.Reason
+			}
+			if diff := cmp.Diff(tc.wantConditions, gotConditions); diff != "" {
+				t.Errorf("updateL4SyncConditions() set unexpected conditions (-want +got):\n%s", diff)
+			}
+			if condition := apimeta.FindStatusCondition(gotSvc.Status.Conditions, LoadBalancerProvisionedConditionType); condition.ObservedGeneration != svc.Generation {
+				t.Errorf("%s condition has observed generation %d, want %d", LoadBalancerProvisionedConditionType, condition.ObservedGeneration, svc.Generation)
+			}
+		})
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/google/go-cmp/cmp"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
@@ -53,6 +54,16 @@ const (
 
 	instanceGroupLink backendLinkType = 0
 	negLink           backendLinkType = 1
+
+	// TargetPoolMigrationConditionType is the type of the Service condition that reports
+	// the progress of the migration of a target pool Service to backend services.
+	TargetPoolMigrationConditionType = "TargetPoolMigration"
+	// TargetPoolMigrationInProgressReason is set while the forwarding rule is cut over to the backend service.
+	TargetPoolMigrationInProgressReason = "MigrationInProgress"
+	// TargetPoolMigrationFailedReason is set when the last migration attempt failed, it is retried.
+	TargetPoolMigrationFailedReason = "MigrationFailed"
+	// TargetPoolMigrationCompletedReason is set once the target pool resources are deleted.
+	TargetPoolMigrationCompletedReason = "MigrationCompleted"
 )
 
 type backendLinkType int64
@@ -281,8 +292,8 @@ func (lc *L4NetLBController) needsUpdate(newSvc, oldSvc *v1.Service) bool {
 
 // shouldProcessService checks if given service should be process by controller
 func (lc *L4NetLBController) shouldProcessService(newSvc, oldSvc *v1.Service, svcLogger klog.Logger) (shouldProcess bool, isResync bool) {
-	// Ignore services with LoadBalancerClass set. LoadBalancerClass can't be updated (see the field API doc) so we don't need to worry about cleaning up services that changed the class.
-	if newSvc.Spec.LoadBalancerClass != nil {
+	// Ignore services with LoadBalancerClass set, unless it is the class handled by this controller. LoadBalancerClass can't be updated (see the field API doc) so we don't need to worry about cleaning up services that changed the class.
+	if newSvc.Spec.LoadBalancerClass != nil && !annotations.HasLoadBalancerClass(newSvc, annotations.RegionalExternalLoadBalancerClass) {
 		svcLogger.Info("Ignoring service managed by another controller", "serviceLoadBalancerClass", *newSvc.Spec.LoadBalancerClass)
 		return false, false
 	}
@@ -307,21 +318,34 @@ func (lc *L4NetLBController) hasForwardingRuleAnnotation(svc *v1.Service, frName
 	if val, ok := svc.Annotations[annotations.UDPForwardingRuleKey]; ok && val == frName {
 		return true
 	}
+	if val, ok := svc.Annotations[annotations.L3ForwardingRuleKey]; ok && val == frName {
+		return true
+	}
 	return false
 }
 
-// isRBSBasedService checks if service has either RBS annotation, finalizer or RBSForwardingRule
+// isRBSBasedService checks if service has either RBS LoadBalancerClass, RBS annotation, finalizer or RBSForwardingRule
 func (lc *L4NetLBController) isRBSBasedService(svc *v1.Service, svcLogger klog.Logger) bool {
 	// Check if the type=LoadBalancer, so we don't execute API calls o non-LB services
 	// this call is nil-safe
 	if !utils.IsLoadBalancerServiceType(svc) {
 		return false
 	}
-	return annotations.HasRBSAnnotation(svc) || utils.HasL4NetLBFinalizerV2(svc) || lc.hasRBSForwardingRule(svc, svcLogger)
+	return annotations.HasLoadBalancerClass(svc, annotations.RegionalExternalLoadBalancerClass) || annotations.HasRBSAnnotation(svc) || utils.HasL4NetLBFinalizerV2(svc) || lc.hasRBSForwardingRule(svc, svcLogger)
 }
 
 func (lc *L4NetLBController) preventLegacyServiceHandling(service *v1.Service, key string, svcLogger klog.Logger) (bool, error) {
+	// The legacy service controller does not handle services with a LoadBalancerClass,
+	// so there can be no target pool race or migration for them.
+	if annotations.HasLoadBalancerClass(service, annotations.RegionalExternalLoadBalancerClass) {
+		return false, nil
+	}
 	if annotations.HasRBSAnnotation(service) && lc.hasTargetPoolForwardingRule(service, svcLogger) {
+		if annotations.HasTargetPoolMigrationAnnotation(service) {
+			// The migration to backend services was requested explicitly,
+			// the target pool forwarding rule is replaced by syncInternal.
+			return false, nil
+		}
 		if utils.HasL4NetLBFinalizerV2(service) {
 			// If we found that RBS finalizer was attached to service, it means that RBS controller
 			// had a race condition on Service creation with Legacy Controller.
@@ -491,6 +515,11 @@ func (lc *L4NetLBController) sync(key string, svcLogger klog.Logger) error {
 		}
 		lc.serviceVersions.Delete(key)
 		lc.publishMetrics(result, svc.Name, svc.Namespace, false, svcLogger)
+		if result.Error == nil {
+			if err := removeL4SyncConditions(lc.ctx, svc, svcLogger); err != nil {
+				svcLogger.Error(err, "Failed to remove L4 conditions of service")
+			}
+		}
 		return result.Error
 	}
 
@@ -500,6 +529,10 @@ func (lc *L4NetLBController) sync(key string, svcLogger klog.Logger) error {
 			// result will be nil if the service was ignored(due to presence of service controller finalizer).
 			return nil
 		}
+		conditionsErr := updateL4SyncConditions(lc.ctx, svc, result.Error, result.GCEResourceInError, lc.enableDualStack, svcLogger)
+		if conditionsErr != nil {
+			svcLogger.Error(conditionsErr, "Failed to update L4 conditions of service")
+		}
 		lc.serviceVersions.SetProcessed(key, svc.ResourceVersion, result.Error == nil, isResync, svcLogger)
 		lc.publishMetrics(result, svc.Name, svc.Namespace, isResync, svcLogger)
 		svcLogger.V(3).Info("Resources modified in the sync", "modifiedResources", result.GCEResourceUpdate.String(), "wasResync", isResync)
@@ -508,6 +541,9 @@ func (lc *L4NetLBController) sync(key string, svcLogger klog.Logger) error {
 				svcLogger.V(3).Error(nil, "Resources were modified but this was not expected for a resync.", "modifiedResources", result.GCEResourceUpdate.String())
 			}
 		}
+		if result.Error == nil && conditionsErr != nil {
+			return conditionsErr
+		}
 		return result.Error
 	}
 	svcLogger.V(3).Info("Ignoring sync of service, neither delete nor ensure needed.")
@@ -560,12 +596,27 @@ func (lc *L4NetLBController) syncInternal(service *v1.Service, svcLogger klog.Lo
 		}
 	}
 
+	migratingTargetPool := annotations.HasTargetPoolMigrationAnnotation(service) && lc.hasTargetPoolForwardingRule(service, svcLogger)
+	if migratingTargetPool {
+		lc.ctx.Recorder(service.Namespace).Eventf(service, v1.EventTypeNormal, "TargetPoolMigrationStarted",
+			"Migrating target pool load balancer to backend service")
+		if err := lc.setTargetPoolMigrationCondition(service, metav1.ConditionFalse, TargetPoolMigrationInProgressReason,
+			"Replacing the target pool forwarding rule with a backend service forwarding rule", svcLogger); err != nil {
+			return &loadbalancers.L4NetLBSyncResult{Error: err}
+		}
+	}
+
 	// Use the same function for both create and updates. If controller crashes and restarts,
 	// all existing services will show up as Service Adds.
 	syncResult := l4netlb.EnsureFrontend(nodeNames, service)
 	if syncResult.Error != nil {
 		lc.ctx.Recorder(service.Namespace).Eventf(service, v1.EventTypeWarning, "SyncExternalLoadBalancerFailed",
 			"Error ensuring Resource for L4 External LoadBalancer, err: %v", syncResult.Error)
+		if migratingTargetPool {
+			if err := lc.setTargetPoolMigrationCondition(service, metav1.ConditionFalse, TargetPoolMigrationFailedReason, syncResult.Error.Error(), svcLogger); err != nil {
+				svcLogger.Error(err, "Failed to update target pool migration condition")
+			}
+		}
 		if utils.IsUserError(syncResult.Error) {
 			syncResult.MetricsLegacyState.IsUserError = true
 			syncResult.MetricsState.Status = metrics.StatusUserError
@@ -612,10 +663,50 @@ func (lc *L4NetLBController) syncInternal(service *v1.Service, svcLogger klog.Lo
 			return syncResult
 		}
 	}
+	if annotations.HasTargetPoolMigrationAnnotation(service) {
+		if err = lc.completeTargetPoolMigration(service, l4netlb, svcLogger); err != nil {
+			lc.ctx.Recorder(service.Namespace).Eventf(service, v1.EventTypeWarning, "TargetPoolMigrationFailed",
+				"Failed to complete migration of target pool load balancer to backend service, err: %v", err)
+			syncResult.Error = err
+			return syncResult
+		}
+	}
 	syncResult.SetMetricsForSuccessfulServiceSync()
 	return syncResult
 }
 
+// completeTargetPoolMigration deletes the target pool resources of a Service that was
+// migrated to backend services, and removes the migration annotation from the Service.
+func (lc *L4NetLBController) completeTargetPoolMigration(service *v1.Service, l4netlb *loadbalancers.L4NetLB, svcLogger klog.Logger) error {
+	if err := l4netlb.DeleteTargetPoolResources(); err != nil {
+		if condErr := lc.setTargetPoolMigrationCondition(service, metav1.ConditionFalse, TargetPoolMigrationFailedReason, err.Error(), svcLogger); condErr != nil {
+			svcLogger.Error(condErr, "Failed to update target pool migration condition")
+		}
+		return err
+	}
+	if err := lc.setTargetPoolMigrationCondition(service, metav1.ConditionTrue, TargetPoolMigrationCompletedReason,
+		"Target pool load balancer was migrated to backend service", svcLogger); err != nil {
+		return err
+	}
+	if err := deleteAnnotation(lc.ctx, service, annotations.TargetPoolMigrationAnnotationKey, svcLogger); err != nil {
+		return fmt.Errorf("deleteAnnotation(_, %v, %s) returned error %v, want nil", service, annotations.TargetPoolMigrationAnnotationKey, err)
+	}
+	lc.ctx.Recorder(service.Namespace).Eventf(service, v1.EventTypeNormal, "TargetPoolMigrationCompleted",
+		"Successfully migrated target pool load balancer to backend service")
+	return nil
+}
+
+func (lc *L4NetLBController) setTargetPoolMigrationCondition(service *v1.Service, status metav1.ConditionStatus, reason, message string, svcLogger klog.Logger) error {
+	return updateServiceConditions(lc.ctx, service, []metav1.Condition{{
+		Type:               TargetPoolMigrationConditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: service.Generation,
+		LastTransitionTime: metav1.Now(),
+	}}, nil, svcLogger)
+}
+
 func (lc *L4NetLBController) emitEnsuredDualStackEvent(service *v1.Service) {
 	var ipFamilies []string
 	for _, ipFamily := range service.Spec.IPFamilies {
@@ -37,6 +37,7 @@ import (
 	ga "google.golang.org/api/compute/v1"
 	"google.golang.org/api/googleapi"
 	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
@@ -333,6 +334,9 @@ func validateNetLBSvcStatus(svc *v1.Service, t *testing.T) {
 	if len(svc.Status.LoadBalancer.Ingress) == 0 || svc.Status.LoadBalancer.Ingress[0].IP != FwIPAddress {
 		t.Fatalf("Invalid LoadBalancer status field in service - %+v", svc.Status.LoadBalancer)
 	}
+	if !apimeta.IsStatusConditionTrue(svc.Status.Conditions, LoadBalancerProvisionedConditionType) {
+		t.Fatalf("Service condition %s is not true - %+v", LoadBalancerProvisionedConditionType, svc.Status.Conditions)
+	}
 }
 
 func calculateNetLBExpectedAnnotationsKeys(svc *v1.Service) []string {
@@ -1330,6 +1334,13 @@ func TestShouldProcessService(t *testing.T) {
 	testLBClass := "testLBClass"
 	svcWithLoadBalancerClass.Spec.LoadBalancerClass = &testLBClass
 
+	svcWithGKELoadBalancerClass, err := l4netController.ctx.KubeClient.CoreV1().Services(legacyNetLBSvc.Namespace).Get(context.TODO(), legacyNetLBSvc.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Errorf("Failed to lookup service %s, err: %v", legacyNetLBSvc.Name, err)
+	}
+	gkeLBClass := annotations.RegionalExternalLoadBalancerClass
+	svcWithGKELoadBalancerClass.Spec.LoadBalancerClass = &gkeLBClass
+
 	for _, testCase := range []struct {
 		oldSvc        *v1.Service
 		newSvc        *v1.Service
@@ -1361,6 +1372,11 @@ func TestShouldProcessService(t *testing.T) {
 			newSvc:        svcWithLoadBalancerClass,
 			shouldProcess: false,
 		},
+		{
+			oldSvc:        nil,
+			newSvc:        svcWithGKELoadBalancerClass,
+			shouldProcess: true,
+		},
 		{
 			// We do not support migration only by finalizer
 			oldSvc:        legacyNetLBSvc,
@@ -1491,6 +1507,95 @@ func TestDualStackServiceNeedsUpdate(t *testing.T) {
 	}
 }
 
+func TestTargetPoolToRBSMigration(t *testing.T) {
+	controller := newL4NetLBServiceController()
+	svc := test.NewL4NetLBRBSService(8080)
+	svc.Annotations[annotations.TargetPoolMigrationAnnotationKey] = annotations.TargetPoolMigrationEnabled
+	gceCloud := controller.ctx.Cloud
+	region := gceCloud.Region()
+
+	// Create the resources of the legacy target pool load balancer.
+	lbName := utils.LegacyForwardingRuleName(svc)
+	clusterID, _ := gceCloud.ClusterID.GetID()
+	legacyFirewalls := []string{gce.MakeFirewallName(lbName), gce.MakeHealthCheckFirewallName(clusterID, lbName, false)}
+	if err := gceCloud.CreateHTTPHealthCheck(&compute.HttpHealthCheck{Name: lbName}); err != nil {
+		t.Fatalf("CreateHTTPHealthCheck(%s) returned error %v, want nil", lbName, err)
+	}
+	if err := gceCloud.CreateTargetPool(&compute.TargetPool{Name: lbName}, region); err != nil {
+		t.Fatalf("CreateTargetPool(%s) returned error %v, want nil", lbName, err)
+	}
+	tp, err := gceCloud.GetTargetPool(lbName, region)
+	if err != nil {
+		t.Fatalf("GetTargetPool(%s) returned error %v, want nil", lbName, err)
+	}
+	for _, fwName := range legacyFirewalls {
+		if err := gceCloud.CreateFirewall(&compute.Firewall{Name: fwName}); err != nil {
+			t.Fatalf("CreateFirewall(%s) returned error %v, want nil", fwName, err)
+		}
+	}
+	legacyFR := &compute.ForwardingRule{
+		Name:                lbName,
+		IPAddress:           usersIP,
+		IPProtocol:          "TCP",
+		PortRange:           "8080-8080",
+		Target:              tp.SelfLink,
+		LoadBalancingScheme: string(cloud.SchemeExternal),
+		NetworkTier:         cloud.NetworkTierDefault.ToGCEValue(),
+	}
+	if err := gceCloud.CreateRegionForwardingRule(legacyFR, region); err != nil {
+		t.Fatalf("CreateRegionForwardingRule(%s) returned error %v, want nil", lbName, err)
+	}
+
+	addNetLBService(controller, svc)
+	key, _ := common.KeyFunc(svc)
+	if err := controller.sync(key, klog.TODO()); err != nil {
+		t.Fatalf("controller.sync(%s) returned error %v, want nil", key, err)
+	}
+
+	fr, err := gceCloud.GetRegionForwardingRule(lbName, region)
+	if err != nil {
+		t.Fatalf("GetRegionForwardingRule(%s) returned error %v, want nil", lbName, err)
+	}
+	if fr.Target != "" || fr.BackendService == "" {
+		t.Errorf("Forwarding rule %s points at target %q and backend service %q, want only a backend service", lbName, fr.Target, fr.BackendService)
+	}
+	if fr.IPAddress != usersIP {
+		t.Errorf("Forwarding rule %s has IP %s, want the IP of the target pool forwarding rule %s", lbName, fr.IPAddress, usersIP)
+	}
+	if _, err := gceCloud.GetTargetPool(lbName, region); !utils.IsNotFoundError(err) {
+		t.Errorf("GetTargetPool(%s) returned error %v, want not found", lbName, err)
+	}
+	if _, err := gceCloud.GetHTTPHealthCheck(lbName); !utils.IsNotFoundError(err) {
+		t.Errorf("GetHTTPHealthCheck(%s) returned error %v, want not found", lbName, err)
+	}
+	for _, fwName := range legacyFirewalls {
+		if _, err := gceCloud.GetFirewall(fwName); !utils.IsNotFoundError(err) {
+			t.Errorf("GetFirewall(%s) returned error %v, want not found", fwName, err)
+		}
+	}
+
+	svc, err = controller.ctx.KubeClient.CoreV1().Services(svc.Namespace).Get(context.TODO(), svc.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to lookup service %s, err: %v", svc.Name, err)
+	}
+	if annotations.HasTargetPoolMigrationAnnotation(svc) {
+		t.Errorf("Service still has the %s annotation after the migration", annotations.TargetPoolMigrationAnnotationKey)
+	}
+	if !annotations.HasRBSAnnotation(svc) || !utils.HasL4NetLBFinalizerV2(svc) {
+		t.Errorf("Migrated service has RBS annotation = %t and NetLB finalizer = %t, want both", annotations.HasRBSAnnotation(svc), utils.HasL4NetLBFinalizerV2(svc))
+	}
+	condition := apimeta.FindStatusCondition(svc.Status.Conditions, TargetPoolMigrationConditionType)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != TargetPoolMigrationCompletedReason {
+		t.Errorf("Service condition %s = %+v, want status %s with reason %s", TargetPoolMigrationConditionType, condition, metav1.ConditionTrue, TargetPoolMigrationCompletedReason)
+	}
+	if len(svc.Status.LoadBalancer.Ingress) != 1 || svc.Status.LoadBalancer.Ingress[0].IP != usersIP {
+		t.Errorf("Service LoadBalancer status = %+v, want IP %s", svc.Status.LoadBalancer, usersIP)
+	}
+	if err := checkBackendService(controller, svc); err != nil {
+		t.Errorf("Check backend service err: %v", err)
+	}
+}
+
 func TestPreventTargetPoolToRBSMigration(t *testing.T) {
 	testCases := []struct {
 		desc                            string
@@ -43,6 +43,7 @@ const (
 	l4LBControllerPanicsMetricName                 = "l4_controllers_panics_count"
 	L4netlbSyncDetailsMetricName                   = "l4_netlb_sync_details_count"
 	l4WeightedLBPodsPerNodeMetricName              = "l4_weighted_lb_pods_per_node"
+	l4OrphanedResourcesMetricName                  = "l4_orphaned_resources"
 )
 
 var (
@@ -182,6 +183,13 @@ var (
 		},
 		[]string{"controller_name", "success", "predicted_periodic_resync", "was_update"},
 	)
+	l4OrphanedResources = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: l4OrphanedResourcesMetricName,
+			Help: "Number of L4 load balancer resources found by the last orphan scan that no longer belong to a LoadBalancer Service",
+		},
+		[]string{"resource_type"},
+	)
 )
 
 // init registers l4 ilb and netlb sync metrics.
@@ -210,6 +218,8 @@ func init() {
 	prometheus.MustRegister(l4LBControllerPanics)
 	klog.V(3).Infof("Registering L4 controller sync details metric: %v", l4LBSyncDetails)
 	prometheus.MustRegister(l4LBSyncDetails)
+	klog.V(3).Infof("Registering L4 orphaned resources metric: %v", l4OrphanedResources)
+	prometheus.MustRegister(l4OrphanedResources)
 }
 
 // PublishILBSyncMetrics exports metrics related to the L4 ILB sync.
@@ -346,3 +356,8 @@ func PublishL4controllerLastSyncTime(controllerName string) {
 func PublishL4SyncDetails(controllerName string, success, isPredictedResync, wasUpdated bool) {
 	l4LBSyncDetails.WithLabelValues(controllerName, strconv.FormatBool(success), strconv.FormatBool(isPredictedResync), strconv.FormatBool(wasUpdated)).Inc()
 }
+
+// PublishL4OrphanedResources records the number of orphaned L4 resources of the given type found by the last orphan scan.
+func PublishL4OrphanedResources(resourceType string, count int) {
+	l4OrphanedResources.WithLabelValues(resourceType).Set(float64(count))
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package l4lb
+
+import (
+	context2 "context"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/context"
+	"k8s.io/ingress-gce/pkg/firewalls"
+	"k8s.io/ingress-gce/pkg/flags"
+	l4metrics "k8s.io/ingress-gce/pkg/l4lb/metrics"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
+)
+
+// orphanMinAge is the minimum age of a resource for it to be considered orphaned.
+// It protects resources of Services that are not in the informer cache yet.
+const orphanMinAge = 10 * time.Minute
+
+// L4OrphanScanner periodically looks for L4 LB resources of the cluster that no longer
+// belong to a LoadBalancer Service, and reports or deletes them.
+// The L4 controllers find the resources of a Service through its annotations, so the
+// resources leak if the annotations are removed before the Service is deleted.
+type L4OrphanScanner struct {
+	cloud         *gce.Cloud
+	namer         namer.L4ResourcesNamer
+	serviceLister cache.Indexer
+	// namespace is the namespace of the watched Services, all namespaces if empty.
+	namespace string
+	period    time.Duration
+	// dryRun reports orphaned resources without deleting them.
+	dryRun    bool
+	hasSynced func() bool
+	stopCh    <-chan struct{}
+	logger    klog.Logger
+}
+
+// l4Resource is a GCE resource following the L4 naming convention of the cluster.
+type l4Resource struct {
+	// resourceType is one of the resource names used in the L4 Service annotations.
+	resourceType string
+	key          *meta.Key
+	description  string
+	created      string
+}
+
+// NewL4OrphanScanner creates a new instance of the L4 orphan scanner.
+func NewL4OrphanScanner(ctx *context.ControllerContext, stopCh <-chan struct{}, logger klog.Logger) *L4OrphanScanner {
+	return &L4OrphanScanner{
+		cloud:         ctx.Cloud,
+		namer:         ctx.L4Namer,
+		serviceLister: ctx.ServiceInformer.GetIndexer(),
+		namespace:     ctx.Namespace,
+		period:        flags.F.L4OrphanScanPeriod,
+		dryRun:        flags.F.L4OrphanScanDryRun,
+		hasSynced:     ctx.HasSynced,
+		stopCh:        stopCh,
+		logger:        logger.WithName("L4OrphanScanner"),
+	}
+}
+
+// Run waits for the initial sync and scans for orphaned resources every scan period until signaled.
+func (s *L4OrphanScanner) Run() {
+	wait.PollUntil(5*time.Second, func() (bool, error) {
+		s.logger.V(2).Info("Waiting for initial sync")
+		return s.hasSynced(), nil
+	}, s.stopCh)
+
+	s.logger.V(2).Info("Starting L4 orphan scanner", "period", s.period, "dryRun", s.dryRun)
+	defer s.logger.V(2).Info("Shutting down L4 orphan scanner")
+
+	go func() {
+		// Wait a scan period before the first scan to let the controllers sync the Services.
+		timer := time.NewTimer(s.period)
+		defer timer.Stop()
+		select {
+		case <-s.stopCh:
+			return
+		case <-timer.C:
+		}
+		wait.Until(s.scan, s.period, s.stopCh)
+	}()
+
+	<-s.stopCh
+}
+
+// scan finds the orphaned resources, and deletes them unless running in dry-run mode.
+func (s *L4OrphanScanner) scan() {
+	s.logger.V(2).Info("Starting L4 orphan scan")
+	defer s.logger.V(2).Info("Finished L4 orphan scan")
+
+	orphans, err := s.orphanedResources()
+	if err != nil {
+		s.logger.Error(err, "Failed to scan for orphaned L4 resources")
+		return
+	}
+	for _, resourceType := range []string{annotations.ForwardingRuleResource, annotations.BackendServiceResource, annotations.HealthcheckResource, annotations.FirewallRuleResource} {
+		count := 0
+		for _, orphan := range orphans {
+			if orphan.resourceType == resourceType {
+				count++
+			}
+		}
+		l4metrics.PublishL4OrphanedResources(resourceType, count)
+	}
+
+	for _, orphan := range orphans {
+		orphanLogger := s.logger.WithValues("resourceType", orphan.resourceType, "resourceName", orphan.key.Name, "description", orphan.description)
+		if s.dryRun {
+			orphanLogger.Info("Found orphaned L4 resource, not deleting it in dry-run mode")
+			continue
+		}
+		orphanLogger.Info("Deleting orphaned L4 resource")
+		if err := s.deleteResource(orphan, orphanLogger); err != nil {
+			orphanLogger.Error(err, "Failed to delete orphaned L4 resource")
+		}
+	}
+}
+
+// orphanedResources returns the L4 resources of the cluster that do not belong to a LoadBalancer Service.
+// Resources are returned in deletion order, resources that use other resources first.
+func (s *L4OrphanScanner) orphanedResources() ([]l4Resource, error) {
+	resources, err := s.listL4Resources()
+	if err != nil {
+		return nil, err
+	}
+	var orphans []l4Resource
+	for _, resource := range resources {
+		if s.isOrphaned(resource) {
+			orphans = append(orphans, resource)
+		}
+	}
+	return orphans, nil
+}
+
+// isOrphaned returns true if the Service recorded in the description of the resource
+// does not exist or is no longer a LoadBalancer Service.
+// Resources shared by Services, and resources created recently are never orphaned.
+func (s *L4OrphanScanner) isOrphaned(resource l4Resource) bool {
+	var desc utils.L4LBResourceDescription
+	if err := desc.Unmarshal(resource.description); err != nil || desc.ServiceName == "" {
+		return false
+	}
+	if created, err := time.Parse(time.RFC3339, resource.created); err == nil && time.Since(created) < orphanMinAge {
+		return false
+	}
+	namespace, _, err := cache.SplitMetaNamespaceKey(desc.ServiceName)
+	if err != nil || (s.namespace != "" && namespace != s.namespace) {
+		return false
+	}
+	obj, exists, err := s.serviceLister.GetByKey(desc.ServiceName)
+	if err != nil {
+		return false
+	}
+	if !exists {
+		return true
+	}
+	svc, ok := obj.(*v1.Service)
+	return ok && svc.Spec.Type != v1.ServiceTypeLoadBalancer
+}
+
+// listL4Resources lists the forwarding rules, backend services, health checks and firewall rules
+// following the L4 naming convention of the cluster.
+func (s *L4OrphanScanner) listL4Resources() ([]l4Resource, error) {
+	var resources []l4Resource
+	region := s.cloud.Region()
+
+	frs, err := composite.ListForwardingRules(s.cloud, meta.RegionalKey("", region), meta.VersionGA, s.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list forwarding rules: %w", err)
+	}
+	for _, fr := range frs {
+		if s.namer.IsL4Resource(fr.Name) {
+			resources = append(resources, l4Resource{annotations.ForwardingRuleResource, meta.RegionalKey(fr.Name, region), fr.Description, fr.CreationTimestamp})
+		}
+	}
+
+	bss, err := composite.ListBackendServices(s.cloud, meta.RegionalKey("", region), meta.VersionGA, s.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backend services: %w", err)
+	}
+	for _, bs := range bss {
+		if s.namer.IsL4Resource(bs.Name) {
+			resources = append(resources, l4Resource{annotations.BackendServiceResource, meta.RegionalKey(bs.Name, region), bs.Description, bs.CreationTimestamp})
+		}
+	}
+
+	for _, key := range []*meta.Key{meta.GlobalKey(""), meta.RegionalKey("", region)} {
+		hcs, err := composite.ListHealthChecks(s.cloud, key, meta.VersionGA, s.logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list health checks: %w", err)
+		}
+		for _, hc := range hcs {
+			if s.namer.IsL4Resource(hc.Name) {
+				hcKey := meta.GlobalKey(hc.Name)
+				if key.Type() == meta.Regional {
+					hcKey = meta.RegionalKey(hc.Name, region)
+				}
+				resources = append(resources, l4Resource{annotations.HealthcheckResource, hcKey, hc.Description, hc.CreationTimestamp})
+			}
+		}
+	}
+
+	fws, err := s.cloud.Compute().Firewalls().List(context2.Background(), filter.None)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list firewall rules: %w", err)
+	}
+	for _, fw := range fws {
+		if s.namer.IsL4Resource(fw.Name) {
+			resources = append(resources, l4Resource{annotations.FirewallRuleResource, meta.GlobalKey(fw.Name), fw.Description, fw.CreationTimestamp})
+		}
+	}
+	return resources, nil
+}
+
+// deleteResource deletes the given orphaned resource, ignoring resources that are already deleted.
+func (s *L4OrphanScanner) deleteResource(resource l4Resource, logger klog.Logger) error {
+	var err error
+	switch resource.resourceType {
+	case annotations.ForwardingRuleResource:
+		err = composite.DeleteForwardingRule(s.cloud, resource.key, meta.VersionGA, logger)
+	case annotations.BackendServiceResource:
+		err = composite.DeleteBackendService(s.cloud, resource.key, meta.VersionGA, logger)
+	case annotations.HealthcheckResource:
+		err = composite.DeleteHealthCheck(s.cloud, resource.key, meta.VersionGA, logger)
+	case annotations.FirewallRuleResource:
+		return firewalls.EnsureL4FirewallRuleDeleted(s.cloud, resource.key.Name, logger)
+	default:
+		return fmt.Errorf("unknown resource type %q", resource.resourceType)
+	}
+	return utils.IgnoreHTTPNotFound(err)
+}
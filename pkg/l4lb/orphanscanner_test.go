@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package l4lb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/test"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// TestL4OrphanScanner verifies that the resources of deleted Services, and of Services that are
+// no longer LoadBalancer Services, are reported in dry-run mode and deleted otherwise.
+func TestL4OrphanScanner(t *testing.T) {
+	l4c := newServiceController(t, newFakeGCE())
+	orphanSvc := test.NewL4ILBService(false, 8080)
+	orphanSvc.Name = "orphan-svc"
+	liveSvc := test.NewL4ILBService(false, 8080)
+	liveSvc.Name = "live-svc"
+	for _, svc := range []*api_v1.Service{orphanSvc, liveSvc} {
+		addILBService(l4c, svc)
+		addNEGAndSvcNegL4Controller(l4c, svc)
+		if err := l4c.sync(getKeyForSvc(svc, t), klog.TODO()); err != nil {
+			t.Fatalf("Failed to sync service %s, err %v", svc.Name, err)
+		}
+	}
+	// The Service is removed without its resources being cleaned up, as if its annotations were lost.
+	deleteILBService(l4c, orphanSvc)
+
+	scanner := NewL4OrphanScanner(l4c.ctx, make(chan struct{}), klog.TODO())
+	scanner.dryRun = true
+	scanner.scan()
+	verifyL4ResourcesExist(t, l4c, orphanSvc, true)
+	verifyL4ResourcesExist(t, l4c, liveSvc, true)
+
+	scanner.dryRun = false
+	scanner.scan()
+	verifyL4ResourcesExist(t, l4c, orphanSvc, false)
+	verifyL4ResourcesExist(t, l4c, liveSvc, true)
+	sharedHCName := l4c.namer.L4HealthCheck(liveSvc.Namespace, liveSvc.Name, true)
+	if _, err := composite.GetHealthCheck(l4c.ctx.Cloud, meta.GlobalKey(sharedHCName), meta.VersionGA, klog.TODO()); err != nil {
+		t.Errorf("Shared health check %s should not be deleted, got error %v", sharedHCName, err)
+	}
+
+	// A Service changed to another type no longer owns load balancer resources.
+	liveSvc.Spec.Type = api_v1.ServiceTypeClusterIP
+	updateILBService(l4c, liveSvc)
+	scanner.scan()
+	verifyL4ResourcesExist(t, l4c, liveSvc, false)
+}
+
+func TestL4OrphanScannerIsOrphaned(t *testing.T) {
+	l4c := newServiceController(t, newFakeGCE())
+	scanner := NewL4OrphanScanner(l4c.ctx, make(chan struct{}), klog.TODO())
+	serviceDesc := func(svcName string) string {
+		desc, err := utils.MakeL4LBServiceDescription(svcName, "", meta.VersionGA, false, utils.ILB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return desc
+	}
+	sharedDesc, err := utils.MakeL4LBServiceDescription("", "", meta.VersionGA, true, utils.ILB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	liveSvc := test.NewL4ILBService(false, 8080)
+	addILBService(l4c, liveSvc)
+
+	for _, tc := range []struct {
+		desc     string
+		resource l4Resource
+		want     bool
+	}{
+		{
+			desc:     "deleted service",
+			resource: l4Resource{description: serviceDesc("default/deleted-svc")},
+			want:     true,
+		},
+		{
+			desc:     "live service",
+			resource: l4Resource{description: serviceDesc(liveSvc.Namespace + "/" + liveSvc.Name)},
+		},
+		{
+			desc:     "shared resource",
+			resource: l4Resource{description: sharedDesc},
+		},
+		{
+			desc:     "no description",
+			resource: l4Resource{},
+		},
+		{
+			desc:     "recently created resource",
+			resource: l4Resource{description: serviceDesc("default/deleted-svc"), created: time.Now().Format(time.RFC3339)},
+		},
+		{
+			desc:     "old resource",
+			resource: l4Resource{description: serviceDesc("default/deleted-svc"), created: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+			want:     true,
+		},
+	} {
+		if got := scanner.isOrphaned(tc.resource); got != tc.want {
+			t.Errorf("%s: isOrphaned() = %v, want %v", tc.desc, got, tc.want)
+		}
+	}
+}
+
+// verifyL4ResourcesExist checks that the forwarding rule, backend service and firewall rule
+// of the given ILB Service exist or are deleted.
+func verifyL4ResourcesExist(t *testing.T, l4c *L4Controller, svc *api_v1.Service, wantExist bool) {
+	t.Helper()
+	region := l4c.ctx.Cloud.Region()
+	frName := l4c.namer.L4ForwardingRule(svc.Namespace, svc.Name, "tcp")
+	_, frErr := composite.GetForwardingRule(l4c.ctx.Cloud, meta.RegionalKey(frName, region), meta.VersionGA, klog.TODO())
+	bsName := l4c.namer.L4Backend(svc.Namespace, svc.Name)
+	_, bsErr := composite.GetBackendService(l4c.ctx.Cloud, meta.RegionalKey(bsName, region), meta.VersionGA, klog.TODO())
+	fwName := l4c.namer.L4Firewall(svc.Namespace, svc.Name)
+	_, fwErr := l4c.ctx.Cloud.GetFirewall(fwName)
+
+	for name, err := range map[string]error{frName: frErr, bsName + " (backend service)": bsErr, fwName + " (firewall)": fwErr} {
+		if wantExist && err != nil {
+			t.Errorf("Resource %s of service %s should exist, got error %v", name, svc.Name, err)
+		}
+		if !wantExist && !utils.IsNotFoundError(err) {
+			t.Errorf("Resource %s of service %s should be deleted, got error %v", name, svc.Name, err)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package loadbalancers
 
 import (
 	"fmt"
+	"maps"
 	"net/http"
 	"strings"
 	"time"
@@ -211,12 +212,11 @@ func (l7 *L7) getEffectiveIP() (string, bool, error) {
 
 // ensureIPv4ForwardingRule creates a forwarding rule with the given name, if it does not exist. It updates the existing
 // forwarding rule if needed.
-func (l4 *L4) ensureIPv4ForwardingRule(bsLink string, options gce.ILBOptions, existingFwdRule *composite.ForwardingRule, subnetworkURL, ipToUse string) (*composite.ForwardingRule, error) {
+func (l4 *L4) ensureIPv4ForwardingRule(frName, bsLink string, options ilbOptions, existingFwdRule *composite.ForwardingRule, subnetworkURL, ipToUse string) (*composite.ForwardingRule, error) {
 	start := time.Now()
 
 	// version used for creating the existing forwarding rule.
 	version := meta.VersionGA
-	frName := l4.GetFRName()
 
 	frLogger := l4.svcLogger.WithValues("forwardingRuleName", frName)
 	frLogger.V(2).Info("Ensuring internal forwarding rule for L4 ILB Service", "backendServiceLink", bsLink)
@@ -226,7 +226,7 @@ func (l4 *L4) ensureIPv4ForwardingRule(bsLink string, options gce.ILBOptions, ex
 
 	servicePorts := l4.Service.Spec.Ports
 	ports := utils.GetPorts(servicePorts)
-	protocol := utils.GetProtocol(servicePorts)
+	protocol := utils.GetForwardingRuleProtocol(servicePorts)
 	// Create the forwarding rule
 	frDesc, err := utils.MakeL4LBServiceDescription(utils.ServiceKeyFunc(l4.Service.Namespace, l4.Service.Name), ipToUse,
 		version, false, utils.ILB)
@@ -239,7 +239,7 @@ func (l4 *L4) ensureIPv4ForwardingRule(bsLink string, options gce.ILBOptions, ex
 		Name:                frName,
 		IPAddress:           ipToUse,
 		Ports:               ports,
-		IPProtocol:          string(protocol),
+		IPProtocol:          protocol,
 		LoadBalancingScheme: string(cloud.SchemeInternal),
 		Subnetwork:          subnetworkURL,
 		Network:             l4.network.NetworkURL,
@@ -248,11 +248,22 @@ func (l4 *L4) ensureIPv4ForwardingRule(bsLink string, options gce.ILBOptions, ex
 		BackendService:      bsLink,
 		AllowGlobalAccess:   options.AllowGlobalAccess,
 		Description:         frDesc,
+		Labels:              options.Labels,
 	}
-	if len(ports) > maxForwardedPorts {
+	// L3_DEFAULT forwarding rules can only forward all ports.
+	if len(ports) > maxForwardedPorts || protocol == utils.L3DefaultProtocol {
 		newFwdRule.Ports = nil
 		newFwdRule.AllPorts = true
 	}
+	if options.ServiceDirectory != nil {
+		newFwdRule.ServiceDirectoryRegistrations = []*composite.ForwardingRuleServiceDirectoryRegistration{
+			{Namespace: options.ServiceDirectory.Namespace, Service: options.ServiceDirectory.Service},
+		}
+	}
+	if options.Labels == nil && existingFwdRule != nil {
+		// Labels are not managed, keep the existing ones if the forwarding rule is recreated.
+		newFwdRule.Labels = existingFwdRule.Labels
+	}
 
 	if existingFwdRule != nil {
 		equal, err := Equal(existingFwdRule, newFwdRule)
@@ -260,23 +271,33 @@ func (l4 *L4) ensureIPv4ForwardingRule(bsLink string, options gce.ILBOptions, ex
 			return nil, err
 		}
 		if equal {
-			// nothing to do
-			frLogger.V(2).Info("ensureIPv4ForwardingRule: Skipping update of unchanged forwarding rule")
-			return existingFwdRule, nil
-		}
-		frDiff := cmp.Diff(existingFwdRule, newFwdRule)
-		frLogger.V(2).Info("ensureIPv4ForwardingRule: forwarding rule changed.",
-			"existingForwardingRule", fmt.Sprintf("%+v", existingFwdRule), "newForwardingRule", fmt.Sprintf("%+v", newFwdRule), "diff", frDiff)
-
-		filtered, patchable := filterPatchableFields(existingFwdRule, newFwdRule)
-		if patchable {
-			if err = l4.forwardingRules.Patch(filtered); err != nil {
+			labelsUpdated, err := l4.ensureForwardingRuleLabels(existingFwdRule, newFwdRule, frLogger)
+			if err != nil {
 				return nil, err
 			}
-			l4.recorder.Eventf(l4.Service, corev1.EventTypeNormal, events.SyncIngress, "ForwardingRule %s patched", existingFwdRule.Name)
+			if !labelsUpdated {
+				// nothing to do
+				frLogger.V(2).Info("ensureIPv4ForwardingRule: Skipping update of unchanged forwarding rule")
+				return existingFwdRule, nil
+			}
 		} else {
-			if err := l4.updateForwardingRule(existingFwdRule, newFwdRule, frLogger); err != nil {
-				return nil, err
+			frDiff := cmp.Diff(existingFwdRule, newFwdRule)
+			frLogger.V(2).Info("ensureIPv4ForwardingRule: forwarding rule changed.",
+				"existingForwardingRule", fmt.Sprintf("%+v", existingFwdRule), "newForwardingRule", fmt.Sprintf("%+v", newFwdRule), "diff", frDiff)
+
+			filtered, patchable := filterPatchableFields(existingFwdRule, newFwdRule)
+			if patchable {
+				if err = l4.forwardingRules.Patch(filtered); err != nil {
+					return nil, err
+				}
+				l4.recorder.Eventf(l4.Service, corev1.EventTypeNormal, events.SyncIngress, "ForwardingRule %s patched", existingFwdRule.Name)
+				if _, err := l4.ensureForwardingRuleLabels(existingFwdRule, newFwdRule, frLogger); err != nil {
+					return nil, err
+				}
+			} else {
+				if err := l4.updateForwardingRule(existingFwdRule, newFwdRule, frLogger); err != nil {
+					return nil, err
+				}
 			}
 		}
 	} else {
@@ -296,6 +317,21 @@ func (l4 *L4) ensureIPv4ForwardingRule(bsLink string, options gce.ILBOptions, ex
 	return readFwdRule, nil
 }
 
+// ensureForwardingRuleLabels sets the labels of the existing forwarding rule to the labels
+// of the new one, if they differ. Labels can't be patched, they are only updated with SetLabels.
+// Returns true if the labels were updated.
+func (l4 *L4) ensureForwardingRuleLabels(existingFwdRule, newFwdRule *composite.ForwardingRule, frLogger klog.Logger) (bool, error) {
+	if maps.Equal(existingFwdRule.Labels, newFwdRule.Labels) {
+		return false, nil
+	}
+	frLogger.V(2).Info("ensureForwardingRuleLabels: updating forwarding rule labels", "existingLabels", existingFwdRule.Labels, "labels", newFwdRule.Labels)
+	if err := l4.forwardingRules.SetLabels(existingFwdRule.Name, newFwdRule.Labels, existingFwdRule.LabelFingerprint); err != nil {
+		return false, err
+	}
+	l4.recorder.Eventf(l4.Service, corev1.EventTypeNormal, events.SyncIngress, "ForwardingRule %s labels updated", existingFwdRule.Name)
+	return true, nil
+}
+
 func (l4 *L4) updateForwardingRule(existingFwdRule, newFr *composite.ForwardingRule, frLogger klog.Logger) error {
 	if err := l4.forwardingRules.Delete(existingFwdRule.Name); err != nil {
 		return err
@@ -322,8 +358,25 @@ func (l4 *L4) createFwdRule(newFr *composite.ForwardingRule, frLogger klog.Logge
 
 // ensureIPv4ForwardingRule creates a forwarding rule with the given name for L4NetLB,
 // if it does not exist. It updates the existing forwarding rule if needed.
-func (l4netlb *L4NetLB) ensureIPv4ForwardingRule(bsLink string) (*composite.ForwardingRule, IPAddressType, utils.ResourceSyncStatus, error) {
+// During a make-before-break recreation, it also returns the previous forwarding rule
+// that keeps serving traffic next to the returned one.
+func (l4netlb *L4NetLB) ensureIPv4ForwardingRule(bsLink string) (*composite.ForwardingRule, *composite.ForwardingRule, IPAddressType, utils.ResourceSyncStatus, error) {
+	netTier, isFromAnnotation := utils.GetNetworkTier(l4netlb.Service)
+	existingFwdRule, previousFwdRule, err := l4netlb.getIPv4ForwardingRules(netTier)
+	if err != nil {
+		l4netlb.svcLogger.Error(err, "l4netlb.getIPv4ForwardingRules returned error")
+		return nil, nil, IPAddrUndefined, utils.ResourceResync, err
+	}
 	frName := l4netlb.frName()
+	if existingFwdRule != nil {
+		frName = existingFwdRule.Name
+	}
+	if isFromAnnotation && l4netlb.shouldMakeBeforeBreak(existingFwdRule, previousFwdRule, netTier) {
+		// Create the replacement next to the existing forwarding rule, instead of recreating it.
+		l4netlb.svcLogger.V(2).Info("Network tier of forwarding rule changed, creating replacement forwarding rule", "previousForwardingRuleName", frName, "networkTier", netTier)
+		previousFwdRule, existingFwdRule = existingFwdRule, nil
+		frName = l4netlb.alternateIPv4FRName(frName)
+	}
 
 	start := time.Now()
 	frLogger := l4netlb.svcLogger.WithValues("forwardingRuleName", frName)
@@ -334,22 +387,16 @@ func (l4netlb *L4NetLB) ensureIPv4ForwardingRule(bsLink string) (*composite.Forw
 
 	// version used for creating the existing forwarding rule.
 	version := meta.VersionGA
-	existingFwdRule, err := l4netlb.forwardingRules.Get(frName)
-	if err != nil {
-		frLogger.Error(err, "l4netlb.forwardingRules.Get returned error")
-		return nil, IPAddrUndefined, utils.ResourceResync, err
-	}
 
 	// Determine IP which will be used for this LB. If no forwarding rule has been established
 	// or specified in the Service spec, then requestedIP = "".
 	ipToUse, err := ipv4AddrToUse(l4netlb.cloud, l4netlb.recorder, l4netlb.Service, existingFwdRule, "")
 	if err != nil {
 		frLogger.Error(err, "ipv4AddrToUse for service returned error")
-		return nil, IPAddrUndefined, utils.ResourceResync, err
+		return nil, nil, IPAddrUndefined, utils.ResourceResync, err
 	}
 	frLogger.V(2).Info("ensureIPv4ForwardingRule: Got LoadBalancer IP", "ip", ipToUse)
 
-	netTier, isFromAnnotation := utils.GetNetworkTier(l4netlb.Service)
 	var isIPManaged IPAddressType
 	// If the network is not a legacy network, use the address manager
 	if !l4netlb.cloud.IsLegacyNetwork() {
@@ -361,13 +408,13 @@ func (l4netlb *L4NetLB) ensureIPv4ForwardingRule(bsLink string) (*composite.Forw
 		// If they do not match, tear down the existing resources with the wrong tier.
 		if isFromAnnotation {
 			if err := l4netlb.tearDownResourcesWithWrongNetworkTier(existingFwdRule, netTier, addrMgr, frLogger); err != nil {
-				return nil, IPAddrUndefined, utils.ResourceResync, err
+				return nil, nil, IPAddrUndefined, utils.ResourceResync, err
 			}
 		}
 
 		ipToUse, isIPManaged, err = addrMgr.HoldAddress()
 		if err != nil {
-			return nil, IPAddrUndefined, utils.ResourceResync, err
+			return nil, nil, IPAddrUndefined, utils.ResourceResync, err
 		}
 		frLogger.V(2).Info("ensureIPv4ForwardingRule: reserved IP for the forwarding rule", "ip", ipToUse)
 		defer func() {
@@ -382,18 +429,18 @@ func (l4netlb *L4NetLB) ensureIPv4ForwardingRule(bsLink string) (*composite.Forw
 	svcPorts := l4netlb.Service.Spec.Ports
 	ports := utils.GetPorts(svcPorts)
 	portRange := utils.MinMaxPortRange(svcPorts)
-	protocol := utils.GetProtocol(svcPorts)
+	protocol := utils.GetForwardingRuleProtocol(svcPorts)
 	serviceKey := utils.ServiceKeyFunc(l4netlb.Service.Namespace, l4netlb.Service.Name)
 	frDesc, err := utils.MakeL4LBServiceDescription(serviceKey, ipToUse, version, false, utils.XLB)
 	if err != nil {
-		return nil, IPAddrUndefined, utils.ResourceResync, fmt.Errorf("Failed to compute description for forwarding rule %s, err: %w", frName,
+		return nil, nil, IPAddrUndefined, utils.ResourceResync, fmt.Errorf("Failed to compute description for forwarding rule %s, err: %w", frName,
 			err)
 	}
 	newFwdRule := &composite.ForwardingRule{
 		Name:                frName,
 		Description:         frDesc,
 		IPAddress:           ipToUse,
-		IPProtocol:          string(protocol),
+		IPProtocol:          protocol,
 		PortRange:           portRange,
 		LoadBalancingScheme: string(cloud.SchemeExternal),
 		BackendService:      bsLink,
@@ -403,21 +450,36 @@ func (l4netlb *L4NetLB) ensureIPv4ForwardingRule(bsLink string) (*composite.Forw
 		newFwdRule.Ports = ports
 		newFwdRule.PortRange = ""
 	}
+	// L3_DEFAULT forwarding rules can only forward all ports.
+	if protocol == utils.L3DefaultProtocol {
+		newFwdRule.Ports = nil
+		newFwdRule.PortRange = ""
+		newFwdRule.AllPorts = true
+	}
 
 	if existingFwdRule != nil {
 		if existingFwdRule.NetworkTier != newFwdRule.NetworkTier {
 			resource := fmt.Sprintf("Forwarding rule (%v)", frName)
 			networkTierMismatchError := utils.NewNetworkTierErr(resource, existingFwdRule.NetworkTier, newFwdRule.NetworkTier)
-			return nil, IPAddrUndefined, utils.ResourceUpdate, networkTierMismatchError
+			return nil, nil, IPAddrUndefined, utils.ResourceUpdate, networkTierMismatchError
+		}
+		if existingFwdRule.BackendService == "" && existingFwdRule.Target != "" {
+			// The forwarding rule of a legacy target pool Service that is migrated to backend services.
+			// It can't be patched to point at the backend service, recreate it with the reserved IP.
+			frLogger.V(2).Info("ensureIPv4ForwardingRule: replacing target pool forwarding rule", "target", existingFwdRule.Target)
+			if err := l4netlb.updateForwardingRule(existingFwdRule, newFwdRule, frLogger); err != nil {
+				return nil, nil, IPAddrUndefined, utils.ResourceUpdate, err
+			}
+			return l4netlb.getCreatedIPv4ForwardingRule(newFwdRule.Name, previousFwdRule, isIPManaged)
 		}
 		equal, err := Equal(existingFwdRule, newFwdRule)
 		if err != nil {
-			return existingFwdRule, IPAddrUndefined, utils.ResourceResync, err
+			return existingFwdRule, previousFwdRule, IPAddrUndefined, utils.ResourceResync, err
 		}
 		if equal {
 			// nothing to do
 			frLogger.V(2).Info("ensureIPv4ForwardingRule: Skipping update of unchanged forwarding rule")
-			return existingFwdRule, isIPManaged, utils.ResourceResync, nil
+			return existingFwdRule, previousFwdRule, isIPManaged, utils.ResourceResync, nil
 		}
 		frDiff := cmp.Diff(existingFwdRule, newFwdRule)
 		frLogger.V(2).Info("ensureIPv4ForwardingRule: forwarding rule changed.",
@@ -426,29 +488,34 @@ func (l4netlb *L4NetLB) ensureIPv4ForwardingRule(bsLink string) (*composite.Forw
 		filtered, patchable := filterPatchableFields(existingFwdRule, newFwdRule)
 		if patchable {
 			if err = l4netlb.forwardingRules.Patch(filtered); err != nil {
-				return nil, IPAddrUndefined, utils.ResourceUpdate, err
+				return nil, nil, IPAddrUndefined, utils.ResourceUpdate, err
 			}
 			l4netlb.recorder.Eventf(l4netlb.Service, corev1.EventTypeNormal, events.SyncIngress, "ForwardingRule %s patched", existingFwdRule.Name)
 		} else {
 			if err := l4netlb.updateForwardingRule(existingFwdRule, newFwdRule, frLogger); err != nil {
-				return nil, IPAddrUndefined, utils.ResourceUpdate, err
+				return nil, nil, IPAddrUndefined, utils.ResourceUpdate, err
 			}
 		}
 
 	} else {
 		if err = l4netlb.createFwdRule(newFwdRule, frLogger); err != nil {
-			return nil, IPAddrUndefined, utils.ResourceUpdate, err
+			return nil, nil, IPAddrUndefined, utils.ResourceUpdate, err
 		}
 		l4netlb.recorder.Eventf(l4netlb.Service, corev1.EventTypeNormal, events.SyncIngress, "ForwardingRule %s created", newFwdRule.Name)
 	}
-	createdFr, err := l4netlb.forwardingRules.Get(newFwdRule.Name)
+	return l4netlb.getCreatedIPv4ForwardingRule(newFwdRule.Name, previousFwdRule, isIPManaged)
+}
+
+// getCreatedIPv4ForwardingRule returns the forwarding rule that was created or updated by ensureIPv4ForwardingRule.
+func (l4netlb *L4NetLB) getCreatedIPv4ForwardingRule(name string, previousFwdRule *composite.ForwardingRule, isIPManaged IPAddressType) (*composite.ForwardingRule, *composite.ForwardingRule, IPAddressType, utils.ResourceSyncStatus, error) {
+	createdFr, err := l4netlb.forwardingRules.Get(name)
 	if err != nil {
-		return nil, IPAddrUndefined, utils.ResourceUpdate, err
+		return nil, nil, IPAddrUndefined, utils.ResourceUpdate, err
 	}
 	if createdFr == nil {
-		return nil, IPAddrUndefined, utils.ResourceUpdate, fmt.Errorf("forwarding rule %s not found", newFwdRule.Name)
+		return nil, nil, IPAddrUndefined, utils.ResourceUpdate, fmt.Errorf("forwarding rule %s not found", name)
 	}
-	return createdFr, isIPManaged, utils.ResourceUpdate, err
+	return createdFr, previousFwdRule, isIPManaged, utils.ResourceUpdate, nil
 }
 
 func (l4netlb *L4NetLB) updateForwardingRule(existingFwdRule, newFr *composite.ForwardingRule, frLogger klog.Logger) error {
@@ -513,6 +580,8 @@ func filterPatchableFields(existing, new *composite.ForwardingRule) (*composite.
 	return filtered, true
 }
 
+// Equal returns true if the two forwarding rules are equivalent.
+// Labels are not compared, they are reconciled separately with SetLabels.
 func Equal(fr1, fr2 *composite.ForwardingRule) (bool, error) {
 	id1, err := cloud.ParseResourceURL(fr1.BackendService)
 	if err != nil {
@@ -531,7 +600,23 @@ func Equal(fr1, fr2 *composite.ForwardingRule) (bool, error) {
 		fr1.AllPorts == fr2.AllPorts &&
 		equalResourcePaths(fr1.Subnetwork, fr2.Subnetwork) &&
 		equalResourcePaths(fr1.Network, fr2.Network) &&
-		fr1.NetworkTier == fr2.NetworkTier, nil
+		fr1.NetworkTier == fr2.NetworkTier &&
+		equalServiceDirectoryRegistrations(fr1.ServiceDirectoryRegistrations, fr2.ServiceDirectoryRegistrations), nil
+}
+
+// equalServiceDirectoryRegistrations compares the Service Directory namespaces and services
+// that two forwarding rules are registered under. Registrations can't be patched, so a
+// change requires the forwarding rule to be recreated.
+func equalServiceDirectoryRegistrations(sdr1, sdr2 []*composite.ForwardingRuleServiceDirectoryRegistration) bool {
+	if len(sdr1) != len(sdr2) {
+		return false
+	}
+	for i := range sdr1 {
+		if sdr1[i].Namespace != sdr2[i].Namespace || sdr1[i].Service != sdr2[i].Service {
+			return false
+		}
+	}
+	return true
 }
 
 // equalPorts compares two port ranges or slices of ports. Before comparison,
@@ -552,6 +637,40 @@ func equalPorts(ports1, ports2 []string, portRange1, portRange2 string) bool {
 	return portRange1 == portRange2
 }
 
+// forwardingRuleNameProtocol returns the protocol part of an L4 forwarding rule name.
+// GCE resource names can't contain underscores, so L3_DEFAULT forwarding rules
+// (and their UNSPECIFIED backend services) use "l3".
+func forwardingRuleNameProtocol(protocol string) string {
+	if protocol == utils.L3DefaultProtocol || protocol == utils.UnspecifiedProtocol {
+		return "l3"
+	}
+	return strings.ToLower(protocol)
+}
+
+// forwardingRuleAnnotationKey returns the Service annotation key that records the given IPv4 forwarding rule.
+func forwardingRuleAnnotationKey(fr *composite.ForwardingRule) string {
+	switch fr.IPProtocol {
+	case string(corev1.ProtocolTCP):
+		return annotations.TCPForwardingRuleKey
+	case utils.L3DefaultProtocol:
+		return annotations.L3ForwardingRuleKey
+	default:
+		return annotations.UDPForwardingRuleKey
+	}
+}
+
+// ipv6ForwardingRuleAnnotationKey returns the Service annotation key that records the given IPv6 forwarding rule.
+func ipv6ForwardingRuleAnnotationKey(fr *composite.ForwardingRule) string {
+	switch fr.IPProtocol {
+	case string(corev1.ProtocolTCP):
+		return annotations.TCPForwardingRuleIPv6Key
+	case utils.L3DefaultProtocol:
+		return annotations.L3ForwardingRuleIPv6Key
+	default:
+		return annotations.UDPForwardingRuleIPv6Key
+	}
+}
+
 func equalResourcePaths(rp1, rp2 string) bool {
 	return rp1 == rp2 || utils.EqualResourceIDs(rp1, rp2)
 }
@@ -0,0 +1,251 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/events"
+	"k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
+)
+
+// Additional forwarding rules serve the addresses of an IP version after the first one in the
+// static addresses annotation. They are copies of the main forwarding rule of the IP version
+// with a different address, and point to the same backend service.
+
+// additionalFRName returns the name of the forwarding rule of the additional address with the
+// given index. Names are derived from the default name of the main forwarding rule, so that
+// they do not change in a make-before-break recreation of the main forwarding rule.
+func additionalFRName(frName string, index int) string {
+	return namer.GetSuffixedName(frName, fmt.Sprintf("-%d", index+1))
+}
+
+// additionalFRNames returns the names of the additional forwarding rules of the Service recorded
+// in the annotation with the given key.
+func additionalFRNames(svc *corev1.Service, key string) []string {
+	value := svc.Annotations[key]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// allAdditionalFRNames returns the names of all the additional forwarding rules that can exist
+// for the main forwarding rule with the given name.
+func allAdditionalFRNames(frName string) []string {
+	var names []string
+	for i := 0; i < annotations.MaxAddressesPerIPVersion-1; i++ {
+		names = append(names, additionalFRName(frName, i))
+	}
+	return names
+}
+
+// additionalForwardingRule returns the expected forwarding rule of an additional address,
+// which has the same configuration as the main forwarding rule fr.
+func additionalForwardingRule(fr *composite.ForwardingRule, name, address string) *composite.ForwardingRule {
+	// External IPv6 forwarding rules need the address with the /96 prefix.
+	if fr.IpVersion == IPVersionIPv6 && fr.LoadBalancingScheme == string(cloud.SchemeExternal) && !strings.HasSuffix(address, prefix96range) {
+		address += prefix96range
+	}
+	return &composite.ForwardingRule{
+		Name:                name,
+		Description:         fr.Description,
+		IPAddress:           address,
+		IPProtocol:          fr.IPProtocol,
+		Ports:               fr.Ports,
+		PortRange:           fr.PortRange,
+		AllPorts:            fr.AllPorts,
+		LoadBalancingScheme: fr.LoadBalancingScheme,
+		BackendService:      fr.BackendService,
+		IpVersion:           fr.IpVersion,
+		Network:             fr.Network,
+		Subnetwork:          fr.Subnetwork,
+		NetworkTier:         fr.NetworkTier,
+		AllowGlobalAccess:   fr.AllowGlobalAccess,
+		Labels:              fr.Labels,
+	}
+}
+
+// additionalForwardingRulesEqual returns true if the existing additional forwarding rule
+// matches the expected one. Labels are not compared, they are reconciled separately.
+func additionalForwardingRulesEqual(existing, expected *composite.ForwardingRule) (bool, error) {
+	if expected.IpVersion == IPVersionIPv6 {
+		equal, err := EqualIPv6ForwardingRules(existing, expected)
+		return equal && ipv6AddressWithoutRange(existing.IPAddress) == ipv6AddressWithoutRange(expected.IPAddress), err
+	}
+	return Equal(existing, expected)
+}
+
+// ensureAdditionalForwardingRules ensures a forwarding rule for each of the additional addresses
+// of the Service, based on the main forwarding rule fr of the IP version and the default name frName
+// of the main forwarding rule. Additional forwarding rules recorded in the annotation with the given
+// key, which are no longer needed, are deleted.
+// It returns the additional forwarding rules in the order of the addresses.
+func ensureAdditionalForwardingRules(forwardingRules ForwardingRulesProvider, recorder record.EventRecorder, svc *corev1.Service, fr *composite.ForwardingRule, frName string, addresses []string, annotationKey string, logger klog.Logger) ([]*composite.ForwardingRule, error) {
+	var additionalFRs []*composite.ForwardingRule
+	names := sets.New[string]()
+	for i, address := range addresses {
+		expected := additionalForwardingRule(fr, additionalFRName(frName, i), address)
+		names.Insert(expected.Name)
+		frLogger := logger.WithValues("forwardingRuleName", expected.Name, "address", address)
+
+		existing, err := forwardingRules.Get(expected.Name)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			equal, err := additionalForwardingRulesEqual(existing, expected)
+			if err != nil {
+				return nil, err
+			}
+			if equal {
+				if !maps.Equal(existing.Labels, expected.Labels) {
+					frLogger.V(2).Info("Updating labels of additional forwarding rule")
+					if err := forwardingRules.SetLabels(existing.Name, expected.Labels, existing.LabelFingerprint); err != nil {
+						return nil, err
+					}
+				}
+				additionalFRs = append(additionalFRs, existing)
+				continue
+			}
+			// Forwarding rules can't be updated in place, the additional forwarding rule is recreated.
+			frLogger.V(2).Info("Deleting changed additional forwarding rule")
+			if err := forwardingRules.Delete(existing.Name); err != nil {
+				return nil, err
+			}
+		}
+		frLogger.V(2).Info("Creating additional forwarding rule")
+		if err := forwardingRules.Create(expected); err != nil {
+			return nil, err
+		}
+		created, err := forwardingRules.Get(expected.Name)
+		if err != nil {
+			return nil, err
+		}
+		if created == nil {
+			return nil, fmt.Errorf("additional forwarding rule %s not found after creation", expected.Name)
+		}
+		recorder.Eventf(svc, corev1.EventTypeNormal, events.SyncIngress, "ForwardingRule %q created for additional address %s", expected.Name, address)
+		additionalFRs = append(additionalFRs, created)
+	}
+
+	for _, name := range additionalFRNames(svc, annotationKey) {
+		if names.Has(name) {
+			continue
+		}
+		logger.V(2).Info("Deleting additional forwarding rule that is no longer needed", "forwardingRuleName", name)
+		if err := forwardingRules.Delete(name); err != nil {
+			return nil, err
+		}
+		recorder.Eventf(svc, corev1.EventTypeNormal, events.SyncIngress, "ForwardingRule %q deleted", name)
+	}
+	return additionalFRs, nil
+}
+
+// deleteAdditionalForwardingRules deletes the additional forwarding rules with the given names.
+func deleteAdditionalForwardingRules(forwardingRules ForwardingRulesProvider, names []string, logger klog.Logger) error {
+	for _, name := range names {
+		logger.V(2).Info("Deleting additional forwarding rule", "forwardingRuleName", name)
+		if err := forwardingRules.Delete(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// additionalAddresses returns the additional addresses of the given IP version requested by
+// the static addresses annotation of the Service.
+func additionalAddresses(cloud *gce.Cloud, svc *corev1.Service, ipVersion IPVersion) ([]string, error) {
+	if ipVersion == IPv6Version {
+		return annotations.FromService(svc).AdditionalIPv6AddressesAnnotation(cloud)
+	}
+	return annotations.FromService(svc).AdditionalIPv4AddressesAnnotation(cloud)
+}
+
+// additionalFRAnnotationKey returns the key of the annotation that records the names of the
+// additional forwarding rules of the given IP version.
+func additionalFRAnnotationKey(ipVersion IPVersion) string {
+	if ipVersion == IPv6Version {
+		return annotations.AdditionalForwardingRulesIPv6Key
+	}
+	return annotations.AdditionalForwardingRulesKey
+}
+
+// additionalFRNamesToDelete returns the names of the additional forwarding rules of the given
+// IP version to delete. On Service deletion, the names of all the additional forwarding rules that
+// the static addresses annotation can request are included, in case the names were not recorded.
+func additionalFRNamesToDelete(svc *corev1.Service, frName string, ipVersion IPVersion, onDelete bool) []string {
+	names := sets.New(additionalFRNames(svc, additionalFRAnnotationKey(ipVersion))...)
+	if onDelete && svc.Annotations[annotations.StaticL4AddressesAnnotationKey] != "" {
+		names.Insert(allAdditionalFRNames(frName)...)
+	}
+	return sets.List(names)
+}
+
+func (l4 *L4) ensureAdditionalForwardingRules(fr *composite.ForwardingRule, frName string, ipVersion IPVersion) ([]*composite.ForwardingRule, error) {
+	addresses, err := additionalAddresses(l4.cloud, l4.Service, ipVersion)
+	if err != nil {
+		return nil, err
+	}
+	return ensureAdditionalForwardingRules(l4.forwardingRules, l4.recorder, l4.Service, fr, frName, addresses, additionalFRAnnotationKey(ipVersion), l4.svcLogger)
+}
+
+func (l4 *L4) deleteAdditionalForwardingRules(frName string, ipVersion IPVersion, onDelete bool) error {
+	return deleteAdditionalForwardingRules(l4.forwardingRules, additionalFRNamesToDelete(l4.Service, frName, ipVersion, onDelete), l4.svcLogger)
+}
+
+func (l4netlb *L4NetLB) ensureAdditionalForwardingRules(fr *composite.ForwardingRule, frName string, ipVersion IPVersion) ([]*composite.ForwardingRule, error) {
+	addresses, err := additionalAddresses(l4netlb.cloud, l4netlb.Service, ipVersion)
+	if err != nil {
+		return nil, err
+	}
+	return ensureAdditionalForwardingRules(l4netlb.forwardingRules, l4netlb.recorder, l4netlb.Service, fr, frName, addresses, additionalFRAnnotationKey(ipVersion), l4netlb.svcLogger)
+}
+
+func (l4netlb *L4NetLB) deleteAdditionalForwardingRules(frName string, ipVersion IPVersion, onDelete bool) error {
+	return deleteAdditionalForwardingRules(l4netlb.forwardingRules, additionalFRNamesToDelete(l4netlb.Service, frName, ipVersion, onDelete), l4netlb.svcLogger)
+}
+
+// additionalFRAddresses returns the IP addresses of the additional forwarding rules,
+// without the range of external IPv6 addresses.
+func additionalFRAddresses(additionalFRs []*composite.ForwardingRule) []string {
+	var addresses []string
+	for _, fr := range additionalFRs {
+		addresses = append(addresses, ipv6AddressWithoutRange(fr.IPAddress))
+	}
+	return addresses
+}
+
+// additionalFRNamesAnnotation returns the value of the annotation that records the names
+// of the additional forwarding rules.
+func additionalFRNamesAnnotation(additionalFRs []*composite.ForwardingRule) string {
+	var names []string
+	for _, fr := range additionalFRs {
+		names = append(names, fr.Name)
+	}
+	return strings.Join(names, ",")
+}
@@ -40,7 +40,7 @@ const (
 	prefix96range = "/96"
 )
 
-func (l4 *L4) ensureIPv6ForwardingRule(bsLink string, options gce.ILBOptions, existingIPv6FwdRule *composite.ForwardingRule, ipv6AddressToUse string) (*composite.ForwardingRule, error) {
+func (l4 *L4) ensureIPv6ForwardingRule(bsLink string, options ilbOptions, existingIPv6FwdRule *composite.ForwardingRule, ipv6AddressToUse string) (*composite.ForwardingRule, error) {
 	start := time.Now()
 
 	expectedIPv6FwdRule, err := l4.buildExpectedIPv6ForwardingRule(bsLink, options, ipv6AddressToUse)
@@ -55,11 +55,22 @@ func (l4 *L4) ensureIPv6ForwardingRule(bsLink string, options gce.ILBOptions, ex
 	}()
 
 	if existingIPv6FwdRule != nil {
+		if options.Labels == nil {
+			// Labels are not managed, keep the existing ones if the forwarding rule is recreated.
+			expectedIPv6FwdRule.Labels = existingIPv6FwdRule.Labels
+		}
 		equal, err := EqualIPv6ForwardingRules(existingIPv6FwdRule, expectedIPv6FwdRule)
 		if err != nil {
 			return existingIPv6FwdRule, err
 		}
 		if equal {
+			labelsUpdated, err := l4.ensureForwardingRuleLabels(existingIPv6FwdRule, expectedIPv6FwdRule, frLogger)
+			if err != nil {
+				return existingIPv6FwdRule, err
+			}
+			if labelsUpdated {
+				return l4.forwardingRules.Get(expectedIPv6FwdRule.Name)
+			}
 			frLogger.V(2).Info("ensureIPv6ForwardingRule: Skipping update of unchanged ipv6 forwarding rule")
 			return existingIPv6FwdRule, nil
 		}
@@ -79,7 +90,7 @@ func (l4 *L4) ensureIPv6ForwardingRule(bsLink string, options gce.ILBOptions, ex
 	return createdFr, err
 }
 
-func (l4 *L4) buildExpectedIPv6ForwardingRule(bsLink string, options gce.ILBOptions, ipv6AddressToUse string) (*composite.ForwardingRule, error) {
+func (l4 *L4) buildExpectedIPv6ForwardingRule(bsLink string, options ilbOptions, ipv6AddressToUse string) (*composite.ForwardingRule, error) {
 	frName := l4.getIPv6FRName()
 
 	frDesc, err := utils.MakeL4IPv6ForwardingRuleDescription(l4.Service)
@@ -98,13 +109,13 @@ func (l4 *L4) buildExpectedIPv6ForwardingRule(bsLink string, options gce.ILBOpti
 
 	svcPorts := l4.Service.Spec.Ports
 	ports := utils.GetPorts(svcPorts)
-	protocol := utils.GetProtocol(svcPorts)
+	protocol := utils.GetForwardingRuleProtocol(svcPorts)
 
 	fr := &composite.ForwardingRule{
 		Name:                frName,
 		Description:         frDesc,
 		IPAddress:           ipv6AddressToUse,
-		IPProtocol:          string(protocol),
+		IPProtocol:          protocol,
 		Ports:               ports,
 		LoadBalancingScheme: string(cloud.SchemeInternal),
 		BackendService:      bsLink,
@@ -113,8 +124,10 @@ func (l4 *L4) buildExpectedIPv6ForwardingRule(bsLink string, options gce.ILBOpti
 		Subnetwork:          subnetworkURL,
 		AllowGlobalAccess:   options.AllowGlobalAccess,
 		NetworkTier:         cloud.NetworkTierPremium.ToGCEValue(),
+		Labels:              options.Labels,
 	}
-	if len(ports) > maxForwardedPorts {
+	// L3_DEFAULT forwarding rules can only forward all ports.
+	if len(ports) > maxForwardedPorts || protocol == utils.L3DefaultProtocol {
 		fr.Ports = nil
 		fr.AllPorts = true
 	}
@@ -258,12 +271,12 @@ func (l4netlb *L4NetLB) buildExpectedIPv6ForwardingRule(bsLink, ipv6AddressToUse
 	svcPorts := l4netlb.Service.Spec.Ports
 	ports := utils.GetPorts(svcPorts)
 	portRange := utils.MinMaxPortRange(svcPorts)
-	protocol := utils.GetProtocol(svcPorts)
+	protocol := utils.GetForwardingRuleProtocol(svcPorts)
 	fr := &composite.ForwardingRule{
 		Name:                frName,
 		Description:         frDesc,
 		IPAddress:           ipv6AddressToUse,
-		IPProtocol:          string(protocol),
+		IPProtocol:          protocol,
 		PortRange:           portRange,
 		LoadBalancingScheme: string(cloud.SchemeExternal),
 		BackendService:      bsLink,
@@ -275,6 +288,12 @@ func (l4netlb *L4NetLB) buildExpectedIPv6ForwardingRule(bsLink, ipv6AddressToUse
 		fr.Ports = utils.GetPorts(svcPorts)
 		fr.PortRange = ""
 	}
+	// L3_DEFAULT forwarding rules can only forward all ports.
+	if protocol == utils.L3DefaultProtocol {
+		fr.Ports = nil
+		fr.PortRange = ""
+		fr.AllPorts = true
+	}
 
 	return fr, nil
 }
@@ -378,6 +378,36 @@ func TestForwardingRulesEqual(t *testing.T) {
 			},
 			expectEqual: true,
 		},
+		{
+			desc:       "service directory registration added",
+			oldFwdRule: fwdRuleTCP,
+			newFwdRule: &composite.ForwardingRule{
+				Name:                "tcp-fwd-rule",
+				IPAddress:           "10.0.0.0",
+				Ports:               []string{"123"},
+				IPProtocol:          "TCP",
+				LoadBalancingScheme: string(cloud.SchemeInternal),
+				BackendService:      "http://www.googleapis.com/projects/test/regions/us-central1/backendServices/bs1",
+				ServiceDirectoryRegistrations: []*composite.ForwardingRuleServiceDirectoryRegistration{
+					{Namespace: "ns", Service: "svc"},
+				},
+			},
+			expectEqual: false,
+		},
+		{
+			desc:       "labels are ignored",
+			oldFwdRule: fwdRuleTCP,
+			newFwdRule: &composite.ForwardingRule{
+				Name:                "tcp-fwd-rule",
+				IPAddress:           "10.0.0.0",
+				Ports:               []string{"123"},
+				IPProtocol:          "TCP",
+				LoadBalancingScheme: string(cloud.SchemeInternal),
+				BackendService:      "http://www.googleapis.com/projects/test/regions/us-central1/backendServices/bs1",
+				Labels:              map[string]string{"team": "a"},
+			},
+			expectEqual: true,
+		},
 	} {
 		t.Run(tc.desc, func(t *testing.T) {
 			flags.F.EnableDiscretePortForwarding = tc.discretePortForwarding
@@ -416,7 +446,7 @@ func TestL4CreateExternalForwardingRuleAddressAlreadyInUse(t *testing.T) {
 	fakeGCE.ReserveRegionAddress(addr, fakeGCE.Region())
 	insertError := &googleapi.Error{Code: http.StatusBadRequest, Message: "Invalid value for field 'resource.IPAddress': '1.1.1.1'. Specified IP address is in-use and would result in a conflict., invalid"}
 	fakeGCE.Compute().(*cloud.MockGCE).MockForwardingRules.InsertHook = test.InsertForwardingRuleErrorHook(insertError)
-	_, _, _, err := l4.ensureIPv4ForwardingRule("link")
+	_, _, _, _, err := l4.ensureIPv4ForwardingRule("link")
 
 	require.Error(t, err)
 	assert.True(t, utils.IsIPConfigurationError(err))
@@ -553,7 +583,7 @@ func TestL4CreateExternalForwardingRule(t *testing.T) {
 			if tc.namedAddress != nil {
 				fakeGCE.ReserveRegionAddress(tc.namedAddress, fakeGCE.Region())
 			}
-			fr, _, updated, err := l4.ensureIPv4ForwardingRule(bsLink)
+			fr, _, _, updated, err := l4.ensureIPv4ForwardingRule(bsLink)
 
 			if err != nil {
 				t.Errorf("ensureIPv4ForwardingRule() err=%v", err)
@@ -710,7 +740,7 @@ func TestL4CreateExternalForwardingRuleUpdate(t *testing.T) {
 			if tc.namedAddress != nil {
 				fakeGCE.ReserveRegionAddress(tc.namedAddress, fakeGCE.Region())
 			}
-			fr, _, updated, err := l4.ensureIPv4ForwardingRule(bsLink)
+			fr, _, _, updated, err := l4.ensureIPv4ForwardingRule(bsLink)
 
 			if err != nil && tc.wantErrMsg == "" {
 				t.Errorf("ensureIPv4ForwardingRule() err=%v", err)
@@ -759,7 +789,7 @@ func TestL4EnsureIPv4ForwardingRuleAddressAlreadyInUse(t *testing.T) {
 	fakeGCE.ReserveRegionAddress(addr, fakeGCE.Region())
 	insertError := &googleapi.Error{Code: http.StatusConflict, Message: "IP_IN_USE_BY_ANOTHER_RESOURCE - IP '10.107.116.14' is already being used by another resource."}
 	fakeGCE.Compute().(*cloud.MockGCE).MockForwardingRules.InsertHook = test.InsertForwardingRuleErrorHook(insertError)
-	_, err := l4.ensureIPv4ForwardingRule("link", gce.ILBOptions{}, nil, "subnetworkX", "1.1.1.1")
+	_, err := l4.ensureIPv4ForwardingRule(l4.GetFRName(), "link", ilbOptions{}, nil, "subnetworkX", "1.1.1.1")
 
 	require.Error(t, err)
 	assert.True(t, utils.IsIPConfigurationError(err))
@@ -801,7 +831,7 @@ func TestL4EnsureIPv4ForwardingRule(t *testing.T) {
 	ipToUse := "1.1.1.1"
 	bsLink := "http://www.googleapis.com/projects/test/regions/us-central1/backendServices/bs1"
 
-	forwardingRule, err := l4.ensureIPv4ForwardingRule(bsLink, gce.ILBOptions{}, nil, subnetworkURL, ipToUse)
+	forwardingRule, err := l4.ensureIPv4ForwardingRule(l4.GetFRName(), bsLink, ilbOptions{}, nil, subnetworkURL, ipToUse)
 	require.NoError(t, err)
 
 	wantForwardingRule := &composite.ForwardingRule{
@@ -48,4 +48,5 @@ type ForwardingRulesProvider interface {
 	Create(forwardingRule *composite.ForwardingRule) error
 	Delete(name string) error
 	Patch(forwardingRule *composite.ForwardingRule) error
+	SetLabels(name string, labels map[string]string, labelFingerprint string) error
 }
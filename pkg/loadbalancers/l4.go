@@ -141,15 +141,52 @@ func (l4 *L4) CreateKey(name string) (*meta.Key, error) {
 	return composite.CreateKey(l4.cloud, name, l4.scope)
 }
 
+// ilbOptions are the optional features requested on an ILB service.
+type ilbOptions struct {
+	gce.ILBOptions
+	// Labels are the resource labels of the forwarding rules.
+	// nil leaves the labels of existing forwarding rules untouched.
+	Labels map[string]string
+	// ServiceDirectory is the Service Directory service the IPv4 forwarding rule is registered under.
+	ServiceDirectory *annotations.L4ServiceDirectoryRegistration
+	// ConnectionTrackingPolicy is the connection tracking policy of the backend service.
+	// nil keeps the GCE defaults.
+	ConnectionTrackingPolicy *composite.BackendServiceConnectionTrackingPolicy
+}
+
 // getILBOptions fetches the optional features requested on the given ILB service.
-func (l4 *L4) getILBOptions() gce.ILBOptions {
+// Options from the L4 options annotation take precedence over the legacy annotations.
+func (l4 *L4) getILBOptions() (ilbOptions, error) {
 	if l4.cloud.IsLegacyNetwork() {
 		l4.recorder.Event(l4.Service, corev1.EventTypeWarning, "ILBOptionsIgnored", "Internal LoadBalancer options are not supported with Legacy Networks.")
-		return gce.ILBOptions{}
+		return ilbOptions{}, nil
 	}
 
-	return gce.ILBOptions{AllowGlobalAccess: gce.GetLoadBalancerAnnotationAllowGlobalAccess(l4.Service),
-		SubnetName: annotations.FromService(l4.Service).GetInternalLoadBalancerAnnotationSubnet()}
+	options := ilbOptions{
+		ILBOptions: gce.ILBOptions{AllowGlobalAccess: gce.GetLoadBalancerAnnotationAllowGlobalAccess(l4.Service),
+			SubnetName: annotations.FromService(l4.Service).GetInternalLoadBalancerAnnotationSubnet()},
+	}
+	connectionTracking, err := annotations.FromService(l4.Service).L4ConnectionTrackingOptions()
+	if err != nil {
+		return ilbOptions{}, utils.NewUserError(err)
+	}
+	options.ConnectionTrackingPolicy = l4ConnectionTrackingPolicy(connectionTracking)
+	l4Options, err := annotations.FromService(l4.Service).L4Options()
+	if err != nil {
+		return ilbOptions{}, utils.NewUserError(err)
+	}
+	if l4Options == nil {
+		return options, nil
+	}
+	if l4Options.GlobalAccess != nil {
+		options.AllowGlobalAccess = *l4Options.GlobalAccess
+	}
+	if l4Options.Subnet != "" {
+		options.SubnetName = l4Options.Subnet
+	}
+	options.Labels = l4Options.Labels
+	options.ServiceDirectory = l4Options.ServiceDirectory
+	return options, nil
 }
 
 // EnsureInternalLoadBalancerDeleted performs a cleanup of all GCE resources for the given loadbalancer service.
@@ -230,7 +267,7 @@ func (l4 *L4) deleteIPv4ResourcesOnDelete(result *L4ILBSyncResult) {
 // This function does not delete Backend Service and Health Check, because they are shared between IPv4 and IPv6.
 // IPv4 Firewall Rule for Health Check also will not be deleted here, and will be left till the Service Deletion.
 func (l4 *L4) deleteIPv4ResourcesAnnotationBased(result *L4ILBSyncResult, shouldIgnoreAnnotations bool) {
-	if shouldIgnoreAnnotations || l4.hasAnnotation(annotations.TCPForwardingRuleKey) || l4.hasAnnotation(annotations.UDPForwardingRuleKey) {
+	if shouldIgnoreAnnotations || l4.hasAnnotation(annotations.TCPForwardingRuleKey) || l4.hasAnnotation(annotations.UDPForwardingRuleKey) || l4.hasAnnotation(annotations.L3ForwardingRuleKey) {
 		err := l4.deleteIPv4ForwardingRule()
 		if err != nil {
 			l4.svcLogger.Error(err, "Failed to delete forwarding rule for internal loadbalancer service")
@@ -238,6 +275,11 @@ func (l4 *L4) deleteIPv4ResourcesAnnotationBased(result *L4ILBSyncResult, should
 			result.GCEResourceInError = annotations.ForwardingRuleResource
 		}
 	}
+	if err := l4.deleteAdditionalForwardingRules(l4.GetFRName(), IPv4Version, shouldIgnoreAnnotations); err != nil {
+		l4.svcLogger.Error(err, "Failed to delete additional forwarding rules for internal loadbalancer service")
+		result.Error = err
+		result.GCEResourceInError = annotations.ForwardingRuleResource
+	}
 
 	// Deleting non-existent address do not print error audit logs, and we don't store address in annotations
 	// that's why we can delete it without checking annotation
@@ -263,25 +305,34 @@ func (l4 *L4) deleteIPv4ForwardingRule() error {
 	start := time.Now()
 
 	frName := l4.GetFRName()
+	// The forwarding rule may have been replaced in a make-before-break recreation.
+	alternateFRName := l4.alternateIPv4FRName(frName)
 
-	l4.svcLogger.Info("Deleting IPv4 forwarding rule for L4 ILB Service", "forwardingRuleName", frName)
+	l4.svcLogger.Info("Deleting IPv4 forwarding rule for L4 ILB Service", "forwardingRuleName", frName, "alternateForwardingRuleName", alternateFRName)
 	defer func() {
 		l4.svcLogger.Info("Finished deleting IPv4 forwarding rule for L4 ILB Service", "forwardingRuleName", frName, "timeTaken", time.Since(start))
 	}()
 
-	return l4.forwardingRules.Delete(frName)
+	if err := l4.forwardingRules.Delete(frName); err != nil {
+		return err
+	}
+	return l4.forwardingRules.Delete(alternateFRName)
 }
 
 func (l4 *L4) deleteIPv4Address() error {
 	addressName := l4.GetFRName()
+	alternateAddressName := l4.alternateIPv4FRName(addressName)
 
 	start := time.Now()
-	l4.svcLogger.Info("Deleting IPv4 address for L4 ILB Service", "addressName", addressName)
+	l4.svcLogger.Info("Deleting IPv4 address for L4 ILB Service", "addressName", addressName, "alternateAddressName", alternateAddressName)
 	defer func() {
 		l4.svcLogger.Info("Finished deleting IPv4 address for L4 ILB Service", "addressName", addressName, "timeTaken", time.Since(start))
 	}()
 
-	return ensureAddressDeleted(l4.cloud, addressName, l4.cloud.Region())
+	if err := ensureAddressDeleted(l4.cloud, addressName, l4.cloud.Region()); err != nil {
+		return err
+	}
+	return ensureAddressDeleted(l4.cloud, alternateAddressName, l4.cloud.Region())
 }
 
 func (l4 *L4) deleteIPv4NodesFirewall() error {
@@ -314,12 +365,12 @@ func (l4 *L4) deleteFirewall(name string, fwLogger klog.Logger) error {
 // This appends the protocol to the forwarding rule name, which will help supporting multiple protocols in the same ILB
 // service.
 func (l4 *L4) GetFRName() string {
-	protocol := utils.GetProtocol(l4.Service.Spec.Ports)
-	return l4.getFRNameWithProtocol(string(protocol))
+	protocol := utils.GetForwardingRuleProtocol(l4.Service.Spec.Ports)
+	return l4.getFRNameWithProtocol(protocol)
 }
 
 func (l4 *L4) getFRNameWithProtocol(protocol string) string {
-	return l4.namer.L4ForwardingRule(l4.Service.Namespace, l4.Service.Name, strings.ToLower(protocol))
+	return l4.namer.L4ForwardingRule(l4.Service.Namespace, l4.Service.Name, forwardingRuleNameProtocol(protocol))
 }
 
 func (l4 *L4) subnetName() string {
@@ -375,7 +426,11 @@ func (l4 *L4) EnsureInternalLoadBalancer(nodeNames []string, svc *corev1.Service
 		return result
 	}
 
-	options := l4.getILBOptions()
+	options, err := l4.getILBOptions()
+	if err != nil {
+		result.Error = err
+		return result
+	}
 	subnetworkURL, err := l4.getServiceSubnetworkURL(options)
 	if err != nil {
 		result.Error = err
@@ -393,16 +448,29 @@ func (l4 *L4) EnsureInternalLoadBalancer(nodeNames []string, svc *corev1.Service
 	}
 
 	// Reserve existing IP address before making any changes
-	var existingIPv4FR *composite.ForwardingRule
+	var existingIPv4FR, previousIPv4FR *composite.ForwardingRule
 	var ipv4AddressToUse string
+	expectedFRName := l4.GetFRName()
 	if !l4.enableDualStack || utils.NeedsIPv4(l4.Service) {
-		existingIPv4FR, err = l4.getOldIPv4ForwardingRule(existingBS)
+		existingIPv4FR, previousIPv4FR, err = l4.getIPv4ForwardingRules(existingBS, subnetworkURL)
+		if err != nil {
+			result.Error = fmt.Errorf("EnsureInternalLoadBalancer error: getIPv4ForwardingRules returned error: %w", err)
+			return result
+		}
+		if existingIPv4FR != nil && existingIPv4FR.Name == l4.alternateIPv4FRName(expectedFRName) {
+			expectedFRName = existingIPv4FR.Name
+		}
+		if l4.shouldMakeBeforeBreak(existingIPv4FR, previousIPv4FR, subnetworkURL) {
+			// Create the replacement next to the existing forwarding rule, instead of recreating it.
+			l4.svcLogger.V(2).Info("Subnetwork of forwarding rule changed, creating replacement forwarding rule", "previousForwardingRuleName", existingIPv4FR.Name, "subnetworkURL", subnetworkURL)
+			previousIPv4FR, existingIPv4FR = existingIPv4FR, nil
+			expectedFRName = l4.alternateIPv4FRName(expectedFRName)
+		}
 		ipv4AddressToUse, err = ipv4AddrToUse(l4.cloud, l4.recorder, l4.Service, existingIPv4FR, subnetworkURL)
 		if err != nil {
 			result.Error = fmt.Errorf("EnsureInternalLoadBalancer error: ipv4AddrToUse returned error: %w", err)
 			return result
 		}
-		expectedFRName := l4.GetFRName()
 
 		if !l4.cloud.IsLegacyNetwork() {
 			l4.svcLogger.V(2).Info("EnsureInternalLoadBalancer, reserve existing IPv4 address before making any changes")
@@ -460,12 +528,12 @@ func (l4 *L4) EnsureInternalLoadBalancer(nodeNames []string, svc *corev1.Service
 	}
 
 	servicePorts := l4.Service.Spec.Ports
-	protocol := utils.GetProtocol(servicePorts)
+	protocol := utils.GetBackendServiceProtocol(servicePorts)
 
 	// if Service protocol changed, we must delete forwarding rule before changing backend service,
 	// otherwise, on updating backend service, google cloud api will return error
-	if existingBS != nil && existingBS.Protocol != string(protocol) {
-		l4.svcLogger.Info("Protocol changed for service", "existingProtocol", existingBS.Protocol, "newProtocol", string(protocol))
+	if existingBS != nil && existingBS.Protocol != protocol {
+		l4.svcLogger.Info("Protocol changed for service", "existingProtocol", existingBS.Protocol, "newProtocol", protocol)
 		if existingIPv4FR != nil {
 			// Delete ipv4 forwarding rule if it exists
 			err = l4.forwardingRules.Delete(existingIPv4FR.Name)
@@ -473,6 +541,14 @@ func (l4 *L4) EnsureInternalLoadBalancer(nodeNames []string, svc *corev1.Service
 				l4.svcLogger.Error(err, "Failed to delete forwarding rule", "forwardingRuleName", existingIPv4FR.Name)
 			}
 		}
+		if previousIPv4FR != nil {
+			// Delete the forwarding rule replaced in a make-before-break recreation, it uses the backend service too.
+			err = l4.forwardingRules.Delete(previousIPv4FR.Name)
+			if err != nil {
+				l4.svcLogger.Error(err, "Failed to delete previous forwarding rule", "forwardingRuleName", previousIPv4FR.Name)
+			}
+			previousIPv4FR = nil
+		}
 
 		if l4.enableDualStack && existingIPv6FR != nil {
 			// Delete ipv6 forwarding rule if it exists
@@ -481,6 +557,11 @@ func (l4 *L4) EnsureInternalLoadBalancer(nodeNames []string, svc *corev1.Service
 				l4.svcLogger.Error(err, "Failed to delete ipv6 forwarding rule", "forwardingRuleName", existingIPv6FR.Name)
 			}
 		}
+		// The additional forwarding rules use the backend service too, they are recreated with the new protocol.
+		additionalFRNames := append(additionalFRNames(l4.Service, annotations.AdditionalForwardingRulesKey), additionalFRNames(l4.Service, annotations.AdditionalForwardingRulesIPv6Key)...)
+		if err := deleteAdditionalForwardingRules(l4.forwardingRules, additionalFRNames, l4.svcLogger); err != nil {
+			l4.svcLogger.Error(err, "Failed to delete additional forwarding rules")
+		}
 	}
 
 	localityLbPolicy := l4.determineBackendServiceLocalityPolicy()
@@ -489,12 +570,12 @@ func (l4 *L4) EnsureInternalLoadBalancer(nodeNames []string, svc *corev1.Service
 	backendParams := backends.L4BackendServiceParams{
 		Name:                     bsName,
 		HealthCheckLink:          hcLink,
-		Protocol:                 string(protocol),
+		Protocol:                 protocol,
 		SessionAffinity:          string(l4.Service.Spec.SessionAffinity),
 		Scheme:                   string(cloud.SchemeInternal),
 		NamespacedName:           l4.NamespacedName,
 		NetworkInfo:              &l4.network,
-		ConnectionTrackingPolicy: noConnectionTrackingPolicy,
+		ConnectionTrackingPolicy: options.ConnectionTrackingPolicy,
 		LocalityLbPolicy:         localityLbPolicy,
 	}
 	bs, _, err := l4.backendPool.EnsureL4BackendService(backendParams, l4.svcLogger)
@@ -511,10 +592,19 @@ func (l4 *L4) EnsureInternalLoadBalancer(nodeNames []string, svc *corev1.Service
 	}
 	result.Annotations[annotations.BackendServiceKey] = bsName
 
+	if dedicatedHealthCheckReplaced(l4.Service, l4.namer, result.Annotations[annotations.HealthcheckKey]) {
+		// The backend service no longer uses the dedicated health check of the Service.
+		if resourceInError, err := l4.healthChecks.DeleteOverriddenHealthCheckWithFirewalls(l4.Service, l4.namer, meta.Global, utils.ILB, l4.enableDualStack, l4.svcLogger); err != nil {
+			result.GCEResourceInError = resourceInError
+			result.Error = err
+			return result
+		}
+	}
+
 	if l4.enableDualStack {
-		l4.ensureDualStackResources(result, nodeNames, options, bs, existingIPv4FR, existingIPv6FR, subnetworkURL, ipv4AddressToUse, ipv6AddrToUse)
+		l4.ensureDualStackResources(result, nodeNames, options, bs, existingIPv4FR, previousIPv4FR, existingIPv6FR, expectedFRName, subnetworkURL, ipv4AddressToUse, ipv6AddrToUse)
 	} else {
-		l4.ensureIPv4Resources(result, nodeNames, options, bs, existingIPv4FR, subnetworkURL, ipv4AddressToUse)
+		l4.ensureIPv4Resources(result, nodeNames, options, bs, existingIPv4FR, previousIPv4FR, expectedFRName, subnetworkURL, ipv4AddressToUse)
 	}
 	if result.Error != nil {
 		return result
@@ -535,6 +625,13 @@ func (l4 *L4) EnsureInternalLoadBalancer(nodeNames []string, svc *corev1.Service
 	return result
 }
 
+// dedicatedHealthCheckReplaced returns true if the Service used its dedicated health check on the
+// previous sync, as recorded in its annotations, and now uses the shared one with the given name.
+func dedicatedHealthCheckReplaced(svc *corev1.Service, l4Namer namer.L4ResourcesNamer, hcName string) bool {
+	return hcName == l4Namer.L4HealthCheck(svc.Namespace, svc.Name, true) &&
+		svc.Annotations[annotations.HealthcheckKey] == l4Namer.L4HealthCheck(svc.Namespace, svc.Name, false)
+}
+
 func (l4 *L4) provideHealthChecks(nodeNames []string, result *L4ILBSyncResult) string {
 	if l4.enableDualStack {
 		return l4.provideDualStackHealthChecks(nodeNames, result)
@@ -574,46 +671,108 @@ func (l4 *L4) provideIPv4HealthChecks(nodeNames []string, result *L4ILBSyncResul
 	return hcResult.HCLink
 }
 
-func (l4 *L4) ensureDualStackResources(result *L4ILBSyncResult, nodeNames []string, options gce.ILBOptions, bs *composite.BackendService, existingIPv4FwdRule, existingIPv6FwdRule *composite.ForwardingRule, subnetworkURL, ipv4AddressToUse, ipv6AddressToUse string) {
+func (l4 *L4) ensureDualStackResources(result *L4ILBSyncResult, nodeNames []string, options ilbOptions, bs *composite.BackendService, existingIPv4FwdRule, previousIPv4FwdRule, existingIPv6FwdRule *composite.ForwardingRule, ipv4FRName, subnetworkURL, ipv4AddressToUse, ipv6AddressToUse string) {
 	if utils.NeedsIPv4(l4.Service) {
-		l4.ensureIPv4Resources(result, nodeNames, options, bs, existingIPv4FwdRule, subnetworkURL, ipv4AddressToUse)
-	} else {
-		l4.deleteIPv4ResourcesOnSync(result)
+		l4.ensureIPv4Resources(result, nodeNames, options, bs, existingIPv4FwdRule, previousIPv4FwdRule, ipv4FRName, subnetworkURL, ipv4AddressToUse)
 	}
 	if utils.NeedsIPv6(l4.Service) {
 		l4.ensureIPv6Resources(result, nodeNames, options, bs.SelfLink, existingIPv6FwdRule, ipv6AddressToUse)
-	} else {
+	}
+	// Resources of an IP family that is no longer used are deleted after the ones of the
+	// other family are ensured, so that they can keep serving traffic in a make-before-break IP version change.
+	if !utils.NeedsIPv4(l4.Service) && !l4.keepPreviousIPv4Resources(result) {
+		l4.deleteIPv4ResourcesOnSync(result)
+	}
+	if !utils.NeedsIPv6(l4.Service) && !l4.keepPreviousIPv6Resources(result) {
 		l4.deleteIPv6ResourcesOnSync(result)
 	}
 }
 
+// keepPreviousIPv4Resources keeps the IPv4 forwarding rule and nodes firewall of a Service
+// that switched to IPv6 only in a make-before-break IP version change, until the soak period passed.
+// It returns true if the IPv4 resources are kept.
+func (l4 *L4) keepPreviousIPv4Resources(result *L4ILBSyncResult) bool {
+	fr, err := previousIPFamilyForwardingRule(l4.forwardingRules, l4.Service, l4.GetFRName(), l4.getIPv6FRName(), l4.svcLogger)
+	if err != nil {
+		result.GCEResourceInError = annotations.ForwardingRuleResource
+		result.Error = err
+		return true
+	}
+	if fr == nil {
+		return false
+	}
+	result.Annotations[forwardingRuleAnnotationKey(fr)] = fr.Name
+	keepAnnotation(l4.Service, result.Annotations, annotations.FirewallRuleKey)
+	result.Status = utils.AddIPToLBStatus(result.Status, fr.IPAddress)
+	return true
+}
+
+// keepPreviousIPv6Resources keeps the IPv6 forwarding rule and nodes firewall of a Service
+// that switched to IPv4 only in a make-before-break IP version change, until the soak period passed.
+// It returns true if the IPv6 resources are kept.
+func (l4 *L4) keepPreviousIPv6Resources(result *L4ILBSyncResult) bool {
+	fr, err := previousIPFamilyForwardingRule(l4.forwardingRules, l4.Service, l4.getIPv6FRName(), l4.GetFRName(), l4.svcLogger)
+	if err != nil {
+		result.GCEResourceInError = annotations.ForwardingRuleIPv6Resource
+		result.Error = err
+		return true
+	}
+	if fr == nil {
+		return false
+	}
+	result.Annotations[ipv6ForwardingRuleAnnotationKey(fr)] = fr.Name
+	keepAnnotation(l4.Service, result.Annotations, annotations.FirewallRuleIPv6Key)
+	result.Status = utils.AddIPToLBStatus(result.Status, strings.Split(fr.IPAddress, "/")[0])
+	return true
+}
+
 // ensureIPv4Resources creates resources specific to IPv4 L4 Load Balancers:
 // - IPv4 Forwarding Rule
 // - IPv4 Firewall
-func (l4 *L4) ensureIPv4Resources(result *L4ILBSyncResult, nodeNames []string, options gce.ILBOptions, bs *composite.BackendService, existingFR *composite.ForwardingRule, subnetworkURL, ipToUse string) {
-	fr, err := l4.ensureIPv4ForwardingRule(bs.SelfLink, options, existingFR, subnetworkURL, ipToUse)
+func (l4 *L4) ensureIPv4Resources(result *L4ILBSyncResult, nodeNames []string, options ilbOptions, bs *composite.BackendService, existingFR, previousFR *composite.ForwardingRule, frName, subnetworkURL, ipToUse string) {
+	fr, err := l4.ensureIPv4ForwardingRule(frName, bs.SelfLink, options, existingFR, subnetworkURL, ipToUse)
 	if err != nil {
 		l4.svcLogger.Error(err, "ensureIPv4Resources: Failed to ensure forwarding rule for L4 ILB Service")
 		result.GCEResourceInError = annotations.ForwardingRuleResource
 		result.Error = err
 		return
 	}
-	if fr.IPProtocol == string(corev1.ProtocolTCP) {
-		result.Annotations[annotations.TCPForwardingRuleKey] = fr.Name
-	} else {
-		result.Annotations[annotations.UDPForwardingRuleKey] = fr.Name
+	result.Annotations[forwardingRuleAnnotationKey(fr)] = fr.Name
+
+	previousFR, err = ensurePreviousForwardingRule(l4.forwardingRules, l4.recorder, l4.Service, fr, previousFR, l4.svcLogger)
+	if err != nil {
+		l4.svcLogger.Error(err, "ensureIPv4Resources: Failed to delete previous forwarding rule for L4 ILB Service")
+		result.GCEResourceInError = annotations.ForwardingRuleResource
+		result.Error = err
+		return
+	}
+	additionalFRs, err := l4.ensureAdditionalForwardingRules(fr, l4.GetFRName(), IPv4Version)
+	if err != nil {
+		l4.svcLogger.Error(err, "ensureIPv4Resources: Failed to ensure additional forwarding rules for L4 ILB Service")
+		result.GCEResourceInError = annotations.ForwardingRuleResource
+		result.Error = err
+		return
 	}
+	if len(additionalFRs) > 0 {
+		result.Annotations[annotations.AdditionalForwardingRulesKey] = additionalFRNamesAnnotation(additionalFRs)
+	}
+	// Both forwarding rules serve traffic until the previous one is deleted.
+	ipAddresses := []string{fr.IPAddress}
+	if previousFR != nil {
+		ipAddresses = append(ipAddresses, previousFR.IPAddress)
+	}
+	ipAddresses = append(ipAddresses, additionalFRAddresses(additionalFRs)...)
 
-	l4.ensureIPv4NodesFirewall(nodeNames, fr.IPAddress, result)
+	l4.ensureIPv4NodesFirewall(nodeNames, ipAddresses, result)
 	if result.Error != nil {
 		l4.svcLogger.Error(err, "ensureIPv4Resources: Failed to ensure nodes firewall for L4 ILB Service")
 		return
 	}
 
-	result.Status = utils.AddIPToLBStatus(result.Status, fr.IPAddress)
+	result.Status = utils.AddIPToLBStatus(result.Status, ipAddresses...)
 }
 
-func (l4 *L4) ensureIPv4NodesFirewall(nodeNames []string, ipAddress string, result *L4ILBSyncResult) {
+func (l4 *L4) ensureIPv4NodesFirewall(nodeNames []string, ipAddresses []string, result *L4ILBSyncResult) {
 	// DisableL4LBFirewall flag disables L4 FW enforcment to remove conflicts with firewall policies
 	if l4.disableNodesFirewallProvisioning {
 		l4.svcLogger.Info("Skipped ensuring IPv4 nodes firewall for L4 ILB Service to enable compatibility with firewall policies. " +
@@ -628,7 +787,7 @@ func (l4 *L4) ensureIPv4NodesFirewall(nodeNames []string, ipAddress string, resu
 	portRanges := utils.GetServicePortRanges(servicePorts)
 
 	fwLogger := l4.svcLogger.WithValues("firewallName", firewallName)
-	fwLogger.V(2).Info("Ensuring IPv4 nodes firewall for L4 ILB Service", "ipAddress", ipAddress, "protocol", protocol, "len(nodeNames)", len(nodeNames), "portRanges", portRanges)
+	fwLogger.V(2).Info("Ensuring IPv4 nodes firewall for L4 ILB Service", "ipAddresses", ipAddresses, "protocol", protocol, "len(nodeNames)", len(nodeNames), "portRanges", portRanges)
 	defer func() {
 		fwLogger.V(2).Info("Finished ensuring IPv4 nodes firewall for L4 ILB Service", "timeTaken", time.Since(start))
 	}()
@@ -643,13 +802,16 @@ func (l4 *L4) ensureIPv4NodesFirewall(nodeNames []string, ipAddress string, resu
 	nodesFWRParams := firewalls.FirewallParams{
 		PortRanges:        portRanges,
 		SourceRanges:      ipv4SourceRanges,
-		DestinationRanges: []string{ipAddress},
+		DestinationRanges: ipAddresses,
 		Protocol:          string(protocol),
 		Name:              firewallName,
 		NodeNames:         nodeNames,
 		L4Type:            utils.ILB,
 		Network:           l4.network,
 	}
+	if utils.HasMixedProtocols(servicePorts) {
+		nodesFWRParams.ProtocolPortRanges = utils.GetServicePortRangesByProtocol(servicePorts)
+	}
 
 	_, err = firewalls.EnsureL4LBFirewallForNodes(l4.Service, &nodesFWRParams, l4.cloud, l4.recorder, fwLogger)
 	if err != nil {
@@ -660,7 +822,7 @@ func (l4 *L4) ensureIPv4NodesFirewall(nodeNames []string, ipAddress string, resu
 	result.Annotations[annotations.FirewallRuleKey] = firewallName
 }
 
-func (l4 *L4) getServiceSubnetworkURL(options gce.ILBOptions) (string, error) {
+func (l4 *L4) getServiceSubnetworkURL(options ilbOptions) (string, error) {
 	// Custom subnet feature is always enabled when running L4 controller.
 	// Changes to subnet annotation will be picked up and reflected in the forwarding rule.
 	// Removing the annotation will set the forwarding rule to use the default subnet.
@@ -690,16 +852,35 @@ func (l4 *L4) hasAnnotation(annotationKey string) bool {
 // because forwarding rule name depends on the protocol, and we need to get forwarding rule from the old protocol name.
 func (l4 *L4) getOldIPv4ForwardingRule(existingBS *composite.BackendService) (*composite.ForwardingRule, error) {
 	servicePorts := l4.Service.Spec.Ports
-	protocol := utils.GetProtocol(servicePorts)
+	protocol := utils.GetBackendServiceProtocol(servicePorts)
 
 	oldFRName := l4.GetFRName()
-	if existingBS != nil && existingBS.Protocol != string(protocol) {
+	if existingBS != nil && existingBS.Protocol != protocol {
 		oldFRName = l4.getFRNameWithProtocol(existingBS.Protocol)
 	}
 
 	return l4.forwardingRules.Get(oldFRName)
 }
 
+// l4ConnectionTrackingPolicy returns the backend service connection tracking policy
+// for the options of the L4 connection tracking annotation, or nil if there are none.
+func l4ConnectionTrackingPolicy(options *annotations.L4ConnectionTrackingOptions) *composite.BackendServiceConnectionTrackingPolicy {
+	if options == nil {
+		return nil
+	}
+	policy := &composite.BackendServiceConnectionTrackingPolicy{
+		TrackingMode:                             options.TrackingMode,
+		ConnectionPersistenceOnUnhealthyBackends: options.ConnectionPersistenceOnUnhealthyBackends,
+		IdleTimeoutSec:                           options.IdleTimeoutSec,
+	}
+	// GCE returns the default tracking mode when it is not specified,
+	// set it to avoid updating the backend service on every sync.
+	if policy.TrackingMode == "" {
+		policy.TrackingMode = annotations.TrackingModePerConnection
+	}
+	return policy
+}
+
 // determineBackendServiceLocalityPolicy returns the locality policy to be used for the backend service of the internal load balancer.
 func (l4 *L4) determineBackendServiceLocalityPolicy() backends.LocalityLBPolicyType {
 	// If the service has weighted load balancing enabled, the locality policy will be WEIGHTED_MAGLEV.
@@ -24,6 +24,7 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/api/compute/v1"
@@ -31,6 +32,7 @@ import (
 	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/backends"
 	"k8s.io/ingress-gce/pkg/firewalls"
+	"k8s.io/ingress-gce/pkg/flags"
 	"k8s.io/ingress-gce/pkg/healthchecksl4"
 	"k8s.io/ingress-gce/pkg/network"
 	"k8s.io/ingress-gce/pkg/utils"
@@ -64,6 +66,7 @@ func getFakeGCECloud(vals gce.TestClusterValues) *gce.Cloud {
 	(fakeGCE.Compute().(*cloud.MockGCE)).MockAddresses.X = mock.AddressAttributes{}
 	(fakeGCE.Compute().(*cloud.MockGCE)).MockForwardingRules.InsertHook = mock.InsertFwdRuleHook
 	(fakeGCE.Compute().(*cloud.MockGCE)).MockForwardingRules.PatchHook = PatchForwardingRuleHook
+	(fakeGCE.Compute().(*cloud.MockGCE)).MockForwardingRules.SetLabelsHook = SetLabelsForwardingRuleHook
 	(fakeGCE.Compute().(*cloud.MockGCE)).MockRegionBackendServices.UpdateHook = mock.UpdateRegionBackendServiceHook
 	(fakeGCE.Compute().(*cloud.MockGCE)).MockHealthChecks.UpdateHook = mock.UpdateHealthCheckHook
 	(fakeGCE.Compute().(*cloud.MockGCE)).MockFirewalls.PatchHook = mock.UpdateFirewallHook
@@ -106,6 +109,17 @@ func PatchForwardingRuleHook(ctx context.Context, key *meta.Key, obj *ga.Forward
 	return nil
 }
 
+func SetLabelsForwardingRuleHook(ctx context.Context, key *meta.Key, req *ga.RegionSetLabelsRequest, m *cloud.MockForwardingRules, options ...cloud.Option) error {
+	existingObj, ok := m.Objects[*key]
+	if !ok {
+		return fmt.Errorf("MockForwardingRule %v does not exists", key)
+	}
+	existingFr := existingObj.ToGA()
+	existingFr.Labels = req.Labels
+	m.Objects[*key] = &cloud.MockForwardingRulesObj{Obj: existingFr}
+	return nil
+}
+
 func TestEnsureInternalBackendServiceUpdates(t *testing.T) {
 	t.Parallel()
 	fakeGCE := getFakeGCECloud(gce.DefaultTestClusterValues())
@@ -905,6 +919,62 @@ func TestEnsureInternalLoadBalancerWithSpecialHealthCheck(t *testing.T) {
 	}
 }
 
+func TestEnsureInternalLoadBalancerDeletesReplacedHealthCheck(t *testing.T) {
+	vals := gce.DefaultTestClusterValues()
+	fakeGCE := getFakeGCECloud(vals)
+
+	nodeNames := []string{"test-node-1"}
+	svc := test.NewL4ILBService(false, 8080)
+	svc.Annotations[annotations.L4HealthCheckKey] = `{"unhealthyThreshold": 5}`
+	namer := namer_util.NewL4Namer(kubeSystemUID, nil)
+
+	l4ilbParams := &L4ILBParams{
+		Service:         svc,
+		Cloud:           fakeGCE,
+		Namer:           namer,
+		Recorder:        record.NewFakeRecorder(100),
+		NetworkResolver: network.NewFakeResolver(network.DefaultNetwork(fakeGCE)),
+	}
+	l4 := NewL4Handler(l4ilbParams, klog.TODO())
+	l4.healthChecks = healthchecksl4.Fake(fakeGCE, l4ilbParams.Recorder)
+
+	if _, err := test.CreateAndInsertNodes(l4.cloud, nodeNames, vals.ZoneName); err != nil {
+		t.Errorf("Unexpected error when adding nodes %v", err)
+	}
+
+	dedicatedHCName := namer.L4HealthCheck(svc.Namespace, svc.Name, false)
+	result := l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	if got := result.Annotations[annotations.HealthcheckKey]; got != dedicatedHCName {
+		t.Fatalf("Service uses health check %q, want the dedicated health check %q", got, dedicatedHCName)
+	}
+
+	// The controller records the health check in the Service annotations.
+	for key, value := range result.Annotations {
+		svc.Annotations[key] = value
+	}
+	delete(svc.Annotations, annotations.L4HealthCheckKey)
+	result = l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	if got, want := result.Annotations[annotations.HealthcheckKey], namer.L4HealthCheck(svc.Namespace, svc.Name, true); got != want {
+		t.Errorf("Service uses health check %q, want the shared health check %q", got, want)
+	}
+	key, err := composite.CreateKey(l4.cloud, dedicatedHCName, meta.Global)
+	if err != nil {
+		t.Fatalf("Unexpected error when creating key - %v", err)
+	}
+	if _, err := composite.GetHealthCheck(l4.cloud, key, meta.VersionGA, klog.TODO()); !utils.IsNotFoundError(err) {
+		t.Errorf("Dedicated health check %s was not deleted, err %v", dedicatedHCName, err)
+	}
+	if _, err := l4.cloud.GetFirewall(namer.L4HealthCheckFirewall(svc.Namespace, svc.Name, false)); !utils.IsNotFoundError(err) {
+		t.Errorf("Firewall of the dedicated health check was not deleted, err %v", err)
+	}
+}
+
 type EnsureILBParams struct {
 	service         *v1.Service
 	networkResolver network.Resolver
@@ -1858,7 +1928,7 @@ func TestEnsureIPv4Firewall4Nodes(t *testing.T) {
 		Annotations: make(map[string]string),
 	}
 
-	l4.ensureIPv4NodesFirewall(nodeNames, "10.0.0.7", syncResult)
+	l4.ensureIPv4NodesFirewall(nodeNames, []string{"10.0.0.7"}, syncResult)
 	if syncResult.Error != nil {
 		t.Fatalf("ensureIPv4NodesFirewall() error %+v", syncResult)
 	}
@@ -1913,7 +1983,7 @@ func TestEnsureIPv6Firewall4Nodes(t *testing.T) {
 		Annotations: make(map[string]string),
 	}
 
-	l4.ensureIPv6NodesFirewall("2001:db8::ff00:42:8329", nodeNames, syncResult)
+	l4.ensureIPv6NodesFirewall([]string{"2001:db8::ff00:42:8329"}, nodeNames, syncResult)
 	if syncResult.Error != nil {
 		t.Fatalf("ensureIPv6NodesFirewall() error %+v", syncResult)
 	}
@@ -2219,6 +2289,126 @@ func TestDualStackILBStaticIPAnnotation(t *testing.T) {
 	}
 }
 
+func TestEnsureInternalLoadBalancerMultipleIPv4Addresses(t *testing.T) {
+	oldEnablePinhole := flags.F.EnablePinhole
+	flags.F.EnablePinhole = true
+	defer func() {
+		flags.F.EnablePinhole = oldEnablePinhole
+	}()
+	nodeNames := []string{"test-node-1"}
+	svc := test.NewL4ILBService(false, 8080)
+	l4 := mustSetupILBTestHandler(t, svc, nodeNames)
+
+	addresses := map[string]string{
+		"ipv4-address-1": "10.1.1.1",
+		"ipv4-address-2": "10.1.1.2",
+		"ipv4-address-3": "10.1.1.3",
+	}
+	for name, address := range addresses {
+		if err := l4.cloud.ReserveRegionAddress(&compute.Address{Name: name, Address: address, AddressType: "INTERNAL"}, l4.cloud.Region()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	frName := l4.GetFRName()
+
+	for _, step := range []struct {
+		desc           string
+		annotation     string
+		wantIPs        []string
+		wantAdditional map[string]string
+		wantAnnotation string
+		wantDeletedFRs []string
+	}{
+		{
+			desc:           "three addresses",
+			annotation:     "ipv4-address-1,ipv4-address-2,ipv4-address-3",
+			wantIPs:        []string{"10.1.1.1", "10.1.1.2", "10.1.1.3"},
+			wantAdditional: map[string]string{frName + "-1": "10.1.1.2", frName + "-2": "10.1.1.3"},
+			wantAnnotation: frName + "-1," + frName + "-2",
+		},
+		{
+			desc:           "remove an additional address",
+			annotation:     "ipv4-address-1,ipv4-address-3",
+			wantIPs:        []string{"10.1.1.1", "10.1.1.3"},
+			wantAdditional: map[string]string{frName + "-1": "10.1.1.3"},
+			wantAnnotation: frName + "-1",
+			wantDeletedFRs: []string{frName + "-2"},
+		},
+		{
+			desc:           "single address",
+			annotation:     "ipv4-address-1",
+			wantIPs:        []string{"10.1.1.1"},
+			wantDeletedFRs: []string{frName + "-1", frName + "-2"},
+		},
+	} {
+		svc.Annotations[annotations.StaticL4AddressesAnnotationKey] = step.annotation
+		result := l4.EnsureInternalLoadBalancer(nodeNames, svc)
+		if result.Error != nil {
+			t.Fatalf("%s: failed to ensure loadBalancer, err %v", step.desc, result.Error)
+		}
+		svc.Annotations = result.Annotations
+		svc.Annotations[annotations.StaticL4AddressesAnnotationKey] = step.annotation
+
+		var gotIPs []string
+		for _, ip := range result.Status.Ingress {
+			gotIPs = append(gotIPs, ip.IP)
+		}
+		if diff := cmp.Diff(step.wantIPs, gotIPs); diff != "" {
+			t.Errorf("%s: unexpected load balancer IPs (-want +got):\n%s", step.desc, diff)
+		}
+		if got := result.Annotations[annotations.AdditionalForwardingRulesKey]; got != step.wantAnnotation {
+			t.Errorf("%s: got additional forwarding rules annotation %q, want %q", step.desc, got, step.wantAnnotation)
+		}
+		mainFR, err := l4.forwardingRules.Get(frName)
+		if err != nil || mainFR == nil {
+			t.Fatalf("%s: forwarding rule %s not found, err %v", step.desc, frName, err)
+		}
+		for name, address := range step.wantAdditional {
+			fr, err := l4.forwardingRules.Get(name)
+			if err != nil || fr == nil {
+				t.Fatalf("%s: additional forwarding rule %s not found, err %v", step.desc, name, err)
+			}
+			if fr.IPAddress != address {
+				t.Errorf("%s: additional forwarding rule %s has address %s, want %s", step.desc, name, fr.IPAddress, address)
+			}
+			if fr.BackendService != mainFR.BackendService {
+				t.Errorf("%s: additional forwarding rule %s points to backend service %s, want %s", step.desc, name, fr.BackendService, mainFR.BackendService)
+			}
+		}
+		for _, name := range step.wantDeletedFRs {
+			if fr, err := l4.forwardingRules.Get(name); err != nil || fr != nil {
+				t.Errorf("%s: expected additional forwarding rule %s to be deleted, got %v, err %v", step.desc, name, fr, err)
+			}
+		}
+		fw, err := l4.cloud.GetFirewall(l4.namer.L4Firewall(svc.Namespace, svc.Name))
+		if err != nil {
+			t.Fatalf("%s: failed to get firewall, err %v", step.desc, err)
+		}
+		if !utils.EqualStringSets(step.wantIPs, fw.DestinationRanges) {
+			t.Errorf("%s: got firewall destination ranges %v, want %v", step.desc, fw.DestinationRanges, step.wantIPs)
+		}
+	}
+
+	// Additional forwarding rules are deleted with the load balancer.
+	svc.Annotations[annotations.StaticL4AddressesAnnotationKey] = "ipv4-address-1,ipv4-address-2,ipv4-address-3"
+	result := l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	svc.Annotations = result.Annotations
+	svc.Annotations[annotations.StaticL4AddressesAnnotationKey] = "ipv4-address-1,ipv4-address-2,ipv4-address-3"
+	result = l4.EnsureInternalLoadBalancerDeleted(svc)
+	if result.Error != nil {
+		t.Fatalf("Unexpected error deleting loadbalancer - err %v", result.Error)
+	}
+	assertILBResourcesDeleted(t, l4)
+	for _, name := range []string{frName + "-1", frName + "-2"} {
+		if fr, err := l4.forwardingRules.Get(name); err != nil || fr != nil {
+			t.Errorf("Expected additional forwarding rule %s to be deleted, got %v, err %v", name, fr, err)
+		}
+	}
+}
+
 func TestWeightedILB(t *testing.T) {
 	t.Parallel()
 
@@ -2332,7 +2522,7 @@ func TestDisableILBIngressFirewall(t *testing.T) {
 		Annotations: make(map[string]string),
 	}
 
-	l4.ensureIPv4NodesFirewall(nodeNames, "10.0.0.7", syncResult)
+	l4.ensureIPv4NodesFirewall(nodeNames, []string{"10.0.0.7"}, syncResult)
 	if syncResult.Error != nil {
 		t.Fatalf("ensureIPv4NodesFirewall() error %+v", syncResult)
 	}
@@ -2343,7 +2533,7 @@ func TestDisableILBIngressFirewall(t *testing.T) {
 		t.Errorf("verifyFirewallNotExists(_, %s) for IPv4 ILB firewall returned error %v, want nil", ipv4FirewallName, err)
 	}
 
-	l4.ensureIPv6NodesFirewall("2001:db8::ff00:42:8329", nodeNames, syncResult)
+	l4.ensureIPv6NodesFirewall([]string{"2001:db8::ff00:42:8329"}, nodeNames, syncResult)
 	if syncResult.Error != nil {
 		t.Fatalf("ensureIPv6NodesFirewall() error %+v", syncResult)
 	}
@@ -2354,6 +2544,366 @@ func TestDisableILBIngressFirewall(t *testing.T) {
 	}
 }
 
+func TestEnsureInternalLoadBalancerMixedProtocol(t *testing.T) {
+	t.Parallel()
+
+	nodeNames := []string{"test-node-1"}
+	svc := test.NewL4ILBService(false, 53)
+	l4 := mustSetupILBTestHandler(t, svc, nodeNames)
+	l4.enableDualStack = false
+
+	result := l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	tcpFRName := l4.getFRNameWithProtocol("TCP")
+
+	// Add a UDP port on the same port number, the service now mixes protocols.
+	svc.Spec.Ports = append(svc.Spec.Ports, v1.ServicePort{Name: "dns-udp", Port: 53, Protocol: v1.ProtocolUDP})
+	result = l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	if len(result.Status.Ingress) == 0 {
+		t.Errorf("Got empty loadBalancer status using handler %v", l4)
+	}
+	if err := verifyForwardingRuleNotExists(l4.cloud, tcpFRName); err != nil {
+		t.Errorf("verifyForwardingRuleNotExists(_, %s) returned error %v, want nil", tcpFRName, err)
+	}
+
+	frName := l4.GetFRName()
+	if frName != l4.getFRNameWithProtocol(utils.L3DefaultProtocol) {
+		t.Errorf("GetFRName() = %q, want L3 forwarding rule name", frName)
+	}
+	if got := result.Annotations[annotations.L3ForwardingRuleKey]; got != frName {
+		t.Errorf("Annotation %s = %q, want %q", annotations.L3ForwardingRuleKey, got, frName)
+	}
+	fwdRule, err := composite.GetForwardingRule(l4.cloud, meta.RegionalKey(frName, l4.cloud.Region()), meta.VersionGA, klog.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error when looking up forwarding rule - %v", err)
+	}
+	if fwdRule.IPProtocol != utils.L3DefaultProtocol || !fwdRule.AllPorts || len(fwdRule.Ports) != 0 {
+		t.Errorf("Unexpected forwarding rule protocol %q, allPorts %v, ports %v, want L3_DEFAULT with all ports", fwdRule.IPProtocol, fwdRule.AllPorts, fwdRule.Ports)
+	}
+
+	bsName := l4.namer.L4Backend(svc.Namespace, svc.Name)
+	bs, err := composite.GetBackendService(l4.cloud, meta.RegionalKey(bsName, l4.cloud.Region()), meta.VersionGA, klog.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error when looking up backend service - %v", err)
+	}
+	if bs.Protocol != utils.UnspecifiedProtocol {
+		t.Errorf("Unexpected backend service protocol %q, want %q", bs.Protocol, utils.UnspecifiedProtocol)
+	}
+
+	fw, err := firewalls.NewFirewallAdapter(l4.cloud).GetFirewall(l4.namer.L4Firewall(svc.Namespace, svc.Name))
+	if err != nil {
+		t.Fatalf("Unexpected error when looking up firewall - %v", err)
+	}
+	wantAllowed := []*ga.FirewallAllowed{
+		{IPProtocol: "tcp", Ports: []string{"53"}},
+		{IPProtocol: "udp", Ports: []string{"53"}},
+	}
+	if diff := cmp.Diff(wantAllowed, fw.Allowed); diff != "" {
+		t.Errorf("Unexpected firewall allowed rules (-want +got):\n%s", diff)
+	}
+
+	l4.Service.Annotations = result.Annotations
+	result = l4.EnsureInternalLoadBalancerDeleted(svc)
+	if result.Error != nil {
+		t.Errorf("Unexpected error %v", result.Error)
+	}
+	assertILBResourcesDeleted(t, l4)
+}
+
+func TestEnsureInternalLoadBalancerL4Options(t *testing.T) {
+	t.Parallel()
+
+	nodeNames := []string{"test-node-1"}
+	svc := test.NewL4ILBService(false, 8080)
+	svc.Annotations[annotations.L4OptionsKey] = `{"globalAccess": true, "labels": {"team": "dns"}, "serviceDirectory": {"namespace": "ns", "service": "svc"}}`
+	l4 := mustSetupILBTestHandler(t, svc, nodeNames)
+	l4.enableDualStack = false
+
+	result := l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	frName := l4.GetFRName()
+	frKey := meta.RegionalKey(frName, l4.cloud.Region())
+	fwdRule, err := composite.GetForwardingRule(l4.cloud, frKey, meta.VersionGA, klog.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error when looking up forwarding rule - %v", err)
+	}
+	if !fwdRule.AllowGlobalAccess {
+		t.Errorf("Forwarding rule %s has global access disabled, want enabled", frName)
+	}
+	if diff := cmp.Diff(map[string]string{"team": "dns"}, fwdRule.Labels); diff != "" {
+		t.Errorf("Unexpected forwarding rule labels (-want +got):\n%s", diff)
+	}
+	wantSDR := []*composite.ForwardingRuleServiceDirectoryRegistration{{Namespace: "ns", Service: "svc"}}
+	if diff := cmp.Diff(wantSDR, fwdRule.ServiceDirectoryRegistrations); diff != "" {
+		t.Errorf("Unexpected forwarding rule service directory registrations (-want +got):\n%s", diff)
+	}
+
+	// Labels and global access are updated in place.
+	svc.Annotations[annotations.L4OptionsKey] = `{"globalAccess": false, "labels": {"team": "web"}, "serviceDirectory": {"namespace": "ns", "service": "svc"}}`
+	c := l4.cloud.Compute().(*cloud.MockGCE)
+	c.MockForwardingRules.DeleteHook = func(_ context.Context, key *meta.Key, _ *cloud.MockForwardingRules, _ ...cloud.Option) (bool, error) {
+		return true, fmt.Errorf("unexpected delete of forwarding rule %v", key)
+	}
+	result = l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	fwdRule, err = composite.GetForwardingRule(l4.cloud, frKey, meta.VersionGA, klog.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error when looking up forwarding rule - %v", err)
+	}
+	if fwdRule.AllowGlobalAccess {
+		t.Errorf("Forwarding rule %s has global access enabled, want disabled", frName)
+	}
+	if diff := cmp.Diff(map[string]string{"team": "web"}, fwdRule.Labels); diff != "" {
+		t.Errorf("Unexpected forwarding rule labels (-want +got):\n%s", diff)
+	}
+	c.MockForwardingRules.DeleteHook = nil
+	syncedAnnotations := result.Annotations
+
+	// An invalid annotation fails the sync with a user error.
+	svc.Annotations[annotations.L4OptionsKey] = `{"labels": {"Team": "web"}}`
+	result = l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if !utils.IsUserError(result.Error) {
+		t.Errorf("EnsureInternalLoadBalancer() returned error %v, want user error", result.Error)
+	}
+
+	l4.Service.Annotations = syncedAnnotations
+	result = l4.EnsureInternalLoadBalancerDeleted(svc)
+	if result.Error != nil {
+		t.Errorf("Unexpected error %v", result.Error)
+	}
+	assertILBResourcesDeleted(t, l4)
+}
+
+func TestEnsureInternalLoadBalancerConnectionTracking(t *testing.T) {
+	t.Parallel()
+
+	nodeNames := []string{"test-node-1"}
+	svc := test.NewL4ILBService(false, 8080)
+	svc.Spec.SessionAffinity = v1.ServiceAffinityClientIP
+	svc.Annotations[annotations.L4ConnectionTrackingKey] = `{"trackingMode": "PER_SESSION", "connectionPersistenceOnUnhealthyBackends": "NEVER_PERSIST", "idleTimeoutSec": 3600}`
+	l4 := mustSetupILBTestHandler(t, svc, nodeNames)
+	(l4.cloud.Compute().(*cloud.MockGCE)).MockRegionBackendServices.UpdateHook = mock.UpdateRegionBackendServiceHook
+	bsKey := meta.RegionalKey(l4.namer.L4Backend(svc.Namespace, svc.Name), l4.cloud.Region())
+
+	for _, step := range []struct {
+		desc       string
+		annotation string
+		wantPolicy *composite.BackendServiceConnectionTrackingPolicy
+	}{
+		{
+			desc:       "create with a connection tracking policy",
+			annotation: `{"trackingMode": "PER_SESSION", "connectionPersistenceOnUnhealthyBackends": "NEVER_PERSIST", "idleTimeoutSec": 3600}`,
+			wantPolicy: &composite.BackendServiceConnectionTrackingPolicy{
+				TrackingMode:                             annotations.TrackingModePerSession,
+				ConnectionPersistenceOnUnhealthyBackends: annotations.ConnectionPersistenceNeverPersist,
+				IdleTimeoutSec:                           3600,
+			},
+		},
+		{
+			desc:       "update the idle timeout",
+			annotation: `{"trackingMode": "PER_SESSION", "idleTimeoutSec": 57600}`,
+			wantPolicy: &composite.BackendServiceConnectionTrackingPolicy{
+				TrackingMode:   annotations.TrackingModePerSession,
+				IdleTimeoutSec: 57600,
+			},
+		},
+		{
+			desc:       "default tracking mode",
+			annotation: `{"connectionPersistenceOnUnhealthyBackends": "ALWAYS_PERSIST"}`,
+			wantPolicy: &composite.BackendServiceConnectionTrackingPolicy{
+				TrackingMode:                             annotations.TrackingModePerConnection,
+				ConnectionPersistenceOnUnhealthyBackends: annotations.ConnectionPersistenceAlwaysPersist,
+			},
+		},
+		{
+			desc: "remove the annotation",
+		},
+	} {
+		if step.annotation != "" {
+			svc.Annotations[annotations.L4ConnectionTrackingKey] = step.annotation
+		} else {
+			delete(svc.Annotations, annotations.L4ConnectionTrackingKey)
+		}
+		result := l4.EnsureInternalLoadBalancer(nodeNames, svc)
+		if result.Error != nil {
+			t.Fatalf("%s: failed to ensure loadBalancer, err %v", step.desc, result.Error)
+		}
+		bs, err := composite.GetBackendService(l4.cloud, bsKey, meta.VersionGA, klog.TODO())
+		if err != nil {
+			t.Fatalf("%s: failed to read BackendService, %v", step.desc, err)
+		}
+		if diff := cmp.Diff(step.wantPolicy, bs.ConnectionTrackingPolicy); diff != "" {
+			t.Errorf("%s: unexpected BackendService ConnectionTrackingPolicy (-want +got):\n%s", step.desc, diff)
+		}
+	}
+
+	// An invalid annotation fails the sync with a user error.
+	svc.Annotations[annotations.L4ConnectionTrackingKey] = `{"idleTimeoutSec": 3600}`
+	result := l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if !utils.IsUserError(result.Error) {
+		t.Errorf("EnsureInternalLoadBalancer() returned error %v, want user error", result.Error)
+	}
+}
+
+func TestEnsureInternalLoadBalancerMakeBeforeBreak(t *testing.T) {
+	oldSoakPeriod, oldEnablePinhole := flags.F.L4ForwardingRuleSoakPeriod, flags.F.EnablePinhole
+	flags.F.L4ForwardingRuleSoakPeriod = time.Hour
+	flags.F.EnablePinhole = true
+	defer func() {
+		flags.F.L4ForwardingRuleSoakPeriod, flags.F.EnablePinhole = oldSoakPeriod, oldEnablePinhole
+	}()
+	nodeNames := []string{"test-node-1"}
+
+	svc := test.NewL4ILBService(false, 8080)
+	svc.Annotations[annotations.MakeBeforeBreakAnnotationKey] = annotations.MakeBeforeBreakEnabled
+	l4 := mustSetupILBTestHandler(t, svc, nodeNames)
+	l4.enableDualStack = false
+
+	result := l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	frName := l4.GetFRName()
+	previousFwdRule, err := composite.GetForwardingRule(l4.cloud, meta.RegionalKey(frName, l4.cloud.Region()), meta.VersionGA, klog.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error when looking up forwarding rule - %v", err)
+	}
+
+	// Changing the subnet creates a replacement forwarding rule next to the existing one.
+	svc.Annotations[annotations.CustomSubnetAnnotationKey] = "test-subnet"
+	result = l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	if _, err := composite.GetForwardingRule(l4.cloud, meta.RegionalKey(frName, l4.cloud.Region()), meta.VersionGA, klog.TODO()); err != nil {
+		t.Errorf("Previous forwarding rule %s should still exist, got error %v", frName, err)
+	}
+	alternateFRName := l4.alternateIPv4FRName(frName)
+	replacementFwdRule, err := composite.GetForwardingRule(l4.cloud, meta.RegionalKey(alternateFRName, l4.cloud.Region()), meta.VersionGA, klog.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error when looking up replacement forwarding rule - %v", err)
+	}
+	if !strings.HasSuffix(replacementFwdRule.Subnetwork, "test-subnet") {
+		t.Errorf("Replacement forwarding rule subnetwork = %q, want test-subnet", replacementFwdRule.Subnetwork)
+	}
+	if got := result.Annotations[annotations.TCPForwardingRuleKey]; got != alternateFRName {
+		t.Errorf("Annotation %s = %q, want %q", annotations.TCPForwardingRuleKey, got, alternateFRName)
+	}
+	wantIPs := []string{replacementFwdRule.IPAddress, previousFwdRule.IPAddress}
+	var gotIPs []string
+	for _, ingress := range result.Status.Ingress {
+		gotIPs = append(gotIPs, ingress.IP)
+	}
+	if !utils.EqualStringSets(gotIPs, wantIPs) {
+		t.Errorf("Load balancer status IPs = %v, want IPs of both forwarding rules %v", gotIPs, wantIPs)
+	}
+	fw, err := firewalls.NewFirewallAdapter(l4.cloud).GetFirewall(l4.namer.L4Firewall(svc.Namespace, svc.Name))
+	if err != nil {
+		t.Fatalf("Unexpected error when looking up firewall - %v", err)
+	}
+	if !utils.EqualStringSets(fw.DestinationRanges, wantIPs) {
+		t.Errorf("Firewall destination ranges = %v, want IPs of both forwarding rules %v", fw.DestinationRanges, wantIPs)
+	}
+
+	// Later syncs keep both forwarding rules during the soak period.
+	result = l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	if _, err := composite.GetForwardingRule(l4.cloud, meta.RegionalKey(frName, l4.cloud.Region()), meta.VersionGA, klog.TODO()); err != nil {
+		t.Errorf("Previous forwarding rule %s should still exist, got error %v", frName, err)
+	}
+
+	// The previous forwarding rule is removed on request.
+	svc.Annotations[annotations.RemovePreviousForwardingRuleAnnotationKey] = "true"
+	result = l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	if err := verifyForwardingRuleNotExists(l4.cloud, frName); err != nil {
+		t.Errorf("verifyForwardingRuleNotExists(_, %s) returned error %v, want nil", frName, err)
+	}
+	if len(result.Status.Ingress) != 1 || result.Status.Ingress[0].IP != replacementFwdRule.IPAddress {
+		t.Errorf("Load balancer status = %+v, want only IP %s", result.Status.Ingress, replacementFwdRule.IPAddress)
+	}
+
+	l4.Service.Annotations = result.Annotations
+	result = l4.EnsureInternalLoadBalancerDeleted(svc)
+	if result.Error != nil {
+		t.Errorf("Unexpected error %v", result.Error)
+	}
+	if err := verifyForwardingRuleNotExists(l4.cloud, alternateFRName); err != nil {
+		t.Errorf("verifyForwardingRuleNotExists(_, %s) returned error %v, want nil", alternateFRName, err)
+	}
+	assertILBResourcesDeleted(t, l4)
+}
+
+func TestDualStackILBMakeBeforeBreakIPVersionChange(t *testing.T) {
+	oldSoakPeriod := flags.F.L4ForwardingRuleSoakPeriod
+	flags.F.L4ForwardingRuleSoakPeriod = time.Hour
+	defer func() {
+		flags.F.L4ForwardingRuleSoakPeriod = oldSoakPeriod
+	}()
+	nodeNames := []string{"test-node-1"}
+
+	svc := test.NewL4ILBDualStackService(8080, v1.ProtocolTCP, []v1.IPFamily{v1.IPv4Protocol}, v1.ServiceExternalTrafficPolicyTypeCluster)
+	svc.Annotations[annotations.MakeBeforeBreakAnnotationKey] = annotations.MakeBeforeBreakEnabled
+	l4 := mustSetupILBTestHandler(t, svc, nodeNames)
+
+	result := l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	for k, v := range result.Annotations {
+		svc.Annotations[k] = v
+	}
+	ipv4FRName := l4.GetFRName()
+
+	// Switching to IPv6 only keeps the IPv4 forwarding rule serving traffic during the soak period.
+	svc.Spec.IPFamilies = []v1.IPFamily{v1.IPv6Protocol}
+	result = l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	if _, err := composite.GetForwardingRule(l4.cloud, meta.RegionalKey(ipv4FRName, l4.cloud.Region()), meta.VersionGA, klog.TODO()); err != nil {
+		t.Errorf("IPv4 forwarding rule %s should still exist, got error %v", ipv4FRName, err)
+	}
+	if got := result.Annotations[annotations.TCPForwardingRuleKey]; got != ipv4FRName {
+		t.Errorf("Annotation %s = %q, want %q", annotations.TCPForwardingRuleKey, got, ipv4FRName)
+	}
+	if got := result.Annotations[annotations.TCPForwardingRuleIPv6Key]; got != l4.getIPv6FRName() {
+		t.Errorf("Annotation %s = %q, want %q", annotations.TCPForwardingRuleIPv6Key, got, l4.getIPv6FRName())
+	}
+	if len(result.Status.Ingress) != 2 {
+		t.Errorf("Load balancer status = %+v, want IPs of both IP families", result.Status.Ingress)
+	}
+	for k, v := range result.Annotations {
+		svc.Annotations[k] = v
+	}
+
+	// The IPv4 resources are removed on request.
+	svc.Annotations[annotations.RemovePreviousForwardingRuleAnnotationKey] = "true"
+	result = l4.EnsureInternalLoadBalancer(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	if err := verifyForwardingRuleNotExists(l4.cloud, ipv4FRName); err != nil {
+		t.Errorf("verifyForwardingRuleNotExists(_, %s) returned error %v, want nil", ipv4FRName, err)
+	}
+	if len(result.Status.Ingress) != 1 {
+		t.Errorf("Load balancer status = %+v, want only the IPv6 address", result.Status.Ingress)
+	}
+}
+
 func mustSetupILBTestHandler(t *testing.T, svc *v1.Service, nodeNames []string) *L4 {
 	vals := gce.DefaultTestClusterValues()
 
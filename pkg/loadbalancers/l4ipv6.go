@@ -21,8 +21,6 @@ import (
 	"strings"
 	"time"
 
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/cloud-provider-gcp/providers/gce"
 	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/composite"
 	"k8s.io/ingress-gce/pkg/firewalls"
@@ -33,7 +31,7 @@ import (
 // - IPv6 Forwarding Rule
 // - IPv6 Firewall
 // it also adds IPv6 address to LB status
-func (l4 *L4) ensureIPv6Resources(syncResult *L4ILBSyncResult, nodeNames []string, options gce.ILBOptions, bsLink string, existingIPv6FwdRule *composite.ForwardingRule, ipv6AddressToUse string) {
+func (l4 *L4) ensureIPv6Resources(syncResult *L4ILBSyncResult, nodeNames []string, options ilbOptions, bsLink string, existingIPv6FwdRule *composite.ForwardingRule, ipv6AddressToUse string) {
 	ipv6fr, err := l4.ensureIPv6ForwardingRule(bsLink, options, existingIPv6FwdRule, ipv6AddressToUse)
 	if err != nil {
 		l4.svcLogger.Error(err, "ensureIPv6Resources: Failed to ensure ipv6 forwarding rule")
@@ -42,22 +40,30 @@ func (l4 *L4) ensureIPv6Resources(syncResult *L4ILBSyncResult, nodeNames []strin
 		return
 	}
 
-	if ipv6fr.IPProtocol == string(corev1.ProtocolTCP) {
-		syncResult.Annotations[annotations.TCPForwardingRuleIPv6Key] = ipv6fr.Name
-	} else {
-		syncResult.Annotations[annotations.UDPForwardingRuleIPv6Key] = ipv6fr.Name
+	syncResult.Annotations[ipv6ForwardingRuleAnnotationKey(ipv6fr)] = ipv6fr.Name
+
+	additionalFRs, err := l4.ensureAdditionalForwardingRules(ipv6fr, l4.getIPv6FRName(), IPv6Version)
+	if err != nil {
+		l4.svcLogger.Error(err, "ensureIPv6Resources: Failed to ensure additional ipv6 forwarding rules")
+		syncResult.GCEResourceInError = annotations.ForwardingRuleIPv6Resource
+		syncResult.Error = err
+		return
+	}
+	if len(additionalFRs) > 0 {
+		syncResult.Annotations[annotations.AdditionalForwardingRulesIPv6Key] = additionalFRNamesAnnotation(additionalFRs)
 	}
 
 	// Google Cloud creates ipv6 forwarding rules with IPAddress in CIDR form. We will take only first address
 	trimmedIPv6Address := strings.Split(ipv6fr.IPAddress, "/")[0]
+	ipv6Addresses := append([]string{trimmedIPv6Address}, additionalFRAddresses(additionalFRs)...)
 
-	l4.ensureIPv6NodesFirewall(trimmedIPv6Address, nodeNames, syncResult)
+	l4.ensureIPv6NodesFirewall(ipv6Addresses, nodeNames, syncResult)
 	if syncResult.Error != nil {
 		l4.svcLogger.Error(err, "ensureIPv6Resources: Failed to ensure ipv6 nodes firewall for L4 ILB")
 		return
 	}
 
-	syncResult.Status = utils.AddIPToLBStatus(syncResult.Status, trimmedIPv6Address)
+	syncResult.Status = utils.AddIPToLBStatus(syncResult.Status, ipv6Addresses...)
 }
 
 // deleteIPv6ResourcesOnSync deletes resources specific to IPv6,
@@ -89,7 +95,7 @@ func (l4 *L4) deleteIPv6ResourcesOnDelete(syncResult *L4ILBSyncResult) {
 // This function does not delete Backend Service and Health Check, because they are shared between IPv4 and IPv6.
 // IPv6 Firewall Rule for Health Check also will not be deleted here, and will be left till the Service Deletion.
 func (l4 *L4) deleteIPv6ResourcesAnnotationBased(syncResult *L4ILBSyncResult, shouldCheckAnnotations bool) {
-	if !shouldCheckAnnotations || l4.hasAnnotation(annotations.TCPForwardingRuleIPv6Key) || l4.hasAnnotation(annotations.UDPForwardingRuleIPv6Key) {
+	if !shouldCheckAnnotations || l4.hasAnnotation(annotations.TCPForwardingRuleIPv6Key) || l4.hasAnnotation(annotations.UDPForwardingRuleIPv6Key) || l4.hasAnnotation(annotations.L3ForwardingRuleIPv6Key) {
 		err := l4.deleteIPv6ForwardingRule()
 		if err != nil {
 			l4.svcLogger.Error(err, "Failed to delete ipv6 forwarding rule for internal loadbalancer service")
@@ -97,6 +103,11 @@ func (l4 *L4) deleteIPv6ResourcesAnnotationBased(syncResult *L4ILBSyncResult, sh
 			syncResult.GCEResourceInError = annotations.ForwardingRuleIPv6Resource
 		}
 	}
+	if err := l4.deleteAdditionalForwardingRules(l4.getIPv6FRName(), IPv6Version, !shouldCheckAnnotations); err != nil {
+		l4.svcLogger.Error(err, "Failed to delete additional ipv6 forwarding rules for internal loadbalancer service")
+		syncResult.Error = err
+		syncResult.GCEResourceInError = annotations.ForwardingRuleIPv6Resource
+	}
 
 	if !shouldCheckAnnotations || l4.hasAnnotation(annotations.FirewallRuleIPv6Key) {
 		err := l4.deleteIPv6NodesFirewall()
@@ -109,15 +120,15 @@ func (l4 *L4) deleteIPv6ResourcesAnnotationBased(syncResult *L4ILBSyncResult, sh
 }
 
 func (l4 *L4) getIPv6FRName() string {
-	protocol := utils.GetProtocol(l4.Service.Spec.Ports)
-	return l4.getIPv6FRNameWithProtocol(string(protocol))
+	protocol := utils.GetForwardingRuleProtocol(l4.Service.Spec.Ports)
+	return l4.getIPv6FRNameWithProtocol(protocol)
 }
 
 func (l4 *L4) getIPv6FRNameWithProtocol(protocol string) string {
-	return l4.namer.L4IPv6ForwardingRule(l4.Service.Namespace, l4.Service.Name, strings.ToLower(protocol))
+	return l4.namer.L4IPv6ForwardingRule(l4.Service.Namespace, l4.Service.Name, forwardingRuleNameProtocol(protocol))
 }
 
-func (l4 *L4) ensureIPv6NodesFirewall(ipAddress string, nodeNames []string, result *L4ILBSyncResult) {
+func (l4 *L4) ensureIPv6NodesFirewall(ipAddresses []string, nodeNames []string, result *L4ILBSyncResult) {
 	// DisableL4LBFirewall flag disables L4 FW enforcment to remove conflicts with firewall policies
 	if l4.disableNodesFirewallProvisioning {
 		l4.svcLogger.Info("Skipped ensuring IPv6 nodes firewall for L4 ILB Service to enable compatibility with firewall policies. " +
@@ -133,7 +144,7 @@ func (l4 *L4) ensureIPv6NodesFirewall(ipAddress string, nodeNames []string, resu
 	protocol := utils.GetProtocol(svcPorts)
 
 	fwLogger := l4.svcLogger.WithValues("firewallName", firewallName)
-	fwLogger.V(2).Info("Ensuring IPv6 nodes firewall for L4 ILB Service", "ipAddress", ipAddress, "protocol", protocol, "len(nodeNames)", len(nodeNames), "portRanges", portRanges)
+	fwLogger.V(2).Info("Ensuring IPv6 nodes firewall for L4 ILB Service", "ipAddresses", ipAddresses, "protocol", protocol, "len(nodeNames)", len(nodeNames), "portRanges", portRanges)
 	defer func() {
 		fwLogger.V(2).Info("Finished ensuring IPv6 nodes firewall for L4 ILB Service", "timeTaken", time.Since(start))
 	}()
@@ -148,13 +159,16 @@ func (l4 *L4) ensureIPv6NodesFirewall(ipAddress string, nodeNames []string, resu
 	ipv6nodesFWRParams := firewalls.FirewallParams{
 		PortRanges:        portRanges,
 		SourceRanges:      ipv6SourceRanges,
-		DestinationRanges: []string{ipAddress},
+		DestinationRanges: ipAddresses,
 		Protocol:          string(protocol),
 		Name:              firewallName,
 		NodeNames:         nodeNames,
 		L4Type:            utils.ILB,
 		Network:           l4.network,
 	}
+	if utils.HasMixedProtocols(svcPorts) {
+		ipv6nodesFWRParams.ProtocolPortRanges = utils.GetServicePortRangesByProtocol(svcPorts)
+	}
 
 	_, err = firewalls.EnsureL4LBFirewallForNodes(l4.Service, &ipv6nodesFWRParams, l4.cloud, l4.recorder, fwLogger)
 	if err != nil {
@@ -196,10 +210,10 @@ func (l4 *L4) deleteIPv6NodesFirewall() error {
 // because forwarding rule name depends on the protocol, and we need to get forwarding rule from the old protocol name.
 func (l4 *L4) getOldIPv6ForwardingRule(existingBS *composite.BackendService) (*composite.ForwardingRule, error) {
 	servicePorts := l4.Service.Spec.Ports
-	protocol := utils.GetProtocol(servicePorts)
+	protocol := utils.GetBackendServiceProtocol(servicePorts)
 
 	oldIPv6FRName := l4.getIPv6FRName()
-	if existingBS != nil && existingBS.Protocol != string(protocol) {
+	if existingBS != nil && existingBS.Protocol != protocol {
 		oldIPv6FRName = l4.getIPv6FRNameWithProtocol(existingBS.Protocol)
 	}
 
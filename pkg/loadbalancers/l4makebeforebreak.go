@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/events"
+	"k8s.io/ingress-gce/pkg/flags"
+	"k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
+)
+
+// alternateFRSuffix is appended to the forwarding rule name of an L4 Service to name
+// the forwarding rule that replaces it in a make-before-break recreation.
+const alternateFRSuffix = "-alt"
+
+// alternateFRName returns the name of the forwarding rule replacing the forwarding rule with
+// the given name. Names alternate between the default and the suffixed name, so that the
+// previous forwarding rule can keep serving traffic next to its replacement.
+func alternateFRName(name, defaultName string) string {
+	if name == defaultName {
+		return namer.GetSuffixedName(name, alternateFRSuffix)
+	}
+	return defaultName
+}
+
+// alternateIPv4FRName returns the name of the forwarding rule replacing the IPv4 forwarding rule
+// of the L4 NetLB Service with the given name.
+func (l4netlb *L4NetLB) alternateIPv4FRName(name string) string {
+	return alternateFRName(name, l4netlb.frName())
+}
+
+// alternateIPv4FRName returns the name of the forwarding rule replacing the IPv4 forwarding rule
+// of the L4 ILB Service with the given name.
+func (l4 *L4) alternateIPv4FRName(name string) string {
+	return alternateFRName(name, l4.GetFRName())
+}
+
+// getIPv4ForwardingRules returns the IPv4 forwarding rule of the Service and, during a
+// make-before-break recreation, the previous forwarding rule that it replaces.
+// If both forwarding rules exist, the one with the desired network tier is the current one.
+func (l4netlb *L4NetLB) getIPv4ForwardingRules(netTier cloud.NetworkTier) (*composite.ForwardingRule, *composite.ForwardingRule, error) {
+	defaultFwdRule, err := l4netlb.forwardingRules.Get(l4netlb.frName())
+	if err != nil {
+		return nil, nil, err
+	}
+	alternateFwdRule, err := l4netlb.forwardingRules.Get(l4netlb.alternateIPv4FRName(l4netlb.frName()))
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case alternateFwdRule == nil:
+		return defaultFwdRule, nil, nil
+	case defaultFwdRule == nil:
+		return alternateFwdRule, nil, nil
+	case alternateFwdRule.NetworkTier == netTier.ToGCEValue() && defaultFwdRule.NetworkTier != netTier.ToGCEValue():
+		return alternateFwdRule, defaultFwdRule, nil
+	default:
+		return defaultFwdRule, alternateFwdRule, nil
+	}
+}
+
+// getIPv4ForwardingRules returns the IPv4 forwarding rule of the Service and, during a
+// make-before-break recreation, the previous forwarding rule that it replaces.
+// If both forwarding rules exist, the one in the desired subnetwork is the current one.
+func (l4 *L4) getIPv4ForwardingRules(existingBS *composite.BackendService, subnetworkURL string) (*composite.ForwardingRule, *composite.ForwardingRule, error) {
+	defaultFwdRule, err := l4.getOldIPv4ForwardingRule(existingBS)
+	if err != nil {
+		return nil, nil, err
+	}
+	alternateFwdRule, err := l4.forwardingRules.Get(l4.alternateIPv4FRName(l4.GetFRName()))
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case alternateFwdRule == nil:
+		return defaultFwdRule, nil, nil
+	case defaultFwdRule == nil:
+		return alternateFwdRule, nil, nil
+	case equalResourcePaths(alternateFwdRule.Subnetwork, subnetworkURL) && !equalResourcePaths(defaultFwdRule.Subnetwork, subnetworkURL):
+		return alternateFwdRule, defaultFwdRule, nil
+	default:
+		return defaultFwdRule, alternateFwdRule, nil
+	}
+}
+
+// shouldMakeBeforeBreak returns true if the existing forwarding rule has to be recreated with
+// another network tier and the Service enables make-before-break recreation.
+// Only one recreation can be in progress at a time.
+func (l4netlb *L4NetLB) shouldMakeBeforeBreak(existingFwdRule, previousFwdRule *composite.ForwardingRule, netTier cloud.NetworkTier) bool {
+	return existingFwdRule != nil && previousFwdRule == nil &&
+		existingFwdRule.NetworkTier != netTier.ToGCEValue() &&
+		annotations.HasMakeBeforeBreakAnnotation(l4netlb.Service)
+}
+
+// shouldMakeBeforeBreak returns true if the existing forwarding rule has to be recreated in
+// another subnetwork and the Service enables make-before-break recreation.
+// Only one recreation can be in progress at a time. Protocol changes are not covered, the
+// forwarding rule is deleted before the protocol of its backend service changes.
+func (l4 *L4) shouldMakeBeforeBreak(existingFwdRule, previousFwdRule *composite.ForwardingRule, subnetworkURL string) bool {
+	if existingFwdRule == nil || previousFwdRule != nil || !annotations.HasMakeBeforeBreakAnnotation(l4.Service) {
+		return false
+	}
+	frName := l4.GetFRName()
+	if existingFwdRule.Name != frName && existingFwdRule.Name != l4.alternateIPv4FRName(frName) {
+		return false
+	}
+	return !equalResourcePaths(existingFwdRule.Subnetwork, subnetworkURL)
+}
+
+// ensurePreviousForwardingRule deletes the forwarding rule replaced by fr in a make-before-break
+// recreation, once the soak period passed since fr was created or the Service requests its removal.
+// It returns the previous forwarding rule if it still exists.
+func ensurePreviousForwardingRule(forwardingRules ForwardingRulesProvider, recorder record.EventRecorder, svc *corev1.Service, fr, previousFwdRule *composite.ForwardingRule, logger klog.Logger) (*composite.ForwardingRule, error) {
+	if previousFwdRule == nil {
+		return nil, nil
+	}
+	if !annotations.WantsPreviousForwardingRuleRemoved(svc) && !soakPeriodPassed(fr, flags.F.L4ForwardingRuleSoakPeriod, time.Now()) {
+		logger.V(2).Info("Keeping previous forwarding rule during soak period", "forwardingRuleName", fr.Name, "previousForwardingRuleName", previousFwdRule.Name)
+		return previousFwdRule, nil
+	}
+	if err := forwardingRules.Delete(previousFwdRule.Name); err != nil {
+		return previousFwdRule, err
+	}
+	recorder.Eventf(svc, corev1.EventTypeNormal, events.SyncIngress, "ForwardingRule %s replaced by %s deleted", previousFwdRule.Name, fr.Name)
+	return nil, nil
+}
+
+// previousIPFamilyForwardingRule returns the forwarding rule with the given name of an IP family
+// that the Service no longer uses, if it has to keep serving traffic next to the forwarding rule
+// of the replacement IP family in a make-before-break IP version change. The previous forwarding
+// rule is kept until the soak period passed since its replacement was created or the Service
+// requests its removal.
+func previousIPFamilyForwardingRule(forwardingRules ForwardingRulesProvider, svc *corev1.Service, previousFRName, replacementFRName string, logger klog.Logger) (*composite.ForwardingRule, error) {
+	if !annotations.HasMakeBeforeBreakAnnotation(svc) {
+		return nil, nil
+	}
+	previousFwdRule, err := forwardingRules.Get(previousFRName)
+	if err != nil || previousFwdRule == nil {
+		return nil, err
+	}
+	replacementFwdRule, err := forwardingRules.Get(replacementFRName)
+	if err != nil {
+		return nil, err
+	}
+	if replacementFwdRule != nil && (annotations.WantsPreviousForwardingRuleRemoved(svc) || soakPeriodPassed(replacementFwdRule, flags.F.L4ForwardingRuleSoakPeriod, time.Now())) {
+		return nil, nil
+	}
+	logger.V(2).Info("Keeping forwarding rule of previous IP family during soak period", "forwardingRuleName", replacementFRName, "previousForwardingRuleName", previousFRName)
+	return previousFwdRule, nil
+}
+
+// keepAnnotation copies the value of the given resource annotation of the Service, if any.
+// It is used for resources of a previous IP family that keep serving traffic.
+func keepAnnotation(svc *corev1.Service, lbAnnotations map[string]string, key string) {
+	if value, ok := svc.Annotations[key]; ok {
+		lbAnnotations[key] = value
+	}
+}
+
+// soakPeriodPassed returns true if the soak period passed since the given forwarding rule was created.
+func soakPeriodPassed(fr *composite.ForwardingRule, soakPeriod time.Duration, now time.Time) bool {
+	if soakPeriod <= 0 {
+		return true
+	}
+	created, err := time.Parse(time.RFC3339, fr.CreationTimestamp)
+	if err != nil {
+		// Without a creation time, wait for the Service to request the removal.
+		return false
+	}
+	return now.Sub(created) >= soakPeriod
+}
@@ -31,6 +31,7 @@ import (
 	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/backends"
 	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/events"
 	"k8s.io/ingress-gce/pkg/firewalls"
 	"k8s.io/ingress-gce/pkg/forwardingrules"
 	"k8s.io/ingress-gce/pkg/healthchecksl4"
@@ -281,12 +282,29 @@ func (l4netlb *L4NetLB) EnsureFrontend(nodeNames []string, svc *corev1.Service)
 		}
 	}
 
+	connectionTracking, err := annotations.FromService(svc).L4ConnectionTrackingOptions()
+	if err != nil {
+		result.GCEResourceInError = annotations.BackendServiceResource
+		result.Error = utils.NewUserError(err)
+		result.MetricsLegacyState.IsUserError = true
+		result.MetricsState.Status = metrics.StatusUserError
+		return result
+	}
+
 	hcLink := l4netlb.provideHealthChecks(nodeNames, result)
 	if result.Error != nil {
 		return result
 	}
 
-	bsLink := l4netlb.provideBackendService(result, hcLink)
+	releaseAddresses, err := l4netlb.deleteForwardingRulesOnProtocolChange()
+	defer releaseAddresses()
+	if err != nil {
+		result.GCEResourceInError = annotations.ForwardingRuleResource
+		result.Error = fmt.Errorf("failed to delete forwarding rules before changing backend service protocol - %w", err)
+		return result
+	}
+
+	bsLink := l4netlb.provideBackendService(result, hcLink, connectionTracking)
 	if result.Error != nil {
 		return result
 	}
@@ -344,30 +362,126 @@ func (l4netlb *L4NetLB) provideIPv4HealthChecks(nodeNames []string, result *L4Ne
 }
 
 // connectionTrackingPolicy returns BackendServiceConnectionTrackingPolicy
-// based on StrongSessionAffinity and IdleTimeoutSec
-func (l4netlb *L4NetLB) connectionTrackingPolicy() *composite.BackendServiceConnectionTrackingPolicy {
+// based on StrongSessionAffinity and IdleTimeoutSec, or on the options of the
+// connection tracking annotation. Strong Session Affinity takes precedence over
+// the tracking mode and idle timeout of the annotation.
+func (l4netlb *L4NetLB) connectionTrackingPolicy(options *annotations.L4ConnectionTrackingOptions) *composite.BackendServiceConnectionTrackingPolicy {
 	if !l4netlb.enableStrongSessionAffinity || !annotations.HasStrongSessionAffinityAnnotation(l4netlb.Service) {
-		return nil
+		return l4ConnectionTrackingPolicy(options)
 	}
 	connectionTrackingPolicy := composite.BackendServiceConnectionTrackingPolicy{}
 	connectionTrackingPolicy.EnableStrongAffinity = true
 	connectionTrackingPolicy.TrackingMode = backends.PerSessionTrackingMode
 	connectionTrackingPolicy.IdleTimeoutSec = int64(*l4netlb.Service.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds)
+	if options != nil {
+		connectionTrackingPolicy.ConnectionPersistenceOnUnhealthyBackends = options.ConnectionPersistenceOnUnhealthyBackends
+	}
 	return &connectionTrackingPolicy
 }
 
-func (l4netlb *L4NetLB) provideBackendService(syncResult *L4NetLBSyncResult, hcLink string) string {
+// deleteForwardingRulesOnProtocolChange deletes the forwarding rules of the Service if the protocol
+// of its backend service changes, e.g. between a single protocol and mixed protocols, because the
+// backend service can not be updated while forwarding rules of another protocol reference it.
+// The IP addresses of the forwarding rules are reserved, so that the forwarding rules are recreated
+// with the same IP addresses. The returned function releases the reservations.
+func (l4netlb *L4NetLB) deleteForwardingRulesOnProtocolChange() (func(), error) {
+	var releases []func()
+	release := func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+
+	bsName := l4netlb.namer.L4Backend(l4netlb.Service.Namespace, l4netlb.Service.Name)
+	existingBS, err := l4netlb.backendPool.Get(bsName, meta.VersionGA, l4netlb.scope, l4netlb.svcLogger)
+	if err != nil {
+		return release, utils.IgnoreHTTPNotFound(err)
+	}
+	protocol := utils.GetBackendServiceProtocol(l4netlb.Service.Spec.Ports)
+	if existingBS.Protocol == protocol {
+		return release, nil
+	}
+	l4netlb.svcLogger.Info("Protocol changed for service", "existingProtocol", existingBS.Protocol, "newProtocol", protocol)
+
+	netTier, _ := utils.GetNetworkTier(l4netlb.Service)
+	holdAddress := func(fr *composite.ForwardingRule, name, subnetURL, ip string, ipVersion IPVersion) error {
+		if l4netlb.cloud.IsLegacyNetwork() || fr.NetworkTier != netTier.ToGCEValue() {
+			return nil
+		}
+		nm := types.NamespacedName{Namespace: l4netlb.Service.Namespace, Name: l4netlb.Service.Name}.String()
+		addrMgr := newAddressManager(l4netlb.cloud, nm, l4netlb.cloud.Region(), subnetURL, name, ip, cloud.SchemeExternal, netTier, ipVersion, l4netlb.svcLogger)
+		if _, _, err := addrMgr.HoldAddress(); err != nil {
+			return err
+		}
+		releases = append(releases, func() {
+			if err := addrMgr.ReleaseAddress(); err != nil {
+				l4netlb.svcLogger.Error(err, "Failed to release address reservation, possibly causing an orphan", "addressName", name)
+			}
+		})
+		return nil
+	}
+	deleteForwardingRule := func(fr *composite.ForwardingRule) error {
+		if err := l4netlb.forwardingRules.Delete(fr.Name); err != nil {
+			return err
+		}
+		l4netlb.recorder.Eventf(l4netlb.Service, corev1.EventTypeNormal, events.SyncIngress, "ForwardingRule %s deleted", fr.Name)
+		return nil
+	}
+
+	existingFwdRule, previousFwdRule, err := l4netlb.getIPv4ForwardingRules(netTier)
+	if err != nil {
+		return release, err
+	}
+	if existingFwdRule != nil {
+		// The forwarding rule is recreated with the default name.
+		if err := holdAddress(existingFwdRule, l4netlb.frName(), "", existingFwdRule.IPAddress, IPv4Version); err != nil {
+			return release, err
+		}
+		if err := deleteForwardingRule(existingFwdRule); err != nil {
+			return release, err
+		}
+	}
+	if previousFwdRule != nil {
+		// The forwarding rule replaced in a make-before-break recreation uses the backend service too.
+		if err := deleteForwardingRule(previousFwdRule); err != nil {
+			return release, err
+		}
+	}
+	// The additional forwarding rules use the backend service too, they are recreated with the
+	// new protocol. Their addresses are reserved by the user, they don't need to be held.
+	additionalFRNames := append(additionalFRNames(l4netlb.Service, annotations.AdditionalForwardingRulesKey), additionalFRNames(l4netlb.Service, annotations.AdditionalForwardingRulesIPv6Key)...)
+	if err := deleteAdditionalForwardingRules(l4netlb.forwardingRules, additionalFRNames, l4netlb.svcLogger); err != nil {
+		return release, err
+	}
+
+	if !l4netlb.enableDualStack {
+		return release, nil
+	}
+	existingIPv6FwdRule, err := l4netlb.forwardingRules.Get(l4netlb.ipv6FRName())
+	if err != nil || existingIPv6FwdRule == nil {
+		return release, err
+	}
+	// IPv6 addresses can only be reserved in Premium Tier.
+	if netTier == cloud.NetworkTierPremium {
+		if err := holdAddress(existingIPv6FwdRule, existingIPv6FwdRule.Name, existingIPv6FwdRule.Subnetwork, ipv6AddressWithoutRange(existingIPv6FwdRule.IPAddress), IPv6Version); err != nil {
+			return release, err
+		}
+	}
+	return release, deleteForwardingRule(existingIPv6FwdRule)
+}
+
+func (l4netlb *L4NetLB) provideBackendService(syncResult *L4NetLBSyncResult, hcLink string, connectionTracking *annotations.L4ConnectionTrackingOptions) string {
 	bsName := l4netlb.namer.L4Backend(l4netlb.Service.Namespace, l4netlb.Service.Name)
 	servicePorts := l4netlb.Service.Spec.Ports
-	protocol := utils.GetProtocol(servicePorts)
+	protocol := utils.GetBackendServiceProtocol(servicePorts)
 
 	localityLbPolicy := l4netlb.determineBackendServiceLocalityPolicy()
 
-	connectionTrackingPolicy := l4netlb.connectionTrackingPolicy()
+	connectionTrackingPolicy := l4netlb.connectionTrackingPolicy(connectionTracking)
 	backendParams := backends.L4BackendServiceParams{
 		Name:                     bsName,
 		HealthCheckLink:          hcLink,
-		Protocol:                 string(protocol),
+		Protocol:                 protocol,
 		SessionAffinity:          string(l4netlb.Service.Spec.SessionAffinity),
 		Scheme:                   string(cloud.SchemeExternal),
 		NamespacedName:           l4netlb.NamespacedName,
@@ -392,27 +506,78 @@ func (l4netlb *L4NetLB) provideBackendService(syncResult *L4NetLBSyncResult, hcL
 	}
 
 	syncResult.Annotations[annotations.BackendServiceKey] = bsName
+
+	if dedicatedHealthCheckReplaced(l4netlb.Service, l4netlb.namer, syncResult.Annotations[annotations.HealthcheckKey]) {
+		// The backend service no longer uses the dedicated health check of the Service.
+		if resourceInError, err := l4netlb.healthChecks.DeleteOverriddenHealthCheckWithFirewalls(l4netlb.Service, l4netlb.namer, meta.Regional, utils.XLB, l4netlb.enableDualStack, l4netlb.svcLogger); err != nil {
+			syncResult.GCEResourceInError = resourceInError
+			syncResult.Error = err
+			return ""
+		}
+	}
 	return bs.SelfLink
 }
 
 func (l4netlb *L4NetLB) ensureDualStackResources(result *L4NetLBSyncResult, nodeNames []string, bsLink string) {
 	if utils.NeedsIPv4(l4netlb.Service) {
 		l4netlb.ensureIPv4Resources(result, nodeNames, bsLink)
-	} else {
-		l4netlb.deleteIPv4ResourcesOnSync(result)
 	}
 	if utils.NeedsIPv6(l4netlb.Service) {
 		l4netlb.ensureIPv6Resources(result, nodeNames, bsLink)
-	} else {
+	}
+	// Resources of an IP family that is no longer used are deleted after the ones of the
+	// other family are ensured, so that they can keep serving traffic in a make-before-break IP version change.
+	if !utils.NeedsIPv4(l4netlb.Service) && !l4netlb.keepPreviousIPv4Resources(result) {
+		l4netlb.deleteIPv4ResourcesOnSync(result)
+	}
+	if !utils.NeedsIPv6(l4netlb.Service) && !l4netlb.keepPreviousIPv6Resources(result) {
 		l4netlb.deleteIPv6ResourcesOnSync(result)
 	}
 }
 
+// keepPreviousIPv4Resources keeps the IPv4 forwarding rule and nodes firewall of a Service
+// that switched to IPv6 only in a make-before-break IP version change, until the soak period passed.
+// It returns true if the IPv4 resources are kept.
+func (l4netlb *L4NetLB) keepPreviousIPv4Resources(result *L4NetLBSyncResult) bool {
+	fr, err := previousIPFamilyForwardingRule(l4netlb.forwardingRules, l4netlb.Service, l4netlb.frName(), l4netlb.ipv6FRName(), l4netlb.svcLogger)
+	if err != nil {
+		result.GCEResourceInError = annotations.ForwardingRuleResource
+		result.Error = err
+		return true
+	}
+	if fr == nil {
+		return false
+	}
+	result.Annotations[forwardingRuleAnnotationKey(fr)] = fr.Name
+	keepAnnotation(l4netlb.Service, result.Annotations, annotations.FirewallRuleKey)
+	result.Status = utils.AddIPToLBStatus(result.Status, fr.IPAddress)
+	return true
+}
+
+// keepPreviousIPv6Resources keeps the IPv6 forwarding rule and nodes firewall of a Service
+// that switched to IPv4 only in a make-before-break IP version change, until the soak period passed.
+// It returns true if the IPv6 resources are kept.
+func (l4netlb *L4NetLB) keepPreviousIPv6Resources(result *L4NetLBSyncResult) bool {
+	fr, err := previousIPFamilyForwardingRule(l4netlb.forwardingRules, l4netlb.Service, l4netlb.ipv6FRName(), l4netlb.frName(), l4netlb.svcLogger)
+	if err != nil {
+		result.GCEResourceInError = annotations.ForwardingRuleIPv6Resource
+		result.Error = err
+		return true
+	}
+	if fr == nil {
+		return false
+	}
+	result.Annotations[ipv6ForwardingRuleAnnotationKey(fr)] = fr.Name
+	keepAnnotation(l4netlb.Service, result.Annotations, annotations.FirewallRuleIPv6Key)
+	result.Status = utils.AddIPToLBStatus(result.Status, strings.Split(fr.IPAddress, "/")[0])
+	return true
+}
+
 // ensureIPv4Resources creates resources specific to IPv4 L4 Load Balancers:
 // - IPv4 Forwarding Rule
 // - IPv4 Firewall
 func (l4netlb *L4NetLB) ensureIPv4Resources(result *L4NetLBSyncResult, nodeNames []string, bsLink string) {
-	fr, ipAddrType, wasUpdate, err := l4netlb.ensureIPv4ForwardingRule(bsLink)
+	fr, previousFr, ipAddrType, wasUpdate, err := l4netlb.ensureIPv4ForwardingRule(bsLink)
 	result.GCEResourceUpdate.forwardingRuleUpdate = wasUpdate
 	if err != nil {
 		// User can misconfigure the forwarding rule if Network Tier will not match service level Network Tier.
@@ -421,24 +586,42 @@ func (l4netlb *L4NetLB) ensureIPv4Resources(result *L4NetLBSyncResult, nodeNames
 		result.MetricsLegacyState.IsUserError = utils.IsUserError(err)
 		return
 	}
-	if fr.IPProtocol == string(corev1.ProtocolTCP) {
-		result.Annotations[annotations.TCPForwardingRuleKey] = fr.Name
-	} else {
-		result.Annotations[annotations.UDPForwardingRuleKey] = fr.Name
-	}
+	result.Annotations[forwardingRuleAnnotationKey(fr)] = fr.Name
 	result.MetricsLegacyState.IsManagedIP = ipAddrType == IPAddrManaged
 	result.MetricsLegacyState.IsPremiumTier = fr.NetworkTier == cloud.NetworkTierPremium.ToGCEValue()
 
-	l4netlb.ensureIPv4NodesFirewall(nodeNames, fr.IPAddress, result)
+	previousFr, err = ensurePreviousForwardingRule(l4netlb.forwardingRules, l4netlb.recorder, l4netlb.Service, fr, previousFr, l4netlb.svcLogger)
+	if err != nil {
+		result.GCEResourceInError = annotations.ForwardingRuleResource
+		result.Error = fmt.Errorf("failed to delete previous forwarding rule - %w", err)
+		return
+	}
+	additionalFRs, err := l4netlb.ensureAdditionalForwardingRules(fr, l4netlb.frName(), IPv4Version)
+	if err != nil {
+		result.GCEResourceInError = annotations.ForwardingRuleResource
+		result.Error = fmt.Errorf("failed to ensure additional forwarding rules - %w", err)
+		return
+	}
+	if len(additionalFRs) > 0 {
+		result.Annotations[annotations.AdditionalForwardingRulesKey] = additionalFRNamesAnnotation(additionalFRs)
+	}
+	// Both forwarding rules serve traffic until the previous one is deleted.
+	ipAddresses := []string{fr.IPAddress}
+	if previousFr != nil {
+		ipAddresses = append(ipAddresses, previousFr.IPAddress)
+	}
+	ipAddresses = append(ipAddresses, additionalFRAddresses(additionalFRs)...)
+
+	l4netlb.ensureIPv4NodesFirewall(nodeNames, ipAddresses, result)
 	if result.Error != nil {
 		l4netlb.svcLogger.Error(err, "ensureIPv4Resources: Failed to ensure nodes firewall for L4 NetLB Service")
 		return
 	}
 
-	result.Status = utils.AddIPToLBStatus(result.Status, fr.IPAddress)
+	result.Status = utils.AddIPToLBStatus(result.Status, ipAddresses...)
 }
 
-func (l4netlb *L4NetLB) ensureIPv4NodesFirewall(nodeNames []string, ipAddress string, result *L4NetLBSyncResult) {
+func (l4netlb *L4NetLB) ensureIPv4NodesFirewall(nodeNames []string, ipAddresses []string, result *L4NetLBSyncResult) {
 	// DisableL4LBFirewall flag disables L4 FW enforcment to remove conflicts with firewall policies
 	if l4netlb.disableNodesFirewallProvisioning {
 		l4netlb.svcLogger.Info("Skipped ensuring IPv4 nodes firewall for L4 NetLB Service to enable compatibility with firewall policies. " +
@@ -453,7 +636,7 @@ func (l4netlb *L4NetLB) ensureIPv4NodesFirewall(nodeNames []string, ipAddress st
 	protocol := utils.GetProtocol(servicePorts)
 
 	fwLogger := l4netlb.svcLogger.WithValues("firewallName", firewallName)
-	fwLogger.V(2).Info("Ensuring nodes firewall for L4 NetLB Service", "ipAddress", ipAddress, "protocol", protocol, "len(nodeNames)", len(nodeNames), "portRanges", portRanges)
+	fwLogger.V(2).Info("Ensuring nodes firewall for L4 NetLB Service", "ipAddresses", ipAddresses, "protocol", protocol, "len(nodeNames)", len(nodeNames), "portRanges", portRanges)
 	defer func() {
 		fwLogger.V(2).Info("Finished ensuring nodes firewall for L4 NetLB Service", "timeTaken", time.Since(start))
 	}()
@@ -468,13 +651,16 @@ func (l4netlb *L4NetLB) ensureIPv4NodesFirewall(nodeNames []string, ipAddress st
 	nodesFWRParams := firewalls.FirewallParams{
 		PortRanges:        portRanges,
 		SourceRanges:      sourceRanges,
-		DestinationRanges: []string{ipAddress},
+		DestinationRanges: ipAddresses,
 		Protocol:          string(protocol),
 		Name:              firewallName,
 		IP:                l4netlb.Service.Spec.LoadBalancerIP,
 		NodeNames:         nodeNames,
 		Network:           l4netlb.networkInfo,
 	}
+	if utils.HasMixedProtocols(servicePorts) {
+		nodesFWRParams.ProtocolPortRanges = utils.GetServicePortRangesByProtocol(servicePorts)
+	}
 	result.GCEResourceUpdate.firewallForNodesUpdate, result.Error = firewalls.EnsureL4LBFirewallForNodes(l4netlb.Service, &nodesFWRParams, l4netlb.cloud, l4netlb.recorder, fwLogger)
 	if result.Error != nil {
 		result.GCEResourceInError = annotations.FirewallRuleResource
@@ -535,7 +721,7 @@ func (l4netlb *L4NetLB) deleteIPv4ResourcesOnDelete(result *L4NetLBSyncResult) {
 // This function does not delete Backend Service and Health Check, because they are shared between IPv4 and IPv6.
 // IPv4 Firewall Rule for Health Check also will not be deleted here, and will be left till the Service Deletion.
 func (l4netlb *L4NetLB) deleteIPv4ResourcesAnnotationBased(result *L4NetLBSyncResult, shouldIgnoreAnnotations bool) {
-	if shouldIgnoreAnnotations || l4netlb.hasAnnotation(annotations.TCPForwardingRuleKey) || l4netlb.hasAnnotation(annotations.UDPForwardingRuleKey) {
+	if shouldIgnoreAnnotations || l4netlb.hasAnnotation(annotations.TCPForwardingRuleKey) || l4netlb.hasAnnotation(annotations.UDPForwardingRuleKey) || l4netlb.hasAnnotation(annotations.L3ForwardingRuleKey) {
 		err := l4netlb.deleteIPv4ForwardingRule()
 		if err != nil {
 			l4netlb.svcLogger.Error(err, "Failed to delete forwarding rule for NetLB RBS service")
@@ -543,6 +729,11 @@ func (l4netlb *L4NetLB) deleteIPv4ResourcesAnnotationBased(result *L4NetLBSyncRe
 			result.GCEResourceInError = annotations.ForwardingRuleResource
 		}
 	}
+	if err := l4netlb.deleteAdditionalForwardingRules(l4netlb.frName(), IPv4Version, shouldIgnoreAnnotations); err != nil {
+		l4netlb.svcLogger.Error(err, "Failed to delete additional forwarding rules for NetLB RBS service")
+		result.Error = err
+		result.GCEResourceInError = annotations.ForwardingRuleResource
+	}
 
 	// Deleting non-existent address do not print error audit logs, and we don't store address in annotations
 	// that's why we can delete it without checking annotation
@@ -568,25 +759,34 @@ func (l4netlb *L4NetLB) deleteIPv4ForwardingRule() error {
 	start := time.Now()
 
 	frName := l4netlb.frName()
+	// The forwarding rule may have been replaced in a make-before-break recreation.
+	alternateFRName := l4netlb.alternateIPv4FRName(frName)
 
-	l4netlb.svcLogger.V(2).Info("Deleting IPv4 external forwarding rule for L4 NetLB Service", "forwardingRuleName", frName)
+	l4netlb.svcLogger.V(2).Info("Deleting IPv4 external forwarding rule for L4 NetLB Service", "forwardingRuleName", frName, "alternateForwardingRuleName", alternateFRName)
 	defer func() {
 		l4netlb.svcLogger.V(2).Info("Finished deleting IPv4 external forwarding rule for L4 NetLB Service", "forwardingRuleName", frName, "timeTaken", time.Since(start))
 	}()
 
-	return l4netlb.forwardingRules.Delete(frName)
+	if err := l4netlb.forwardingRules.Delete(frName); err != nil {
+		return err
+	}
+	return l4netlb.forwardingRules.Delete(alternateFRName)
 }
 
 func (l4netlb *L4NetLB) deleteIPv4Address() error {
 	addressName := l4netlb.frName()
+	alternateAddressName := l4netlb.alternateIPv4FRName(addressName)
 
 	start := time.Now()
-	l4netlb.svcLogger.V(2).Info("Deleting IPv4 external static address for L4 NetLB service", "addressName", addressName)
+	l4netlb.svcLogger.V(2).Info("Deleting IPv4 external static address for L4 NetLB service", "addressName", addressName, "alternateAddressName", alternateAddressName)
 	defer func() {
 		l4netlb.svcLogger.V(2).Info("Finished deleting IPv4 external static address for L4 NetLB service", "addressName", addressName, "timeTaken", time.Since(start))
 	}()
 
-	return ensureAddressDeleted(l4netlb.cloud, addressName, l4netlb.cloud.Region())
+	if err := ensureAddressDeleted(l4netlb.cloud, addressName, l4netlb.cloud.Region()); err != nil {
+		return err
+	}
+	return ensureAddressDeleted(l4netlb.cloud, alternateAddressName, l4netlb.cloud.Region())
 }
 
 func (l4netlb *L4NetLB) deleteIPv4NodesFirewall() error {
@@ -603,6 +803,36 @@ func (l4netlb *L4NetLB) deleteIPv4NodesFirewall() error {
 	return l4netlb.deleteFirewall(firewallName, fwLogger)
 }
 
+// DeleteTargetPoolResources deletes the resources of the legacy target pool load balancer
+// of a Service that was migrated to backend services. The forwarding rule must not point
+// at the target pool anymore. The nodes health check, which is shared by all target pool
+// Services with the Cluster traffic policy, is kept.
+func (l4netlb *L4NetLB) DeleteTargetPoolResources() error {
+	lbName := l4netlb.frName()
+	tpLogger := l4netlb.svcLogger.WithValues("targetPoolName", lbName)
+	tpLogger.V(2).Info("Deleting target pool resources for L4 NetLB Service")
+
+	if err := utils.IgnoreHTTPNotFound(l4netlb.cloud.DeleteTargetPool(lbName, l4netlb.cloud.Region())); err != nil {
+		return fmt.Errorf("failed to delete target pool %s: %w", lbName, err)
+	}
+	// The health check of a target pool Service with the Local traffic policy has the name of
+	// the target pool, and can only be deleted once the target pool is gone.
+	if err := utils.IgnoreHTTPNotFound(l4netlb.cloud.DeleteHTTPHealthCheck(lbName)); err != nil {
+		return fmt.Errorf("failed to delete target pool health check %s: %w", lbName, err)
+	}
+	clusterID, err := l4netlb.cloud.ClusterID.GetID()
+	if err != nil {
+		return err
+	}
+	for _, fwName := range []string{gce.MakeFirewallName(lbName), gce.MakeHealthCheckFirewallName(clusterID, lbName, false)} {
+		if err := l4netlb.deleteFirewall(fwName, tpLogger.WithValues("firewallName", fwName)); err != nil {
+			return fmt.Errorf("failed to delete target pool firewall %s: %w", fwName, err)
+		}
+	}
+	l4netlb.recorder.Eventf(l4netlb.Service, corev1.EventTypeNormal, events.SyncIngress, "TargetPool %s deleted", lbName)
+	return nil
+}
+
 func (l4netlb *L4NetLB) deleteFirewall(firewallName string, fwLogger klog.Logger) error {
 	err := firewalls.EnsureL4FirewallRuleDeleted(l4netlb.cloud, firewallName, fwLogger)
 	if err != nil {
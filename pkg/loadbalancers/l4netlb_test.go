@@ -22,6 +22,7 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/ingress-gce/pkg/backends"
 	"k8s.io/ingress-gce/pkg/network"
@@ -92,6 +93,271 @@ func TestEnsureL4NetLoadBalancer(t *testing.T) {
 	}
 }
 
+func TestEnsureL4NetLoadBalancerMixedProtocol(t *testing.T) {
+	t.Parallel()
+	nodeNames := []string{"test-node-1"}
+
+	svc := test.NewL4NetLBRBSService(53)
+	svc.Spec.Ports = append(svc.Spec.Ports, v1.ServicePort{Name: "dns-udp", Port: 53, Protocol: v1.ProtocolUDP})
+	l4netlb := mustSetupNetLBTestHandler(t, svc, nodeNames)
+	l4netlb.enableDualStack = false
+
+	result := l4netlb.EnsureFrontend(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	if len(result.Status.Ingress) == 0 {
+		t.Errorf("Got empty loadBalancer status using handler %v", l4netlb)
+	}
+
+	frName := l4netlb.frName()
+	if got := result.Annotations[annotations.L3ForwardingRuleKey]; got != frName {
+		t.Errorf("Annotation %s = %q, want %q", annotations.L3ForwardingRuleKey, got, frName)
+	}
+	fwdRule, err := composite.GetForwardingRule(l4netlb.cloud, meta.RegionalKey(frName, l4netlb.cloud.Region()), meta.VersionGA, klog.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error when looking up forwarding rule - %v", err)
+	}
+	if fwdRule.IPProtocol != utils.L3DefaultProtocol || !fwdRule.AllPorts || len(fwdRule.Ports) != 0 || fwdRule.PortRange != "" {
+		t.Errorf("Unexpected forwarding rule protocol %q, allPorts %v, ports %v, portRange %q, want L3_DEFAULT with all ports", fwdRule.IPProtocol, fwdRule.AllPorts, fwdRule.Ports, fwdRule.PortRange)
+	}
+
+	bsName := l4netlb.namer.L4Backend(svc.Namespace, svc.Name)
+	bs, err := composite.GetBackendService(l4netlb.cloud, meta.RegionalKey(bsName, l4netlb.cloud.Region()), meta.VersionGA, klog.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error when looking up backend service - %v", err)
+	}
+	if bs.Protocol != utils.UnspecifiedProtocol {
+		t.Errorf("Unexpected backend service protocol %q, want %q", bs.Protocol, utils.UnspecifiedProtocol)
+	}
+
+	fw, err := firewalls.NewFirewallAdapter(l4netlb.cloud).GetFirewall(l4netlb.namer.L4Firewall(svc.Namespace, svc.Name))
+	if err != nil {
+		t.Fatalf("Unexpected error when looking up firewall - %v", err)
+	}
+	if len(fw.Allowed) != 2 || fw.Allowed[0].IPProtocol != "tcp" || fw.Allowed[1].IPProtocol != "udp" {
+		t.Errorf("Unexpected firewall allowed rules %+v, want tcp and udp", fw.Allowed)
+	}
+
+	l4netlb.Service.Annotations = result.Annotations
+	result = l4netlb.EnsureLoadBalancerDeleted(svc)
+	if result.Error != nil {
+		t.Errorf("Unexpected error %v", result.Error)
+	}
+	assertNetLBResourcesDeleted(t, l4netlb)
+}
+
+func TestEnsureL4NetLoadBalancerProtocolChange(t *testing.T) {
+	t.Parallel()
+	nodeNames := []string{"test-node-1"}
+	tcpPorts := []v1.ServicePort{{Name: "dns-tcp", Port: 53, Protocol: v1.ProtocolTCP}}
+	mixedPorts := []v1.ServicePort{{Name: "dns-tcp", Port: 53, Protocol: v1.ProtocolTCP}, {Name: "dns-udp", Port: 53, Protocol: v1.ProtocolUDP}}
+
+	for _, tc := range []struct {
+		desc           string
+		ports          []v1.ServicePort
+		newPorts       []v1.ServicePort
+		wantFRProtocol string
+	}{
+		{
+			desc:           "single protocol to mixed protocols",
+			ports:          tcpPorts,
+			newPorts:       mixedPorts,
+			wantFRProtocol: utils.L3DefaultProtocol,
+		},
+		{
+			desc:           "mixed protocols to single protocol",
+			ports:          mixedPorts,
+			newPorts:       tcpPorts,
+			wantFRProtocol: "TCP",
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			svc := test.NewL4NetLBRBSService(53)
+			svc.Spec.Ports = tc.ports
+			l4netlb := mustSetupNetLBTestHandler(t, svc, nodeNames)
+			l4netlb.enableDualStack = false
+
+			result := l4netlb.EnsureFrontend(nodeNames, svc)
+			if result.Error != nil {
+				t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+			}
+			frKey := meta.RegionalKey(l4netlb.frName(), l4netlb.cloud.Region())
+			fwdRule, err := composite.GetForwardingRule(l4netlb.cloud, frKey, meta.VersionGA, klog.TODO())
+			if err != nil {
+				t.Fatalf("Unexpected error when looking up forwarding rule - %v", err)
+			}
+			ip := fwdRule.IPAddress
+
+			// The protocol of a backend service can not be changed while a forwarding rule of
+			// another protocol references it.
+			c := l4netlb.cloud.Compute().(*cloud.MockGCE)
+			c.MockRegionBackendServices.UpdateHook = func(ctx context.Context, key *meta.Key, bs *ga.BackendService, m *cloud.MockRegionBackendServices, options ...cloud.Option) error {
+				wantFRProtocol := bs.Protocol
+				if bs.Protocol == utils.UnspecifiedProtocol {
+					wantFRProtocol = utils.L3DefaultProtocol
+				}
+				fr, err := c.MockForwardingRules.Get(ctx, frKey)
+				if utils.IgnoreHTTPNotFound(err) != nil {
+					return err
+				}
+				if fr != nil && fr.IPProtocol != wantFRProtocol {
+					return fmt.Errorf("protocol mismatch between Forwarding Rule value %q and Backend service value %q", fr.IPProtocol, bs.Protocol)
+				}
+				return mock.UpdateRegionBackendServiceHook(ctx, key, bs, m)
+			}
+
+			svc.Spec.Ports = tc.newPorts
+			svc.Annotations = result.Annotations
+			result = l4netlb.EnsureFrontend(nodeNames, svc)
+			if result.Error != nil {
+				t.Fatalf("Failed to ensure loadBalancer after protocol change, err %v", result.Error)
+			}
+			fwdRule, err = composite.GetForwardingRule(l4netlb.cloud, frKey, meta.VersionGA, klog.TODO())
+			if err != nil {
+				t.Fatalf("Unexpected error when looking up forwarding rule - %v", err)
+			}
+			if fwdRule.IPProtocol != tc.wantFRProtocol {
+				t.Errorf("Got forwarding rule protocol %q, want %q", fwdRule.IPProtocol, tc.wantFRProtocol)
+			}
+			if fwdRule.IPAddress != ip {
+				t.Errorf("Got forwarding rule IP %q after protocol change, want %q", fwdRule.IPAddress, ip)
+			}
+			addr, err := l4netlb.cloud.GetRegionAddress(l4netlb.frName(), l4netlb.cloud.Region())
+			if utils.IgnoreHTTPNotFound(err) != nil {
+				t.Fatalf("Unexpected error when looking up address - %v", err)
+			}
+			if addr != nil {
+				t.Errorf("Address %s reserved during the protocol change was not released", addr.Name)
+			}
+		})
+	}
+}
+
+func TestEnsureL4NetLoadBalancerMakeBeforeBreak(t *testing.T) {
+	oldSoakPeriod, oldEnablePinhole := flags.F.L4ForwardingRuleSoakPeriod, flags.F.EnablePinhole
+	flags.F.L4ForwardingRuleSoakPeriod = time.Hour
+	flags.F.EnablePinhole = true
+	defer func() {
+		flags.F.L4ForwardingRuleSoakPeriod, flags.F.EnablePinhole = oldSoakPeriod, oldEnablePinhole
+	}()
+	nodeNames := []string{"test-node-1"}
+
+	svc := test.NewL4NetLBRBSService(8080)
+	svc.Annotations[annotations.NetworkTierAnnotationKey] = string(cloud.NetworkTierPremium)
+	svc.Annotations[annotations.MakeBeforeBreakAnnotationKey] = annotations.MakeBeforeBreakEnabled
+	l4netlb := mustSetupNetLBTestHandler(t, svc, nodeNames)
+	l4netlb.enableDualStack = false
+
+	result := l4netlb.EnsureFrontend(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	frName := l4netlb.frName()
+	premiumFwdRule, err := composite.GetForwardingRule(l4netlb.cloud, meta.RegionalKey(frName, l4netlb.cloud.Region()), meta.VersionGA, klog.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error when looking up forwarding rule - %v", err)
+	}
+
+	// Changing the network tier creates a replacement forwarding rule next to the existing one.
+	svc.Annotations[annotations.NetworkTierAnnotationKey] = string(cloud.NetworkTierStandard)
+	result = l4netlb.EnsureFrontend(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	if _, err := composite.GetForwardingRule(l4netlb.cloud, meta.RegionalKey(frName, l4netlb.cloud.Region()), meta.VersionGA, klog.TODO()); err != nil {
+		t.Errorf("Previous forwarding rule %s should still exist, got error %v", frName, err)
+	}
+	alternateFRName := l4netlb.alternateIPv4FRName(frName)
+	standardFwdRule, err := composite.GetForwardingRule(l4netlb.cloud, meta.RegionalKey(alternateFRName, l4netlb.cloud.Region()), meta.VersionGA, klog.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error when looking up replacement forwarding rule - %v", err)
+	}
+	if standardFwdRule.NetworkTier != cloud.NetworkTierStandard.ToGCEValue() {
+		t.Errorf("Replacement forwarding rule network tier = %q, want %q", standardFwdRule.NetworkTier, cloud.NetworkTierStandard.ToGCEValue())
+	}
+	if got := result.Annotations[annotations.TCPForwardingRuleKey]; got != alternateFRName {
+		t.Errorf("Annotation %s = %q, want %q", annotations.TCPForwardingRuleKey, got, alternateFRName)
+	}
+	wantIngress := []v1.LoadBalancerIngress{{IP: standardFwdRule.IPAddress}, {IP: premiumFwdRule.IPAddress}}
+	if diff := cmp.Diff(wantIngress, result.Status.Ingress); diff != "" {
+		t.Errorf("Unexpected load balancer status (-want +got):\n%s", diff)
+	}
+	fw, err := firewalls.NewFirewallAdapter(l4netlb.cloud).GetFirewall(l4netlb.namer.L4Firewall(svc.Namespace, svc.Name))
+	if err != nil {
+		t.Fatalf("Unexpected error when looking up firewall - %v", err)
+	}
+	if !utils.EqualStringSets(fw.DestinationRanges, []string{standardFwdRule.IPAddress, premiumFwdRule.IPAddress}) {
+		t.Errorf("Firewall destination ranges = %v, want IPs of both forwarding rules", fw.DestinationRanges)
+	}
+
+	// The previous forwarding rule is kept until the soak period passes or its removal is requested.
+	svc.Annotations[annotations.RemovePreviousForwardingRuleAnnotationKey] = "true"
+	result = l4netlb.EnsureFrontend(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	if err := verifyForwardingRuleNotExists(l4netlb.cloud, frName); err != nil {
+		t.Errorf("verifyForwardingRuleNotExists(_, %s) returned error %v, want nil", frName, err)
+	}
+	wantIngress = []v1.LoadBalancerIngress{{IP: standardFwdRule.IPAddress}}
+	if diff := cmp.Diff(wantIngress, result.Status.Ingress); diff != "" {
+		t.Errorf("Unexpected load balancer status (-want +got):\n%s", diff)
+	}
+
+	l4netlb.Service.Annotations = result.Annotations
+	result = l4netlb.EnsureLoadBalancerDeleted(svc)
+	if result.Error != nil {
+		t.Errorf("Unexpected error %v", result.Error)
+	}
+	if err := verifyForwardingRuleNotExists(l4netlb.cloud, alternateFRName); err != nil {
+		t.Errorf("verifyForwardingRuleNotExists(_, %s) returned error %v, want nil", alternateFRName, err)
+	}
+	assertNetLBResourcesDeleted(t, l4netlb)
+}
+
+func TestSoakPeriodPassed(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		desc              string
+		creationTimestamp string
+		soakPeriod        time.Duration
+		want              bool
+	}{
+		{
+			desc:              "soak period passed",
+			creationTimestamp: "2024-05-01T10:00:00Z",
+			soakPeriod:        time.Hour,
+			want:              true,
+		},
+		{
+			desc:              "soak period not passed",
+			creationTimestamp: "2024-05-01T04:30:00-07:00",
+			soakPeriod:        time.Hour,
+			want:              false,
+		},
+		{
+			desc:       "unknown creation time",
+			soakPeriod: time.Hour,
+			want:       false,
+		},
+		{
+			desc: "no soak period",
+			want: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			fr := &composite.ForwardingRule{CreationTimestamp: tc.creationTimestamp}
+			if got := soakPeriodPassed(fr, tc.soakPeriod, now); got != tc.want {
+				t.Errorf("soakPeriodPassed(%q, %v) = %v, want %v", tc.creationTimestamp, tc.soakPeriod, got, tc.want)
+			}
+		})
+	}
+}
+
 func TestEnsureMultinetL4NetLoadBalancer(t *testing.T) {
 	t.Parallel()
 	nodeNames := []string{"test-node-1"}
@@ -884,6 +1150,82 @@ func TestDualStackNetLBStaticIPAnnotation(t *testing.T) {
 	}
 }
 
+func TestDualStackNetLBMultipleAddresses(t *testing.T) {
+	oldEnablePinhole := flags.F.EnablePinhole
+	flags.F.EnablePinhole = true
+	defer func() {
+		flags.F.EnablePinhole = oldEnablePinhole
+	}()
+	nodeNames := []string{"test-node-1"}
+	svc := test.NewL4NetLBRBSService(8080)
+	svc.Spec.IPFamilies = []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol}
+	l4NetLB := mustSetupNetLBTestHandler(t, svc, nodeNames)
+
+	for _, addr := range []*ga.Address{
+		{Name: "ipv4-address-1", Address: "111.111.111.111"},
+		{Name: "ipv4-address-2", Address: "111.111.111.112"},
+		{Name: "ipv6-address-1", Address: "2::2/80", IpVersion: "IPV6"},
+		{Name: "ipv6-address-2", Address: "3::3/80", IpVersion: "IPV6"},
+	} {
+		if err := l4NetLB.cloud.ReserveRegionAddress(addr, l4NetLB.cloud.Region()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	staticAnnotation := "ipv4-address-1,ipv6-address-1,ipv4-address-2,ipv6-address-2"
+	svc.Annotations[annotations.StaticL4AddressesAnnotationKey] = staticAnnotation
+
+	result := l4NetLB.EnsureFrontend(nodeNames, svc)
+	if result.Error != nil {
+		t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+	}
+	svc.Annotations = result.Annotations
+	svc.Annotations[annotations.StaticL4AddressesAnnotationKey] = staticAnnotation
+
+	var gotIPs []string
+	for _, ip := range result.Status.Ingress {
+		gotIPs = append(gotIPs, ip.IP)
+	}
+	if !utils.EqualStringSets(gotIPs, []string{"111.111.111.111", "111.111.111.112", "2::2", "3::3"}) {
+		t.Errorf("Unexpected load balancer IPs %v", gotIPs)
+	}
+
+	ipv4FRName := l4NetLB.frName() + "-1"
+	ipv6FRName := l4NetLB.ipv6FRName() + "-1"
+	if got := result.Annotations[annotations.AdditionalForwardingRulesKey]; got != ipv4FRName {
+		t.Errorf("Got additional forwarding rules annotation %q, want %q", got, ipv4FRName)
+	}
+	if got := result.Annotations[annotations.AdditionalForwardingRulesIPv6Key]; got != ipv6FRName {
+		t.Errorf("Got additional IPv6 forwarding rules annotation %q, want %q", got, ipv6FRName)
+	}
+	for name, address := range map[string]string{ipv4FRName: "111.111.111.112", ipv6FRName: "3::3"} {
+		fr, err := l4NetLB.forwardingRules.Get(name)
+		if err != nil || fr == nil {
+			t.Fatalf("Additional forwarding rule %s not found, err %v", name, err)
+		}
+		if got := strings.Split(fr.IPAddress, "/")[0]; got != address {
+			t.Errorf("Additional forwarding rule %s has address %s, want %s", name, got, address)
+		}
+	}
+	ipv6Firewall, err := l4NetLB.cloud.GetFirewall(l4NetLB.namer.L4IPv6Firewall(svc.Namespace, svc.Name))
+	if err != nil {
+		t.Fatalf("Failed to get IPv6 firewall, err %v", err)
+	}
+	if !utils.EqualStringSets(ipv6Firewall.DestinationRanges, []string{"2::2", "3::3"}) {
+		t.Errorf("Unexpected IPv6 firewall destination ranges %v", ipv6Firewall.DestinationRanges)
+	}
+
+	result = l4NetLB.EnsureLoadBalancerDeleted(svc)
+	if result.Error != nil {
+		t.Fatalf("Unexpected error deleting loadbalancer - err %v", result.Error)
+	}
+	assertDualStackNetLBResourcesDeleted(t, l4NetLB)
+	for _, name := range []string{ipv4FRName, ipv6FRName} {
+		if fr, err := l4NetLB.forwardingRules.Get(name); err != nil || fr != nil {
+			t.Errorf("Expected additional forwarding rule %s to be deleted, got %v, err %v", name, fr, err)
+		}
+	}
+}
+
 func mustSetupNetLBTestHandler(t *testing.T, svc *v1.Service, nodeNames []string) *L4NetLB {
 	t.Helper()
 
@@ -1200,6 +1542,84 @@ func TestWeightedNetLB(t *testing.T) {
 	}
 }
 
+func TestEnsureNetLBConnectionTracking(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc                 string
+		annotation           string
+		strongAffinity       bool
+		wantPolicy           *composite.BackendServiceConnectionTrackingPolicy
+		wantUserError        bool
+		wantGCEResourceError string
+	}{
+		{
+			desc:       "connection tracking annotation",
+			annotation: `{"trackingMode": "PER_SESSION", "connectionPersistenceOnUnhealthyBackends": "NEVER_PERSIST", "idleTimeoutSec": 3600}`,
+			wantPolicy: &composite.BackendServiceConnectionTrackingPolicy{
+				TrackingMode:                             annotations.TrackingModePerSession,
+				ConnectionPersistenceOnUnhealthyBackends: annotations.ConnectionPersistenceNeverPersist,
+				IdleTimeoutSec:                           3600,
+			},
+		},
+		{
+			desc:           "strong session affinity takes precedence over the annotation",
+			annotation:     `{"trackingMode": "PER_CONNECTION", "connectionPersistenceOnUnhealthyBackends": "NEVER_PERSIST"}`,
+			strongAffinity: true,
+			wantPolicy: &composite.BackendServiceConnectionTrackingPolicy{
+				EnableStrongAffinity:                     true,
+				TrackingMode:                             backends.PerSessionTrackingMode,
+				ConnectionPersistenceOnUnhealthyBackends: annotations.ConnectionPersistenceNeverPersist,
+				IdleTimeoutSec:                           300,
+			},
+		},
+		{
+			desc:                 "invalid annotation",
+			annotation:           `{"trackingMode": "PER_PACKET"}`,
+			wantUserError:        true,
+			wantGCEResourceError: annotations.BackendServiceResource,
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			svc := test.NewL4NetLBRBSService(8080)
+			svc.Annotations[annotations.L4ConnectionTrackingKey] = tc.annotation
+			if tc.strongAffinity {
+				svc.Annotations[annotations.StrongSessionAffinityAnnotationKey] = annotations.StrongSessionAffinityEnabled
+				svc.Spec.SessionAffinity = v1.ServiceAffinityClientIP
+				timeout := int32(300)
+				svc.Spec.SessionAffinityConfig = &v1.SessionAffinityConfig{ClientIP: &v1.ClientIPConfig{TimeoutSeconds: &timeout}}
+			}
+			nodeNames := []string{"test-node-1"}
+			l4NetLB := mustSetupNetLBTestHandler(t, svc, nodeNames)
+			l4NetLB.enableStrongSessionAffinity = tc.strongAffinity
+
+			result := l4NetLB.EnsureFrontend(nodeNames, svc)
+			if tc.wantUserError {
+				if !utils.IsUserError(result.Error) {
+					t.Errorf("EnsureFrontend() returned error %v, want user error", result.Error)
+				}
+				if result.GCEResourceInError != tc.wantGCEResourceError {
+					t.Errorf("EnsureFrontend() returned GCEResourceInError %q, want %q", result.GCEResourceInError, tc.wantGCEResourceError)
+				}
+				return
+			}
+			if result.Error != nil {
+				t.Fatalf("Failed to ensure loadBalancer, err %v", result.Error)
+			}
+			backendServiceName := l4NetLB.namer.L4Backend(l4NetLB.Service.Namespace, l4NetLB.Service.Name)
+			key := meta.RegionalKey(backendServiceName, l4NetLB.cloud.Region())
+			bs, err := composite.GetBackendService(l4NetLB.cloud, key, meta.VersionGA, klog.TODO())
+			if err != nil {
+				t.Fatalf("failed to read BackendService, %v", err)
+			}
+			if diff := cmp.Diff(tc.wantPolicy, bs.ConnectionTrackingPolicy); diff != "" {
+				t.Errorf("Unexpected BackendService ConnectionTrackingPolicy (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestDisableNetLBIngressFirewall(t *testing.T) {
 	t.Parallel()
 	fakeGCE := getFakeGCECloud(gce.DefaultTestClusterValues())
@@ -1221,7 +1641,7 @@ func TestDisableNetLBIngressFirewall(t *testing.T) {
 		Annotations: make(map[string]string),
 	}
 
-	l4netlb.ensureIPv4NodesFirewall(nodeNames, "10.0.0.7", syncResult)
+	l4netlb.ensureIPv4NodesFirewall(nodeNames, []string{"10.0.0.7"}, syncResult)
 	if syncResult.Error != nil {
 		t.Fatalf("ensureIPv4NodesFirewall() error %+v", syncResult)
 	}
@@ -1232,7 +1652,7 @@ func TestDisableNetLBIngressFirewall(t *testing.T) {
 		t.Errorf("verifyFirewallNotExists(_, %s) for IPv4 NetLB firewall returned error %v, want nil", ipv4FirewallName, err)
 	}
 
-	l4netlb.ensureIPv6NodesFirewall("2001:db8::ff00:42:8329", nodeNames, syncResult)
+	l4netlb.ensureIPv6NodesFirewall([]string{"2001:db8::ff00:42:8329"}, nodeNames, syncResult)
 	if syncResult.Error != nil {
 		t.Fatalf("ensureIPv6NodesFirewall() error %+v", syncResult)
 	}
@@ -23,7 +23,6 @@ import (
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/firewalls"
 	"k8s.io/ingress-gce/pkg/utils"
@@ -49,21 +48,29 @@ func (l4netlb *L4NetLB) ensureIPv6Resources(syncResult *L4NetLBSyncResult, nodeN
 		return
 	}
 
-	if ipv6fr.IPProtocol == string(corev1.ProtocolTCP) {
-		syncResult.Annotations[annotations.TCPForwardingRuleIPv6Key] = ipv6fr.Name
-	} else {
-		syncResult.Annotations[annotations.UDPForwardingRuleIPv6Key] = ipv6fr.Name
+	syncResult.Annotations[ipv6ForwardingRuleAnnotationKey(ipv6fr)] = ipv6fr.Name
+
+	additionalFRs, err := l4netlb.ensureAdditionalForwardingRules(ipv6fr, l4netlb.ipv6FRName(), IPv6Version)
+	if err != nil {
+		l4netlb.svcLogger.Error(err, "ensureIPv6Resources: Failed to ensure additional ipv6 forwarding rules")
+		syncResult.GCEResourceInError = annotations.ForwardingRuleIPv6Resource
+		syncResult.Error = err
+		return
+	}
+	if len(additionalFRs) > 0 {
+		syncResult.Annotations[annotations.AdditionalForwardingRulesIPv6Key] = additionalFRNamesAnnotation(additionalFRs)
 	}
 
 	// Google Cloud creates ipv6 forwarding rules with IPAddress in CIDR form. We will take only first address
 	trimmedIPv6Address := strings.Split(ipv6fr.IPAddress, "/")[0]
+	ipv6Addresses := append([]string{trimmedIPv6Address}, additionalFRAddresses(additionalFRs)...)
 
-	l4netlb.ensureIPv6NodesFirewall(trimmedIPv6Address, nodeNames, syncResult)
+	l4netlb.ensureIPv6NodesFirewall(ipv6Addresses, nodeNames, syncResult)
 	if syncResult.Error != nil {
 		return
 	}
 
-	syncResult.Status = utils.AddIPToLBStatus(syncResult.Status, trimmedIPv6Address)
+	syncResult.Status = utils.AddIPToLBStatus(syncResult.Status, ipv6Addresses...)
 }
 
 // deleteIPv6ResourcesOnSync deletes resources specific to IPv6,
@@ -93,9 +100,14 @@ func (l4netlb *L4NetLB) deleteIPv6ResourcesOnDelete(syncResult *L4NetLBSyncResul
 // This function does not delete Backend Service and Health Check, because they are shared between IPv4 and IPv6.
 // IPv6 Firewall Rule for Health Check also will not be deleted here, and will be left till the Service Deletion.
 func (l4netlb *L4NetLB) deleteIPv6ResourcesAnnotationBased(syncResult *L4NetLBSyncResult, shouldIgnoreAnnotations bool) {
-	if shouldIgnoreAnnotations || l4netlb.hasAnnotation(annotations.TCPForwardingRuleIPv6Key) || l4netlb.hasAnnotation(annotations.UDPForwardingRuleIPv6Key) {
+	if shouldIgnoreAnnotations || l4netlb.hasAnnotation(annotations.TCPForwardingRuleIPv6Key) || l4netlb.hasAnnotation(annotations.UDPForwardingRuleIPv6Key) || l4netlb.hasAnnotation(annotations.L3ForwardingRuleIPv6Key) {
 		l4netlb.deleteIPv6ForwardingRule(syncResult)
 	}
+	if err := l4netlb.deleteAdditionalForwardingRules(l4netlb.ipv6FRName(), IPv6Version, shouldIgnoreAnnotations); err != nil {
+		l4netlb.svcLogger.Error(err, "Failed to delete additional ipv6 forwarding rules for external loadbalancer service")
+		syncResult.Error = err
+		syncResult.GCEResourceInError = annotations.ForwardingRuleIPv6Resource
+	}
 
 	if shouldIgnoreAnnotations || l4netlb.hasAnnotation(annotations.FirewallRuleIPv6Key) {
 		l4netlb.deleteIPv6NodesFirewall(syncResult)
@@ -106,7 +118,7 @@ func (l4netlb *L4NetLB) ipv6FRName() string {
 	return namer.GetSuffixedName(l4netlb.frName(), ipv6Suffix)
 }
 
-func (l4netlb *L4NetLB) ensureIPv6NodesFirewall(ipAddress string, nodeNames []string, syncResult *L4NetLBSyncResult) {
+func (l4netlb *L4NetLB) ensureIPv6NodesFirewall(ipAddresses []string, nodeNames []string, syncResult *L4NetLBSyncResult) {
 	// DisableL4LBFirewall flag disables L4 FW enforcment to remove conflicts with firewall policies
 	if l4netlb.disableNodesFirewallProvisioning {
 		l4netlb.svcLogger.Info("Skipped ensuring IPv6 nodes firewall for L4 NetLB Service to enable compatibility with firewall policies. " +
@@ -121,7 +133,7 @@ func (l4netlb *L4NetLB) ensureIPv6NodesFirewall(ipAddress string, nodeNames []st
 	protocol := utils.GetProtocol(svcPorts)
 
 	fwLogger := l4netlb.svcLogger.WithValues("firewallName", firewallName)
-	fwLogger.V(2).Info("Ensuring IPv6 nodes firewall for L4 NetLB Service", "ipAddress", ipAddress, "protocol", protocol, "len(nodeNames)", len(nodeNames), "portRanges", portRanges)
+	fwLogger.V(2).Info("Ensuring IPv6 nodes firewall for L4 NetLB Service", "ipAddresses", ipAddresses, "protocol", protocol, "len(nodeNames)", len(nodeNames), "portRanges", portRanges)
 	defer func() {
 		fwLogger.V(2).Info("Finished ensuring IPv6 nodes firewall for L4 NetLB Service", "timeTaken", time.Since(start))
 	}()
@@ -136,13 +148,16 @@ func (l4netlb *L4NetLB) ensureIPv6NodesFirewall(ipAddress string, nodeNames []st
 	ipv6nodesFWRParams := firewalls.FirewallParams{
 		PortRanges:        portRanges,
 		SourceRanges:      ipv6SourceRanges,
-		DestinationRanges: []string{ipAddress},
+		DestinationRanges: ipAddresses,
 		Protocol:          string(protocol),
 		Name:              firewallName,
 		NodeNames:         nodeNames,
 		L4Type:            utils.XLB,
 		Network:           l4netlb.networkInfo,
 	}
+	if utils.HasMixedProtocols(svcPorts) {
+		ipv6nodesFWRParams.ProtocolPortRanges = utils.GetServicePortRangesByProtocol(svcPorts)
+	}
 
 	wasUpdate, err := firewalls.EnsureL4LBFirewallForNodes(l4netlb.Service, &ipv6nodesFWRParams, l4netlb.cloud, l4netlb.recorder, fwLogger)
 	syncResult.GCEResourceUpdate.firewallForNodesUpdate = wasUpdate
@@ -29,9 +29,14 @@ var L4ResourceAnnotationKeys = []string{
 	annotations.BackendServiceKey,
 	annotations.TCPForwardingRuleKey,
 	annotations.UDPForwardingRuleKey,
+	annotations.L3ForwardingRuleKey,
+	annotations.AdditionalForwardingRulesKey,
 	annotations.HealthcheckKey,
 	annotations.FirewallRuleKey,
 	annotations.FirewallRuleForHealthcheckKey,
+	// The request to remove the previous forwarding rule of a make-before-break recreation
+	// is consumed by the sync that handles it, so that it does not apply to later recreations.
+	annotations.RemovePreviousForwardingRuleAnnotationKey,
 }
 
 var l4IPv6ResourceAnnotationKeys = []string{
@@ -39,5 +44,7 @@ var l4IPv6ResourceAnnotationKeys = []string{
 	annotations.FirewallRuleForHealthcheckIPv6Key,
 	annotations.TCPForwardingRuleIPv6Key,
 	annotations.UDPForwardingRuleIPv6Key,
+	annotations.L3ForwardingRuleIPv6Key,
+	annotations.AdditionalForwardingRulesIPv6Key,
 }
 var L4DualStackResourceAnnotationKeys = append(L4ResourceAnnotationKeys, l4IPv6ResourceAnnotationKeys...)
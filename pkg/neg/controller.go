@@ -18,6 +18,7 @@ package neg
 
 import (
 	"fmt"
+	"reflect"
 	"time"
 
 	apiv1 "k8s.io/api/core/v1"
@@ -85,6 +86,9 @@ type Controller struct {
 	endpointQueue workqueue.RateLimitingInterface
 	// nodeQueue takes node name as work item.
 	nodeQueue workqueue.RateLimitingInterface
+	// resourceNEGQueue takes ServiceNetworkEndpointGroup key as work item.
+	// Only ServiceNetworkEndpointGroups that declare a NEG in their spec are queued.
+	resourceNEGQueue workqueue.RateLimitingInterface
 
 	// syncTracker tracks the latest time that service and endpoint changes are processed
 	syncTracker utils.TimeTracker
@@ -104,6 +108,13 @@ type Controller struct {
 	// gce-regional-external ingresses
 	enableIngressRegionalExternal bool
 
+	// enableResourceNEGs indicates whether NEG controller should sync the
+	// serverless and internet NEGs declared in ServiceNetworkEndpointGroups.
+	enableResourceNEGs bool
+
+	// lpConfig configures the pod label to be propagated to NEG endpoints.
+	lpConfig *labels.ConfigStore
+
 	stopCh <-chan struct{}
 	logger klog.Logger
 }
@@ -137,7 +148,7 @@ func NewController(
 	enableDualStackNEG bool,
 	enableAsm bool,
 	asmServiceNEGSkipNamespaces []string,
-	lpConfig labels.PodLabelPropagationConfig,
+	lpConfig *labels.ConfigStore,
 	enableMultiNetworking bool,
 	enableIngressRegionalExternal bool,
 	stopCh <-chan struct{},
@@ -183,6 +194,7 @@ func NewController(
 		enableNonGcpMode,
 		enableDualStackNEG,
 		numGCWorkers,
+		flags.F.MaxConcurrentNegSyncs,
 		lpConfig,
 		logger)
 
@@ -195,6 +207,11 @@ func NewController(
 			cloud,
 			manager,
 			zoneGetter,
+			readiness.PollerConfig{
+				MaxConcurrentPolls: flags.F.NegReadinessMaxConcurrentPolls,
+				QPS:                flags.F.NegReadinessPollQPS,
+				Burst:              flags.F.NegReadinessPollBurst,
+			},
 			enableDualStackNEG,
 			flags.F.EnableMultiSubnetCluster,
 			logger,
@@ -228,11 +245,14 @@ func NewController(
 		serviceQueue:                  workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "neg_service_queue"),
 		endpointQueue:                 workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "neg_endpoint_queue"),
 		nodeQueue:                     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "neg_node_queue"),
+		resourceNEGQueue:              workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "neg_resource_neg_queue"),
 		syncTracker:                   utils.NewTimeTracker(),
 		reflector:                     reflector,
 		syncerMetrics:                 syncerMetrics,
 		runL4:                         runL4Controller,
 		enableIngressRegionalExternal: enableIngressRegionalExternal,
+		enableResourceNEGs:            flags.F.EnableResourceNEGs,
+		lpConfig:                      lpConfig,
 		stopCh:                        stopCh,
 		logger:                        logger,
 	}
@@ -274,6 +294,7 @@ func NewController(
 		AddFunc: func(obj interface{}) {
 			pod := obj.(*apiv1.Pod)
 			negController.reflector.SyncPod(pod)
+			negController.checkPodLabelTruncation(pod)
 		},
 		UpdateFunc: func(old, cur interface{}) {
 			pod := cur.(*apiv1.Pod)
@@ -323,6 +344,25 @@ func NewController(
 		},
 	})
 
+	if negController.enableResourceNEGs {
+		svcNegInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: negController.enqueueResourceNEG,
+			UpdateFunc: func(old, cur interface{}) {
+				oldNeg, ok := old.(*svcnegv1beta1.ServiceNetworkEndpointGroup)
+				if !ok {
+					return
+				}
+				curNeg, ok := cur.(*svcnegv1beta1.ServiceNetworkEndpointGroup)
+				if !ok {
+					return
+				}
+				if resourceNEGNeedsSync(oldNeg, curNeg) {
+					negController.enqueueResourceNEG(cur)
+				}
+			},
+		})
+	}
+
 	if enableAsm {
 		negController.enableASM = enableAsm
 		negController.asmServiceNEGSkipNamespaces = asmServiceNEGSkipNamespaces
@@ -346,6 +386,9 @@ func (c *Controller) Run() {
 	go wait.Until(c.serviceWorker, time.Second, c.stopCh)
 	go wait.Until(c.endpointWorker, time.Second, c.stopCh)
 	go wait.Until(c.nodeWorker, time.Second, c.stopCh)
+	if c.enableResourceNEGs {
+		go wait.Until(c.resourceNEGWorker, time.Second, c.stopCh)
+	}
 	go func() {
 		// Wait for gcPeriod to run the first GC
 		// This is to make sure that all services are fully processed before running GC.
@@ -375,6 +418,7 @@ func (c *Controller) stop() {
 	c.serviceQueue.ShutDown()
 	c.endpointQueue.ShutDown()
 	c.nodeQueue.ShutDown()
+	c.resourceNEGQueue.ShutDown()
 	c.manager.ShutDown()
 }
 
@@ -404,6 +448,35 @@ func (c *Controller) nodeWorker() {
 	}
 }
 
+func (c *Controller) resourceNEGWorker() {
+	for {
+		func() {
+			key, quit := c.resourceNEGQueue.Get()
+			if quit {
+				return
+			}
+			defer c.resourceNEGQueue.Done(key)
+			err := c.processResourceNEG(key.(string))
+			if err != nil {
+				c.logger.Error(err, "Error processing ServiceNetworkEndpointGroup", "svcneg", key)
+				c.resourceNEGQueue.AddRateLimited(key)
+			} else {
+				c.resourceNEGQueue.Forget(key)
+			}
+			metrics.PublishNegControllerErrorCountMetrics(err, false)
+		}()
+	}
+}
+
+// processResourceNEG syncs the NEG declared in the spec of a ServiceNetworkEndpointGroup.
+func (c *Controller) processResourceNEG(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	return c.manager.SyncResourceNEG(namespace, name)
+}
+
 // processNode finds the related syncers and signal it to sync
 // use a semaphore approach where all vm_ip syncers can wake up.
 func (c *Controller) processNode() {
@@ -598,7 +671,8 @@ func (c *Controller) mergeStandaloneNEGsPortInfo(service *apiv1.Service, name ty
 func (c *Controller) mergeVmIpNEGsPortInfo(service *apiv1.Service, name types.NamespacedName, portInfoMap negtypes.PortInfoMap, negUsage *metricscollector.NegServiceState, networkInfo *network.NetworkInfo) error {
 	wantsILB, _ := annotations.WantsL4ILB(service)
 	wantsNetLB, _ := annotations.WantsL4NetLB(service)
-	needsNEGForNetLB := wantsNetLB && !networkInfo.IsDefault && annotations.HasRBSAnnotation(service)
+	isRBSService := annotations.HasRBSAnnotation(service) || annotations.HasLoadBalancerClass(service, annotations.RegionalExternalLoadBalancerClass)
+	needsNEGForNetLB := wantsNetLB && !networkInfo.IsDefault && isRBSService
 	if !wantsILB && !needsNEGForNetLB {
 		return nil
 	}
@@ -610,7 +684,7 @@ func (c *Controller) mergeVmIpNEGsPortInfo(service *apiv1.Service, name types.Na
 		return nil
 	}
 
-	if service.Spec.LoadBalancerClass != nil {
+	if service.Spec.LoadBalancerClass != nil && !annotations.HasGKELoadBalancerClass(service) {
 		msg := fmt.Sprintf("Ignoring Service %s, namespace %s as it uses a LoadBalancerClass %s", service.Name, service.Namespace, *service.Spec.LoadBalancerClass)
 		c.logger.Info(msg)
 		return nil
@@ -761,6 +835,58 @@ func (c *Controller) handleErr(err error, key interface{}) {
 	c.serviceQueue.AddRateLimited(key)
 }
 
+// enqueueResourceNEG enqueues ServiceNetworkEndpointGroups that declare a NEG
+// in their spec. Deletions are handled through the finalizer, so the update
+// setting the deletion timestamp is the last event that needs processing.
+func (c *Controller) enqueueResourceNEG(obj interface{}) {
+	negCR, ok := obj.(*svcnegv1beta1.ServiceNetworkEndpointGroup)
+	if !ok {
+		c.logger.Error(nil, "Unexpected object type, expected *ServiceNetworkEndpointGroup", "objectTypeFound", fmt.Sprintf("%T", obj))
+		return
+	}
+	if negtypes.ResourceNEGType(negCR) == "" {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(negCR)
+	if err != nil {
+		c.logger.Error(err, "Failed to generate ServiceNetworkEndpointGroup key")
+		metrics.PublishNegControllerErrorCountMetrics(err, true)
+		return
+	}
+	c.resourceNEGQueue.Add(key)
+}
+
+// resourceNEGNeedsSync returns true if the update of a ServiceNetworkEndpointGroup
+// has to be synced. Status updates, including the ones made by the syncer itself,
+// are ignored. The CRD has no status subresource, so the generation also changes
+// on status updates and the spec is compared instead. Informer resyncs are synced.
+func resourceNEGNeedsSync(old, cur *svcnegv1beta1.ServiceNetworkEndpointGroup) bool {
+	if old.ResourceVersion == cur.ResourceVersion {
+		return true
+	}
+	return !reflect.DeepEqual(old.Spec, cur.Spec) ||
+		!old.DeletionTimestamp.Equal(cur.DeletionTimestamp) ||
+		!reflect.DeepEqual(old.Finalizers, cur.Finalizers)
+}
+
+// checkPodLabelTruncation records a warning event if any label of a newly
+// created pod will be truncated when propagated to its NEG endpoints.
+// Pods already scheduled to a node are skipped to avoid repeating the
+// warning for all existing pods whenever the controller restarts. The
+// admission-time warning is returned by the label propagation webhook.
+func (c *Controller) checkPodLabelTruncation(pod *apiv1.Pod) {
+	if pod.Spec.NodeName != "" {
+		return
+	}
+	lpConfig := c.lpConfig.Get()
+	if len(lpConfig.Labels) == 0 && len(lpConfig.Rules) == 0 && len(lpConfig.DerivedLabels) == 0 {
+		return
+	}
+	if err := labels.CheckPodLabelTruncation(pod, lpConfig); err != nil {
+		c.recorder.Eventf(pod, apiv1.EventTypeWarning, "LabelWillBeTruncated", "Label Propagation Warning: %v", err)
+	}
+}
+
 func (c *Controller) enqueueEndpointSlice(obj interface{}) {
 	endpointSlice, ok := obj.(*discovery.EndpointSlice)
 	if !ok {
@@ -41,6 +41,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/cloud-provider-gcp/providers/gce"
 	"k8s.io/ingress-gce/pkg/annotations"
+	svcnegv1beta1 "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1"
 	"k8s.io/ingress-gce/pkg/neg/metrics/metricscollector"
 	"k8s.io/ingress-gce/pkg/neg/syncers/labels"
 	negtypes "k8s.io/ingress-gce/pkg/neg/types"
@@ -125,6 +126,7 @@ func newTestControllerWithParamsAndContext(kubeClient kubernetes.Interface, test
 	nodeInformer := zonegetter.FakeNodeInformer()
 	zonegetter.PopulateFakeNodeInformer(nodeInformer, false)
 	zoneGetter := zonegetter.NewFakeZoneGetter(nodeInformer, defaultTestSubnetURL, false)
+	lpConfigStore, _ := labels.NewConfigStore(labels.PodLabelPropagationConfig{}, klog.TODO())
 
 	return NewController(
 		kubeClient,
@@ -155,7 +157,7 @@ func newTestControllerWithParamsAndContext(kubeClient kubernetes.Interface, test
 		testContext.EnableDualStackNEG,
 		enableASM, //enableAsm
 		[]string{},
-		labels.PodLabelPropagationConfig{},
+		lpConfigStore,
 		true,
 		false,
 		make(<-chan struct{}),
@@ -1254,6 +1256,12 @@ func TestMergeVmIpNEGsPortInfo(t *testing.T) {
 	serviceWithLoadBalancerClass.Spec.LoadBalancerClass = &testLBClass
 	serviceWithLoadBalancerClass.Finalizers = append(serviceILBWithFinalizer.Finalizers, common.ILBFinalizerV2)
 
+	serviceWithGKELoadBalancerClass := newTestILBService(controller, false, 80)
+	serviceWithGKELoadBalancerClass.Annotations = nil
+	gkeLBClass := annotations.RegionalInternalLoadBalancerClass
+	serviceWithGKELoadBalancerClass.Spec.LoadBalancerClass = &gkeLBClass
+	serviceWithGKELoadBalancerClass.Finalizers = append(serviceWithGKELoadBalancerClass.Finalizers, common.ILBFinalizerV2)
+
 	testCases := []struct {
 		desc           string
 		svc            *apiv1.Service
@@ -1290,6 +1298,12 @@ func TestMergeVmIpNEGsPortInfo(t *testing.T) {
 			networkInfo:    defaultNetwork,
 			wantSvcPortMap: nil,
 		},
+		{
+			desc:           "ILB service with GKE load balancer class",
+			svc:            serviceWithGKELoadBalancerClass,
+			networkInfo:    defaultNetwork,
+			wantSvcPortMap: negtypes.NewPortInfoMapForVMIPNEG(testServiceNamespace, testServiceName, controller.l4Namer, false, defaultNetwork),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2147,3 +2161,66 @@ func newTestServiceCus(t *testing.T, c *Controller, namespace, name string, port
 	c.client.CoreV1().Services(namespace).Create(context.TODO(), svc, metav1.CreateOptions{})
 	return svc
 }
+
+func TestResourceNEGNeedsSync(t *testing.T) {
+	now := metav1.Now()
+	base := &svcnegv1beta1.ServiceNetworkEndpointGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "neg", Namespace: "default", ResourceVersion: "1", Generation: 1},
+		Spec: svcnegv1beta1.ServiceNetworkEndpointGroupSpec{
+			Internet: &svcnegv1beta1.InternetNEGSpec{FQDN: "example.com"},
+		},
+	}
+	testCases := []struct {
+		desc   string
+		update func(neg *svcnegv1beta1.ServiceNetworkEndpointGroup)
+		want   bool
+	}{
+		{
+			desc:   "informer resync",
+			update: func(neg *svcnegv1beta1.ServiceNetworkEndpointGroup) {},
+			want:   true,
+		},
+		{
+			desc: "status update",
+			update: func(neg *svcnegv1beta1.ServiceNetworkEndpointGroup) {
+				neg.ResourceVersion = "2"
+				neg.Generation = 2
+				neg.Status.LastSyncTime = now
+			},
+			want: false,
+		},
+		{
+			desc: "spec update",
+			update: func(neg *svcnegv1beta1.ServiceNetworkEndpointGroup) {
+				neg.ResourceVersion = "2"
+				neg.Spec.Internet = &svcnegv1beta1.InternetNEGSpec{FQDN: "example.org"}
+			},
+			want: true,
+		},
+		{
+			desc: "deletion",
+			update: func(neg *svcnegv1beta1.ServiceNetworkEndpointGroup) {
+				neg.ResourceVersion = "2"
+				neg.DeletionTimestamp = &now
+			},
+			want: true,
+		},
+		{
+			desc: "finalizer removed",
+			update: func(neg *svcnegv1beta1.ServiceNetworkEndpointGroup) {
+				neg.ResourceVersion = "2"
+				neg.Finalizers = []string{"other"}
+			},
+			want: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cur := base.DeepCopy()
+			tc.update(cur)
+			if got := resourceNEGNeedsSync(base, cur); got != tc.want {
+				t.Errorf("resourceNEGNeedsSync() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/ingress-gce/pkg/annotations"
 	negv1beta1 "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1"
 	"k8s.io/ingress-gce/pkg/neg/metrics"
 	"k8s.io/ingress-gce/pkg/neg/metrics/metricscollector"
@@ -113,7 +114,20 @@ type syncerManager struct {
 	vmIpPortZoneMap map[string]struct{}
 
 	// lpConfig configures the pod label to be propagated to NEG endpoints.
-	lpConfig podlabels.PodLabelPropagationConfig
+	lpConfig *podlabels.ConfigStore
+
+	// resourceNEGSyncer syncs the NEGs declared in the spec of
+	// ServiceNetworkEndpointGroups.
+	resourceNEGSyncer *negsyncer.ResourceNEGSyncer
+
+	// syncScheduler limits the number of concurrent NEG syncs and operations and shares them
+	// among the syncers according to the NEG sync priority of their Services.
+	syncScheduler *negsyncer.SyncScheduler
+
+	// invalidReadinessAnnotations stores the error reported for the invalid NEG
+	// readiness annotation of each service, so that it is reported once.
+	invalidReadinessAnnotations map[serviceKey]string
+	invalidReadinessMu          sync.Mutex
 }
 
 func newSyncerManager(namer negtypes.NetworkEndpointGroupNamer,
@@ -131,7 +145,8 @@ func newSyncerManager(namer negtypes.NetworkEndpointGroupNamer,
 	enableNonGcpMode bool,
 	enableDualStackNEG bool,
 	numGCWorkers int,
-	lpConfig podlabels.PodLabelPropagationConfig,
+	maxConcurrentSyncs int,
+	lpConfig *podlabels.ConfigStore,
 	logger klog.Logger) *syncerManager {
 
 	var vmIpPortZoneMap map[string]struct{}
@@ -158,6 +173,10 @@ func newSyncerManager(namer negtypes.NetworkEndpointGroupNamer,
 		logger:              logger,
 		vmIpPortZoneMap:     vmIpPortZoneMap,
 		lpConfig:            lpConfig,
+		resourceNEGSyncer:   negsyncer.NewResourceNEGSyncer(cloud, namer, svcNegClient, svcNegLister, recorder, string(kubeSystemUID), logger),
+		syncScheduler:       negsyncer.NewSyncScheduler(maxConcurrentSyncs, logger),
+
+		invalidReadinessAnnotations: make(map[serviceKey]string),
 	}
 }
 
@@ -258,6 +277,7 @@ func (manager *syncerManager) EnsureSyncers(namespace, name string, newPorts neg
 				manager.lpConfig,
 				manager.enableDualStackNEG,
 				portInfo.NetworkInfo,
+				manager.syncScheduler,
 			)
 			manager.syncerMap[syncerKey] = syncer
 		}
@@ -308,6 +328,12 @@ func (manager *syncerManager) Sync(namespace, name string) {
 	}
 }
 
+// SyncResourceNEG syncs the NEG declared in the spec of the
+// ServiceNetworkEndpointGroup namespace/name.
+func (manager *syncerManager) SyncResourceNEG(namespace, name string) error {
+	return manager.resourceNEGSyncer.Sync(namespace, name)
+}
+
 // SyncNodes signals all GCE_VM_IP syncers to sync.
 // Only these use nodes selected at random as endpoints and hence need to sync upon node updates.
 func (manager *syncerManager) SyncNodes() {
@@ -447,6 +473,42 @@ func (manager *syncerManager) ReadinessGateEnabled(syncerKey negtypes.NegSyncerK
 	return false
 }
 
+// ReadinessGateHealthRequirement returns the backend services which must report
+// the endpoints of the NEG as healthy before their pods are marked ready. It is
+// configured by the NEG readiness backend services annotation on the service.
+func (manager *syncerManager) ReadinessGateHealthRequirement(syncerKey negtypes.NegSyncerKey) readiness.HealthRequirement {
+	obj, exists, err := manager.serviceLister.GetByKey(getServiceKey(syncerKey.Namespace, syncerKey.Name).Key())
+	if err != nil || !exists {
+		return readiness.HealthRequirement{}
+	}
+	service := obj.(*v1.Service)
+	requireAll, backendServices, err := annotations.FromService(service).NEGReadinessBackendServices()
+	manager.reportInvalidReadinessAnnotation(service, err)
+	if err != nil {
+		return readiness.HealthRequirement{Invalid: true}
+	}
+	return readiness.HealthRequirement{All: requireAll, BackendServices: backendServices}
+}
+
+// reportInvalidReadinessAnnotation emits a Warning event on the service the
+// first time the given error is returned for its NEG readiness annotation.
+// Pods of the service wait for the annotation to be fixed.
+func (manager *syncerManager) reportInvalidReadinessAnnotation(service *v1.Service, err error) {
+	key := serviceKey{namespace: service.Namespace, name: service.Name}
+	manager.invalidReadinessMu.Lock()
+	defer manager.invalidReadinessMu.Unlock()
+	if err == nil {
+		delete(manager.invalidReadinessAnnotations, key)
+		return
+	}
+	if manager.invalidReadinessAnnotations[key] == err.Error() {
+		return
+	}
+	manager.invalidReadinessAnnotations[key] = err.Error()
+	manager.logger.Error(err, "Invalid NEG readiness annotation, pods will not be marked ready until it is fixed", "service", klog.KObj(service))
+	manager.recorder.Eventf(service, v1.EventTypeWarning, negtypes.NegReadinessAnnotationInvalid, "Invalid NEG readiness annotation, pods will not be marked ready until it is fixed: %v", err)
+}
+
 // ensureDeleteSvcNegCR will set the deletion timestamp for the specified NEG CR based
 // on the given neg name. If the Deletion timestamp has already been set on the CR, no
 // change will occur.
@@ -543,6 +605,11 @@ func (manager *syncerManager) garbageCollectNEGWithCRD() error {
 	negCRs := manager.svcNegLister.List()
 	for _, obj := range negCRs {
 		neg := obj.(*negv1beta1.ServiceNetworkEndpointGroup)
+		// NEGs declared in the CR spec are owned by the CR, not by a service,
+		// and are deleted by SyncResourceNEG when the CR is deleted.
+		if negtypes.ResourceNEGType(neg) != "" {
+			continue
+		}
 		deletionCandidates[neg.Name] = neg
 	}
 
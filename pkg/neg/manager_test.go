@@ -43,8 +43,10 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/ingress-gce/pkg/annotations"
 	negv1beta1 "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1"
 	"k8s.io/ingress-gce/pkg/neg/metrics/metricscollector"
+	"k8s.io/ingress-gce/pkg/neg/readiness"
 	"k8s.io/ingress-gce/pkg/neg/syncers/labels"
 	"k8s.io/ingress-gce/pkg/neg/types"
 	negtypes "k8s.io/ingress-gce/pkg/neg/types"
@@ -89,6 +91,7 @@ func NewTestSyncerManager(kubeClient kubernetes.Interface) (*syncerManager, *gce
 	nodeInformer := zonegetter.FakeNodeInformer()
 	zonegetter.PopulateFakeNodeInformer(nodeInformer, false)
 	zoneGetter := zonegetter.NewFakeZoneGetter(nodeInformer, defaultTestSubnetURL, false)
+	lpConfigStore, _ := labels.NewConfigStore(labels.PodLabelPropagationConfig{}, klog.TODO())
 	manager := newSyncerManager(
 		testContext.NegNamer,
 		record.NewFakeRecorder(100),
@@ -105,7 +108,8 @@ func NewTestSyncerManager(kubeClient kubernetes.Interface) (*syncerManager, *gce
 		false, //enableNonGcpMode
 		testContext.EnableDualStackNEG,
 		testContext.NumGCWorkers,
-		labels.PodLabelPropagationConfig{},
+		0, // maxConcurrentSyncs
+		lpConfigStore,
 		klog.TODO(),
 	)
 	return manager, testContext.Cloud
@@ -1150,6 +1154,31 @@ func TestNegCRDeletions(t *testing.T) {
 	}
 }
 
+func TestGarbageCollectionSkipsResourceNEGs(t *testing.T) {
+	t.Parallel()
+	manager, _ := NewTestSyncerManager(fake.NewSimpleClientset())
+
+	svcNeg := &negv1beta1.ServiceNetworkEndpointGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testServiceNamespace, Name: "serverless-neg"},
+		Spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+			Serverless: &negv1beta1.ServerlessNEGSpec{CloudRun: &negv1beta1.CloudRunNEGSpec{Service: "hello"}},
+		},
+	}
+	manager.svcNegLister.Add(svcNeg)
+	manager.svcNegClient.NetworkingV1beta1().ServiceNetworkEndpointGroups(testServiceNamespace).Create(context2.Background(), svcNeg, metav1.CreateOptions{})
+
+	if err := manager.GC(); err != nil {
+		t.Fatalf("Failed to GC: %v", err)
+	}
+	cr, err := manager.svcNegClient.NetworkingV1beta1().ServiceNetworkEndpointGroups(testServiceNamespace).Get(context2.Background(), svcNeg.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected resource NEG CR to be kept, got error: %v", err)
+	}
+	if !cr.GetDeletionTimestamp().IsZero() {
+		t.Errorf("Expected resource NEG CR not to be marked for deletion")
+	}
+}
+
 func TestGarbageCollectionNegCrdEnabled(t *testing.T) {
 	t.Parallel()
 
@@ -1875,3 +1904,37 @@ func rebuildSvcNegCache(t *testing.T, manager *syncerManager, svcNegClient svcne
 	manager.svcNegLister.Replace([]any{}, "")
 	populateSvcNegCache(t, manager, svcNegClient, namespace)
 }
+
+func TestReadinessGateHealthRequirementInvalidAnnotation(t *testing.T) {
+	t.Parallel()
+
+	manager, _ := NewTestSyncerManager(fake.NewSimpleClientset())
+	recorder := manager.recorder.(*record.FakeRecorder)
+	syncerKey := negtypes.NegSyncerKey{Namespace: testServiceNamespace, Name: testServiceName}
+	svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   testServiceNamespace,
+		Name:        testServiceName,
+		Annotations: map[string]string{annotations.NEGReadinessBackendServicesKey: "bs1,,bs2"},
+	}}
+	manager.serviceLister.Add(svc)
+
+	for i := 0; i < 3; i++ {
+		if got := manager.ReadinessGateHealthRequirement(syncerKey); !got.Invalid {
+			t.Errorf("ReadinessGateHealthRequirement() = %+v, want an invalid requirement", got)
+		}
+	}
+	if got := len(recorder.Events); got != 1 {
+		t.Errorf("got %d events, want 1", got)
+	}
+
+	svc = svc.DeepCopy()
+	svc.Annotations[annotations.NEGReadinessBackendServicesKey] = "bs1,bs2"
+	manager.serviceLister.Update(svc)
+	want := readiness.HealthRequirement{BackendServices: []string{"bs1", "bs2"}}
+	if got := manager.ReadinessGateHealthRequirement(syncerKey); !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadinessGateHealthRequirement() = %+v, want %+v", got, want)
+	}
+	if len(manager.invalidReadinessAnnotations) != 0 {
+		t.Errorf("invalid annotation of service %s/%s still recorded after it was fixed", testServiceNamespace, testServiceName)
+	}
+}
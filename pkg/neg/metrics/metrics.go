@@ -54,6 +54,10 @@ const (
 	DetachNERequest       = "Detach"
 	ListNERequest         = "ListNE"
 	ListNEHealthRequest   = "ListNEHealth"
+
+	// States of NEG health status polls in the readiness reflector
+	ReadinessPollWaiting = "waiting"
+	ReadinessPollRunning = "running"
 )
 
 var (
@@ -254,6 +258,43 @@ var (
 		},
 		[]string{"request", "result"},
 	)
+
+	// SyncSchedulerWaitTime tracks the time NEG syncers wait for a sync slot
+	// in the syncer manager, per NEG sync priority.
+	SyncSchedulerWaitTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: negControllerSubsystem,
+			Name:      "sync_scheduler_wait_duration_seconds",
+			Help:      "Time NEG syncers wait for a sync slot, per NEG sync priority",
+			// custom buckets - [0.001, 0.01, 0.1, 1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024, 2048, 4096, 8192, +Inf]
+			Buckets: append([]float64{0.001, 0.01, 0.1}, prometheus.ExponentialBuckets(1, 2, 14)...),
+		},
+		[]string{"priority"},
+	)
+
+	// ReadinessPollQueueDepth tracks the number of NEG health status polls
+	// of the readiness reflector that are waiting for or holding a polling slot.
+	ReadinessPollQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: negControllerSubsystem,
+			Name:      "readiness_poll_queue_depth",
+			Help:      "Number of NEG health status polls waiting for or holding a polling slot",
+		},
+		[]string{"state"},
+	)
+
+	// ReadinessTimeToReady tracks the time from pod creation until the NEG
+	// readiness condition of the pod is marked True.
+	ReadinessTimeToReady = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: negControllerSubsystem,
+			Name:      "readiness_time_to_ready_seconds",
+			Help:      "Time from pod creation until the NEG readiness condition of the pod is marked True",
+			// custom buckets - [1s, 2s, 4s, 8s, 16s, 32s, 64s, 128s, 256s(~4min), 512s(~8min), 1024s(~17min), 2048 (~34min), 4096(~68min), +Inf]
+			Buckets: prometheus.ExponentialBuckets(1, 2, 13),
+		},
+		[]string{"reason"},
+	)
 )
 
 var register sync.Once
@@ -277,6 +318,9 @@ func RegisterMetrics() {
 		prometheus.MustRegister(GCERequestLatency)
 		prometheus.MustRegister(K8sRequestCount)
 		prometheus.MustRegister(K8sRequestLatency)
+		prometheus.MustRegister(SyncSchedulerWaitTime)
+		prometheus.MustRegister(ReadinessPollQueueDepth)
+		prometheus.MustRegister(ReadinessTimeToReady)
 	})
 }
 
@@ -341,6 +385,25 @@ func PublishAnnotationMetrics(annotationSize int, labelNumber int) {
 	LabelNumber.Observe(float64(labelNumber))
 }
 
+// PublishNegSyncSchedulerWaitMetrics publishes the time a NEG syncer of the
+// given priority waited for a sync slot.
+func PublishNegSyncSchedulerWaitMetrics(priority string, wait time.Duration) {
+	SyncSchedulerWaitTime.WithLabelValues(priority).Observe(wait.Seconds())
+}
+
+// PublishReadinessPollQueueMetrics publishes the number of NEG health status
+// polls waiting for and holding a polling slot.
+func PublishReadinessPollQueueMetrics(waiting, running int) {
+	ReadinessPollQueueDepth.WithLabelValues(ReadinessPollWaiting).Set(float64(waiting))
+	ReadinessPollQueueDepth.WithLabelValues(ReadinessPollRunning).Set(float64(running))
+}
+
+// PublishReadinessTimeToReadyMetrics publishes the time it took for a pod to
+// get its NEG readiness condition marked True.
+func PublishReadinessTimeToReadyMetrics(reason string, latency time.Duration) {
+	ReadinessTimeToReady.WithLabelValues(reason).Observe(latency.Seconds())
+}
+
 // PublishGCERequestCountMetrics publishes collected metrics for GCE Request Counts
 func PublishGCERequestCountMetrics(start time.Time, requestType string, err error) {
 	var result string
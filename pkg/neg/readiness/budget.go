@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"k8s.io/ingress-gce/pkg/neg/metrics"
+)
+
+// pollBudget limits the number of concurrent health status polls across all
+// NEGs. When the budget is exhausted, the NEG with the oldest unready pod is
+// granted the next available slot.
+type pollBudget struct {
+	lock sync.Mutex
+	// limit is the maximum number of concurrent polls. Non-positive means unlimited.
+	limit int
+	// inUse is the number of polls currently holding a slot.
+	inUse int
+	// waiters are the polls waiting for a slot, ordered by priority.
+	waiters budgetWaiters
+	// seq is used to keep the ordering of waiters with the same priority stable.
+	seq int64
+}
+
+func newPollBudget(limit int) *pollBudget {
+	return &pollBudget{limit: limit}
+}
+
+// acquire blocks until a polling slot is available.
+// oldestUnreadyPod is the creation time of the oldest unready pod in the NEG,
+// or the zero time if none is known. Polls with older unready pods are granted
+// a slot first.
+func (b *pollBudget) acquire(oldestUnreadyPod time.Time) {
+	b.lock.Lock()
+	if b.limit <= 0 || (b.inUse < b.limit && len(b.waiters) == 0) {
+		b.inUse++
+		b.publishMetrics()
+		b.lock.Unlock()
+		return
+	}
+	w := &budgetWaiter{priority: oldestUnreadyPod, seq: b.seq, ready: make(chan struct{})}
+	b.seq++
+	heap.Push(&b.waiters, w)
+	b.publishMetrics()
+	b.lock.Unlock()
+	<-w.ready
+}
+
+// release returns a polling slot. The slot is handed over to the waiter with
+// the highest priority if there is any.
+func (b *pollBudget) release() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if len(b.waiters) > 0 {
+		w := heap.Pop(&b.waiters).(*budgetWaiter)
+		close(w.ready)
+	} else {
+		b.inUse--
+	}
+	b.publishMetrics()
+}
+
+// publishMetrics publishes the queue depth of the budget.
+// Assumes b.lock is held when calling this method.
+func (b *pollBudget) publishMetrics() {
+	metrics.PublishReadinessPollQueueMetrics(len(b.waiters), b.inUse)
+}
+
+// budgetWaiter is a poll waiting for a slot in pollBudget.
+type budgetWaiter struct {
+	priority time.Time
+	seq      int64
+	ready    chan struct{}
+}
+
+// budgetWaiters implements heap.Interface and orders the waiters by the
+// creation time of their oldest unready pod. Waiters with the zero time, whose
+// pods are not known, come last.
+type budgetWaiters []*budgetWaiter
+
+func (w budgetWaiters) Len() int { return len(w) }
+
+func (w budgetWaiters) Less(i, j int) bool {
+	pi, pj := w[i].priority, w[j].priority
+	switch {
+	case pi.Equal(pj):
+		return w[i].seq < w[j].seq
+	case pi.IsZero():
+		return false
+	case pj.IsZero():
+		return true
+	}
+	return pi.Before(pj)
+}
+
+func (w budgetWaiters) Swap(i, j int) { w[i], w[j] = w[j], w[i] }
+
+func (w *budgetWaiters) Push(x interface{}) { *w = append(*w, x.(*budgetWaiter)) }
+
+func (w *budgetWaiters) Pop() interface{} {
+	old := *w
+	n := len(old)
+	ret := old[n-1]
+	old[n-1] = nil
+	*w = old[:n-1]
+	return ret
+}
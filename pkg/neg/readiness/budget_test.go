@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPollBudget(t *testing.T) {
+	t.Parallel()
+
+	budget := newPollBudget(1)
+	now := time.Now()
+	// Hold the only slot.
+	budget.acquire(now)
+
+	// The zero time is used for NEGs whose pods are not found.
+	priorities := []time.Time{now.Add(-time.Minute), {}, now.Add(-10 * time.Minute), now.Add(-5 * time.Minute)}
+	granted := make(chan int, len(priorities))
+	for i, priority := range priorities {
+		i, priority := i, priority
+		go func() {
+			budget.acquire(priority)
+			granted <- i
+			budget.release()
+		}()
+		// Wait until the poll is queued so that the waiters are known
+		// before the slot is released.
+		for {
+			budget.lock.Lock()
+			queued := len(budget.waiters)
+			budget.lock.Unlock()
+			if queued == i+1 {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	budget.release()
+	var got []int
+	for range priorities {
+		got = append(got, <-granted)
+	}
+	// Polls with older unready pods are granted the slot first, polls without
+	// known pods last.
+	if diff := cmp.Diff([]int{2, 3, 0, 1}, got); diff != "" {
+		t.Errorf("Got unexpected order of granted polls (-want +got):\n%s", diff)
+	}
+	if budget.inUse != 0 {
+		t.Errorf("Got %d slots in use after all polls finished, want 0", budget.inUse)
+	}
+}
+
+func TestPollBudgetUnlimited(t *testing.T) {
+	t.Parallel()
+
+	budget := newPollBudget(0)
+	for i := 0; i < 100; i++ {
+		budget.acquire(time.Time{})
+	}
+	if len(budget.waiters) != 0 {
+		t.Errorf("Got %d waiters for unlimited budget, want 0", len(budget.waiters))
+	}
+	for i := 0; i < 100; i++ {
+		budget.release()
+	}
+	if budget.inUse != 0 {
+		t.Errorf("Got %d slots in use after all polls finished, want 0", budget.inUse)
+	}
+}
@@ -42,6 +42,29 @@ type NegLookup interface {
 	ReadinessGateEnabledNegs(namespace string, labels map[string]string) []string
 	// ReadinessGateEnabled returns true if the NEG requires readiness feedback
 	ReadinessGateEnabled(syncerKey negtypes.NegSyncerKey) bool
+	// ReadinessGateHealthRequirement returns the backend services which must report
+	// the endpoints of the NEG as healthy before their pods are marked ready.
+	ReadinessGateHealthRequirement(syncerKey negtypes.NegSyncerKey) HealthRequirement
+}
+
+// HealthRequirement specifies which backend services must report an endpoint as
+// healthy for its pod to pass the NEG readiness gate. The zero value requires
+// the endpoint to be healthy in any backend service.
+type HealthRequirement struct {
+	// All requires the endpoint to be healthy in all backend services the NEG
+	// is attached to.
+	All bool
+	// BackendServices are the names of the backend services which must all
+	// report the endpoint as healthy. It is ignored if All is set.
+	BackendServices []string
+	// Invalid indicates that the requirement is misconfigured. No endpoint
+	// satisfies it, so that pods keep waiting until it is fixed.
+	Invalid bool
+}
+
+// IsDefault returns true if health reported by any backend service is sufficient.
+func (r HealthRequirement) IsDefault() bool {
+	return !r.Invalid && !r.All && len(r.BackendServices) == 0
 }
 
 type NoopReflector struct{}
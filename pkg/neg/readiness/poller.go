@@ -27,6 +27,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/ingress-gce/pkg/backoff"
 	"k8s.io/ingress-gce/pkg/composite"
 	"k8s.io/ingress-gce/pkg/neg/metrics"
 	negtypes "k8s.io/ingress-gce/pkg/neg/types"
@@ -36,6 +38,12 @@ import (
 
 const (
 	healthyState = "HEALTHY"
+	// unknownState is reported for a backend service which does not report a
+	// health state for the endpoint.
+	unknownState = "UNKNOWN"
+	// notAttachedState is reported for a required backend service which the
+	// NEG is not attached to.
+	notAttachedState = "NOT_ATTACHED"
 
 	// retryDelay is the delay to retry health status polling.
 	// GCE NEG API RPS quota is rate limited per every 100 seconds.
@@ -43,8 +51,23 @@ const (
 	// More detail: https://cloud.google.com/compute/docs/api-rate-limits
 	retryDelay   = 100 * time.Second
 	hcRetryDelay = time.Second
+	// maxRetryDelay is the maximum delay to retry health status polling of a
+	// NEG after consecutive GCE API errors.
+	maxRetryDelay = 10 * time.Minute
 )
 
+// PollerConfig configures how the NEG health statuses are polled.
+type PollerConfig struct {
+	// MaxConcurrentPolls is the maximum number of NEGs polled concurrently.
+	// Non-positive means unlimited.
+	MaxConcurrentPolls int
+	// QPS is the rate of ListNetworkEndpoints calls shared by all NEGs.
+	// Non-positive means unlimited.
+	QPS float32
+	// Burst is the burst of ListNetworkEndpoints calls shared by all NEGs.
+	Burst int
+}
+
 // negMeta references a GCE NEG resource
 type negMeta struct {
 	SyncerKey negtypes.NegSyncerKey
@@ -64,7 +87,22 @@ type podStatusPatcher interface {
 	// podKey is the key to the pod. It is the namespaced name in the format of "namespace/name"
 	// neg is the key of the NEG resource
 	// backendService is the key of the BackendService resource.
-	syncPod(podKey string, neg, backendService *meta.Key) error
+	// healths is the health of the endpoint in each required BackendService,
+	// which is only set if the NEG requires health from specific BackendServices.
+	// If backendService is nil, the endpoint is not healthy in all of them yet.
+	syncPod(podKey string, neg, backendService *meta.Key, healths []backendServiceHealth) error
+}
+
+// backendServiceHealth is the health state of an endpoint reported by a backend service.
+type backendServiceHealth struct {
+	// backendService is the name of the backend service
+	backendService string
+	// state is the health state of the endpoint in the backend service
+	state string
+}
+
+func (h backendServiceHealth) String() string {
+	return fmt.Sprintf("%s: %s", h.backendService, h.state)
 }
 
 // pollTarget is the target for polling
@@ -73,6 +111,11 @@ type pollTarget struct {
 	endpointMap negtypes.EndpointPodMap
 	// polling indicates if the NEG is being polled
 	polling bool
+	// oldestUnreadyPod is the creation time of the oldest pod in endpointMap.
+	// NEGs with older unready pods are polled first.
+	oldestUnreadyPod time.Time
+	// backoff handles the retry delay after GCE API errors.
+	backoff backoff.BackoffHandler
 }
 
 // poller tracks the negs and corresponding targets needed to be polled.
@@ -87,6 +130,11 @@ type poller struct {
 	patcher   podStatusPatcher
 	negCloud  negtypes.NetworkEndpointGroupCloud
 
+	// budget limits the number of NEGs polled concurrently.
+	budget *pollBudget
+	// rateLimiter limits the rate of health status polls across all NEGs.
+	rateLimiter flowcontrol.RateLimiter
+
 	// Enables support for Dual-Stack NEGs within the NEG Controller.
 	enableDualStackNEG bool
 
@@ -95,13 +143,19 @@ type poller struct {
 	logger klog.Logger
 }
 
-func NewPoller(podLister cache.Indexer, lookup NegLookup, patcher podStatusPatcher, negCloud negtypes.NetworkEndpointGroupCloud, enableDualStackNEG bool, logger klog.Logger) *poller {
+func NewPoller(podLister cache.Indexer, lookup NegLookup, patcher podStatusPatcher, negCloud negtypes.NetworkEndpointGroupCloud, config PollerConfig, enableDualStackNEG bool, logger klog.Logger) *poller {
+	rateLimiter := flowcontrol.NewFakeAlwaysRateLimiter()
+	if config.QPS > 0 {
+		rateLimiter = flowcontrol.NewTokenBucketRateLimiter(config.QPS, config.Burst)
+	}
 	return &poller{
 		pollMap:            make(map[negMeta]*pollTarget),
 		podLister:          podLister,
 		lookup:             lookup,
 		patcher:            patcher,
 		negCloud:           negCloud,
+		budget:             newPollBudget(config.MaxConcurrentPolls),
+		rateLimiter:        rateLimiter,
 		enableDualStackNEG: enableDualStackNEG,
 		clock:              clock.RealClock{},
 		logger:             logger.WithName("Poller"),
@@ -125,15 +179,22 @@ func (p *poller) registerNegEndpoints(key negMeta, endpointMap negtypes.Endpoint
 		return false
 	}
 
+	oldestUnreadyPod := oldestPodCreationTime(endpointsToPoll, p.podLister)
 	if v, ok := p.pollMap[key]; ok {
 		v.endpointMap = endpointsToPoll
+		v.oldestUnreadyPod = oldestUnreadyPod
 	} else {
-		p.pollMap[key] = &pollTarget{endpointMap: endpointsToPoll}
+		p.pollMap[key] = &pollTarget{
+			endpointMap:      endpointsToPoll,
+			oldestUnreadyPod: oldestUnreadyPod,
+		}
 	}
 	return true
 }
 
-// ScanForWork returns the list of NEGs that should be polled
+// ScanForWork returns the list of NEGs that should be polled.
+// NEGs are polled concurrently, the poll budget decides which NEG is polled
+// first if the number of concurrent polls is limited.
 func (p *poller) ScanForWork() []negMeta {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -158,24 +219,26 @@ func (p *poller) Poll(key negMeta) (retry bool, err error) {
 	}
 	defer p.unMarkPolling(key)
 
+	oldestUnreadyPod, backoffHandler := p.pollState(key)
 	p.logger.V(2).Info("polling NEG", "neg", key.Name, "negZone", key.Zone)
-	// TODO(freehan): filter the NEs that are in interest once the API supports it
-	res, err := p.negCloud.ListNetworkEndpoints(key.Name, key.Zone /*showHealthStatus*/, true, key.SyncerKey.GetAPIVersion(), p.logger)
+	res, err := p.listNetworkEndpoints(key, oldestUnreadyPod)
 	if err != nil {
 		if negtypes.IsStrategyQuotaError(err) {
 			p.logger.V(4).Error(err, "Failed to ListNetworkEndpoints in NEG", "neg", key.String())
 		} else {
 			// On receiving GCE API error, do not retry immediately. This is to prevent the reflector to overwhelm the GCE NEG API when
 			// rate limiting is in effect. This will prevent readiness reflector to overwhelm the GCE NEG API and cause NEG syncers to backoff.
-			// This will effectively batch NEG health status updates for 100s. The pods added into NEG in this 100s will not be marked ready
+			// The retry delay starts at retryDelay to batch NEG health status updates for the GCE API rate limiting interval, and
+			// grows exponentially on consecutive errors up to maxRetryDelay. The pods added into NEG during this delay will not be marked ready
 			// until the next status poll is executed. However, the pods are not marked as Ready and still passes the LB health check will
 			// serve LB traffic. The side effect during the delay period is the workload (depending on rollout strategy) might slow down rollout.
-			// TODO(freehan): enable exponential backoff.
-			p.logger.Error(err, "Failed to ListNetworkEndpoints in NEG. Retrying after some time.", "neg", key.String(), "retryDelay", retryDelay.String())
-			<-p.clock.After(retryDelay)
+			delay, _ := backoffHandler.NextDelay()
+			p.logger.Error(err, "Failed to ListNetworkEndpoints in NEG. Retrying after some time.", "neg", key.String(), "retryDelay", delay.String())
+			<-p.clock.After(delay)
 		}
 		return true, err
 	}
+	backoffHandler.ResetDelay()
 
 	retry, err = p.processHealthStatus(key, res)
 	metrics.PublishNegControllerErrorCountMetrics(err, true)
@@ -189,9 +252,12 @@ func (p *poller) Poll(key negMeta) (retry bool, err error) {
 // updates the [readiness gates] of the pods.
 //
 // We update the pod (using the patcher) in ANY of the following cases:
-//  1. If the endpoint is considered healthy by ANY GCE Backend Service.
+//  1. If the endpoint is considered healthy by the GCE Backend Services
+//     required by the NEG's HealthRequirement. By default, ANY GCE Backend
+//     Service is sufficient.
 //  2. If the endpoint belongs to a NEG which is not associated with any GCE
-//     Backend Service.
+//     Backend Service, unless the HealthRequirement names specific Backend
+//     Services.
 //
 // True is returned if retry is needed.
 //
@@ -212,6 +278,7 @@ func (p *poller) processHealthStatus(key negMeta, healthStatuses []*composite.Ne
 		// patchCount is the count of the pod got patched
 		patchCount    int
 		unhealthyPods []types.NamespacedName
+		requirement   = p.lookup.ReadinessGateHealthRequirement(key.SyncerKey)
 	)
 
 	for _, healthStatus := range healthStatuses {
@@ -242,13 +309,20 @@ func (p *poller) processHealthStatus(key negMeta, healthStatuses []*composite.Ne
 			continue
 		}
 
-		bsKey := getHealthyBackendService(healthStatus, p.enableDualStackNEG, p.logger)
+		bsKey, healths := evaluateHealthRequirement(healthStatus, requirement, p.enableDualStackNEG, p.logger)
 		if bsKey == nil {
 			unhealthyPods = append(unhealthyPods, podName)
+			if len(healths) > 0 {
+				// Report the health of the endpoint in each required backend
+				// service, so that the condition shows which one is unhealthy.
+				if err := p.patcher.syncPod(keyFunc(podName.Namespace, podName.Name), meta.ZonalKey(key.Name, key.Zone), nil, healths); err != nil {
+					errList = append(errList, err)
+				}
+			}
 			continue
 		}
 
-		err := p.patcher.syncPod(keyFunc(podName.Namespace, podName.Name), meta.ZonalKey(key.Name, key.Zone), bsKey)
+		err := p.patcher.syncPod(keyFunc(podName.Namespace, podName.Name), meta.ZonalKey(key.Name, key.Zone), bsKey, healths)
 		if err != nil {
 			errList = append(errList, err)
 			continue
@@ -258,10 +332,11 @@ func (p *poller) processHealthStatus(key negMeta, healthStatuses []*composite.Ne
 
 	// if the NEG is not health checked, signal the patcher to mark the unhealthy pods to be Ready.
 	// This is most likely due to health check is not configured for the NEG. Hence none of the endpoints
-	// in the NEG has health status.
-	if !healthChecked {
+	// in the NEG has health status. If specific backend services are required,
+	// keep waiting for the NEG to be attached to them.
+	if !healthChecked && !requirement.Invalid && (requirement.All || len(requirement.BackendServices) == 0) {
 		for _, podName := range unhealthyPods {
-			err := p.patcher.syncPod(keyFunc(podName.Namespace, podName.Name), meta.ZonalKey(key.Name, key.Zone), nil)
+			err := p.patcher.syncPod(keyFunc(podName.Namespace, podName.Name), meta.ZonalKey(key.Name, key.Zone), nil, nil)
 			if err != nil {
 				errList = append(errList, err)
 				continue
@@ -310,6 +385,75 @@ func getHealthyBackendService(healthStatus *composite.NetworkEndpointWithHealthS
 	return nil
 }
 
+// evaluateHealthRequirement returns the key of a backend service where the
+// endpoint is considered healthy if the endpoint satisfies the health
+// requirement, or nil otherwise. Unless the requirement is the default one, it
+// also returns the health of the endpoint in each required backend service.
+func evaluateHealthRequirement(healthStatus *composite.NetworkEndpointWithHealthStatus, requirement HealthRequirement, enableDualStackNEG bool, logger klog.Logger) (*meta.Key, []backendServiceHealth) {
+	if requirement.IsDefault() {
+		return getHealthyBackendService(healthStatus, enableDualStackNEG, logger), nil
+	}
+	if requirement.Invalid {
+		return nil, nil
+	}
+
+	var (
+		bsKeys  = map[string]*meta.Key{}
+		healths = map[string]backendServiceHealth{}
+		names   []string
+	)
+	for _, hs := range healthStatus.Healths {
+		if hs == nil || hs.BackendService == nil {
+			continue
+		}
+		id, err := cloud.ParseResourceURL(hs.BackendService.BackendService)
+		if err != nil || id == nil {
+			logger.Error(err, "Failed to parse backend service reference from a Network Endpoint health status", "healthStatus", healthStatus)
+			metrics.PublishNegControllerErrorCountMetrics(err, true)
+			continue
+		}
+		state := hs.HealthState
+		if enableDualStackNEG && hs.Ipv6HealthState == healthyState {
+			state = healthyState
+		}
+		if state == "" {
+			state = unknownState
+		}
+		name := id.Key.Name
+		if _, ok := healths[name]; !ok {
+			names = append(names, name)
+		}
+		// An endpoint may be reported more than once by a backend service, e.g.
+		// for IPv4 and IPv6. Prefer the healthy report.
+		if prev, ok := healths[name]; !ok || prev.state != healthyState {
+			healths[name] = backendServiceHealth{backendService: name, state: state}
+			bsKeys[name] = id.Key
+		}
+	}
+
+	if !requirement.All {
+		names = requirement.BackendServices
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var ret []backendServiceHealth
+	ready := true
+	for _, name := range names {
+		health, ok := healths[name]
+		if !ok {
+			health = backendServiceHealth{backendService: name, state: notAttachedState}
+		}
+		ready = ready && health.state == healthyState
+		ret = append(ret, health)
+	}
+	if !ready {
+		return nil, ret
+	}
+	return bsKeys[names[0]], ret
+}
+
 // hasSupportedHealthStatus returns true if there is at least 1 backendService health status associated with the endpoint.
 func hasSupportedHealthStatus(healthStatus *composite.NetworkEndpointWithHealthStatus) bool {
 	if healthStatus == nil {
@@ -336,6 +480,37 @@ func (p *poller) getPod(key negMeta, endpoint negtypes.NetworkEndpoint) (namespa
 	return ret, ok
 }
 
+// listNetworkEndpoints lists the network endpoints of the NEG with their
+// health status, within the polling budget and rate shared by all NEGs.
+func (p *poller) listNetworkEndpoints(key negMeta, oldestUnreadyPod time.Time) ([]*composite.NetworkEndpointWithHealthStatus, error) {
+	p.budget.acquire(oldestUnreadyPod)
+	defer p.budget.release()
+	p.rateLimiter.Accept()
+	// TODO(freehan): filter the NEs that are in interest once the API supports it
+	return p.negCloud.ListNetworkEndpoints(key.Name, key.Zone /*showHealthStatus*/, true, key.SyncerKey.GetAPIVersion(), p.logger)
+}
+
+// pollState returns the creation time of the oldest unready pod in the NEG
+// and the backoff handler of the NEG.
+func (p *poller) pollState(key negMeta) (time.Time, backoff.BackoffHandler) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	t, ok := p.pollMap[key]
+	if !ok {
+		return time.Time{}, newPollBackoffHandler()
+	}
+	if t.backoff == nil {
+		t.backoff = newPollBackoffHandler()
+	}
+	return t.oldestUnreadyPod, t.backoff
+}
+
+// newPollBackoffHandler returns the backoff handler for the health status
+// polling of a NEG. It never runs out of retries.
+func newPollBackoffHandler() backoff.BackoffHandler {
+	return backoff.NewExponentialBackoffHandler(0, retryDelay, maxRetryDelay)
+}
+
 // markPolling returns true if the NEG is successfully marked as polling
 func (p *poller) markPolling(key negMeta) bool {
 	p.lock.Lock()
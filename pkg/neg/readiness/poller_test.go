@@ -29,6 +29,7 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/api/compute/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cloud-provider-gcp/providers/gce"
 	"k8s.io/ingress-gce/pkg/composite"
@@ -39,17 +40,19 @@ import (
 )
 
 type testPatcher struct {
-	count      int
-	lastPod    string
-	lastNegKey *meta.Key
-	lastBsKey  *meta.Key
+	count       int
+	lastPod     string
+	lastNegKey  *meta.Key
+	lastBsKey   *meta.Key
+	lastHealths []backendServiceHealth
 }
 
-func (p *testPatcher) syncPod(pod string, negKey, bsKey *meta.Key) error {
+func (p *testPatcher) syncPod(pod string, negKey, bsKey *meta.Key, healths []backendServiceHealth) error {
 	p.count++
 	p.lastPod = pod
 	p.lastNegKey = negKey
 	p.lastBsKey = bsKey
+	p.lastHealths = healths
 	return nil
 }
 
@@ -392,7 +395,8 @@ func TestPoll(t *testing.T) {
 		if stepClock {
 			go func() {
 				time.Sleep(2 * time.Second)
-				delay := retryDelay
+				// Retry delay grows exponentially on consecutive errors, up to maxRetryDelay.
+				delay := maxRetryDelay
 				if healthStatusDelay {
 					delay = hcRetryDelay
 				}
@@ -715,3 +719,179 @@ func TestProcessHealthStatus_dualStackNEGs(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessHealthStatus_healthRequirement(t *testing.T) {
+	namespace := "ns1"
+	podName := "podName1"
+	ip := "10.0.0.1"
+	backendServiceURL := func(name string) string {
+		return fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/foo/global/backendServices/%v", name)
+	}
+	health := func(bs, state string) *composite.HealthStatusForNetworkEndpoint {
+		return &composite.HealthStatusForNetworkEndpoint{
+			BackendService: &composite.BackendServiceReference{BackendService: backendServiceURL(bs)},
+			HealthState:    state,
+		}
+	}
+
+	testCases := []struct {
+		desc              string
+		requirement       HealthRequirement
+		healths           []*composite.HealthStatusForNetworkEndpoint
+		expectUpdate      bool
+		expectBackendSvc  string
+		expectHealthsDesc string
+	}{
+		{
+			desc:             "default requirement, any healthy backend service is sufficient",
+			healths:          []*composite.HealthStatusForNetworkEndpoint{health("bs1", "UNHEALTHY"), health("bs2", healthyState)},
+			expectUpdate:     true,
+			expectBackendSvc: "bs2",
+		},
+		{
+			desc:              "all backend services required, one unhealthy",
+			requirement:       HealthRequirement{All: true},
+			healths:           []*composite.HealthStatusForNetworkEndpoint{health("bs1", "UNHEALTHY"), health("bs2", healthyState)},
+			expectUpdate:      true,
+			expectHealthsDesc: "bs1: UNHEALTHY, bs2: HEALTHY",
+		},
+		{
+			desc:              "all backend services required, all healthy",
+			requirement:       HealthRequirement{All: true},
+			healths:           []*composite.HealthStatusForNetworkEndpoint{health("bs1", healthyState), health("bs2", healthyState)},
+			expectUpdate:      true,
+			expectBackendSvc:  "bs1",
+			expectHealthsDesc: "bs1: HEALTHY, bs2: HEALTHY",
+		},
+		{
+			desc:              "named backend services required, ignored backend service unhealthy",
+			requirement:       HealthRequirement{BackendServices: []string{"bs2"}},
+			healths:           []*composite.HealthStatusForNetworkEndpoint{health("bs1", "UNHEALTHY"), health("bs2", healthyState)},
+			expectUpdate:      true,
+			expectBackendSvc:  "bs2",
+			expectHealthsDesc: "bs2: HEALTHY",
+		},
+		{
+			desc:              "named backend service not attached",
+			requirement:       HealthRequirement{BackendServices: []string{"bs2", "bs3"}},
+			healths:           []*composite.HealthStatusForNetworkEndpoint{health("bs2", healthyState)},
+			expectUpdate:      true,
+			expectHealthsDesc: "bs2: HEALTHY, bs3: NOT_ATTACHED",
+		},
+		{
+			desc:              "named backend service required, NEG not health checked",
+			requirement:       HealthRequirement{BackendServices: []string{"bs1"}},
+			healths:           []*composite.HealthStatusForNetworkEndpoint{},
+			expectUpdate:      true,
+			expectHealthsDesc: "bs1: NOT_ATTACHED",
+		},
+		{
+			desc:         "invalid requirement, endpoint healthy",
+			requirement:  HealthRequirement{Invalid: true},
+			healths:      []*composite.HealthStatusForNetworkEndpoint{health("bs1", healthyState)},
+			expectUpdate: false,
+		},
+		{
+			desc:         "invalid requirement, NEG not health checked",
+			requirement:  HealthRequirement{Invalid: true},
+			healths:      []*composite.HealthStatusForNetworkEndpoint{},
+			expectUpdate: false,
+		},
+		{
+			desc:         "all backend services required, NEG not health checked",
+			requirement:  HealthRequirement{All: true},
+			healths:      []*composite.HealthStatusForNetworkEndpoint{},
+			expectUpdate: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			neg := negMeta{SyncerKey: negtypes.NegSyncerKey{}, Name: "negName", Zone: "zone1"}
+			poller := newFakePoller()
+			poller.lookup = &fakeLookUp{healthRequirement: tc.requirement}
+			poller.pollMap[neg] = &pollTarget{
+				endpointMap: negtypes.EndpointPodMap{
+					negtypes.NetworkEndpoint{IP: ip, Port: "0"}: {Namespace: namespace, Name: podName},
+				},
+				polling: true,
+			}
+
+			poller.processHealthStatus(neg, []*composite.NetworkEndpointWithHealthStatus{{
+				NetworkEndpoint: &composite.NetworkEndpoint{IpAddress: ip},
+				Healths:         tc.healths,
+			}})
+
+			patcher := poller.patcher.(*testPatcher)
+			if gotUpdate := patcher.count > 0; gotUpdate != tc.expectUpdate {
+				t.Fatalf("readiness gate updated = %v, want %v", gotUpdate, tc.expectUpdate)
+			}
+			if !tc.expectUpdate {
+				return
+			}
+			var bsKey *meta.Key
+			if tc.expectBackendSvc != "" {
+				bsKey = meta.GlobalKey(tc.expectBackendSvc)
+			}
+			patcher.Eval(t, keyFunc(namespace, podName), meta.ZonalKey(neg.Name, neg.Zone), bsKey)
+			if got := formatBackendServiceHealths(patcher.lastHealths); got != tc.expectHealthsDesc {
+				t.Errorf("got healths %q, want %q", got, tc.expectHealthsDesc)
+			}
+		})
+	}
+}
+
+func TestEvaluateHealthRequirement(t *testing.T) {
+	healthStatus := &composite.NetworkEndpointWithHealthStatus{
+		NetworkEndpoint: &composite.NetworkEndpoint{IpAddress: "10.0.0.1"},
+		Healths: []*composite.HealthStatusForNetworkEndpoint{
+			{
+				BackendService: &composite.BackendServiceReference{BackendService: "https://www.googleapis.com/compute/v1/projects/foo/global/backendServices/bs1"},
+				HealthState:    "UNHEALTHY",
+			},
+			{
+				BackendService: &composite.BackendServiceReference{BackendService: "https://www.googleapis.com/compute/v1/projects/foo/regions/us-central1/backendServices/bs2"},
+			},
+		},
+	}
+
+	bsKey, healths := evaluateHealthRequirement(healthStatus, HealthRequirement{All: true}, false, klog.TODO())
+	if bsKey != nil {
+		t.Errorf("got backend service %v, want nil", bsKey)
+	}
+	if got, want := formatBackendServiceHealths(healths), "bs1: UNHEALTHY, bs2: UNKNOWN"; got != want {
+		t.Errorf("got healths %q, want %q", got, want)
+	}
+}
+
+func TestScanForWorkRecordsOldestUnreadyPod(t *testing.T) {
+	t.Parallel()
+
+	poller := newFakePoller()
+	// registerNegEndpoints drops NEGs without endpoints to poll, so fake the
+	// readiness gate lookup to keep all endpoints.
+	poller.lookup = &fakeLookUp{readinessGateEnabled: true}
+	created := time.Now().Add(-5 * time.Minute).Truncate(time.Second)
+	for i, age := range []time.Duration{time.Minute, 5 * time.Minute} {
+		pod := generatePod(testServiceNamespace, fmt.Sprintf("pod%d", i), true, false, false)
+		pod.CreationTimestamp = metav1.NewTime(created.Add(5*time.Minute - age))
+		poller.podLister.Add(pod)
+	}
+	found := negMeta{SyncerKey: negtypes.NegSyncerKey{}, Name: "neg0", Zone: "zone1"}
+	poller.pollMap[found] = &pollTarget{endpointMap: negtypes.EndpointPodMap{
+		negtypes.NetworkEndpoint{IP: "10.0.0.0", Port: "80"}: {Namespace: testServiceNamespace, Name: "pod0"},
+		negtypes.NetworkEndpoint{IP: "10.0.0.1", Port: "80"}: {Namespace: testServiceNamespace, Name: "pod1"},
+	}}
+	if diff := cmp.Diff([]negMeta{found}, poller.ScanForWork()); diff != "" {
+		t.Errorf("ScanForWork() returned unexpected NEGs (-want +got):\n%s", diff)
+	}
+	if got := poller.pollMap[found].oldestUnreadyPod; !got.Equal(created) {
+		t.Errorf("Got oldest unready pod created at %v, want %v", got, created)
+	}
+	missing := negtypes.EndpointPodMap{
+		negtypes.NetworkEndpoint{IP: "10.0.0.2", Port: "80"}: {Namespace: testServiceNamespace, Name: "missing"},
+	}
+	if got := oldestPodCreationTime(missing, poller.podLister); !got.IsZero() {
+		t.Errorf("Got oldest unready pod created at %v for missing pods, want zero time", got)
+	}
+}
@@ -83,7 +83,7 @@ type readinessReflector struct {
 	logger klog.Logger
 }
 
-func NewReadinessReflector(kubeClient, eventRecorderClient kubernetes.Interface, podLister cache.Indexer, negCloud negtypes.NetworkEndpointGroupCloud, lookup NegLookup, zoneGetter *zonegetter.ZoneGetter, enableDualStackNEG, enableMultiSubnetCluster bool, logger klog.Logger) Reflector {
+func NewReadinessReflector(kubeClient, eventRecorderClient kubernetes.Interface, podLister cache.Indexer, negCloud negtypes.NetworkEndpointGroupCloud, lookup NegLookup, zoneGetter *zonegetter.ZoneGetter, pollerConfig PollerConfig, enableDualStackNEG, enableMultiSubnetCluster bool, logger klog.Logger) Reflector {
 	broadcaster := record.NewBroadcaster()
 	broadcaster.StartLogging(klog.Infof)
 	broadcaster.StartRecordingToSink(&unversionedcore.EventSinkImpl{
@@ -102,7 +102,7 @@ func NewReadinessReflector(kubeClient, eventRecorderClient kubernetes.Interface,
 		enableMultiSubnetCluster: enableMultiSubnetCluster,
 		logger:                   logger,
 	}
-	poller := NewPoller(podLister, lookup, reflector, negCloud, enableDualStackNEG, logger)
+	poller := NewPoller(podLister, lookup, reflector, negCloud, pollerConfig, enableDualStackNEG, logger)
 	reflector.poller = poller
 	return reflector
 }
@@ -128,7 +128,7 @@ func (r *readinessReflector) processNextWorkItem() bool {
 	}
 	defer r.queue.Done(key)
 
-	err := r.syncPod(key.(string), nil, nil)
+	err := r.syncPod(key.(string), nil, nil, nil)
 	r.handleErr(err, key)
 	return true
 }
@@ -152,7 +152,8 @@ func (r *readinessReflector) handleErr(err error, key interface{}) {
 
 // syncPod process pod and patch the NEG readiness condition if needed
 // if neg and backendService is specified, it means pod is Healthy in the NEG attached to backendService.
-func (r *readinessReflector) syncPod(podKey string, neg, backendService *meta.Key) (err error) {
+// healths is the health of the pod in each BackendService required by the NEG, if any.
+func (r *readinessReflector) syncPod(podKey string, neg, backendService *meta.Key, healths []backendServiceHealth) (err error) {
 	// podUpdateLock to ensure there is no race in pod status update
 	r.podUpdateLock.Lock()
 	defer r.podUpdateLock.Unlock()
@@ -177,20 +178,36 @@ func (r *readinessReflector) syncPod(podKey string, neg, backendService *meta.Ke
 	}
 
 	r.logger.V(3).Info("Syncing pod", "pod", podKey, "neg", neg, "backendService", backendService)
-	expectedCondition := r.getExpectedNegCondition(pod, neg, backendService)
+	expectedCondition := r.getExpectedNegCondition(pod, neg, backendService, healths)
 	return r.ensurePodNegCondition(pod, expectedCondition)
 }
 
 // getExpectedCondition returns the expected NEG readiness condition for the given pod
-func (r *readinessReflector) getExpectedNegCondition(pod *v1.Pod, neg, backendService *meta.Key) v1.PodCondition {
+func (r *readinessReflector) getExpectedNegCondition(pod *v1.Pod, neg, backendService *meta.Key, healths []backendServiceHealth) v1.PodCondition {
 	expectedCondition := v1.PodCondition{Type: shared.NegReadinessGate}
 	if pod == nil {
 		expectedCondition.Message = "Unknown status for unknown pod."
 		return expectedCondition
 	}
 
-	if neg != nil {
-		if backendService != nil {
+	timedOut := r.clock.Now().After(pod.CreationTimestamp.Add(unreadyTimeout))
+	if neg != nil && backendService == nil && len(healths) > 0 {
+		// The pod is not healthy in all required BackendServices yet. If the pod
+		// is in other NEGs, their pollers may mark it ready concurrently, so the
+		// condition is only set to False if this is its only NEG. The timeout and
+		// the other cases are handled below.
+		if !timedOut && len(r.lookup.ReadinessGateEnabledNegs(pod.Namespace, pod.Labels)) <= 1 {
+			expectedCondition.Status = v1.ConditionFalse
+			expectedCondition.Reason = negNotReadyReason
+			expectedCondition.Message = fmt.Sprintf("Waiting for pod to become healthy in NEG %q in all required BackendServices (%s)", neg.String(), formatBackendServiceHealths(healths))
+			return expectedCondition
+		}
+	} else if neg != nil {
+		if backendService != nil && len(healths) > 0 {
+			expectedCondition.Status = v1.ConditionTrue
+			expectedCondition.Reason = negReadyReason
+			expectedCondition.Message = fmt.Sprintf("Pod has become Healthy in NEG %q in all required BackendServices (%s). Marking condition %q to True.", neg.String(), formatBackendServiceHealths(healths), shared.NegReadinessGate)
+		} else if backendService != nil {
 			expectedCondition.Status = v1.ConditionTrue
 			expectedCondition.Reason = negReadyReason
 			expectedCondition.Message = fmt.Sprintf("Pod has become Healthy in NEG %q attached to BackendService %q. Marking condition %q to True.", neg.String(), backendService.String(), shared.NegReadinessGate)
@@ -212,7 +229,7 @@ func (r *readinessReflector) getExpectedNegCondition(pod *v1.Pod, neg, backendSe
 	}
 
 	// check if the pod has been waiting for the endpoint to show up as Healthy in NEG for too long
-	if r.clock.Now().After(pod.CreationTimestamp.Add(unreadyTimeout)) {
+	if timedOut {
 		r.logger.Info(pod.Name, "now", r.clock.Now(), "pod time", pod.CreationTimestamp)
 		expectedCondition.Status = v1.ConditionTrue
 		expectedCondition.Reason = negReadyTimedOutReason
@@ -321,5 +338,8 @@ func (r *readinessReflector) ensurePodNegCondition(pod *v1.Pod, expectedConditio
 	}
 	r.eventRecorder.Eventf(pod, v1.EventTypeNormal, expectedCondition.Reason, expectedCondition.Message)
 	_, _, err = patchPodStatus(r.client, pod.Namespace, pod.Name, patchBytes)
+	if err == nil && expectedCondition.Status == v1.ConditionTrue {
+		metrics.PublishReadinessTimeToReadyMetrics(expectedCondition.Reason, r.clock.Since(pod.CreationTimestamp.Time))
+	}
 	return err
 }
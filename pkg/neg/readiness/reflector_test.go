@@ -47,6 +47,7 @@ const (
 type fakeLookUp struct {
 	readinessGateEnabled     bool
 	readinessGateEnabledNegs []string
+	healthRequirement        HealthRequirement
 }
 
 func (f *fakeLookUp) ReadinessGateEnabledNegs(namespace string, labels map[string]string) []string {
@@ -58,6 +59,11 @@ func (f *fakeLookUp) ReadinessGateEnabled(syncerKey negtypes.NegSyncerKey) bool
 	return f.readinessGateEnabled
 }
 
+// ReadinessGateHealthRequirement returns the configured health requirement
+func (f *fakeLookUp) ReadinessGateHealthRequirement(syncerKey negtypes.NegSyncerKey) HealthRequirement {
+	return f.healthRequirement
+}
+
 func newTestReadinessReflector(testContext *negtypes.TestContext, enableMultiSubnetCluster bool) *readinessReflector {
 	fakeZoneGetter := zonegetter.NewFakeZoneGetter(testContext.NodeInformer, defaultTestSubnetURL, enableMultiSubnetCluster)
 	reflector := NewReadinessReflector(
@@ -67,6 +73,7 @@ func newTestReadinessReflector(testContext *negtypes.TestContext, enableMultiSub
 		negtypes.NewAdapter(testContext.Cloud),
 		&fakeLookUp{},
 		fakeZoneGetter,
+		PollerConfig{},
 		false,
 		enableMultiSubnetCluster,
 		klog.TODO(),
@@ -386,7 +393,7 @@ func TestSyncPod(t *testing.T) {
 			for _, enableMultiSubnetCluster := range []bool{true, false} {
 				testReadinessReflector.enableMultiSubnetCluster = enableMultiSubnetCluster
 				tc.mutateState(testlookUp)
-				err := testReadinessReflector.syncPod(tc.inputKey, tc.inputNeg, tc.inputBackendService)
+				err := testReadinessReflector.syncPod(tc.inputKey, tc.inputNeg, tc.inputBackendService, nil)
 				if err != nil {
 					t.Errorf("For test case %q with enableMultiSubnetCluster = %v, expect syncPod() return nil, but got %v", tc.desc, enableMultiSubnetCluster, err)
 				}
@@ -620,7 +627,7 @@ func TestSyncPodMultipleSubnets(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
 			tc.mutateState(testlookUp)
-			err := testReadinessReflector.syncPod(tc.inputKey, tc.inputNeg, tc.inputBackendService)
+			err := testReadinessReflector.syncPod(tc.inputKey, tc.inputNeg, tc.inputBackendService, nil)
 			if err != nil {
 				t.Errorf("For test case %q with multi-subnet cluster enabled, expect err to be nil, but got %v", tc.desc, err)
 			}
@@ -637,3 +644,84 @@ func TestSyncPodMultipleSubnets(t *testing.T) {
 		})
 	}
 }
+
+func TestGetExpectedNegConditionWithBackendServiceHealths(t *testing.T) {
+	t.Parallel()
+	testReadinessReflector := newTestReadinessReflector(negtypes.NewTestContext(), false)
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: testServiceNamespace, Name: "pod1"}}
+	neg := meta.ZonalKey("neg1", "zone1")
+	healths := []backendServiceHealth{
+		{backendService: "bs1", state: healthyState},
+		{backendService: "bs2", state: healthyState},
+	}
+
+	got := testReadinessReflector.getExpectedNegCondition(pod, neg, meta.GlobalKey("bs1"), healths)
+	want := v1.PodCondition{
+		Type:    shared.NegReadinessGate,
+		Status:  v1.ConditionTrue,
+		Reason:  negReadyReason,
+		Message: fmt.Sprintf("Pod has become Healthy in NEG %q in all required BackendServices (bs1: HEALTHY, bs2: HEALTHY). Marking condition %q to True.", neg.String(), shared.NegReadinessGate),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getExpectedNegCondition() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetExpectedNegConditionWithUnhealthyBackendServices(t *testing.T) {
+	t.Parallel()
+	neg := meta.ZonalKey("neg1", "zone1")
+	healths := []backendServiceHealth{
+		{backendService: "bs1", state: healthyState},
+		{backendService: "bs2", state: "UNHEALTHY"},
+	}
+
+	for _, tc := range []struct {
+		desc       string
+		negs       []string
+		created    time.Time
+		wantStatus v1.ConditionStatus
+		wantReason string
+		wantMsg    string
+	}{
+		{
+			desc:       "pod only in this NEG",
+			negs:       []string{"neg1"},
+			created:    time.Now(),
+			wantStatus: v1.ConditionFalse,
+			wantReason: negNotReadyReason,
+			wantMsg:    fmt.Sprintf("Waiting for pod to become healthy in NEG %q in all required BackendServices (bs1: HEALTHY, bs2: UNHEALTHY)", neg.String()),
+		},
+		{
+			desc:       "pod in multiple NEGs",
+			negs:       []string{"neg1", "neg2"},
+			created:    time.Now(),
+			wantReason: negNotReadyReason,
+			wantMsg:    "Waiting for pod to become healthy in at least one of the NEG(s): [neg1 neg2]",
+		},
+		{
+			desc:       "timed out",
+			negs:       []string{"neg1"},
+			created:    time.Now().Add(-2 * unreadyTimeout),
+			wantStatus: v1.ConditionTrue,
+			wantReason: negReadyTimedOutReason,
+			wantMsg:    fmt.Sprintf("Timeout waiting for pod to become healthy in at least one of the NEG(s): [neg1]. Marking condition %q to True.", shared.NegReadinessGate),
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			testReadinessReflector := newTestReadinessReflector(negtypes.NewTestContext(), false)
+			testReadinessReflector.lookup = &fakeLookUp{readinessGateEnabledNegs: tc.negs}
+			pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: testServiceNamespace, Name: "pod1", CreationTimestamp: metav1.NewTime(tc.created)}}
+
+			got := testReadinessReflector.getExpectedNegCondition(pod, neg, nil, healths)
+			want := v1.PodCondition{
+				Type:    shared.NegReadinessGate,
+				Status:  tc.wantStatus,
+				Reason:  tc.wantReason,
+				Message: tc.wantMsg,
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("getExpectedNegCondition() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package readiness
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -149,6 +150,22 @@ func removeIrrelevantEndpoints(endpointMap negtypes.EndpointPodMap, podLister ca
 	}
 }
 
+// oldestPodCreationTime returns the creation time of the oldest pod in the endpoint map,
+// or the zero time if none of the pods is found.
+func oldestPodCreationTime(endpointMap negtypes.EndpointPodMap, podLister cache.Indexer) time.Time {
+	var ret time.Time
+	for _, namespacedName := range endpointMap {
+		pod, exists, err := getPodFromStore(podLister, namespacedName.Namespace, namespacedName.Name)
+		if err != nil || !exists {
+			continue
+		}
+		if ret.IsZero() || pod.CreationTimestamp.Time.Before(ret) {
+			ret = pod.CreationTimestamp.Time
+		}
+	}
+	return ret
+}
+
 // needToProcess check if the pod needs to be processed by readiness reflector
 // If pod has neg readiness gate and its condition is False, then return true.
 func needToProcess(pod *v1.Pod) bool {
@@ -158,3 +175,13 @@ func needToProcess(pod *v1.Pod) bool {
 	negConditionReady, readinessGateExists := evalNegReadinessGate(pod)
 	return readinessGateExists && !negConditionReady
 }
+
+// formatBackendServiceHealths returns the health of an endpoint in each backend
+// service in the format of "bs1: HEALTHY, bs2: HEALTHY".
+func formatBackendServiceHealths(healths []backendServiceHealth) string {
+	var ret []string
+	for _, health := range healths {
+		ret = append(ret, health.String())
+	}
+	return strings.Join(ret, ", ")
+}
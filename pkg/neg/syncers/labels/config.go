@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package labels
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/ingress-gce/pkg/neg/metrics"
+	"k8s.io/klog/v2"
+)
+
+// ConfigMapKey is the key in the ConfigMap data holding the
+// PodLabelPropagationConfig in JSON format.
+const ConfigMapKey = "config"
+
+// ConfigStore holds the current PodLabelPropagationConfig.
+// It is safe for concurrent use. A nil ConfigStore holds an empty config.
+type ConfigStore struct {
+	lock   sync.RWMutex
+	config PodLabelPropagationConfig
+	// defaultConfig is the config to fall back to when the ConfigMap is deleted.
+	defaultConfig PodLabelPropagationConfig
+
+	logger klog.Logger
+}
+
+// NewConfigStore returns a ConfigStore holding the given config, or an error
+// if the config is invalid. The given config is also used when the ConfigMap
+// is deleted.
+func NewConfigStore(config PodLabelPropagationConfig, logger klog.Logger) (*ConfigStore, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	config = config.withCompiledRegexes()
+	return &ConfigStore{
+		config:        config,
+		defaultConfig: config,
+		logger:        logger.WithName("LabelPropagationConfigStore"),
+	}, nil
+}
+
+// Get returns the current PodLabelPropagationConfig.
+func (s *ConfigStore) Get() PodLabelPropagationConfig {
+	if s == nil {
+		return PodLabelPropagationConfig{}
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.config
+}
+
+// Set replaces the current PodLabelPropagationConfig, along with the compiled
+// regular expressions of its rules.
+func (s *ConfigStore) Set(config PodLabelPropagationConfig) {
+	config = config.withCompiledRegexes()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.config = config
+}
+
+// LoadConfigMap parses and validates the PodLabelPropagationConfig in the
+// ConfigMap and replaces the current config with it. The current config is
+// kept if the ConfigMap does not hold a valid config.
+func (s *ConfigStore) LoadConfigMap(cm *v1.ConfigMap) error {
+	data, ok := cm.Data[ConfigMapKey]
+	if !ok {
+		return fmt.Errorf("%w: ConfigMap %s/%s does not contain key %q", ErrInvalidConfig, cm.Namespace, cm.Name, ConfigMapKey)
+	}
+	config := PodLabelPropagationConfig{}
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	s.Set(config)
+	return nil
+}
+
+// RegisterInformer registers the handlers to the configMapInformer to reload
+// the config whenever the ConfigMap namespace/name changes, without
+// restarting the controller.
+func (s *ConfigStore) RegisterInformer(configMapInformer cache.SharedIndexInformer, namespace, name string) {
+	matches := func(cm *v1.ConfigMap) bool {
+		return cm.Namespace == namespace && cm.Name == name
+	}
+	load := func(obj interface{}) {
+		cm, ok := obj.(*v1.ConfigMap)
+		if !ok || !matches(cm) {
+			return
+		}
+		if err := s.LoadConfigMap(cm); err != nil {
+			s.logger.Error(err, "Failed to load label propagation config, keeping the current config", "configMap", klog.KObj(cm))
+			metrics.PublishLabelPropagationError(ConfigError)
+			return
+		}
+		s.logger.Info("Loaded label propagation config", "configMap", klog.KObj(cm), "config", s.Get())
+	}
+	configMapInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: load,
+		UpdateFunc: func(_, cur interface{}) {
+			load(cur)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			cm, ok := obj.(*v1.ConfigMap)
+			if !ok || !matches(cm) {
+				return
+			}
+			s.logger.Info("Label propagation ConfigMap deleted, using the default config", "configMap", klog.KObj(cm), "config", s.defaultConfig)
+			s.Set(s.defaultConfig)
+		},
+	})
+}
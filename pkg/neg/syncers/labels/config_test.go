@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package labels
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+func TestConfigStoreLoadConfigMap(t *testing.T) {
+	defaultConfig := PodLabelPropagationConfig{
+		Labels: []Label{{Key: "app", MaxLabelSizeBytes: 10}},
+	}
+	newConfigMap := func(data map[string]string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "lp-config"},
+			Data:       data,
+		}
+	}
+
+	for _, tc := range []struct {
+		desc         string
+		configMap    *v1.ConfigMap
+		expectConfig PodLabelPropagationConfig
+		expectErr    bool
+	}{
+		{
+			desc: "Valid config",
+			configMap: newConfigMap(map[string]string{
+				ConfigMapKey: `{"Rules": [{"KeyRegex": "^team/", "MaxLabelSizeBytes": 30}], "DerivedLabels": [{"Source": "NodeZone", "Key": "zone", "MaxLabelSizeBytes": 30}]}`,
+			}),
+			expectConfig: PodLabelPropagationConfig{
+				Rules:         []Rule{{KeyRegex: "^team/", MaxLabelSizeBytes: 30}},
+				DerivedLabels: []DerivedLabel{{Source: NodeZone, Key: "zone", MaxLabelSizeBytes: 30}},
+			},
+		},
+		{
+			desc:         "Missing key keeps the current config",
+			configMap:    newConfigMap(map[string]string{"foo": "bar"}),
+			expectConfig: defaultConfig,
+			expectErr:    true,
+		},
+		{
+			desc:         "Malformed JSON keeps the current config",
+			configMap:    newConfigMap(map[string]string{ConfigMapKey: `{"Rules": [`}),
+			expectConfig: defaultConfig,
+			expectErr:    true,
+		},
+		{
+			desc:         "Invalid config keeps the current config",
+			configMap:    newConfigMap(map[string]string{ConfigMapKey: `{"Rules": [{"KeyRegex": "("}]}`}),
+			expectConfig: defaultConfig,
+			expectErr:    true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			store, err := NewConfigStore(defaultConfig, klog.TODO())
+			if err != nil {
+				t.Fatalf("NewConfigStore() = %v, want nil", err)
+			}
+			err = store.LoadConfigMap(tc.configMap)
+			if (err != nil) != tc.expectErr {
+				t.Errorf("LoadConfigMap() = %v, expectErr: %t", err, tc.expectErr)
+			}
+			if diff := cmp.Diff(tc.expectConfig, store.Get(), cmpopts.IgnoreUnexported(PodLabelPropagationConfig{})); diff != "" {
+				t.Errorf("Get() returned unexpected config (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewConfigStoreInvalidConfig(t *testing.T) {
+	config := PodLabelPropagationConfig{Rules: []Rule{{KeyRegex: "("}}}
+	if _, err := NewConfigStore(config, klog.TODO()); err == nil {
+		t.Errorf("NewConfigStore() = nil, want error")
+	}
+}
+
+func TestConfigStoreRegexes(t *testing.T) {
+	storeA, err := NewConfigStore(PodLabelPropagationConfig{Rules: []Rule{{KeyRegex: "^team/"}}}, klog.TODO())
+	if err != nil {
+		t.Fatalf("NewConfigStore() = %v, want nil", err)
+	}
+	storeB, err := NewConfigStore(PodLabelPropagationConfig{Rules: []Rule{{KeyRegex: "^owner/"}}}, klog.TODO())
+	if err != nil {
+		t.Fatalf("NewConfigStore() = %v, want nil", err)
+	}
+	for _, tc := range []struct {
+		store       *ConfigStore
+		data        string
+		expectRegex []string
+	}{
+		{store: storeA, expectRegex: []string{"", "^team/"}},
+		{store: storeB, expectRegex: []string{"", "^owner/"}},
+		{store: storeA, data: `{"Rules": [{"KeyRegex": "^app/", "ValueRegex": "-v[0-9]+$"}]}`, expectRegex: []string{"-v[0-9]+$", "^app/"}},
+		// Loading a config in a store does not affect the other stores.
+		{store: storeB, expectRegex: []string{"", "^owner/"}},
+		{store: storeA, data: `{"Rules": [{"KeyRegex": "("}]}`, expectRegex: []string{"-v[0-9]+$", "^app/"}},
+	} {
+		if tc.data != "" {
+			tc.store.LoadConfigMap(&v1.ConfigMap{Data: map[string]string{ConfigMapKey: tc.data}})
+		}
+		var got []string
+		for expr := range tc.store.Get().regexes {
+			got = append(got, expr)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tc.expectRegex) {
+			t.Errorf("After loading %q, compiled regular expressions = %q, want %q", tc.data, got, tc.expectRegex)
+		}
+	}
+}
+
+func TestNilConfigStore(t *testing.T) {
+	var store *ConfigStore
+	if got := store.Get(); !reflect.DeepEqual(got, PodLabelPropagationConfig{}) {
+		t.Errorf("Get() = %+v, want empty config", got)
+	}
+}
@@ -114,7 +114,7 @@ func TestGetPodLabelMap(t *testing.T) {
 			expectErr: true,
 		},
 	} {
-		ret, err := GetPodLabelMap(pod, tc.lpConfig)
+		ret, err := GetPodLabelMap(pod, "", tc.lpConfig)
 		if !reflect.DeepEqual(ret, tc.expect) {
 			t.Errorf("For test case %q, got label map %+v, want %+v", tc.desc, ret, tc.expect)
 		}
@@ -183,3 +183,188 @@ func TestTruncateLabel(t *testing.T) {
 		}
 	}
 }
+
+func TestGetPodLabelMapWithRulesAndDerivedLabels(t *testing.T) {
+	t.Parallel()
+
+	isController := true
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "web-6b7c9d8f5-abcde",
+			Labels: map[string]string{
+				"team.example.com/owner":  "payments",
+				"team.example.com/tier":   "backend-v2",
+				"pod-template-hash":       "6b7c9d8f5",
+				"app.kubernetes.io/name":  "web",
+				"unrelated.example.com/x": "y",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "web-6b7c9d8f5", Controller: &isController},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		desc      string
+		lpConfig  PodLabelPropagationConfig
+		zone      string
+		expect    PodLabelMap
+		expectErr bool
+	}{
+		{
+			desc: "Rule selects labels by key regex",
+			lpConfig: PodLabelPropagationConfig{
+				Rules: []Rule{
+					{KeyRegex: `^team\.example\.com/`, MaxLabelSizeBytes: 40},
+				},
+			},
+			expect: PodLabelMap{
+				"team.example.com/owner": "payments",
+				"team.example.com/tier":  "backend-v2",
+			},
+		},
+		{
+			desc: "Rule rewrites label values",
+			lpConfig: PodLabelPropagationConfig{
+				Rules: []Rule{
+					{KeyRegex: `/tier$`, ValueRegex: `-v[0-9]+$`, ValueReplacement: "", MaxLabelSizeBytes: 40},
+				},
+			},
+			expect: PodLabelMap{
+				"team.example.com/tier": "backend",
+			},
+		},
+		{
+			desc: "Explicit label takes precedence over rule",
+			lpConfig: PodLabelPropagationConfig{
+				Labels: []Label{
+					{Key: "team.example.com/owner", MaxLabelSizeBytes: 40},
+				},
+				Rules: []Rule{
+					{KeyRegex: `owner`, ValueRegex: `.*`, ValueReplacement: "rewritten", MaxLabelSizeBytes: 40},
+				},
+			},
+			expect: PodLabelMap{
+				"team.example.com/owner": "payments",
+			},
+		},
+		{
+			desc: "Derived labels",
+			lpConfig: PodLabelPropagationConfig{
+				DerivedLabels: []DerivedLabel{
+					{Source: OwnerDeployment, Key: "deployment", MaxLabelSizeBytes: 40},
+					{Source: NodeZone, Key: "zone", MaxLabelSizeBytes: 40},
+				},
+			},
+			zone: "us-central1-a",
+			expect: PodLabelMap{
+				"deployment": "web",
+				"zone":       "us-central1-a",
+			},
+		},
+		{
+			desc: "Derived zone label is skipped when the zone is unknown",
+			lpConfig: PodLabelPropagationConfig{
+				DerivedLabels: []DerivedLabel{
+					{Source: NodeZone, Key: "zone", MaxLabelSizeBytes: 40},
+				},
+			},
+			expect: PodLabelMap{},
+		},
+		{
+			desc: "Rule with truncation",
+			lpConfig: PodLabelPropagationConfig{
+				Rules: []Rule{
+					{KeyRegex: `/tier$`, MaxLabelSizeBytes: 26},
+				},
+			},
+			expect: PodLabelMap{
+				"team.example.com/tier": "backe",
+			},
+			expectErr: true,
+		},
+		{
+			desc: "Invalid rule regex",
+			lpConfig: PodLabelPropagationConfig{
+				Rules: []Rule{
+					{KeyRegex: `(`, MaxLabelSizeBytes: 40},
+				},
+			},
+			expect:    PodLabelMap{},
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ret, err := GetPodLabelMap(pod, tc.zone, tc.lpConfig)
+			if !reflect.DeepEqual(ret, tc.expect) {
+				t.Errorf("got label map %+v, want %+v", ret, tc.expect)
+			}
+			if (err != nil) != tc.expectErr {
+				t.Errorf("got error %v, expectErr: %t ", err, tc.expectErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	for _, tc := range []struct {
+		desc      string
+		lpConfig  PodLabelPropagationConfig
+		expectErr bool
+	}{
+		{
+			desc: "Valid config",
+			lpConfig: PodLabelPropagationConfig{
+				Labels:        []Label{{Key: "app", MaxLabelSizeBytes: 10}},
+				Rules:         []Rule{{KeyRegex: "^team/", ValueRegex: "-v[0-9]+$", MaxLabelSizeBytes: 10}},
+				DerivedLabels: []DerivedLabel{{Source: OwnerDeployment, Key: "deployment", MaxLabelSizeBytes: 20}},
+			},
+		},
+		{
+			desc:      "Invalid key regex",
+			lpConfig:  PodLabelPropagationConfig{Rules: []Rule{{KeyRegex: "[", MaxLabelSizeBytes: 10}}},
+			expectErr: true,
+		},
+		{
+			desc:      "Invalid value regex",
+			lpConfig:  PodLabelPropagationConfig{Rules: []Rule{{KeyRegex: "app", ValueRegex: "(", MaxLabelSizeBytes: 10}}},
+			expectErr: true,
+		},
+		{
+			desc:      "Empty key regex",
+			lpConfig:  PodLabelPropagationConfig{Rules: []Rule{{MaxLabelSizeBytes: 10}}},
+			expectErr: true,
+		},
+		{
+			desc:      "Unknown derived label source",
+			lpConfig:  PodLabelPropagationConfig{DerivedLabels: []DerivedLabel{{Source: "NodeName", Key: "node"}}},
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.lpConfig.Validate()
+			if (err != nil) != tc.expectErr {
+				t.Errorf("Validate() = %v, expectErr: %t", err, tc.expectErr)
+			}
+		})
+	}
+}
+
+func TestCheckPodLabelTruncation(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app.kubernetes.io/name": "pod-with-long-name"},
+		},
+	}
+	lpConfig := PodLabelPropagationConfig{
+		Labels: []Label{{Key: "app.kubernetes.io/name", MaxLabelSizeBytes: 30}},
+	}
+	if err := CheckPodLabelTruncation(pod, lpConfig); err == nil {
+		t.Errorf("CheckPodLabelTruncation() = nil, want truncation error")
+	}
+	lpConfig.Labels[0].MaxLabelSizeBytes = 40
+	if err := CheckPodLabelTruncation(pod, lpConfig); err != nil {
+		t.Errorf("CheckPodLabelTruncation() = %v, want nil", err)
+	}
+}
@@ -18,8 +18,10 @@ package labels
 
 import (
 	"errors"
-
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/ingress-gce/pkg/neg/metrics"
@@ -28,8 +30,17 @@ import (
 )
 
 // PodLabelPropagationConfig contains a list of configurations for labels to be propagated to GCE network endpoints.
+// Labels are propagated first, followed by Rules and DerivedLabels. A label key
+// that is already propagated is not overwritten by a later configuration.
 type PodLabelPropagationConfig struct {
-	Labels []Label
+	Labels        []Label
+	Rules         []Rule
+	DerivedLabels []DerivedLabel
+
+	// regexes holds the compiled regular expressions of Rules, keyed by the
+	// expression. It is set by ConfigStore and never modified afterwards, so
+	// copies of the config can share it.
+	regexes map[string]*regexp.Regexp
 }
 
 // Label contains configuration for a label to be propagated to GCE network endpoints.
@@ -39,6 +50,36 @@ type Label struct {
 	MaxLabelSizeBytes int
 }
 
+// Rule contains configuration for pod labels to be propagated to GCE network
+// endpoints based on the label key.
+type Rule struct {
+	// KeyRegex selects the pod labels to be propagated. The label key is kept as is.
+	KeyRegex string
+	// ValueRegex and ValueReplacement rewrite the label value, following the
+	// semantics of regexp.ReplaceAllString. The value is kept as is if ValueRegex is empty.
+	ValueRegex        string
+	ValueReplacement  string
+	MaxLabelSizeBytes int
+}
+
+// DerivedLabelSource is the source a derived label value is computed from.
+type DerivedLabelSource string
+
+const (
+	// OwnerDeployment derives the name of the Deployment owning the pod.
+	OwnerDeployment DerivedLabelSource = "OwnerDeployment"
+	// NodeZone derives the zone of the node the pod is running on.
+	NodeZone DerivedLabelSource = "NodeZone"
+)
+
+// DerivedLabel contains configuration for a label that is not a pod label,
+// but is computed from the pod, to be propagated to GCE network endpoints.
+type DerivedLabel struct {
+	Source            DerivedLabelSource
+	Key               string
+	MaxLabelSizeBytes int
+}
+
 // PodLabelMap is a map of pod label key, label values.
 type PodLabelMap map[string]string
 
@@ -49,22 +90,121 @@ const (
 	Truncated         = "truncated"
 	TruncationFailure = "truncation_failed"
 	OtherError        = "other_error"
+	ConfigError       = "config_error"
+
+	// replicaSetKind is the kind of the owner of pods managed by a Deployment.
+	replicaSetKind = "ReplicaSet"
+	// podTemplateHashLabel is the label added by the Deployment controller to
+	// pods and ReplicaSets. ReplicaSet names are suffixed with its value.
+	podTemplateHashLabel = "pod-template-hash"
 )
 
 var (
 	ErrLabelTruncated        = errors.New("label is truncated")
 	ErrLabelTruncationFailed = errors.New("failed to truncate label")
+	ErrInvalidConfig         = errors.New("invalid label propagation config")
 )
 
+// withCompiledRegexes returns a copy of the config holding the compiled
+// regular expressions of its rules.
+func (c PodLabelPropagationConfig) withCompiledRegexes() PodLabelPropagationConfig {
+	regexes := make(map[string]*regexp.Regexp)
+	for _, rule := range c.Rules {
+		for _, expr := range []string{rule.KeyRegex, rule.ValueRegex} {
+			if re, err := regexp.Compile(expr); err == nil {
+				regexes[expr] = re
+			}
+		}
+	}
+	c.regexes = regexes
+	return c
+}
+
+// compileRegex returns the compiled regular expression held by the config,
+// or compiles it if the config was not compiled by a ConfigStore.
+func (c PodLabelPropagationConfig) compileRegex(expr string) (*regexp.Regexp, error) {
+	if re, ok := c.regexes[expr]; ok {
+		return re, nil
+	}
+	return regexp.Compile(expr)
+}
+
+// Validate returns an error if the config is invalid.
+func (c PodLabelPropagationConfig) Validate() error {
+	var errs []error
+	for _, label := range c.Labels {
+		if label.Key == "" {
+			errs = append(errs, fmt.Errorf("%w: label key must not be empty", ErrInvalidConfig))
+		}
+	}
+	for _, rule := range c.Rules {
+		if rule.KeyRegex == "" {
+			errs = append(errs, fmt.Errorf("%w: rule key regex must not be empty", ErrInvalidConfig))
+		}
+		for _, expr := range []string{rule.KeyRegex, rule.ValueRegex} {
+			if _, err := regexp.Compile(expr); err != nil {
+				errs = append(errs, fmt.Errorf("%w: invalid regex %q: %v", ErrInvalidConfig, expr, err))
+			}
+		}
+	}
+	for _, label := range c.DerivedLabels {
+		if label.Key == "" {
+			errs = append(errs, fmt.Errorf("%w: derived label key must not be empty", ErrInvalidConfig))
+		}
+		if label.Source != OwnerDeployment && label.Source != NodeZone {
+			errs = append(errs, fmt.Errorf("%w: unknown derived label source %q, valid values are: %s/%s", ErrInvalidConfig, label.Source, OwnerDeployment, NodeZone))
+		}
+	}
+	if len(errs) != 0 {
+		return utils.JoinErrs(errs)
+	}
+	return nil
+}
+
 // minLabelLength defines the minimum space left for the label value.
 const minLabelLength = 5
 
 // GetPodLabelMap will return the label map extracted from a pod according to PodLabelPropagationConfig.
 // The returned map has the pod label key as key and label value as value.
+// zone is the zone of the pod, used for the NodeZone derived label.
 // This function will raise an error if pod label truncation happens or truncation fails.
-func GetPodLabelMap(pod *v1.Pod, lpConfig PodLabelPropagationConfig) (PodLabelMap, error) {
+func GetPodLabelMap(pod *v1.Pod, zone string, lpConfig PodLabelPropagationConfig) (PodLabelMap, error) {
+	return getPodLabelMap(pod, zone, lpConfig, true)
+}
+
+// CheckPodLabelTruncation returns an error if any label of the pod will be
+// truncated, or fails to be truncated, when propagated according to
+// PodLabelPropagationConfig. It is used to warn about truncation as soon as
+// the pod is created, before its endpoint is attached to a NEG. Derived labels
+// depending on the zone of the pod are not checked.
+func CheckPodLabelTruncation(pod *v1.Pod, lpConfig PodLabelPropagationConfig) error {
+	_, err := getPodLabelMap(pod, "", lpConfig, false)
+	return err
+}
+
+// getPodLabelMap implements GetPodLabelMap. Truncation metrics are only
+// published if publishMetrics is true.
+func getPodLabelMap(pod *v1.Pod, zone string, lpConfig PodLabelPropagationConfig, publishMetrics bool) (PodLabelMap, error) {
 	labelMap := PodLabelMap{}
 	var errs []error
+	addLabel := func(key, val string, maxLabelSizeBytes int) {
+		if _, ok := labelMap[key]; ok {
+			return
+		}
+		labelVal, err := truncatePodLabel(key, val, maxLabelSizeBytes)
+		if err != nil {
+			errs = append(errs, err)
+			if publishMetrics {
+				publishLabelPropagationTruncationMetrics(err)
+			}
+		}
+
+		// Add the label to the map only if the truncation result is valid
+		if err == nil || errors.Is(err, ErrLabelTruncated) {
+			labelMap[key] = labelVal
+		}
+	}
+
 	for _, label := range lpConfig.Labels {
 		val, ok := pod.Labels[label.Key]
 		if ok {
@@ -72,24 +212,82 @@ func GetPodLabelMap(pod *v1.Pod, lpConfig PodLabelPropagationConfig) (PodLabelMa
 			if label.ShortKey != "" {
 				lpKey = label.ShortKey
 			}
-			labelVal, err := truncatePodLabel(lpKey, val, label.MaxLabelSizeBytes)
-			if err != nil {
-				errs = append(errs, err)
-				publishLabelPropagationTruncationMetrics(err)
-			}
+			addLabel(lpKey, val, label.MaxLabelSizeBytes)
+		}
+	}
 
-			// Add the label to the map only if the truncation result is valid
-			if err == nil || errors.Is(err, ErrLabelTruncated) {
-				labelMap[lpKey] = labelVal
+	for _, rule := range lpConfig.Rules {
+		keyRegex, err := lpConfig.compileRegex(rule.KeyRegex)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %v", ErrInvalidConfig, err))
+			metrics.PublishLabelPropagationError(ConfigError)
+			continue
+		}
+		var valueRegex *regexp.Regexp
+		if rule.ValueRegex != "" {
+			if valueRegex, err = lpConfig.compileRegex(rule.ValueRegex); err != nil {
+				errs = append(errs, fmt.Errorf("%w: %v", ErrInvalidConfig, err))
+				metrics.PublishLabelPropagationError(ConfigError)
+				continue
 			}
 		}
+		for _, key := range sortedKeys(pod.Labels) {
+			if !keyRegex.MatchString(key) {
+				continue
+			}
+			val := pod.Labels[key]
+			if valueRegex != nil {
+				val = valueRegex.ReplaceAllString(val, rule.ValueReplacement)
+			}
+			addLabel(key, val, rule.MaxLabelSizeBytes)
+		}
 	}
+
+	for _, label := range lpConfig.DerivedLabels {
+		if val, ok := derivedLabelValue(pod, zone, label.Source); ok {
+			addLabel(label.Key, val, label.MaxLabelSizeBytes)
+		}
+	}
+
 	if len(errs) != 0 {
 		return labelMap, utils.JoinErrs(errs)
 	}
 	return labelMap, nil
 }
 
+// derivedLabelValue returns the value of a derived label for the pod, and
+// false if the value cannot be derived.
+func derivedLabelValue(pod *v1.Pod, zone string, source DerivedLabelSource) (string, bool) {
+	switch source {
+	case OwnerDeployment:
+		hash, ok := pod.Labels[podTemplateHashLabel]
+		if !ok {
+			return "", false
+		}
+		for _, owner := range pod.OwnerReferences {
+			if owner.Kind == replicaSetKind && owner.Controller != nil && *owner.Controller && strings.HasSuffix(owner.Name, "-"+hash) {
+				return strings.TrimSuffix(owner.Name, "-"+hash), true
+			}
+		}
+	case NodeZone:
+		if zone != "" {
+			return zone, true
+		}
+	}
+	return "", false
+}
+
+// sortedKeys returns the keys of the label map in sorted order, so that
+// the errors and metrics are deterministic.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // publishLabelPropagationTruncationMetrics publishes errors occured during
 // label truncation.
 func publishLabelPropagationTruncationMetrics(err error) {
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package labels
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// TruncationWarningWebhookPath is the path of the validating admission
+// webhook warning about pod labels which will be truncated.
+const TruncationWarningWebhookPath = "/validate-pod-label-propagation"
+
+// truncationWarningWebhook is a validating admission webhook for pods. It
+// admits all pods, and returns a warning to the client if any label of the pod
+// will be truncated when propagated to its NEG endpoints.
+type truncationWarningWebhook struct {
+	store *ConfigStore
+
+	logger klog.Logger
+}
+
+// NewTruncationWarningWebhook returns the handler of the validating admission
+// webhook warning about pod labels which will be truncated according to the
+// config of the store.
+func NewTruncationWarningWebhook(store *ConfigStore, logger klog.Logger) http.Handler {
+	return &truncationWarningWebhook{
+		store:  store,
+		logger: logger.WithName("LabelTruncationWebhook"),
+	}
+}
+
+// ServeHTTP handles an AdmissionReview request.
+func (wh *truncationWarningWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var review admissionv1.AdmissionReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+		wh.logger.Error(err, "Failed to decode AdmissionReview request")
+		http.Error(w, "invalid AdmissionReview request", http.StatusBadRequest)
+		return
+	}
+
+	response := &admissionv1.AdmissionResponse{
+		UID:     review.Request.UID,
+		Allowed: true,
+	}
+	if warning := wh.warning(review.Request); warning != "" {
+		response.Warnings = []string{warning}
+	}
+	review.Request = nil
+	review.Response = response
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		wh.logger.Error(err, "Failed to encode AdmissionReview response")
+	}
+}
+
+// warning returns the warning for the pod of the admission request, empty if
+// none of its labels will be truncated.
+func (wh *truncationWarningWebhook) warning(req *admissionv1.AdmissionRequest) string {
+	if req.Resource.Resource != "pods" || req.SubResource != "" {
+		return ""
+	}
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return ""
+	}
+	var pod v1.Pod
+	if err := json.Unmarshal(req.Object.Raw, &pod); err != nil {
+		wh.logger.Error(err, "Failed to decode pod of admission request", "pod", klog.KRef(req.Namespace, req.Name))
+		return ""
+	}
+	if err := CheckPodLabelTruncation(&pod, wh.store.Get()); err != nil {
+		return fmt.Sprintf("Label Propagation Warning: %v", err)
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package labels
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+func TestTruncationWarningWebhook(t *testing.T) {
+	store, err := NewConfigStore(PodLabelPropagationConfig{
+		Labels: []Label{{Key: "app.kubernetes.io/name", MaxLabelSizeBytes: 30}},
+	}, klog.TODO())
+	if err != nil {
+		t.Fatalf("NewConfigStore() returned error %v", err)
+	}
+	webhook := NewTruncationWarningWebhook(store, klog.TODO())
+
+	for _, tc := range []struct {
+		desc        string
+		resource    string
+		operation   admissionv1.Operation
+		labelValue  string
+		wantWarning bool
+	}{
+		{
+			desc:        "created pod with label to truncate",
+			resource:    "pods",
+			operation:   admissionv1.Create,
+			labelValue:  "pod-with-long-name",
+			wantWarning: true,
+		},
+		{
+			desc:        "updated pod with label to truncate",
+			resource:    "pods",
+			operation:   admissionv1.Update,
+			labelValue:  "pod-with-long-name",
+			wantWarning: true,
+		},
+		{
+			desc:       "created pod with short label",
+			resource:   "pods",
+			operation:  admissionv1.Create,
+			labelValue: "pod",
+		},
+		{
+			desc:       "deleted pod",
+			resource:   "pods",
+			operation:  admissionv1.Delete,
+			labelValue: "pod-with-long-name",
+		},
+		{
+			desc:       "not a pod",
+			resource:   "services",
+			operation:  admissionv1.Create,
+			labelValue: "pod-with-long-name",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "ns",
+					Name:      "pod",
+					Labels:    map[string]string{"app.kubernetes.io/name": tc.labelValue},
+				},
+			}
+			raw, err := json.Marshal(pod)
+			if err != nil {
+				t.Fatalf("json.Marshal() returned error %v", err)
+			}
+			review := admissionv1.AdmissionReview{
+				TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+				Request: &admissionv1.AdmissionRequest{
+					UID:       types.UID("uid"),
+					Resource:  metav1.GroupVersionResource{Version: "v1", Resource: tc.resource},
+					Operation: tc.operation,
+					Object:    runtime.RawExtension{Raw: raw},
+				},
+			}
+			body, err := json.Marshal(review)
+			if err != nil {
+				t.Fatalf("json.Marshal() returned error %v", err)
+			}
+
+			recorder := httptest.NewRecorder()
+			webhook.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, TruncationWarningWebhookPath, bytes.NewReader(body)))
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("Got status code %d, want %d", recorder.Code, http.StatusOK)
+			}
+			var got admissionv1.AdmissionReview
+			if err := json.Unmarshal(recorder.Body.Bytes(), &got); err != nil {
+				t.Fatalf("json.Unmarshal() returned error %v", err)
+			}
+			if got.Kind != "AdmissionReview" || got.Response == nil || got.Response.UID != review.Request.UID || !got.Response.Allowed {
+				t.Fatalf("Got AdmissionReview %+v, want an allowed response for request %s", got, review.Request.UID)
+			}
+			if gotWarning := len(got.Response.Warnings) != 0; gotWarning != tc.wantWarning {
+				t.Errorf("Got warnings %v, want warning %t", got.Response.Warnings, tc.wantWarning)
+			}
+		})
+	}
+}
+
+func TestTruncationWarningWebhookInvalidRequest(t *testing.T) {
+	webhook := NewTruncationWarningWebhook(nil, klog.TODO())
+	recorder := httptest.NewRecorder()
+	webhook.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, TruncationWarningWebhookPath, bytes.NewReader([]byte("{"))))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Got status code %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	compute "google.golang.org/api/compute/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	negv1beta1 "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1"
+	"k8s.io/ingress-gce/pkg/neg/metrics"
+	negtypes "k8s.io/ingress-gce/pkg/neg/types"
+	svcnegclient "k8s.io/ingress-gce/pkg/svcneg/client/clientset/versioned"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/common"
+	"k8s.io/ingress-gce/pkg/utils/patch"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/strings/slices"
+)
+
+// ResourceNEGSyncer syncs the NEGs declared in the spec of user created
+// ServiceNetworkEndpointGroups. Unlike the transaction syncer, the NEGs it
+// manages are not backed by Kubernetes endpoints: a SERVERLESS NEG has no
+// endpoints and an INTERNET_FQDN_PORT NEG has a single endpoint taken from
+// the spec. The GCE NEG is named after the namespace and name of the CR.
+type ResourceNEGSyncer struct {
+	cloud         negtypes.NetworkEndpointGroupCloud
+	namer         negtypes.NetworkEndpointGroupNamer
+	svcNegClient  svcnegclient.Interface
+	svcNegLister  cache.Indexer
+	recorder      record.EventRecorder
+	kubeSystemUID string
+
+	logger klog.Logger
+}
+
+func NewResourceNEGSyncer(cloud negtypes.NetworkEndpointGroupCloud, namer negtypes.NetworkEndpointGroupNamer, svcNegClient svcnegclient.Interface, svcNegLister cache.Indexer, recorder record.EventRecorder, kubeSystemUID string, logger klog.Logger) *ResourceNEGSyncer {
+	return &ResourceNEGSyncer{
+		cloud:         cloud,
+		namer:         namer,
+		svcNegClient:  svcNegClient,
+		svcNegLister:  svcNegLister,
+		recorder:      recorder,
+		kubeSystemUID: kubeSystemUID,
+		logger:        logger.WithName("ResourceNEGSyncer"),
+	}
+}
+
+// Sync ensures that the NEG declared by the ServiceNetworkEndpointGroup
+// namespace/name exists and is recorded in the CR status. If the CR is being
+// deleted, the NEG is deleted and the finalizer is removed from the CR.
+// ServiceNetworkEndpointGroups with an empty spec are ignored.
+func (s *ResourceNEGSyncer) Sync(namespace, name string) error {
+	obj, exists, err := s.svcNegLister.GetByKey(fmt.Sprintf("%s/%s", namespace, name))
+	if err != nil {
+		return fmt.Errorf("failed to retrieve neg cr %s/%s from cache: %w", namespace, name, err)
+	}
+	if !exists {
+		return nil
+	}
+	negCR := obj.(*negv1beta1.ServiceNetworkEndpointGroup)
+	negType := negtypes.ResourceNEGType(negCR)
+	if negType == "" {
+		return nil
+	}
+	logger := s.logger.WithValues("svcneg", klog.KObj(negCR), "negType", negType)
+	key := s.negKey(negCR, negType)
+
+	if !negCR.GetDeletionTimestamp().IsZero() {
+		return s.ensureDeleted(negCR, key, logger)
+	}
+
+	if err := s.ensureFinalizer(negCR, logger); err != nil {
+		return err
+	}
+
+	var negRef negv1beta1.NegObjectReference
+	err = negtypes.ValidateResourceNEGSpec(negCR.Spec)
+	if err == nil {
+		negRef, err = s.ensureNEG(negCR, negType, key, logger)
+	}
+	if err != nil {
+		s.recorder.Eventf(negCR, apiv1.EventTypeWarning, "SyncNetworkEndpointGroupFailed", "Failed to sync NEG %q: %v", key.Name, err)
+	}
+	if statusErr := s.updateStatus(negCR, negRef, err); statusErr != nil {
+		logger.Error(statusErr, "Error updating Neg CR")
+		metrics.PublishNegControllerErrorCountMetrics(statusErr, true)
+		if err == nil {
+			err = statusErr
+		}
+	}
+	return err
+}
+
+// negKey returns the key of the GCE NEG for the given NEG type. Serverless
+// NEGs are regional and live in the region of the cluster, internet NEGs are
+// global.
+func (s *ResourceNEGSyncer) negKey(negCR *negv1beta1.ServiceNetworkEndpointGroup, negType negtypes.NetworkEndpointType) *meta.Key {
+	name := s.namer.ResourceNEG(negCR.Namespace, negCR.Name)
+	if negType == negtypes.ServerlessEndpointType {
+		return meta.RegionalKey(name, s.cloud.Region())
+	}
+	return meta.GlobalKey(name)
+}
+
+// expectedDescription returns the description of NEGs managed on behalf of
+// the given CR. The service name and port are left empty as the NEG is not
+// backed by a Service.
+func (s *ResourceNEGSyncer) expectedDescription(negCR *negv1beta1.ServiceNetworkEndpointGroup) utils.NegDescription {
+	return utils.NegDescription{
+		ClusterUID: s.kubeSystemUID,
+		Namespace:  negCR.Namespace,
+	}
+}
+
+// ensureNEG creates the GCE NEG if it does not exist, and for internet NEGs,
+// ensures that its only endpoint is the one in the spec. NEGs are immutable, so
+// a spec change of the serverless target is reported as an error rather than
+// silently recreating a NEG that may be in use by a backend service.
+func (s *ResourceNEGSyncer) ensureNEG(negCR *negv1beta1.ServiceNetworkEndpointGroup, negType negtypes.NetworkEndpointType, key *meta.Key, logger klog.Logger) (negv1beta1.NegObjectReference, error) {
+	expectedNEG := s.expectedNEG(negCR, negType, key)
+	neg, err := s.cloud.GetResourceNetworkEndpointGroup(key, logger)
+	if err != nil {
+		if !utils.IsNotFoundError(err) {
+			return negv1beta1.NegObjectReference{}, err
+		}
+		logger.Info("Creating NEG", "negName", key.Name)
+		if err := s.cloud.CreateResourceNetworkEndpointGroup(expectedNEG, key, logger); err != nil {
+			return negv1beta1.NegObjectReference{}, err
+		}
+		s.recorder.Eventf(negCR, apiv1.EventTypeNormal, "Create", "Created NEG %q.", key.Name)
+		if neg, err = s.cloud.GetResourceNetworkEndpointGroup(key, logger); err != nil {
+			return negv1beta1.NegObjectReference{}, err
+		}
+	} else {
+		if matches, err := utils.VerifyDescription(s.expectedDescription(negCR), neg.Description, key.Name, key.Region); !matches || neg.Description == "" {
+			if err == nil {
+				err = fmt.Errorf("NEG %s has an empty description", key.Name)
+			}
+			return negv1beta1.NegObjectReference{}, fmt.Errorf("NEG %s already exists and is not managed by this ServiceNetworkEndpointGroup: %w", key.Name, err)
+		}
+		if !resourceNEGTargetEqual(neg, expectedNEG) {
+			return negv1beta1.NegObjectReference{}, fmt.Errorf("NEG %s does not match the spec and NEGs cannot be updated, delete and recreate the ServiceNetworkEndpointGroup to change its target", key.Name)
+		}
+	}
+
+	if negType == negtypes.InternetFQDNEndpointType {
+		if err := s.ensureInternetEndpoint(negCR, key.Name, logger); err != nil {
+			return negv1beta1.NegObjectReference{}, err
+		}
+	}
+
+	return negv1beta1.NegObjectReference{
+		Id:                  fmt.Sprint(neg.Id),
+		SelfLink:            neg.SelfLink,
+		NetworkEndpointType: negv1beta1.NetworkEndpointType(neg.NetworkEndpointType),
+		State:               negv1beta1.ActiveState,
+	}, nil
+}
+
+func (s *ResourceNEGSyncer) expectedNEG(negCR *negv1beta1.ServiceNetworkEndpointGroup, negType negtypes.NetworkEndpointType, key *meta.Key) *compute.NetworkEndpointGroup {
+	neg := &compute.NetworkEndpointGroup{
+		Name:                key.Name,
+		NetworkEndpointType: string(negType),
+		Description:         s.expectedDescription(negCR).String(),
+	}
+	if serverless := negCR.Spec.Serverless; serverless != nil {
+		neg.Region = key.Region
+		if serverless.CloudRun != nil {
+			neg.CloudRun = &compute.NetworkEndpointGroupCloudRun{
+				Service: serverless.CloudRun.Service,
+				Tag:     serverless.CloudRun.Tag,
+				UrlMask: serverless.CloudRun.URLMask,
+			}
+		}
+		if serverless.CloudFunction != nil {
+			neg.CloudFunction = &compute.NetworkEndpointGroupCloudFunction{
+				Function: serverless.CloudFunction.Function,
+				UrlMask:  serverless.CloudFunction.URLMask,
+			}
+		}
+	}
+	return neg
+}
+
+// resourceNEGTargetEqual returns true if the existing NEG has the same type and
+// serverless target as the expected NEG.
+func resourceNEGTargetEqual(existing, expected *compute.NetworkEndpointGroup) bool {
+	if existing.NetworkEndpointType != expected.NetworkEndpointType {
+		return false
+	}
+	if (existing.CloudRun == nil) != (expected.CloudRun == nil) || (existing.CloudFunction == nil) != (expected.CloudFunction == nil) {
+		return false
+	}
+	if expected.CloudRun != nil &&
+		(existing.CloudRun.Service != expected.CloudRun.Service || existing.CloudRun.Tag != expected.CloudRun.Tag || existing.CloudRun.UrlMask != expected.CloudRun.UrlMask) {
+		return false
+	}
+	if expected.CloudFunction != nil &&
+		(existing.CloudFunction.Function != expected.CloudFunction.Function || existing.CloudFunction.UrlMask != expected.CloudFunction.UrlMask) {
+		return false
+	}
+	return true
+}
+
+// ensureInternetEndpoint ensures that the internet NEG negName contains only
+// the endpoint specified in the CR.
+func (s *ResourceNEGSyncer) ensureInternetEndpoint(negCR *negv1beta1.ServiceNetworkEndpointGroup, negName string, logger klog.Logger) error {
+	port := int64(negCR.Spec.Internet.Port)
+	if port == 0 {
+		port = negtypes.DefaultInternetNEGPort
+	}
+	expected := &compute.NetworkEndpoint{Fqdn: negCR.Spec.Internet.FQDN, Port: port}
+
+	endpoints, err := s.cloud.ListGlobalNetworkEndpoints(negName, logger)
+	if err != nil {
+		return err
+	}
+	found := false
+	var toDetach []*compute.NetworkEndpoint
+	for _, ep := range endpoints {
+		if ep.Fqdn == expected.Fqdn && ep.Port == expected.Port {
+			found = true
+			continue
+		}
+		toDetach = append(toDetach, ep)
+	}
+	if len(toDetach) > 0 {
+		logger.Info("Detaching stale endpoints from internet NEG", "negName", negName, "count", len(toDetach))
+		if err := s.cloud.DetachGlobalNetworkEndpoints(negName, toDetach, logger); err != nil {
+			return err
+		}
+	}
+	if !found {
+		logger.Info("Attaching endpoint to internet NEG", "negName", negName, "fqdn", expected.Fqdn, "port", expected.Port)
+		if err := s.cloud.AttachGlobalNetworkEndpoints(negName, []*compute.NetworkEndpoint{expected}, logger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureDeleted deletes the NEG of a CR being deleted and then removes the
+// finalizer. A NEG that is still in use by a backend service cannot be
+// deleted, in which case the error is reported and deletion is retried.
+func (s *ResourceNEGSyncer) ensureDeleted(negCR *negv1beta1.ServiceNetworkEndpointGroup, key *meta.Key, logger klog.Logger) error {
+	if !common.HasGivenFinalizer(negCR.ObjectMeta, common.NegFinalizerKey) {
+		return nil
+	}
+
+	neg, err := s.cloud.GetResourceNetworkEndpointGroup(key, logger)
+	if err != nil && !utils.IsNotFoundError(err) && !utils.IsHTTPErrorCode(err, http.StatusBadRequest) {
+		return err
+	}
+	if err == nil {
+		if matches, _ := utils.VerifyDescription(s.expectedDescription(negCR), neg.Description, key.Name, key.Region); matches && neg.Description != "" {
+			logger.Info("Deleting NEG", "negName", key.Name)
+			if err := s.cloud.DeleteResourceNetworkEndpointGroup(key, logger); err != nil && !utils.IsNotFoundError(err) {
+				err = fmt.Errorf("failed to delete NEG %s: %w", key.Name, err)
+				s.recorder.Eventf(negCR, apiv1.EventTypeWarning, negtypes.NegGCError, err.Error())
+				return err
+			}
+			s.recorder.Eventf(negCR, apiv1.EventTypeNormal, "Delete", "Deleted NEG %q.", key.Name)
+		} else {
+			logger.Info("Skipping deletion of NEG not managed by this ServiceNetworkEndpointGroup", "negName", key.Name)
+		}
+	}
+
+	updatedCR := negCR.DeepCopy()
+	updatedCR.Finalizers = slices.Filter(nil, negCR.Finalizers, func(f string) bool { return f != common.NegFinalizerKey })
+	if err := s.patchMetadata(negCR, updatedCR); err != nil {
+		return err
+	}
+	logger.V(2).Info("Removed finalizer on ServiceNetworkEndpointGroup CR")
+	return nil
+}
+
+func (s *ResourceNEGSyncer) ensureFinalizer(negCR *negv1beta1.ServiceNetworkEndpointGroup, logger klog.Logger) error {
+	if common.HasGivenFinalizer(negCR.ObjectMeta, common.NegFinalizerKey) {
+		return nil
+	}
+	updatedCR := negCR.DeepCopy()
+	updatedCR.Finalizers = append(updatedCR.Finalizers, common.NegFinalizerKey)
+	if err := s.patchMetadata(negCR, updatedCR); err != nil {
+		return err
+	}
+	logger.V(2).Info("Added finalizer on ServiceNetworkEndpointGroup CR")
+	return nil
+}
+
+// patchMetadata patches the object meta of the given CR.
+func (s *ResourceNEGSyncer) patchMetadata(oldNeg, newNeg *negv1beta1.ServiceNetworkEndpointGroup) error {
+	patchBytes, err := patch.MergePatchBytes(negv1beta1.ServiceNetworkEndpointGroup{ObjectMeta: oldNeg.ObjectMeta}, negv1beta1.ServiceNetworkEndpointGroup{ObjectMeta: newNeg.ObjectMeta})
+	if err != nil {
+		return fmt.Errorf("failed to prepare patch bytes: %w", err)
+	}
+	start := time.Now()
+	_, err = s.svcNegClient.NetworkingV1beta1().ServiceNetworkEndpointGroups(oldNeg.Namespace).Patch(context.Background(), oldNeg.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	metrics.PublishK8sRequestCountMetrics(start, metrics.PatchRequest, err)
+	return err
+}
+
+// updateStatus records the NEG reference and the Initialized and Synced
+// conditions on the CR.
+func (s *ResourceNEGSyncer) updateStatus(origNeg *negv1beta1.ServiceNetworkEndpointGroup, negRef negv1beta1.NegObjectReference, syncErr error) error {
+	neg := origNeg.DeepCopy()
+	if syncErr == nil {
+		neg.Status.NetworkEndpointGroups = []negv1beta1.NegObjectReference{negRef}
+	}
+	if _, _, exists := findCondition(neg.Status.Conditions, negv1beta1.Initialized); !exists || syncErr == nil {
+		initializedCondition := getInitializedCondition(syncErr)
+		initializedCondition.ObservedGeneration = neg.Generation
+		ensureCondition(neg, initializedCondition)
+	}
+	syncedCondition := getSyncedCondition(syncErr)
+	syncedCondition.ObservedGeneration = neg.Generation
+	ensureCondition(neg, syncedCondition)
+	neg.Status.LastSyncTime = metav1.Now()
+
+	_, err := patchNegStatus(s.svcNegClient, origNeg.Status, neg.Status, neg.Namespace, neg.Name)
+	return err
+}
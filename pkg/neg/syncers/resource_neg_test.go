@@ -0,0 +1,292 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/compute/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	negv1beta1 "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1"
+	negtypes "k8s.io/ingress-gce/pkg/neg/types"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/common"
+	"k8s.io/klog/v2"
+)
+
+const testResourceNegName = "resource-neg"
+
+func newTestResourceNEGSyncer() (*ResourceNEGSyncer, *negtypes.FakeNetworkEndpointGroupCloud, *negtypes.TestContext) {
+	testContext := negtypes.NewTestContext()
+	fakeCloud := negtypes.NewFakeNetworkEndpointGroupCloud("test-subnetwork", "test-network").(*negtypes.FakeNetworkEndpointGroupCloud)
+	s := NewResourceNEGSyncer(fakeCloud, testContext.NegNamer, testContext.SvcNegClient, testContext.SvcNegInformer.GetIndexer(), record.NewFakeRecorder(100), string(kubeSystemUID), klog.TODO())
+	return s, fakeCloud, testContext
+}
+
+// addResourceNegCR creates the CR in the client and adds it to the lister.
+func addResourceNegCR(t *testing.T, testContext *negtypes.TestContext, negCR *negv1beta1.ServiceNetworkEndpointGroup) {
+	t.Helper()
+	cr, err := testContext.SvcNegClient.NetworkingV1beta1().ServiceNetworkEndpointGroups(negCR.Namespace).Create(context.Background(), negCR, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create NEG CR: %v", err)
+	}
+	if err := testContext.SvcNegInformer.GetIndexer().Add(cr); err != nil {
+		t.Fatalf("Failed to add NEG CR to lister: %v", err)
+	}
+}
+
+// refreshResourceNegCR syncs the lister with the client and returns the CR.
+func refreshResourceNegCR(t *testing.T, testContext *negtypes.TestContext) *negv1beta1.ServiceNetworkEndpointGroup {
+	t.Helper()
+	cr, err := testContext.SvcNegClient.NetworkingV1beta1().ServiceNetworkEndpointGroups(testServiceNamespace).Get(context.Background(), testResourceNegName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get NEG CR: %v", err)
+	}
+	if err := testContext.SvcNegInformer.GetIndexer().Update(cr); err != nil {
+		t.Fatalf("Failed to update NEG CR in lister: %v", err)
+	}
+	return cr
+}
+
+func TestResourceNEGSyncerSync(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		spec            negv1beta1.ServiceNetworkEndpointGroupSpec
+		expectErr       bool
+		expectRegional  bool
+		expectType      string
+		expectEndpoints []*compute.NetworkEndpoint
+	}{
+		{
+			desc: "cloud run NEG",
+			spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+				Serverless: &negv1beta1.ServerlessNEGSpec{
+					CloudRun: &negv1beta1.CloudRunNEGSpec{Service: "hello", Tag: "blue"},
+				},
+			},
+			expectRegional: true,
+			expectType:     string(negtypes.ServerlessEndpointType),
+		},
+		{
+			desc: "cloud function NEG with url mask",
+			spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+				Serverless: &negv1beta1.ServerlessNEGSpec{
+					CloudFunction: &negv1beta1.CloudFunctionNEGSpec{URLMask: "/<function>"},
+				},
+			},
+			expectRegional: true,
+			expectType:     string(negtypes.ServerlessEndpointType),
+		},
+		{
+			desc: "internet NEG with default port",
+			spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+				Internet: &negv1beta1.InternetNEGSpec{FQDN: "example.com"},
+			},
+			expectType:      string(negtypes.InternetFQDNEndpointType),
+			expectEndpoints: []*compute.NetworkEndpoint{{Fqdn: "example.com", Port: 443}},
+		},
+		{
+			desc: "invalid spec",
+			spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+				Serverless: &negv1beta1.ServerlessNEGSpec{
+					CloudRun: &negv1beta1.CloudRunNEGSpec{Service: "hello"},
+				},
+				Internet: &negv1beta1.InternetNEGSpec{FQDN: "example.com"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s, fakeCloud, testContext := newTestResourceNEGSyncer()
+			negName := testContext.NegNamer.ResourceNEG(testServiceNamespace, testResourceNegName)
+			expectKey := meta.GlobalKey(negName)
+			if tc.expectRegional {
+				expectKey = meta.RegionalKey(negName, "test-region")
+			}
+			addResourceNegCR(t, testContext, &negv1beta1.ServiceNetworkEndpointGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: testResourceNegName, Namespace: testServiceNamespace, Generation: 1},
+				Spec:       tc.spec,
+			})
+
+			err := s.Sync(testServiceNamespace, testResourceNegName)
+			if gotErr := err != nil; gotErr != tc.expectErr {
+				t.Fatalf("Sync() = %v, expectErr %v", err, tc.expectErr)
+			}
+
+			cr := refreshResourceNegCR(t, testContext)
+			if !common.HasGivenFinalizer(cr.ObjectMeta, common.NegFinalizerKey) {
+				t.Errorf("Expected finalizer %q on NEG CR, got %v", common.NegFinalizerKey, cr.Finalizers)
+			}
+			condition, _, exists := findCondition(cr.Status.Conditions, negv1beta1.Initialized)
+			if !exists {
+				t.Fatalf("Expected Initialized condition on NEG CR")
+			}
+			if condition.ObservedGeneration != 1 {
+				t.Errorf("Expected Initialized condition ObservedGeneration 1, got %d", condition.ObservedGeneration)
+			}
+
+			if tc.expectErr {
+				if condition.Status != corev1.ConditionFalse {
+					t.Errorf("Expected Initialized condition to be False, got %v", condition.Status)
+				}
+				if len(fakeCloud.ResourceNetworkEndpointGroups) != 0 {
+					t.Errorf("Expected no NEG to be created, got %v", fakeCloud.ResourceNetworkEndpointGroups)
+				}
+				return
+			}
+
+			if condition.Status != corev1.ConditionTrue {
+				t.Errorf("Expected Initialized condition to be True, got %v", condition.Status)
+			}
+			neg, ok := fakeCloud.ResourceNetworkEndpointGroups[expectKey.String()]
+			if !ok {
+				t.Fatalf("Expected NEG %s to be created, got %v", expectKey, fakeCloud.ResourceNetworkEndpointGroups)
+			}
+			if neg.NetworkEndpointType != tc.expectType {
+				t.Errorf("Expected NEG type %q, got %q", tc.expectType, neg.NetworkEndpointType)
+			}
+			if len(cr.Status.NetworkEndpointGroups) != 1 || cr.Status.NetworkEndpointGroups[0].SelfLink != neg.SelfLink {
+				t.Errorf("Expected NEG CR status to reference %q, got %+v", neg.SelfLink, cr.Status.NetworkEndpointGroups)
+			}
+			endpoints := fakeCloud.GlobalNetworkEndpoints[negName]
+			if len(endpoints) != len(tc.expectEndpoints) {
+				t.Fatalf("Expected endpoints %v, got %v", tc.expectEndpoints, endpoints)
+			}
+			for i := range endpoints {
+				if endpoints[i].Fqdn != tc.expectEndpoints[i].Fqdn || endpoints[i].Port != tc.expectEndpoints[i].Port {
+					t.Errorf("Expected endpoint %v, got %v", tc.expectEndpoints[i], endpoints[i])
+				}
+			}
+
+			// A second sync must be a no-op.
+			if err := s.Sync(testServiceNamespace, testResourceNegName); err != nil {
+				t.Errorf("Second Sync() = %v, want nil", err)
+			}
+			if got := len(fakeCloud.GlobalNetworkEndpoints[negName]); got != len(tc.expectEndpoints) {
+				t.Errorf("Expected %d endpoints after second sync, got %d", len(tc.expectEndpoints), got)
+			}
+		})
+	}
+}
+
+func TestResourceNEGSyncerIgnoresEmptySpec(t *testing.T) {
+	s, fakeCloud, testContext := newTestResourceNEGSyncer()
+	addResourceNegCR(t, testContext, &negv1beta1.ServiceNetworkEndpointGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: testResourceNegName, Namespace: testServiceNamespace},
+	})
+
+	if err := s.Sync(testServiceNamespace, testResourceNegName); err != nil {
+		t.Fatalf("Sync() = %v, want nil", err)
+	}
+	cr := refreshResourceNegCR(t, testContext)
+	if len(cr.Finalizers) != 0 || len(cr.Status.Conditions) != 0 {
+		t.Errorf("Expected NEG CR to be left untouched, got %+v", cr)
+	}
+	if len(fakeCloud.ResourceNetworkEndpointGroups) != 0 {
+		t.Errorf("Expected no NEG to be created, got %v", fakeCloud.ResourceNetworkEndpointGroups)
+	}
+}
+
+func TestResourceNEGSyncerUpdatesInternetEndpoint(t *testing.T) {
+	s, fakeCloud, testContext := newTestResourceNEGSyncer()
+	negName := testContext.NegNamer.ResourceNEG(testServiceNamespace, testResourceNegName)
+	addResourceNegCR(t, testContext, &negv1beta1.ServiceNetworkEndpointGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: testResourceNegName, Namespace: testServiceNamespace},
+		Spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+			Internet: &negv1beta1.InternetNEGSpec{FQDN: "example.com", Port: 8443},
+		},
+	})
+	fakeCloud.ResourceNetworkEndpointGroups[meta.GlobalKey(negName).String()] = &compute.NetworkEndpointGroup{
+		Name:                negName,
+		NetworkEndpointType: string(negtypes.InternetFQDNEndpointType),
+		Description:         utils.NegDescription{ClusterUID: string(kubeSystemUID), Namespace: testServiceNamespace}.String(),
+	}
+	fakeCloud.GlobalNetworkEndpoints[negName] = []*compute.NetworkEndpoint{{Fqdn: "old.example.com", Port: 443}}
+
+	if err := s.Sync(testServiceNamespace, testResourceNegName); err != nil {
+		t.Fatalf("Sync() = %v, want nil", err)
+	}
+	endpoints := fakeCloud.GlobalNetworkEndpoints[negName]
+	if len(endpoints) != 1 || endpoints[0].Fqdn != "example.com" || endpoints[0].Port != 8443 {
+		t.Errorf("Expected only endpoint example.com:8443, got %v", endpoints)
+	}
+}
+
+func TestResourceNEGSyncerRejectsForeignNEG(t *testing.T) {
+	s, fakeCloud, testContext := newTestResourceNEGSyncer()
+	negName := testContext.NegNamer.ResourceNEG(testServiceNamespace, testResourceNegName)
+	addResourceNegCR(t, testContext, &negv1beta1.ServiceNetworkEndpointGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: testResourceNegName, Namespace: testServiceNamespace},
+		Spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+			Internet: &negv1beta1.InternetNEGSpec{FQDN: "example.com"},
+		},
+	})
+	fakeCloud.ResourceNetworkEndpointGroups[meta.GlobalKey(negName).String()] = &compute.NetworkEndpointGroup{
+		Name:                negName,
+		NetworkEndpointType: string(negtypes.InternetFQDNEndpointType),
+		Description:         utils.NegDescription{ClusterUID: "another-cluster", Namespace: testServiceNamespace}.String(),
+	}
+
+	if err := s.Sync(testServiceNamespace, testResourceNegName); err == nil {
+		t.Errorf("Sync() = nil, want error for a NEG owned by another cluster")
+	}
+	if endpoints := fakeCloud.GlobalNetworkEndpoints[negName]; len(endpoints) != 0 {
+		t.Errorf("Expected endpoints of the foreign NEG to be untouched, got %v", endpoints)
+	}
+}
+
+func TestResourceNEGSyncerDelete(t *testing.T) {
+	s, fakeCloud, testContext := newTestResourceNEGSyncer()
+	negName := testContext.NegNamer.ResourceNEG(testServiceNamespace, testResourceNegName)
+	now := metav1.Now()
+	addResourceNegCR(t, testContext, &negv1beta1.ServiceNetworkEndpointGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              testResourceNegName,
+			Namespace:         testServiceNamespace,
+			Finalizers:        []string{common.NegFinalizerKey},
+			DeletionTimestamp: &now,
+		},
+		Spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+			Serverless: &negv1beta1.ServerlessNEGSpec{
+				CloudRun: &negv1beta1.CloudRunNEGSpec{Service: "hello"},
+			},
+		},
+	})
+	key := meta.RegionalKey(negName, fakeCloud.Region())
+	fakeCloud.ResourceNetworkEndpointGroups[key.String()] = &compute.NetworkEndpointGroup{
+		Name:                negName,
+		NetworkEndpointType: string(negtypes.ServerlessEndpointType),
+		Description:         utils.NegDescription{ClusterUID: string(kubeSystemUID), Namespace: testServiceNamespace}.String(),
+	}
+
+	if err := s.Sync(testServiceNamespace, testResourceNegName); err != nil {
+		t.Fatalf("Sync() = %v, want nil", err)
+	}
+	if _, ok := fakeCloud.ResourceNetworkEndpointGroups[key.String()]; ok {
+		t.Errorf("Expected NEG %s to be deleted", key)
+	}
+	cr := refreshResourceNegCR(t, testContext)
+	if common.HasGivenFinalizer(cr.ObjectMeta, common.NegFinalizerKey) {
+		t.Errorf("Expected finalizer to be removed, got %v", cr.Finalizers)
+	}
+}
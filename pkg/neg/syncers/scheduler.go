@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncers
+
+import (
+	"sync"
+
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/neg/metrics"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+// syncPriorityWeights are the shares of the sync slots granted to each NEG
+// sync priority when syncers of several priorities are waiting.
+var syncPriorityWeights = map[annotations.NEGSyncPriority]int{
+	annotations.NEGSyncPriorityHigh:   4,
+	annotations.NEGSyncPriorityNormal: 2,
+	annotations.NEGSyncPriorityLow:    1,
+}
+
+// strideBase is divided by the weight of a priority to compute its stride.
+const strideBase = 1 << 20
+
+// SyncScheduler limits the number of NEG syncs and endpoint operations running
+// concurrently and schedules the waiting syncers with weighted fair sharing
+// among the NEG sync priorities. Endpoint operations run after the sync that
+// started them returned, so they acquire slots of their own. Waiting syncers of the same priority are scheduled in FIFO order.
+// A nil SyncScheduler does not limit the syncers.
+type SyncScheduler struct {
+	lock sync.Mutex
+	// limit is the maximum number of concurrent syncs. Non-positive means unlimited.
+	limit int
+	// inUse is the number of syncs currently holding a slot.
+	inUse int
+	// queues are the syncers waiting for a slot for each priority.
+	queues map[annotations.NEGSyncPriority][]chan struct{}
+	// pass is the virtual time of each priority for stride scheduling.
+	// The waiting priority with the smallest pass is granted the next slot.
+	pass map[annotations.NEGSyncPriority]int64
+	// virtualTime is the pass of the priority granted last.
+	virtualTime int64
+
+	clock  clock.Clock
+	logger klog.Logger
+}
+
+// NewSyncScheduler returns a SyncScheduler allowing at most maxConcurrentSyncs
+// concurrent syncs. Non-positive maxConcurrentSyncs means unlimited.
+func NewSyncScheduler(maxConcurrentSyncs int, logger klog.Logger) *SyncScheduler {
+	return &SyncScheduler{
+		limit:  maxConcurrentSyncs,
+		queues: map[annotations.NEGSyncPriority][]chan struct{}{},
+		pass:   map[annotations.NEGSyncPriority]int64{},
+		clock:  clock.RealClock{},
+		logger: logger.WithName("SyncScheduler"),
+	}
+}
+
+// Acquire blocks until a sync slot is granted to a syncer of the given
+// priority. The returned function must be called to release the slot.
+func (s *SyncScheduler) Acquire(priority annotations.NEGSyncPriority) (release func()) {
+	if s == nil {
+		return func() {}
+	}
+	if _, ok := syncPriorityWeights[priority]; !ok {
+		priority = annotations.NEGSyncPriorityNormal
+	}
+	start := s.clock.Now()
+	defer func() {
+		metrics.PublishNegSyncSchedulerWaitMetrics(string(priority), s.clock.Since(start))
+	}()
+
+	s.lock.Lock()
+	if s.limit <= 0 || (s.inUse < s.limit && s.numWaiting() == 0) {
+		s.inUse++
+		s.lock.Unlock()
+		return s.release
+	}
+	if len(s.queues[priority]) == 0 && s.pass[priority] < s.virtualTime {
+		// A priority that has been idle does not accumulate credit.
+		s.pass[priority] = s.virtualTime
+	}
+	ready := make(chan struct{})
+	s.queues[priority] = append(s.queues[priority], ready)
+	s.lock.Unlock()
+
+	<-ready
+	return s.release
+}
+
+// release returns a sync slot. The slot is handed over to the next waiting
+// syncer if there is any.
+func (s *SyncScheduler) release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	next, ok := s.nextPriority()
+	if !ok {
+		s.inUse--
+		return
+	}
+	ready := s.queues[next][0]
+	s.queues[next] = s.queues[next][1:]
+	s.virtualTime = s.pass[next]
+	s.pass[next] += strideBase / int64(syncPriorityWeights[next])
+	close(ready)
+}
+
+// nextPriority returns the waiting priority with the smallest pass.
+// Assumes s.lock is held when calling this method.
+func (s *SyncScheduler) nextPriority() (annotations.NEGSyncPriority, bool) {
+	var (
+		ret   annotations.NEGSyncPriority
+		found bool
+	)
+	// Iterate in a fixed order so that ties are broken by priority.
+	for _, priority := range []annotations.NEGSyncPriority{annotations.NEGSyncPriorityHigh, annotations.NEGSyncPriorityNormal, annotations.NEGSyncPriorityLow} {
+		if len(s.queues[priority]) == 0 {
+			continue
+		}
+		if !found || s.pass[priority] < s.pass[ret] {
+			ret, found = priority, true
+		}
+	}
+	return ret, found
+}
+
+// numWaiting returns the number of syncers waiting for a slot.
+// Assumes s.lock is held when calling this method.
+func (s *SyncScheduler) numWaiting() int {
+	var ret int
+	for _, queue := range s.queues {
+		ret += len(queue)
+	}
+	return ret
+}
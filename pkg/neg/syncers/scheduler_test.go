@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/klog/v2"
+)
+
+func TestSyncSchedulerWeightedFairSharing(t *testing.T) {
+	t.Parallel()
+
+	const (
+		high   = annotations.NEGSyncPriorityHigh
+		normal = annotations.NEGSyncPriorityNormal
+		low    = annotations.NEGSyncPriorityLow
+	)
+	scheduler := NewSyncScheduler(1, klog.TODO())
+	// Hold the only slot so that all the syncers below have to wait.
+	release := scheduler.Acquire(normal)
+
+	var priorities []annotations.NEGSyncPriority
+	for _, priority := range []annotations.NEGSyncPriority{low, high, normal} {
+		for i := 0; i < 4; i++ {
+			priorities = append(priorities, priority)
+		}
+	}
+	granted := make(chan annotations.NEGSyncPriority, len(priorities))
+	for i, priority := range priorities {
+		priority := priority
+		go func() {
+			release := scheduler.Acquire(priority)
+			granted <- priority
+			release()
+		}()
+		// Wait until the syncer is queued so that all the syncers are
+		// waiting before the slot is released.
+		for {
+			scheduler.lock.Lock()
+			waiting := scheduler.numWaiting()
+			scheduler.lock.Unlock()
+			if waiting == i+1 {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	release()
+	var got []annotations.NEGSyncPriority
+	for range priorities {
+		got = append(got, <-granted)
+	}
+	// Slots are shared 4:2:1 between high, normal and low priorities while
+	// syncers of all priorities are waiting.
+	want := []annotations.NEGSyncPriority{high, normal, low, high, high, normal, high, normal, low, normal, low, low}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Got unexpected order of granted syncs (-want +got):\n%s", diff)
+	}
+	if scheduler.inUse != 0 {
+		t.Errorf("Got %d slots in use after all syncs finished, want 0", scheduler.inUse)
+	}
+}
+
+func TestSyncSchedulerUnlimited(t *testing.T) {
+	t.Parallel()
+
+	for _, scheduler := range []*SyncScheduler{nil, NewSyncScheduler(0, klog.TODO())} {
+		var releases []func()
+		for i := 0; i < 100; i++ {
+			releases = append(releases, scheduler.Acquire(annotations.NEGSyncPriorityLow))
+		}
+		for _, release := range releases {
+			release()
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/backoff"
 	"k8s.io/ingress-gce/pkg/neg/metrics"
 	negtypes "k8s.io/ingress-gce/pkg/neg/types"
@@ -58,13 +59,17 @@ type syncer struct {
 	clock   clock.Clock
 	backoff backoff.BackoffHandler
 
+	// scheduler limits the number of concurrent syncs across syncers.
+	scheduler *SyncScheduler
+
 	logger klog.Logger
 }
 
-func newSyncer(negSyncerKey negtypes.NegSyncerKey, serviceLister cache.Indexer, recorder record.EventRecorder, core syncerCore, logger klog.Logger) *syncer {
+func newSyncer(negSyncerKey negtypes.NegSyncerKey, serviceLister cache.Indexer, recorder record.EventRecorder, core syncerCore, scheduler *SyncScheduler, logger klog.Logger) *syncer {
 	return &syncer{
 		NegSyncerKey:  negSyncerKey,
 		core:          core,
+		scheduler:     scheduler,
 		serviceLister: serviceLister,
 		recorder:      recorder,
 		stopped:       true,
@@ -89,7 +94,9 @@ func (s *syncer) Start() error {
 		for {
 			// equivalent to never retry
 			retryCh := make(<-chan time.Time)
+			release := s.scheduler.Acquire(s.syncPriority())
 			err := s.core.sync()
+			release()
 			if err != nil {
 				go metrics.PublishNegControllerErrorCountMetrics(err, false)
 				delay, retryErr := time.Duration(0), error(nil)
@@ -128,6 +135,25 @@ func (s *syncer) Start() error {
 	return nil
 }
 
+// syncPriority returns the NEG sync priority of the service of the syncer.
+func (s *syncer) syncPriority() annotations.NEGSyncPriority {
+	return serviceSyncPriority(s.serviceLister, s.NegSyncerKey, s.logger)
+}
+
+// serviceSyncPriority returns the NEG sync priority of the service of the
+// given syncer key.
+func serviceSyncPriority(serviceLister cache.Indexer, negSyncerKey negtypes.NegSyncerKey, logger klog.Logger) annotations.NEGSyncPriority {
+	svc := getService(serviceLister, negSyncerKey.Namespace, negSyncerKey.Name, logger)
+	if svc == nil {
+		return annotations.NEGSyncPriorityNormal
+	}
+	priority, err := annotations.FromService(svc).NEGSyncPriority()
+	if err != nil {
+		logger.V(2).Info("Invalid NEG sync priority, using the default priority", "negSyncerKey", negSyncerKey.String(), "err", err)
+	}
+	return priority
+}
+
 func (s *syncer) init() {
 	s.stateLock.Lock()
 	defer s.stateLock.Unlock()
@@ -90,6 +90,7 @@ func newSyncerTester() *syncerTester {
 		testContext.ServiceInformer.GetIndexer(),
 		record.NewFakeRecorder(100),
 		st,
+		nil,
 		klog.TODO(),
 	)
 	st.syncer = s
@@ -123,13 +123,16 @@ type transactionSyncer struct {
 	enableDualStackNEG bool
 
 	// podLabelPropagationConfig configures the pod label to be propagated to NEG endpoints
-	podLabelPropagationConfig labels.PodLabelPropagationConfig
+	podLabelPropagationConfig *labels.ConfigStore
 
 	dsMigrator *dualstack.Migrator
 
 	// networkInfo contains the network information to use in GCP resources (VPC URL, Subnetwork URL).
 	// and the k8s network name (can be used in endpoints calculation).
 	networkInfo network.NetworkInfo
+
+	// scheduler limits the number of concurrent NEG operations across syncers.
+	scheduler *SyncScheduler
 }
 
 func NewTransactionSyncer(
@@ -149,9 +152,10 @@ func NewTransactionSyncer(
 	syncerMetrics *metricscollector.SyncerMetrics,
 	customName bool,
 	log klog.Logger,
-	lpConfig labels.PodLabelPropagationConfig,
+	lpConfig *labels.ConfigStore,
 	enableDualStackNEG bool,
 	networkInfo network.NetworkInfo,
+	scheduler *SyncScheduler,
 ) negtypes.NegSyncer {
 
 	logger := log.WithName("Syncer").WithValues("service", klog.KRef(negSyncerKey.Namespace, negSyncerKey.Name), "negName", negSyncerKey.NegName)
@@ -182,9 +186,10 @@ func NewTransactionSyncer(
 		enableDualStackNEG:        enableDualStackNEG,
 		podLabelPropagationConfig: lpConfig,
 		networkInfo:               networkInfo,
+		scheduler:                 scheduler,
 	}
 	// Syncer implements life cycle logic
-	syncer := newSyncer(negSyncerKey, serviceLister, recorder, ts, logger)
+	syncer := newSyncer(negSyncerKey, serviceLister, recorder, ts, scheduler, logger)
 	// transactionSyncer needs syncer interface for internals
 	ts.syncer = syncer
 	ts.retry = backoff.NewDelayRetryHandler(func() { syncer.Sync() }, backoff.NewExponentialBackoffHandler(maxRetries, minRetryDelay, maxRetryDelay))
@@ -356,7 +361,7 @@ func (s *transactionSyncer) syncInternalImpl() error {
 	var endpointPodLabelMap labels.EndpointPodLabelMap
 	// Only fetch label from pod for L7 endpoints
 	if flags.F.EnableNEGLabelPropagation && s.NegType == negtypes.VmIpPortEndpointType {
-		endpointPodLabelMap = getEndpointPodLabelMap(addEndpoints, endpointPodMap, s.podLister, s.podLabelPropagationConfig, s.recorder, s.logger)
+		endpointPodLabelMap = getEndpointPodLabelMap(addEndpoints, endpointPodMap, s.podLister, s.podLabelPropagationConfig.Get(), s.recorder, s.logger)
 		publishAnnotationSizeMetrics(addEndpoints, endpointPodLabelMap)
 	}
 
@@ -554,12 +559,16 @@ func (s *transactionSyncer) operationInternal(operation transactionOp, zone stri
 		networkEndpoints = append(networkEndpoints, ne)
 	}
 
+	// The operation runs after the sync returned, so it needs its own slot to
+	// be limited by the scheduler.
+	release := s.scheduler.Acquire(serviceSyncPriority(s.serviceLister, s.NegSyncerKey, s.logger))
 	if operation == attachOp {
 		err = s.cloud.AttachNetworkEndpoints(s.NegSyncerKey.NegName, zone, networkEndpoints, s.NegSyncerKey.GetAPIVersion(), logger)
 	}
 	if operation == detachOp {
 		err = s.cloud.DetachNetworkEndpoints(s.NegSyncerKey.NegName, zone, networkEndpoints, s.NegSyncerKey.GetAPIVersion(), logger)
 	}
+	release()
 
 	if err == nil {
 		s.recordEvent(apiv1.EventTypeNormal, operation.String(), fmt.Sprintf("%s %d network endpoint(s) (NEG %q in zone %q)", operation.String(), len(networkEndpointMap), s.NegSyncerKey.NegName, zone))
@@ -1013,7 +1022,7 @@ func findCondition(conditions []negv1beta1.Condition, conditionType string) (neg
 // getEndpointPodLabelMap goes through all the endpoints to be attached and fetches the labels from the endpoint pods.
 func getEndpointPodLabelMap(endpoints map[string]negtypes.NetworkEndpointSet, endpointPodMap negtypes.EndpointPodMap, podLister cache.Store, lpConfig labels.PodLabelPropagationConfig, recorder record.EventRecorder, logger klog.Logger) labels.EndpointPodLabelMap {
 	endpointPodLabelMap := labels.EndpointPodLabelMap{}
-	for _, endpointSet := range endpoints {
+	for zone, endpointSet := range endpoints {
 		for endpoint := range endpointSet {
 			key := fmt.Sprintf("%s/%s", endpointPodMap[endpoint].Namespace, endpointPodMap[endpoint].Name)
 			obj, ok, err := podLister.GetByKey(key)
@@ -1029,7 +1038,7 @@ func getEndpointPodLabelMap(endpoints map[string]negtypes.NetworkEndpointSet, en
 				logger.Error(nil, "expected type *v1.Pod", "pod", key, "type", fmt.Sprintf("%T", obj))
 				continue
 			}
-			labelMap, err := labels.GetPodLabelMap(pod, lpConfig)
+			labelMap, err := labels.GetPodLabelMap(pod, zone, lpConfig)
 			if err != nil {
 				recorder.Eventf(pod, apiv1.EventTypeWarning, "LabelsExceededLimit", "Label Propagation Error: %v", err)
 				metrics.PublishNegControllerErrorCountMetrics(err, true)
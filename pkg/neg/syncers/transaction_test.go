@@ -42,6 +42,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/annotations"
 	negv1beta1 "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1"
 	"k8s.io/ingress-gce/pkg/composite"
 	"k8s.io/ingress-gce/pkg/flags"
@@ -250,6 +251,43 @@ func TestTransactionSyncNetworkEndpoints(t *testing.T) {
 	}
 }
 
+func TestTransactionSyncerOperationsHoldSchedulerSlot(t *testing.T) {
+	t.Parallel()
+
+	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+	negtypes.MockNetworkEndpointAPIs(fakeGCE)
+	fakeCloud := negtypes.NewAdapter(fakeGCE)
+	_, transactionSyncer := newTestTransactionSyncer(fakeCloud, negtypes.VmIpPortEndpointType, false)
+	transactionSyncer.scheduler = NewSyncScheduler(1, klog.TODO())
+	if err := transactionSyncer.ensureNetworkEndpointGroups(); err != nil {
+		t.Fatalf("ensureNetworkEndpointGroups() = %v, want nil", err)
+	}
+
+	// Hold the only slot, as a sync of another syncer would.
+	release := transactionSyncer.scheduler.Acquire(annotations.NEGSyncPriorityNormal)
+	addEndpoints := map[string]negtypes.NetworkEndpointSet{
+		testZone1: generateEndpointSet(net.ParseIP("1.1.1.1"), 10, testInstance1, "8080"),
+	}
+	if err := transactionSyncer.syncNetworkEndpoints(addEndpoints, nil, labels.EndpointPodLabelMap{}, ""); err != nil {
+		t.Fatalf("syncNetworkEndpoints() = %v, want nil", err)
+	}
+
+	if err := waitForTransactions(transactionSyncer); err == nil {
+		t.Errorf("Endpoints attached while the scheduler slot was held by another sync")
+	}
+	release()
+	if err := waitForTransactions(transactionSyncer); err != nil {
+		t.Fatalf("waitForTransactions() = %v, want nil", err)
+	}
+	list, err := fakeCloud.ListNetworkEndpoints(transactionSyncer.NegSyncerKey.NegName, testZone1, false, transactionSyncer.NegSyncerKey.GetAPIVersion(), klog.TODO())
+	if err != nil {
+		t.Fatalf("ListNetworkEndpoints() = %v, want nil", err)
+	}
+	if len(list) != 10 {
+		t.Errorf("Got %d endpoints attached, want 10", len(list))
+	}
+}
+
 func TestSyncNetworkEndpointLabel(t *testing.T) {
 
 	var (
@@ -2428,6 +2466,7 @@ func newTestTransactionSyncer(fakeGCE negtypes.NetworkEndpointGroupCloud, negTyp
 		},
 		NegName: testNegName,
 	}
+	lpConfigStore, _ := labels.NewConfigStore(labels.PodLabelPropagationConfig{}, klog.TODO())
 
 	var mode negtypes.EndpointsCalculatorMode
 	if negType == negtypes.VmIpEndpointType {
@@ -2462,9 +2501,10 @@ func newTestTransactionSyncer(fakeGCE negtypes.NetworkEndpointGroupCloud, negTyp
 		metricscollector.FakeSyncerMetrics(),
 		customName,
 		klog.TODO(),
-		labels.PodLabelPropagationConfig{},
+		lpConfigStore,
 		testContext.EnableDualStackNEG,
 		network.NetworkInfo{NetworkURL: fakeGCE.NetworkURL(), SubnetworkURL: fakeGCE.SubnetworkURL()},
+		nil,
 	)
 	transactionSyncer := negsyncer.(*syncer).core.(*transactionSyncer)
 	indexers := map[string]cache.IndexFunc{
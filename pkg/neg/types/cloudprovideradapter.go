@@ -21,6 +21,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	compute "google.golang.org/api/compute/v1"
 	"k8s.io/cloud-provider-gcp/providers/gce"
@@ -164,6 +166,106 @@ func (a *cloudProviderAdapter) ListNetworkEndpoints(name, zone string, showHealt
 	return networkEndpoints, err
 }
 
+// GetResourceNetworkEndpointGroup implements NetworkEndpointGroupCloud.
+func (a *cloudProviderAdapter) GetResourceNetworkEndpointGroup(key *meta.Key, logger klog.Logger) (*compute.NetworkEndpointGroup, error) {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	start := time.Now()
+	var neg *compute.NetworkEndpointGroup
+	var err error
+	switch key.Type() {
+	case meta.Regional:
+		neg, err = a.c.Compute().RegionNetworkEndpointGroups().Get(ctx, key)
+	case meta.Global:
+		neg, err = a.c.Compute().GlobalNetworkEndpointGroups().Get(ctx, key)
+	default:
+		return nil, fmt.Errorf("key %v is not valid for a regional or global NetworkEndpointGroup", key)
+	}
+	metrics.PublishGCERequestCountMetrics(start, metrics.GetRequest, err)
+	return neg, err
+}
+
+// CreateResourceNetworkEndpointGroup implements NetworkEndpointGroupCloud.
+func (a *cloudProviderAdapter) CreateResourceNetworkEndpointGroup(neg *compute.NetworkEndpointGroup, key *meta.Key, logger klog.Logger) error {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	start := time.Now()
+	var err error
+	switch key.Type() {
+	case meta.Regional:
+		logger.Info("Creating regional NetworkEndpointGroup", "name", key.Name, "region", key.Region)
+		err = a.c.Compute().RegionNetworkEndpointGroups().Insert(ctx, key, neg)
+	case meta.Global:
+		logger.Info("Creating global NetworkEndpointGroup", "name", key.Name)
+		err = a.c.Compute().GlobalNetworkEndpointGroups().Insert(ctx, key, neg)
+	default:
+		return fmt.Errorf("key %v is not valid for a regional or global NetworkEndpointGroup", key)
+	}
+	metrics.PublishGCERequestCountMetrics(start, metrics.CreateRequest, err)
+	return err
+}
+
+// DeleteResourceNetworkEndpointGroup implements NetworkEndpointGroupCloud.
+func (a *cloudProviderAdapter) DeleteResourceNetworkEndpointGroup(key *meta.Key, logger klog.Logger) error {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	start := time.Now()
+	var err error
+	switch key.Type() {
+	case meta.Regional:
+		logger.Info("Deleting regional NetworkEndpointGroup", "name", key.Name, "region", key.Region)
+		err = a.c.Compute().RegionNetworkEndpointGroups().Delete(ctx, key)
+	case meta.Global:
+		logger.Info("Deleting global NetworkEndpointGroup", "name", key.Name)
+		err = a.c.Compute().GlobalNetworkEndpointGroups().Delete(ctx, key)
+	default:
+		return fmt.Errorf("key %v is not valid for a regional or global NetworkEndpointGroup", key)
+	}
+	metrics.PublishGCERequestCountMetrics(start, metrics.DeleteRequest, err)
+	return err
+}
+
+// ListGlobalNetworkEndpoints implements NetworkEndpointGroupCloud.
+func (a *cloudProviderAdapter) ListGlobalNetworkEndpoints(name string, logger klog.Logger) ([]*compute.NetworkEndpoint, error) {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	start := time.Now()
+	endpointsWithHealth, err := a.c.Compute().GlobalNetworkEndpointGroups().ListNetworkEndpoints(ctx, meta.GlobalKey(name), filter.None)
+	metrics.PublishGCERequestCountMetrics(start, metrics.ListNERequest, err)
+	if err != nil {
+		return nil, err
+	}
+	var endpoints []*compute.NetworkEndpoint
+	for _, ep := range endpointsWithHealth {
+		if ep.NetworkEndpoint != nil {
+			endpoints = append(endpoints, ep.NetworkEndpoint)
+		}
+	}
+	return endpoints, nil
+}
+
+// AttachGlobalNetworkEndpoints implements NetworkEndpointGroupCloud.
+func (a *cloudProviderAdapter) AttachGlobalNetworkEndpoints(name string, endpoints []*compute.NetworkEndpoint, logger klog.Logger) error {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	req := &compute.GlobalNetworkEndpointGroupsAttachEndpointsRequest{NetworkEndpoints: endpoints}
+	start := time.Now()
+	err := a.c.Compute().GlobalNetworkEndpointGroups().AttachNetworkEndpoints(ctx, meta.GlobalKey(name), req)
+	metrics.PublishGCERequestCountMetrics(start, metrics.AttachNERequest, err)
+	return err
+}
+
+// DetachGlobalNetworkEndpoints implements NetworkEndpointGroupCloud.
+func (a *cloudProviderAdapter) DetachGlobalNetworkEndpoints(name string, endpoints []*compute.NetworkEndpoint, logger klog.Logger) error {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	req := &compute.GlobalNetworkEndpointGroupsDetachEndpointsRequest{NetworkEndpoints: endpoints}
+	start := time.Now()
+	err := a.c.Compute().GlobalNetworkEndpointGroups().DetachNetworkEndpoints(ctx, meta.GlobalKey(name), req)
+	metrics.PublishGCERequestCountMetrics(start, metrics.DetachNERequest, err)
+	return err
+}
+
 // NetworkURL implements NetworkEndpointGroupCloud.
 func (a *cloudProviderAdapter) NetworkURL() string {
 	return a.networkURL
@@ -66,18 +66,23 @@ const (
 type FakeNetworkEndpointGroupCloud struct {
 	NetworkEndpointGroups map[string][]*composite.NetworkEndpointGroup
 	NetworkEndpoints      map[string][]*composite.NetworkEndpoint
-	Subnetwork            string
-	Network               string
-	mu                    sync.Mutex
+	// ResourceNetworkEndpointGroups holds regional and global NEGs keyed by meta.Key.String().
+	ResourceNetworkEndpointGroups map[string]*compute.NetworkEndpointGroup
+	GlobalNetworkEndpoints        map[string][]*compute.NetworkEndpoint
+	Subnetwork                    string
+	Network                       string
+	mu                            sync.Mutex
 }
 
 // DEPRECATED: Please do not use this mock function. Use the pkg/neg/types/mock.go instead.
 func NewFakeNetworkEndpointGroupCloud(subnetwork, network string) NetworkEndpointGroupCloud {
 	return &FakeNetworkEndpointGroupCloud{
-		Subnetwork:            subnetwork,
-		Network:               network,
-		NetworkEndpointGroups: map[string][]*composite.NetworkEndpointGroup{},
-		NetworkEndpoints:      map[string][]*composite.NetworkEndpoint{},
+		Subnetwork:                    subnetwork,
+		Network:                       network,
+		NetworkEndpointGroups:         map[string][]*composite.NetworkEndpointGroup{},
+		NetworkEndpoints:              map[string][]*composite.NetworkEndpoint{},
+		ResourceNetworkEndpointGroups: map[string]*compute.NetworkEndpointGroup{},
+		GlobalNetworkEndpoints:        map[string][]*compute.NetworkEndpoint{},
 	}
 }
 
@@ -192,6 +197,68 @@ func (f *FakeNetworkEndpointGroupCloud) ListNetworkEndpoints(name, zone string,
 	return ret, nil
 }
 
+func (f *FakeNetworkEndpointGroupCloud) GetResourceNetworkEndpointGroup(key *meta.Key, _ klog.Logger) (*compute.NetworkEndpointGroup, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	neg, ok := f.ResourceNetworkEndpointGroups[key.String()]
+	if !ok {
+		return nil, test.FakeGoogleAPINotFoundErr()
+	}
+	return neg, nil
+}
+
+func (f *FakeNetworkEndpointGroupCloud) CreateResourceNetworkEndpointGroup(neg *compute.NetworkEndpointGroup, key *meta.Key, _ klog.Logger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	neg.SelfLink = cloud.SelfLink(meta.VersionGA, "mock-project", "networkEndpointGroups", key)
+	f.ResourceNetworkEndpointGroups[key.String()] = neg
+	return nil
+}
+
+func (f *FakeNetworkEndpointGroupCloud) DeleteResourceNetworkEndpointGroup(key *meta.Key, _ klog.Logger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.ResourceNetworkEndpointGroups[key.String()]; !ok {
+		return test.FakeGoogleAPINotFoundErr()
+	}
+	delete(f.ResourceNetworkEndpointGroups, key.String())
+	delete(f.GlobalNetworkEndpoints, key.Name)
+	return nil
+}
+
+func (f *FakeNetworkEndpointGroupCloud) ListGlobalNetworkEndpoints(name string, _ klog.Logger) ([]*compute.NetworkEndpoint, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.GlobalNetworkEndpoints[name], nil
+}
+
+func (f *FakeNetworkEndpointGroupCloud) AttachGlobalNetworkEndpoints(name string, endpoints []*compute.NetworkEndpoint, _ klog.Logger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.GlobalNetworkEndpoints[name] = append(f.GlobalNetworkEndpoints[name], endpoints...)
+	return nil
+}
+
+func (f *FakeNetworkEndpointGroupCloud) DetachGlobalNetworkEndpoints(name string, endpoints []*compute.NetworkEndpoint, _ klog.Logger) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	newList := []*compute.NetworkEndpoint{}
+	for _, ne := range f.GlobalNetworkEndpoints[name] {
+		found := false
+		for _, remove := range endpoints {
+			if reflect.DeepEqual(*ne, *remove) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			newList = append(newList, ne)
+		}
+	}
+	f.GlobalNetworkEndpoints[name] = newList
+	return nil
+}
+
 func (f *FakeNetworkEndpointGroupCloud) NetworkURL() string {
 	return f.Network
 }
@@ -33,6 +33,15 @@ type NetworkEndpointGroupCloud interface {
 	AttachNetworkEndpoints(name, zone string, endpoints []*composite.NetworkEndpoint, version meta.Version, logger klog.Logger) error
 	DetachNetworkEndpoints(name, zone string, endpoints []*composite.NetworkEndpoint, version meta.Version, logger klog.Logger) error
 	ListNetworkEndpoints(name, zone string, showHealthStatus bool, version meta.Version, logger klog.Logger) ([]*composite.NetworkEndpointWithHealthStatus, error)
+	// The methods below manage the regional (SERVERLESS) and global
+	// (INTERNET_FQDN_PORT) NEGs declared through ServiceNetworkEndpointGroup
+	// resources. The scope of the NEG is determined by the key.
+	GetResourceNetworkEndpointGroup(key *meta.Key, logger klog.Logger) (*compute.NetworkEndpointGroup, error)
+	CreateResourceNetworkEndpointGroup(neg *compute.NetworkEndpointGroup, key *meta.Key, logger klog.Logger) error
+	DeleteResourceNetworkEndpointGroup(key *meta.Key, logger klog.Logger) error
+	ListGlobalNetworkEndpoints(name string, logger klog.Logger) ([]*compute.NetworkEndpoint, error)
+	AttachGlobalNetworkEndpoints(name string, endpoints []*compute.NetworkEndpoint, logger klog.Logger) error
+	DetachGlobalNetworkEndpoints(name string, endpoints []*compute.NetworkEndpoint, logger klog.Logger) error
 	NetworkURL() string
 	SubnetworkURL() string
 	NetworkProjectID() string
@@ -43,6 +52,7 @@ type NetworkEndpointGroupCloud interface {
 // NetworkEndpointGroupNamer is an interface for generating network endpoint group name.
 type NetworkEndpointGroupNamer interface {
 	NEG(namespace, name string, port int32) string
+	ResourceNEG(namespace, name string) string
 	IsNEG(name string) bool
 }
 
@@ -72,6 +82,8 @@ type NegSyncerManager interface {
 	Sync(namespace, name string)
 	// SyncNodes signals all syncers watching nodes to sync. This call is asynchronous.
 	SyncNodes()
+	// SyncResourceNEG syncs the NEG declared in the spec of the ServiceNetworkEndpointGroup.
+	SyncResourceNEG(namespace, name string) error
 	// GC garbage collects network endpoint group and syncers
 	GC() error
 	// ShutDown shuts down the manager
@@ -43,10 +43,17 @@ const (
 	VmIpPortEndpointType      = NetworkEndpointType("GCE_VM_IP_PORT")
 	VmIpEndpointType          = NetworkEndpointType("GCE_VM_IP")
 	NonGCPPrivateEndpointType = NetworkEndpointType("NON_GCP_PRIVATE_IP_PORT")
+	ServerlessEndpointType    = NetworkEndpointType("SERVERLESS")
+	InternetFQDNEndpointType  = NetworkEndpointType("INTERNET_FQDN_PORT")
 	L7Mode                    = EndpointsCalculatorMode("L7")
 	L4LocalMode               = EndpointsCalculatorMode("L4, ExternalTrafficPolicy:Local")
 	L4ClusterMode             = EndpointsCalculatorMode("L4, ExternalTrafficPolicy:Cluster")
 
+	// DefaultInternetNEGPort is the port used for the endpoint of an
+	// INTERNET_FQDN_PORT NEG when the ServiceNetworkEndpointGroup does not
+	// specify one.
+	DefaultInternetNEGPort = 443
+
 	// These keys are to be used as label keys for NEG CRs when enabled
 
 	NegCRManagedByKey   = "networking.gke.io/managed-by"
@@ -63,8 +70,60 @@ const (
 
 	// NEG CRD Enabled Garbage Collection Event Reasons
 	NegGCError = "NegCRError"
+
+	// NegReadinessAnnotationInvalid is the event reason when the NEG readiness
+	// annotation of a Service is invalid.
+	NegReadinessAnnotationInvalid = "NegReadinessAnnotationInvalid"
 )
 
+// ResourceNEGType returns the type of the NEG declared in the spec of the given
+// ServiceNetworkEndpointGroup. An empty type is returned if the spec is empty,
+// which means the NEG CR is managed by the NEG controller on behalf of a Service.
+func ResourceNEGType(negCR *negv1beta1.ServiceNetworkEndpointGroup) NetworkEndpointType {
+	switch {
+	case negCR.Spec.Serverless != nil:
+		return ServerlessEndpointType
+	case negCR.Spec.Internet != nil:
+		return InternetFQDNEndpointType
+	default:
+		return ""
+	}
+}
+
+// ValidateResourceNEGSpec validates the NEG declared in the spec of a
+// ServiceNetworkEndpointGroup.
+func ValidateResourceNEGSpec(spec negv1beta1.ServiceNetworkEndpointGroupSpec) error {
+	if spec.Serverless != nil && spec.Internet != nil {
+		return fmt.Errorf("only one of serverless and internet can be specified")
+	}
+	if spec.Serverless != nil {
+		cloudRun, cloudFunction := spec.Serverless.CloudRun, spec.Serverless.CloudFunction
+		switch {
+		case cloudRun != nil && cloudFunction != nil:
+			return fmt.Errorf("only one of serverless.cloudRun and serverless.cloudFunction can be specified")
+		case cloudRun != nil:
+			if cloudRun.Service == "" && cloudRun.URLMask == "" {
+				return fmt.Errorf("one of serverless.cloudRun.service and serverless.cloudRun.urlMask must be specified")
+			}
+		case cloudFunction != nil:
+			if cloudFunction.Function == "" && cloudFunction.URLMask == "" {
+				return fmt.Errorf("one of serverless.cloudFunction.function and serverless.cloudFunction.urlMask must be specified")
+			}
+		default:
+			return fmt.Errorf("one of serverless.cloudRun and serverless.cloudFunction must be specified")
+		}
+	}
+	if spec.Internet != nil {
+		if spec.Internet.FQDN == "" {
+			return fmt.Errorf("internet.fqdn must be specified")
+		}
+		if spec.Internet.Port < 0 || spec.Internet.Port > 65535 {
+			return fmt.Errorf("internet.port %d is out of range", spec.Internet.Port)
+		}
+	}
+	return nil
+}
+
 // SvcPortTuple is the tuple representing one service port
 type SvcPortTuple struct {
 	// Port is the service port number
@@ -26,6 +26,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/ingress-gce/pkg/annotations"
+	negv1beta1 "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1"
 	"k8s.io/ingress-gce/pkg/network"
 	"k8s.io/ingress-gce/pkg/utils/zonegetter"
 	"k8s.io/klog/v2"
@@ -39,6 +40,10 @@ func (*negNamer) NEG(namespace, name string, svcPort int32) string {
 	return fmt.Sprintf("%v-%v-%v", namespace, name, svcPort)
 }
 
+func (*negNamer) ResourceNEG(namespace, name string) string {
+	return fmt.Sprintf("%v-%v", namespace, name)
+}
+
 func (*negNamer) IsNEG(name string) bool {
 	return false
 }
@@ -766,6 +771,104 @@ func TestNodePredicateForEndpointCalculatorMode(t *testing.T) {
 	}
 }
 
+func TestValidateResourceNEGSpec(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		spec       negv1beta1.ServiceNetworkEndpointGroupSpec
+		expectType NetworkEndpointType
+		expectErr  bool
+	}{
+		{
+			desc: "empty spec",
+		},
+		{
+			desc: "cloud run service",
+			spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+				Serverless: &negv1beta1.ServerlessNEGSpec{CloudRun: &negv1beta1.CloudRunNEGSpec{Service: "hello"}},
+			},
+			expectType: ServerlessEndpointType,
+		},
+		{
+			desc: "cloud run url mask",
+			spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+				Serverless: &negv1beta1.ServerlessNEGSpec{CloudRun: &negv1beta1.CloudRunNEGSpec{URLMask: "<service>.example.com"}},
+			},
+			expectType: ServerlessEndpointType,
+		},
+		{
+			desc: "cloud run without service or url mask",
+			spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+				Serverless: &negv1beta1.ServerlessNEGSpec{CloudRun: &negv1beta1.CloudRunNEGSpec{Tag: "blue"}},
+			},
+			expectType: ServerlessEndpointType,
+			expectErr:  true,
+		},
+		{
+			desc: "cloud function",
+			spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+				Serverless: &negv1beta1.ServerlessNEGSpec{CloudFunction: &negv1beta1.CloudFunctionNEGSpec{Function: "hello"}},
+			},
+			expectType: ServerlessEndpointType,
+		},
+		{
+			desc: "both cloud run and cloud function",
+			spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+				Serverless: &negv1beta1.ServerlessNEGSpec{
+					CloudRun:      &negv1beta1.CloudRunNEGSpec{Service: "hello"},
+					CloudFunction: &negv1beta1.CloudFunctionNEGSpec{Function: "hello"},
+				},
+			},
+			expectType: ServerlessEndpointType,
+			expectErr:  true,
+		},
+		{
+			desc:       "serverless without target",
+			spec:       negv1beta1.ServiceNetworkEndpointGroupSpec{Serverless: &negv1beta1.ServerlessNEGSpec{}},
+			expectType: ServerlessEndpointType,
+			expectErr:  true,
+		},
+		{
+			desc:       "internet",
+			spec:       negv1beta1.ServiceNetworkEndpointGroupSpec{Internet: &negv1beta1.InternetNEGSpec{FQDN: "example.com", Port: 8443}},
+			expectType: InternetFQDNEndpointType,
+		},
+		{
+			desc:       "internet without fqdn",
+			spec:       negv1beta1.ServiceNetworkEndpointGroupSpec{Internet: &negv1beta1.InternetNEGSpec{Port: 443}},
+			expectType: InternetFQDNEndpointType,
+			expectErr:  true,
+		},
+		{
+			desc:       "internet with invalid port",
+			spec:       negv1beta1.ServiceNetworkEndpointGroupSpec{Internet: &negv1beta1.InternetNEGSpec{FQDN: "example.com", Port: 70000}},
+			expectType: InternetFQDNEndpointType,
+			expectErr:  true,
+		},
+		{
+			desc: "both serverless and internet",
+			spec: negv1beta1.ServiceNetworkEndpointGroupSpec{
+				Serverless: &negv1beta1.ServerlessNEGSpec{CloudRun: &negv1beta1.CloudRunNEGSpec{Service: "hello"}},
+				Internet:   &negv1beta1.InternetNEGSpec{FQDN: "example.com"},
+			},
+			expectType: ServerlessEndpointType,
+			expectErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := ValidateResourceNEGSpec(tc.spec)
+			if gotErr := err != nil; gotErr != tc.expectErr {
+				t.Errorf("ValidateResourceNEGSpec() = %v, expectErr %v", err, tc.expectErr)
+			}
+			negType := ResourceNEGType(&negv1beta1.ServiceNetworkEndpointGroup{Spec: tc.spec})
+			if negType != tc.expectType {
+				t.Errorf("ResourceNEGType() = %q, want %q", negType, tc.expectType)
+			}
+		})
+	}
+}
+
 func ValidatePortData(portData PortData, port int32, name string, t *testing.T) {
 	if portData.Port != port {
 		t.Errorf("Invalid port number, got %d expected %d", portData.Port, port)
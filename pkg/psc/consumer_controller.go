@@ -0,0 +1,620 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package psc
+
+import (
+	context2 "context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	ga "google.golang.org/api/compute/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/cloud-provider-gcp/providers/gce"
+	sav1 "k8s.io/ingress-gce/pkg/apis/serviceattachment/v1"
+	"k8s.io/ingress-gce/pkg/context"
+	serviceattachmentclient "k8s.io/ingress-gce/pkg/serviceattachment/client/clientset/versioned"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/common"
+	"k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/ingress-gce/pkg/utils/patch"
+	"k8s.io/ingress-gce/pkg/utils/slice"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// SvcAttachmentConsumerGCError is the ServiceAttachmentConsumer GC error event reason
+	SvcAttachmentConsumerGCError = "ServiceAttachmentConsumerGCError"
+	// pscConsumerManagedByValue is the value of the managed-by label of the EndpointSlices
+	// created for ServiceAttachmentConsumers
+	pscConsumerManagedByValue = "psc-consumer-controller.networking.gke.io"
+	// serviceAttachmentsResource is the GCE resource type of Service Attachments
+	serviceAttachmentsResource = "serviceAttachments"
+)
+
+var (
+	// InvalidServiceAttachmentURLError is returned when the service attachment url of the spec is not
+	// the URL of a GCE Service Attachment.
+	InvalidServiceAttachmentURLError = errors.New("invalid service attachment url")
+	// ConsumerServiceConflictError is returned when the Service of a ServiceAttachmentConsumer
+	// already exists and is not managed by it.
+	ConsumerServiceConflictError = errors.New("service exists and is not managed by the ServiceAttachmentConsumer")
+)
+
+// ConsumerController is the private service connect (psc) consumer controller
+// It watches ServiceAttachmentConsumer resources and creates, deletes, and manages
+// the corresponding PSC endpoints: the GCE Address and consumer Forwarding Rule
+// connecting to the Service Attachment, and the headless Service and EndpointSlice
+// exposing the endpoint in the cluster
+type ConsumerController struct {
+	cloud         *gce.Cloud
+	saClient      serviceattachmentclient.Interface
+	kubeClient    kubernetes.Interface
+	consumerQueue workqueue.RateLimitingInterface
+
+	saNamer        namer.ServiceAttachmentNamer
+	consumerLister cache.Indexer
+	recorder       func(string) record.EventRecorder
+
+	hasSynced func() bool
+
+	stopCh <-chan struct{}
+
+	logger klog.Logger
+}
+
+func NewConsumerController(ctx *context.ControllerContext, stopCh <-chan struct{}, logger klog.Logger) *ConsumerController {
+	logger = logger.WithName("PSCConsumerController")
+	controller := &ConsumerController{
+		cloud:          ctx.Cloud,
+		saClient:       ctx.SAClient,
+		kubeClient:     ctx.KubeClient,
+		consumerQueue:  workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		saNamer:        namer.NewServiceAttachmentNamer(ctx.ClusterNamer, string(ctx.KubeSystemUID)),
+		consumerLister: ctx.SAConsumerInformer.GetIndexer(),
+		recorder:       ctx.Recorder,
+		hasSynced:      ctx.HasSynced,
+		stopCh:         stopCh,
+		logger:         logger,
+	}
+
+	ctx.SAConsumerInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueConsumer,
+		UpdateFunc: func(old, cur interface{}) {
+			curConsumer := cur.(*sav1.ServiceAttachmentConsumer)
+			oldConsumer := old.(*sav1.ServiceAttachmentConsumer)
+
+			if !shouldProcessConsumer(oldConsumer, curConsumer, logger) {
+				return
+			}
+			controller.enqueueConsumer(cur)
+		},
+	})
+	return controller
+}
+
+// Run waits for the initial sync and will process keys in the queue until signaled
+func (c *ConsumerController) Run() {
+	wait.PollUntil(5*time.Second, func() (bool, error) {
+		c.logger.V(2).Info("Waiting for initial sync")
+		return c.hasSynced(), nil
+	}, c.stopCh)
+
+	c.logger.V(2).Info("Starting private service connect consumer controller")
+	defer func() {
+		c.logger.V(2).Info("Shutting down private service connect consumer controller")
+		c.consumerQueue.ShutDown()
+	}()
+
+	go wait.Until(func() { c.consumerWorker(c.stopCh) }, time.Second, c.stopCh)
+
+	<-c.stopCh
+}
+
+// consumerWorker keeps processing ServiceAttachmentConsumer keys in the queue
+// until stopChan has been signaled
+func (c *ConsumerController) consumerWorker(stopChan <-chan struct{}) {
+	processKey := func() {
+		key, quit := c.consumerQueue.Get()
+		if quit {
+			return
+		}
+		defer c.consumerQueue.Done(key)
+		err := c.processConsumer(key.(string))
+		c.handleErr(err, key)
+	}
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		default:
+			processKey()
+		}
+	}
+}
+
+// handleErr will check for an error and report it as an event on the provided
+// ServiceAttachmentConsumer cr
+func (c *ConsumerController) handleErr(err error, key interface{}) {
+	if err == nil {
+		c.consumerQueue.Forget(key)
+		return
+	}
+	eventMsg := fmt.Sprintf("error processing service attachment consumer %q: %q", key, err)
+	c.logger.Error(err, eventMsg)
+	if obj, exists, err := c.consumerLister.GetByKey(key.(string)); err != nil {
+		c.logger.Info("failed to retrieve service attachment consumer from the store", "consumerKey", key.(string), "err", err)
+	} else if exists {
+		consumer := obj.(*sav1.ServiceAttachmentConsumer)
+		c.recorder(consumer.Namespace).Eventf(consumer, v1.EventTypeWarning, "ProcessServiceAttachmentConsumerFailed", eventMsg)
+	}
+	c.consumerQueue.AddRateLimited(key)
+}
+
+// enqueueConsumer adds the ServiceAttachmentConsumer object to the queue
+func (c *ConsumerController) enqueueConsumer(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		c.logger.Error(err, "Failed to generate service attachment consumer key")
+		return
+	}
+	c.consumerQueue.Add(key)
+}
+
+// processConsumer will process a ServiceAttachmentConsumer key and will create and update
+// the GCE Address and consumer Forwarding Rule of the PSC endpoint, and the headless Service
+// and EndpointSlice if requested. ServiceAttachmentConsumers marked for deletion have their
+// resources deleted and their finalizer removed.
+func (c *ConsumerController) processConsumer(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	obj, exists, err := c.consumerLister.GetByKey(key)
+	if err != nil {
+		return fmt.Errorf("errored getting service attachment consumer from store: %w", err)
+	}
+	if !exists {
+		c.logger.V(2).Info("Service attachment consumer does not exist in store", "consumerKey", klog.KRef(namespace, name))
+		return nil
+	}
+	consumer := obj.(*sav1.ServiceAttachmentConsumer)
+
+	if !consumer.GetDeletionTimestamp().IsZero() {
+		if !common.IsDeletionCandidateForGivenFinalizer(consumer.ObjectMeta, common.PSCConsumerFinalizerKey) {
+			return nil
+		}
+		return c.deleteConsumer(consumer)
+	}
+
+	c.logger.V(2).Info("Processing service attachment consumer", "consumerKey", klog.KRef(namespace, name))
+	defer c.logger.V(4).Info("Finished processing service attachment consumer", "consumerKey", klog.KRef(namespace, name))
+
+	if err = validateServiceAttachmentURL(consumer.Spec.ServiceAttachmentURL); err != nil {
+		return err
+	}
+
+	updatedCR, err := c.ensureConsumerFinalizer(consumer)
+	if err != nil {
+		return fmt.Errorf("errored adding finalizer on ServiceAttachmentConsumer CR %s/%s: %w", namespace, name, err)
+	}
+
+	gceName := c.saNamer.ServiceAttachmentConsumer(namespace, name, string(updatedCR.UID))
+	fr, err := c.cloud.GetRegionForwardingRule(gceName, c.cloud.Region())
+	if err != nil && !utils.IsHTTPErrorCode(err, http.StatusNotFound) {
+		return fmt.Errorf("failed querying for GCE Forwarding Rule %s: %w", gceName, err)
+	}
+	addr, err := c.cloud.GetRegionAddress(gceName, c.cloud.Region())
+	if err != nil && !utils.IsHTTPErrorCode(err, http.StatusNotFound) {
+		return fmt.Errorf("failed querying for GCE Address %s: %w", gceName, err)
+	}
+
+	// The target and the IP address of a forwarding rule can't be updated, and neither can the
+	// IP address and the subnetwork of an address, so the PSC endpoint is recreated when they change.
+	addrChanged := addr != nil && ((updatedCR.Spec.IPAddress != "" && addr.Address != updatedCR.Spec.IPAddress) ||
+		c.subnetworkChanged(addr, updatedCR.Spec.Subnetwork))
+	if fr != nil && (addrChanged || !utils.EqualResourceIDs(fr.Target, updatedCR.Spec.ServiceAttachmentURL)) {
+		c.logger.V(2).Info("PSC endpoint configuration changed, deleting forwarding rule", "forwardingRuleName", gceName)
+		if err = utils.IgnoreHTTPNotFound(c.cloud.DeleteRegionForwardingRule(gceName, c.cloud.Region())); err != nil {
+			return fmt.Errorf("failed to delete GCE Forwarding Rule %s: %w", gceName, err)
+		}
+		fr = nil
+	}
+	if addrChanged {
+		c.logger.V(2).Info("PSC endpoint address changed, releasing address", "addressName", gceName, "oldIP", addr.Address, "oldSubnet", addr.Subnetwork)
+		if err = utils.IgnoreHTTPNotFound(c.cloud.DeleteRegionAddress(gceName, c.cloud.Region())); err != nil {
+			return fmt.Errorf("failed to release GCE Address %s: %w", gceName, err)
+		}
+		addr = nil
+	}
+
+	if addr == nil {
+		if addr, err = c.reserveAddress(updatedCR, gceName); err != nil {
+			return err
+		}
+	}
+
+	if fr == nil {
+		if fr, err = c.createForwardingRule(updatedCR, gceName, addr); err != nil {
+			return err
+		}
+		c.recorder(updatedCR.Namespace).Eventf(updatedCR, v1.EventTypeNormal, "PSCEndpointCreated",
+			"PSC endpoint %s with IP %s was created for Service Attachment %s", gceName, addr.Address, updatedCR.Spec.ServiceAttachmentURL)
+	}
+
+	var svcName string
+	if svcName, err = c.ensureConsumerService(updatedCR, addr.Address); err != nil {
+		return err
+	}
+
+	_, err = c.updateConsumerStatus(updatedCR, addr, fr, svcName)
+	return err
+}
+
+// subnetworkChanged returns true if the address is not in the subnetwork of the spec, which is either
+// the name of a subnetwork of the region or its full resource URL, or in the subnetwork of the cluster
+// if the spec does not set one.
+func (c *ConsumerController) subnetworkChanged(addr *ga.Address, subnetwork string) bool {
+	if subnetwork == "" {
+		subnetwork = c.cloud.SubnetworkURL()
+	}
+	if addr.Subnetwork == subnetwork {
+		return false
+	}
+	if _, err := cloud.ParseResourceURL(subnetwork); err == nil {
+		return !utils.EqualResourceIDs(addr.Subnetwork, subnetwork)
+	}
+	existingSubnet, err := cloud.ParseResourceURL(addr.Subnetwork)
+	return err != nil || existingSubnet.Key.Name != subnetwork
+}
+
+// reserveAddress reserves the internal GCE Address of the PSC endpoint in the subnet
+// of the ServiceAttachmentConsumer
+func (c *ConsumerController) reserveAddress(cr *sav1.ServiceAttachmentConsumer, name string) (*ga.Address, error) {
+	subnetURL := c.cloud.SubnetworkURL()
+	if cr.Spec.Subnetwork != "" {
+		var err error
+		if subnetURL, err = getSubnetURL(c.cloud, cr.Spec.Subnetwork); err != nil {
+			return nil, err
+		}
+	}
+
+	addr := &ga.Address{
+		Name:        name,
+		Description: consumerDescription(cr),
+		Address:     cr.Spec.IPAddress,
+		AddressType: string(cloud.SchemeInternal),
+		Subnetwork:  subnetURL,
+	}
+	c.logger.V(2).Info("Reserving address for PSC endpoint", "addressName", name, "ip", cr.Spec.IPAddress, "subnet", subnetURL)
+	if err := c.cloud.ReserveRegionAddress(addr, c.cloud.Region()); err != nil {
+		return nil, fmt.Errorf("failed to reserve GCE Address %s: %w", name, err)
+	}
+	reserved, err := c.cloud.GetRegionAddress(name, c.cloud.Region())
+	if err != nil {
+		return nil, fmt.Errorf("failed querying for GCE Address %s: %w", name, err)
+	}
+	return reserved, nil
+}
+
+// createForwardingRule creates the consumer GCE Forwarding Rule of the PSC endpoint, which
+// connects the reserved address to the Service Attachment
+func (c *ConsumerController) createForwardingRule(cr *sav1.ServiceAttachmentConsumer, name string, addr *ga.Address) (*ga.ForwardingRule, error) {
+	fr := &ga.ForwardingRule{
+		Name:        name,
+		Description: consumerDescription(cr),
+		IPAddress:   addr.SelfLink,
+		Target:      cr.Spec.ServiceAttachmentURL,
+		Network:     c.cloud.NetworkURL(),
+		// PSC consumer forwarding rules must not set a load balancing scheme.
+		LoadBalancingScheme: "",
+	}
+	c.logger.V(2).Info("Creating PSC endpoint forwarding rule", "forwardingRuleName", name, "target", cr.Spec.ServiceAttachmentURL)
+	if err := c.cloud.CreateRegionForwardingRule(fr, c.cloud.Region()); err != nil {
+		return nil, fmt.Errorf("failed to create GCE Forwarding Rule %s: %w", name, err)
+	}
+	created, err := c.cloud.GetRegionForwardingRule(name, c.cloud.Region())
+	if err != nil {
+		return nil, fmt.Errorf("failed querying for GCE Forwarding Rule %s: %w", name, err)
+	}
+	return created, nil
+}
+
+// ensureConsumerService ensures the headless Service and EndpointSlice of the ServiceAttachmentConsumer
+// point at the ip of the PSC endpoint, or deletes them if the spec does not request a Service.
+// It returns the name of the Service, empty if there is none.
+func (c *ConsumerController) ensureConsumerService(cr *sav1.ServiceAttachmentConsumer, ip string) (string, error) {
+	if cr.Spec.Service == nil {
+		return "", c.ensureConsumerServiceDeleted(cr)
+	}
+
+	desiredSvc := consumerService(cr)
+	svc, err := c.kubeClient.CoreV1().Services(cr.Namespace).Get(context2.Background(), cr.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		c.logger.V(2).Info("Creating Service for service attachment consumer", "consumerKey", klog.KRef(cr.Namespace, cr.Name))
+		if _, err = c.kubeClient.CoreV1().Services(cr.Namespace).Create(context2.Background(), desiredSvc, metav1.CreateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to create Service %s/%s: %w", cr.Namespace, cr.Name, err)
+		}
+	case err != nil:
+		return "", fmt.Errorf("failed to get Service %s/%s: %w", cr.Namespace, cr.Name, err)
+	case !metav1.IsControlledBy(svc, cr):
+		return "", fmt.Errorf("%w: %s/%s", ConsumerServiceConflictError, cr.Namespace, cr.Name)
+	case !equality.Semantic.DeepEqual(svc.Spec.Ports, desiredSvc.Spec.Ports):
+		updatedSvc := svc.DeepCopy()
+		updatedSvc.Spec.Ports = desiredSvc.Spec.Ports
+		c.logger.V(2).Info("Updating Service ports for service attachment consumer", "consumerKey", klog.KRef(cr.Namespace, cr.Name))
+		if _, err = c.kubeClient.CoreV1().Services(cr.Namespace).Update(context2.Background(), updatedSvc, metav1.UpdateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to update Service %s/%s: %w", cr.Namespace, cr.Name, err)
+		}
+	}
+
+	desiredEPS := consumerEndpointSlice(cr, ip)
+	eps, err := c.kubeClient.DiscoveryV1().EndpointSlices(cr.Namespace).Get(context2.Background(), cr.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		if _, err = c.kubeClient.DiscoveryV1().EndpointSlices(cr.Namespace).Create(context2.Background(), desiredEPS, metav1.CreateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to create EndpointSlice %s/%s: %w", cr.Namespace, cr.Name, err)
+		}
+	case err != nil:
+		return "", fmt.Errorf("failed to get EndpointSlice %s/%s: %w", cr.Namespace, cr.Name, err)
+	case !metav1.IsControlledBy(eps, cr):
+		return "", fmt.Errorf("%w: EndpointSlice %s/%s", ConsumerServiceConflictError, cr.Namespace, cr.Name)
+	case eps.AddressType != desiredEPS.AddressType:
+		// The address type of an EndpointSlice is immutable.
+		if err = c.kubeClient.DiscoveryV1().EndpointSlices(cr.Namespace).Delete(context2.Background(), cr.Name, metav1.DeleteOptions{}); err != nil {
+			return "", fmt.Errorf("failed to delete EndpointSlice %s/%s: %w", cr.Namespace, cr.Name, err)
+		}
+		if _, err = c.kubeClient.DiscoveryV1().EndpointSlices(cr.Namespace).Create(context2.Background(), desiredEPS, metav1.CreateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to create EndpointSlice %s/%s: %w", cr.Namespace, cr.Name, err)
+		}
+	case !equality.Semantic.DeepEqual(eps.Endpoints, desiredEPS.Endpoints) || !equality.Semantic.DeepEqual(eps.Ports, desiredEPS.Ports) || !reflect.DeepEqual(eps.Labels, desiredEPS.Labels):
+		updatedEPS := eps.DeepCopy()
+		updatedEPS.Labels = desiredEPS.Labels
+		updatedEPS.Endpoints = desiredEPS.Endpoints
+		updatedEPS.Ports = desiredEPS.Ports
+		if _, err = c.kubeClient.DiscoveryV1().EndpointSlices(cr.Namespace).Update(context2.Background(), updatedEPS, metav1.UpdateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to update EndpointSlice %s/%s: %w", cr.Namespace, cr.Name, err)
+		}
+	}
+	return cr.Name, nil
+}
+
+// ensureConsumerServiceDeleted deletes the Service and EndpointSlice of the ServiceAttachmentConsumer,
+// if they exist and are managed by it
+func (c *ConsumerController) ensureConsumerServiceDeleted(cr *sav1.ServiceAttachmentConsumer) error {
+	eps, err := c.kubeClient.DiscoveryV1().EndpointSlices(cr.Namespace).Get(context2.Background(), cr.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get EndpointSlice %s/%s: %w", cr.Namespace, cr.Name, err)
+	}
+	if err == nil && metav1.IsControlledBy(eps, cr) {
+		if err = c.kubeClient.DiscoveryV1().EndpointSlices(cr.Namespace).Delete(context2.Background(), cr.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete EndpointSlice %s/%s: %w", cr.Namespace, cr.Name, err)
+		}
+	}
+
+	svc, err := c.kubeClient.CoreV1().Services(cr.Namespace).Get(context2.Background(), cr.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get Service %s/%s: %w", cr.Namespace, cr.Name, err)
+	}
+	if err == nil && metav1.IsControlledBy(svc, cr) {
+		c.logger.V(2).Info("Deleting Service of service attachment consumer", "consumerKey", klog.KRef(cr.Namespace, cr.Name))
+		if err = c.kubeClient.CoreV1().Services(cr.Namespace).Delete(context2.Background(), cr.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete Service %s/%s: %w", cr.Namespace, cr.Name, err)
+		}
+	}
+	return nil
+}
+
+// updateConsumerStatus updates the CR's status with the GCE Address and Forwarding Rule of
+// the PSC endpoint, and the connection status reported by GCE
+func (c *ConsumerController) updateConsumerStatus(cr *sav1.ServiceAttachmentConsumer, addr *ga.Address, fr *ga.ForwardingRule, svcName string) (*sav1.ServiceAttachmentConsumer, error) {
+	updatedCR := cr.DeepCopy()
+	updatedCR.Status.IPAddress = addr.Address
+	updatedCR.Status.AddressURL = addr.SelfLink
+	updatedCR.Status.ForwardingRuleURL = fr.SelfLink
+	updatedCR.Status.ConnectionStatus = fr.PscConnectionStatus
+	updatedCR.Status.ServiceName = svcName
+
+	if reflect.DeepEqual(cr.Status, updatedCR.Status) {
+		c.logger.V(2).Info("Service attachment consumer has no status update. Skipping patch", "consumerKey", klog.KRef(cr.Namespace, cr.Name))
+		return cr, nil
+	}
+	updatedCR.Status.LastModifiedTimestamp = metav1.Now()
+
+	c.logger.V(2).Info("Updating service attachment consumer status", "consumerKey", klog.KRef(cr.Namespace, cr.Name))
+	return c.patchConsumer(cr, updatedCR)
+}
+
+// deleteConsumer deletes the GCE Forwarding Rule and Address of the PSC endpoint, and
+// removes the finalizer from the CR. The Service and EndpointSlice are owned by the CR
+// and are garbage collected by Kubernetes.
+func (c *ConsumerController) deleteConsumer(cr *sav1.ServiceAttachmentConsumer) error {
+	gceName := c.saNamer.ServiceAttachmentConsumer(cr.Namespace, cr.Name, string(cr.UID))
+
+	c.logger.V(2).Info("Deleting PSC endpoint", "consumerKey", klog.KRef(cr.Namespace, cr.Name), "forwardingRuleName", gceName)
+	if err := utils.IgnoreHTTPNotFound(c.cloud.DeleteRegionForwardingRule(gceName, c.cloud.Region())); err != nil {
+		c.recorder(cr.Namespace).Eventf(cr, v1.EventTypeWarning, SvcAttachmentConsumerGCError, "Failed to delete GCE Forwarding Rule %s: %q", gceName, err)
+		return fmt.Errorf("failed to delete GCE Forwarding Rule %s: %w", gceName, err)
+	}
+	if err := utils.IgnoreHTTPNotFound(c.cloud.DeleteRegionAddress(gceName, c.cloud.Region())); err != nil {
+		c.recorder(cr.Namespace).Eventf(cr, v1.EventTypeWarning, SvcAttachmentConsumerGCError, "Failed to release GCE Address %s: %q", gceName, err)
+		return fmt.Errorf("failed to release GCE Address %s: %w", gceName, err)
+	}
+	c.logger.V(2).Info("Deleted PSC endpoint", "consumerKey", klog.KRef(cr.Namespace, cr.Name))
+
+	updatedCR := cr.DeepCopy()
+	updatedCR.Finalizers = slice.RemoveString(updatedCR.Finalizers, common.PSCConsumerFinalizerKey, nil)
+	if _, err := c.patchConsumer(cr, updatedCR); err != nil {
+		return fmt.Errorf("failed to remove finalizer on ServiceAttachmentConsumer %s/%s: %w", cr.Namespace, cr.Name, err)
+	}
+	c.logger.V(2).Info("Removed finalizer on service attachment consumer", "consumerKey", klog.KRef(cr.Namespace, cr.Name))
+	return nil
+}
+
+// ensureConsumerFinalizer ensures that the PSC consumer finalizer exists on the provided
+// CR. If it does not, the CR will be patched with the finalizer
+func (c *ConsumerController) ensureConsumerFinalizer(cr *sav1.ServiceAttachmentConsumer) (*sav1.ServiceAttachmentConsumer, error) {
+	if common.HasGivenFinalizer(cr.ObjectMeta, common.PSCConsumerFinalizerKey) {
+		return cr, nil
+	}
+	updatedCR := cr.DeepCopy()
+	updatedCR.Finalizers = append(updatedCR.Finalizers, common.PSCConsumerFinalizerKey)
+	return c.patchConsumer(cr, updatedCR)
+}
+
+// patchConsumer patches the original CR to the desired updated CR
+func (c *ConsumerController) patchConsumer(original, updated *sav1.ServiceAttachmentConsumer) (*sav1.ServiceAttachmentConsumer, error) {
+	patchBytes, err := patch.MergePatchBytes(original, updated)
+	if err != nil {
+		return original, err
+	}
+	return c.saClient.NetworkingV1().ServiceAttachmentConsumers(original.Namespace).Patch(context2.Background(), updated.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+}
+
+// validateServiceAttachmentURL validates that the provided url is the URL of a GCE Service Attachment
+func validateServiceAttachmentURL(saURL string) error {
+	id, err := cloud.ParseResourceURL(saURL)
+	if err != nil {
+		return fmt.Errorf("%w %q: %v", InvalidServiceAttachmentURLError, saURL, err)
+	}
+	if id.Resource != serviceAttachmentsResource {
+		return fmt.Errorf("%w %q: resource must be %s", InvalidServiceAttachmentURLError, saURL, serviceAttachmentsResource)
+	}
+	return nil
+}
+
+// consumerDescription returns the description of the GCE resources of the ServiceAttachmentConsumer
+func consumerDescription(cr *sav1.ServiceAttachmentConsumer) string {
+	return fmt.Sprintf(`{"kubernetes.io/service-attachment-consumer-name":"%s/%s"}`, cr.Namespace, cr.Name)
+}
+
+// consumerOwnerReference returns the owner reference to the ServiceAttachmentConsumer set on the
+// Service and EndpointSlice
+func consumerOwnerReference(cr *sav1.ServiceAttachmentConsumer) metav1.OwnerReference {
+	return *metav1.NewControllerRef(cr, sav1.SchemeGroupVersion.WithKind("ServiceAttachmentConsumer"))
+}
+
+// consumerService returns the headless Service of the ServiceAttachmentConsumer
+func consumerService(cr *sav1.ServiceAttachmentConsumer) *v1.Service {
+	var ports []v1.ServicePort
+	for _, p := range cr.Spec.Service.Ports {
+		ports = append(ports, v1.ServicePort{
+			Name:     p.Name,
+			Protocol: consumerPortProtocol(p),
+			Port:     p.Port,
+			// Set to the default of the API server, so that the ports of
+			// existing Services are equal to the desired ones.
+			TargetPort: intstr.FromInt32(p.Port),
+		})
+	}
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            cr.Name,
+			Namespace:       cr.Namespace,
+			OwnerReferences: []metav1.OwnerReference{consumerOwnerReference(cr)},
+		},
+		Spec: v1.ServiceSpec{
+			ClusterIP: v1.ClusterIPNone,
+			Ports:     ports,
+		},
+	}
+}
+
+// consumerEndpointSlice returns the EndpointSlice of the Service of the ServiceAttachmentConsumer,
+// with the ip of the PSC endpoint as the only endpoint
+func consumerEndpointSlice(cr *sav1.ServiceAttachmentConsumer, ip string) *discoveryv1.EndpointSlice {
+	addressType := discoveryv1.AddressTypeIPv4
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+		addressType = discoveryv1.AddressTypeIPv6
+	}
+	var ports []discoveryv1.EndpointPort
+	for _, p := range cr.Spec.Service.Ports {
+		name, protocol, port := p.Name, consumerPortProtocol(p), p.Port
+		ports = append(ports, discoveryv1.EndpointPort{
+			Name:     &name,
+			Protocol: &protocol,
+			Port:     &port,
+		})
+	}
+	ready := true
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: cr.Name,
+				discoveryv1.LabelManagedBy:   pscConsumerManagedByValue,
+			},
+			OwnerReferences: []metav1.OwnerReference{consumerOwnerReference(cr)},
+		},
+		AddressType: addressType,
+		Endpoints: []discoveryv1.Endpoint{
+			{
+				Addresses:  []string{ip},
+				Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+			},
+		},
+		Ports: ports,
+	}
+}
+
+// consumerPortProtocol returns the protocol of the port, defaulting to TCP
+func consumerPortProtocol(port sav1.ConsumerServicePort) v1.Protocol {
+	if port.Protocol == "" {
+		return v1.ProtocolTCP
+	}
+	return port.Protocol
+}
+
+// shouldProcessConsumer checks if the ServiceAttachmentConsumer should be processed or not.
+// It will ignore status only updates but will return true for periodic enqueues, which refresh
+// the connection status, and for CRs marked for deletion
+func shouldProcessConsumer(old, cur *sav1.ServiceAttachmentConsumer, logger klog.Logger) bool {
+	logger = logger.WithValues("consumerKey", klog.KRef(cur.Namespace, cur.Name))
+	if cur.GetDeletionTimestamp() != nil {
+		logger.V(4).Info("Deletion timestamp is set, queuing service attachment consumer")
+		return true
+	}
+	if !reflect.DeepEqual(old.Spec, cur.Spec) {
+		logger.V(4).Info("Spec has changed, queuing service attachment consumer")
+		return true
+	}
+	if reflect.DeepEqual(old.Status, cur.Status) {
+		logger.V(4).Info("Periodic sync, queuing service attachment consumer")
+		return true
+	}
+	logger.V(4).Info("Status only update, skipping service attachment consumer")
+	return false
+}
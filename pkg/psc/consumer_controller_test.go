@@ -0,0 +1,472 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package psc
+
+import (
+	context2 "context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/cloud-provider-gcp/providers/gce"
+	sav1 "k8s.io/ingress-gce/pkg/apis/serviceattachment/v1"
+	"k8s.io/ingress-gce/pkg/context"
+	safake "k8s.io/ingress-gce/pkg/serviceattachment/client/clientset/versioned/fake"
+	"k8s.io/ingress-gce/pkg/test"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/common"
+	"k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
+)
+
+const (
+	consumerName = "consumer"
+	consumerUID  = "consumer-uid"
+)
+
+func TestConsumerCreate(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		spec        sav1.ServiceAttachmentConsumerSpec
+		expectIP    string
+		expectSvc   bool
+		expectErr   bool
+		existingSvc bool
+	}{
+		{
+			desc: "endpoint without service",
+			spec: sav1.ServiceAttachmentConsumerSpec{
+				ServiceAttachmentURL: testServiceAttachmentURL("producer-sa"),
+			},
+		},
+		{
+			desc: "endpoint with requested ip",
+			spec: sav1.ServiceAttachmentConsumerSpec{
+				ServiceAttachmentURL: testServiceAttachmentURL("producer-sa"),
+				IPAddress:            "10.0.0.10",
+			},
+			expectIP: "10.0.0.10",
+		},
+		{
+			desc: "endpoint with service",
+			spec: sav1.ServiceAttachmentConsumerSpec{
+				ServiceAttachmentURL: testServiceAttachmentURL("producer-sa"),
+				Service: &sav1.ConsumerServiceSpec{
+					Ports: []sav1.ConsumerServicePort{{Name: "http", Port: 80}, {Name: "dns", Protocol: v1.ProtocolUDP, Port: 53}},
+				},
+			},
+			expectSvc: true,
+		},
+		{
+			desc: "service exists and is not managed by the consumer",
+			spec: sav1.ServiceAttachmentConsumerSpec{
+				ServiceAttachmentURL: testServiceAttachmentURL("producer-sa"),
+				Service: &sav1.ConsumerServiceSpec{
+					Ports: []sav1.ConsumerServicePort{{Port: 80}},
+				},
+			},
+			existingSvc: true,
+			expectErr:   true,
+		},
+		{
+			desc: "invalid service attachment url",
+			spec: sav1.ServiceAttachmentConsumerSpec{
+				ServiceAttachmentURL: "producer-sa",
+			},
+			expectErr: true,
+		},
+		{
+			desc: "url is not a service attachment",
+			spec: sav1.ServiceAttachmentConsumerSpec{
+				ServiceAttachmentURL: cloud.SelfLink(meta.VersionGA, "producer-project", "forwardingRules", meta.RegionalKey("producer-fr", "us-central1")),
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			controller := newTestConsumerController()
+			if tc.existingSvc {
+				svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: consumerName}}
+				if _, err := controller.kubeClient.CoreV1().Services(testNamespace).Create(context2.TODO(), svc, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("failed to create Service: %v", err)
+				}
+			}
+			cr := createConsumer(t, controller, tc.spec)
+
+			err := controller.processConsumer(SvcAttachmentKeyFunc(testNamespace, consumerName))
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("expected an error when processing the service attachment consumer")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error processing the service attachment consumer: %v", err)
+			}
+
+			updatedCR, err := controller.saClient.NetworkingV1().ServiceAttachmentConsumers(testNamespace).Get(context2.TODO(), consumerName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get service attachment consumer: %v", err)
+			}
+			if !common.HasGivenFinalizer(updatedCR.ObjectMeta, common.PSCConsumerFinalizerKey) {
+				t.Errorf("expected finalizer %s on the service attachment consumer, found %v", common.PSCConsumerFinalizerKey, updatedCR.Finalizers)
+			}
+
+			gceName := controller.saNamer.ServiceAttachmentConsumer(testNamespace, consumerName, string(cr.UID))
+			addr, fr := verifyPSCEndpoint(t, controller, gceName, tc.spec.ServiceAttachmentURL)
+			if tc.expectIP != "" && addr.Address != tc.expectIP {
+				t.Errorf("got address %s, want %s", addr.Address, tc.expectIP)
+			}
+			if addr.AddressType != string(cloud.SchemeInternal) {
+				t.Errorf("got address type %s, want %s", addr.AddressType, cloud.SchemeInternal)
+			}
+			if addr.Subnetwork != controller.cloud.SubnetworkURL() {
+				t.Errorf("got address subnetwork %s, want %s", addr.Subnetwork, controller.cloud.SubnetworkURL())
+			}
+
+			expectedStatus := sav1.ServiceAttachmentConsumerStatus{
+				IPAddress:         addr.Address,
+				AddressURL:        addr.SelfLink,
+				ForwardingRuleURL: fr.SelfLink,
+			}
+			if tc.expectSvc {
+				expectedStatus.ServiceName = consumerName
+			}
+			updatedCR.Status.LastModifiedTimestamp = metav1.Time{}
+			if updatedCR.Status != expectedStatus {
+				t.Errorf("got status %+v, want %+v", updatedCR.Status, expectedStatus)
+			}
+
+			verifyConsumerService(t, controller, updatedCR, tc.expectSvc, addr.Address)
+		})
+	}
+}
+
+func TestConsumerUpdate(t *testing.T) {
+	controller := newTestConsumerController()
+	spec := sav1.ServiceAttachmentConsumerSpec{
+		ServiceAttachmentURL: testServiceAttachmentURL("producer-sa"),
+		Service: &sav1.ConsumerServiceSpec{
+			Ports: []sav1.ConsumerServicePort{{Name: "http", Port: 80}},
+		},
+	}
+	cr := createConsumer(t, controller, spec)
+	key := SvcAttachmentKeyFunc(testNamespace, consumerName)
+	if err := controller.processConsumer(key); err != nil {
+		t.Fatalf("unexpected error processing the service attachment consumer: %v", err)
+	}
+	gceName := controller.saNamer.ServiceAttachmentConsumer(testNamespace, consumerName, string(cr.UID))
+
+	// Updating the service attachment recreates the forwarding rule
+	spec.ServiceAttachmentURL = testServiceAttachmentURL("other-producer-sa")
+	spec.Service.Ports = append(spec.Service.Ports, sav1.ConsumerServicePort{Name: "https", Port: 443})
+	updateConsumer(t, controller, func(cr *sav1.ServiceAttachmentConsumer) { cr.Spec = spec })
+	if err := controller.processConsumer(key); err != nil {
+		t.Fatalf("unexpected error processing the service attachment consumer: %v", err)
+	}
+	addr, _ := verifyPSCEndpoint(t, controller, gceName, spec.ServiceAttachmentURL)
+	updatedCR, err := controller.saClient.NetworkingV1().ServiceAttachmentConsumers(testNamespace).Get(context2.TODO(), consumerName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get service attachment consumer: %v", err)
+	}
+	verifyConsumerService(t, controller, updatedCR, true, addr.Address)
+
+	// Updating the ip recreates the address and the forwarding rule
+	spec.IPAddress = "10.0.0.20"
+	updateConsumer(t, controller, func(cr *sav1.ServiceAttachmentConsumer) { cr.Spec = spec })
+	if err := controller.processConsumer(key); err != nil {
+		t.Fatalf("unexpected error processing the service attachment consumer: %v", err)
+	}
+	addr, fr := verifyPSCEndpoint(t, controller, gceName, spec.ServiceAttachmentURL)
+	if addr.Address != spec.IPAddress {
+		t.Errorf("got address %s, want %s", addr.Address, spec.IPAddress)
+	}
+	if fr.IPAddress != addr.SelfLink {
+		t.Errorf("got forwarding rule ip address %s, want %s", fr.IPAddress, addr.SelfLink)
+	}
+	updatedCR, err = controller.saClient.NetworkingV1().ServiceAttachmentConsumers(testNamespace).Get(context2.TODO(), consumerName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get service attachment consumer: %v", err)
+	}
+	if updatedCR.Status.IPAddress != spec.IPAddress {
+		t.Errorf("got status ip address %s, want %s", updatedCR.Status.IPAddress, spec.IPAddress)
+	}
+	verifyConsumerService(t, controller, updatedCR, true, addr.Address)
+
+	// Updating the subnetwork recreates the address and the forwarding rule
+	spec.Subnetwork = "https://www.googleapis.com/compute/v1/projects/test-project/regions/us-central1/subnetworks/other-subnet"
+	updateConsumer(t, controller, func(cr *sav1.ServiceAttachmentConsumer) { cr.Spec = spec })
+	if err := controller.processConsumer(key); err != nil {
+		t.Fatalf("unexpected error processing the service attachment consumer: %v", err)
+	}
+	addr, fr = verifyPSCEndpoint(t, controller, gceName, spec.ServiceAttachmentURL)
+	if addr.Subnetwork != spec.Subnetwork {
+		t.Errorf("got address subnetwork %s, want %s", addr.Subnetwork, spec.Subnetwork)
+	}
+	if fr.IPAddress != addr.SelfLink {
+		t.Errorf("got forwarding rule ip address %s, want %s", fr.IPAddress, addr.SelfLink)
+	}
+
+	// Removing the service deletes the Service and the EndpointSlice
+	spec.Service = nil
+	updateConsumer(t, controller, func(cr *sav1.ServiceAttachmentConsumer) { cr.Spec = spec })
+	if err := controller.processConsumer(key); err != nil {
+		t.Fatalf("unexpected error processing the service attachment consumer: %v", err)
+	}
+	updatedCR, err = controller.saClient.NetworkingV1().ServiceAttachmentConsumers(testNamespace).Get(context2.TODO(), consumerName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get service attachment consumer: %v", err)
+	}
+	if updatedCR.Status.ServiceName != "" {
+		t.Errorf("got status service name %q, want empty", updatedCR.Status.ServiceName)
+	}
+	verifyConsumerService(t, controller, updatedCR, false, "")
+}
+
+func TestConsumerServiceNotUpdatedOnResync(t *testing.T) {
+	controller := newTestConsumerController()
+	spec := sav1.ServiceAttachmentConsumerSpec{
+		ServiceAttachmentURL: testServiceAttachmentURL("producer-sa"),
+		Service: &sav1.ConsumerServiceSpec{
+			Ports: []sav1.ConsumerServicePort{{Name: "http", Port: 80}, {Name: "dns", Protocol: v1.ProtocolUDP, Port: 53}},
+		},
+	}
+	createConsumer(t, controller, spec)
+	key := SvcAttachmentKeyFunc(testNamespace, consumerName)
+	if err := controller.processConsumer(key); err != nil {
+		t.Fatalf("unexpected error processing the service attachment consumer: %v", err)
+	}
+
+	// The API server defaults the target ports to the ports.
+	svc, err := controller.kubeClient.CoreV1().Services(testNamespace).Get(context2.TODO(), consumerName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get Service: %v", err)
+	}
+	for i := range svc.Spec.Ports {
+		svc.Spec.Ports[i].TargetPort = intstr.FromInt32(svc.Spec.Ports[i].Port)
+	}
+	if _, err := controller.kubeClient.CoreV1().Services(testNamespace).Update(context2.TODO(), svc, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update Service: %v", err)
+	}
+
+	kubeClient := controller.kubeClient.(*fake.Clientset)
+	kubeClient.ClearActions()
+	if err := controller.processConsumer(key); err != nil {
+		t.Fatalf("unexpected error processing the service attachment consumer: %v", err)
+	}
+	for _, action := range kubeClient.Actions() {
+		if action.GetVerb() == "update" && action.GetResource().Resource == "services" {
+			t.Errorf("unexpected update of the Service of the service attachment consumer on resync")
+		}
+	}
+}
+
+func TestConsumerDelete(t *testing.T) {
+	controller := newTestConsumerController()
+	cr := createConsumer(t, controller, sav1.ServiceAttachmentConsumerSpec{
+		ServiceAttachmentURL: testServiceAttachmentURL("producer-sa"),
+	})
+	key := SvcAttachmentKeyFunc(testNamespace, consumerName)
+	if err := controller.processConsumer(key); err != nil {
+		t.Fatalf("unexpected error processing the service attachment consumer: %v", err)
+	}
+	gceName := controller.saNamer.ServiceAttachmentConsumer(testNamespace, consumerName, string(cr.UID))
+	verifyPSCEndpoint(t, controller, gceName, cr.Spec.ServiceAttachmentURL)
+
+	deletionTimestamp := metav1.NewTime(time.Now())
+	updateConsumer(t, controller, func(cr *sav1.ServiceAttachmentConsumer) { cr.DeletionTimestamp = &deletionTimestamp })
+	if err := controller.processConsumer(key); err != nil {
+		t.Fatalf("unexpected error deleting the service attachment consumer: %v", err)
+	}
+
+	if _, err := controller.cloud.GetRegionForwardingRule(gceName, controller.cloud.Region()); !utils.IsHTTPErrorCode(err, http.StatusNotFound) {
+		t.Errorf("expected forwarding rule %s to be deleted, got err %v", gceName, err)
+	}
+	if _, err := controller.cloud.GetRegionAddress(gceName, controller.cloud.Region()); !utils.IsHTTPErrorCode(err, http.StatusNotFound) {
+		t.Errorf("expected address %s to be deleted, got err %v", gceName, err)
+	}
+	updatedCR, err := controller.saClient.NetworkingV1().ServiceAttachmentConsumers(testNamespace).Get(context2.TODO(), consumerName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get service attachment consumer: %v", err)
+	}
+	if common.HasGivenFinalizer(updatedCR.ObjectMeta, common.PSCConsumerFinalizerKey) {
+		t.Errorf("expected finalizer %s to be removed, found %v", common.PSCConsumerFinalizerKey, updatedCR.Finalizers)
+	}
+
+	// Processing again is a noop once the finalizer is removed
+	if err := controller.consumerLister.Update(updatedCR); err != nil {
+		t.Fatalf("failed to update the service attachment consumer lister: %v", err)
+	}
+	if err := controller.processConsumer(key); err != nil {
+		t.Errorf("unexpected error processing the deleted service attachment consumer: %v", err)
+	}
+}
+
+func TestValidateServiceAttachmentURL(t *testing.T) {
+	for _, tc := range []struct {
+		url       string
+		expectErr bool
+	}{
+		{url: testServiceAttachmentURL("producer-sa")},
+		{url: "projects/producer-project/regions/us-central1/serviceAttachments/producer-sa"},
+		{url: "producer-sa", expectErr: true},
+		{url: cloud.SelfLink(meta.VersionGA, "producer-project", "forwardingRules", meta.RegionalKey("producer-fr", "us-central1")), expectErr: true},
+	} {
+		err := validateServiceAttachmentURL(tc.url)
+		if tc.expectErr != (err != nil) {
+			t.Errorf("validateServiceAttachmentURL(%q) = %v, expectErr %t", tc.url, err, tc.expectErr)
+		}
+		if err != nil && !errors.Is(err, InvalidServiceAttachmentURLError) {
+			t.Errorf("validateServiceAttachmentURL(%q) = %v, want %v", tc.url, err, InvalidServiceAttachmentURLError)
+		}
+	}
+}
+
+func newTestConsumerController() *ConsumerController {
+	kubeClient := fake.NewSimpleClientset()
+	gceClient := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+	resourceNamer := namer.NewNamer(ClusterID, "", klog.TODO())
+	saClient := safake.NewSimpleClientset()
+
+	ctxConfig := context.ControllerContextConfig{
+		Namespace:             v1.NamespaceAll,
+		ResyncPeriod:          1 * time.Minute,
+		DefaultBackendSvcPort: test.DefaultBeSvcPort,
+		HealthCheckPath:       "/",
+		EnablePSCConsumer:     true,
+	}
+
+	ctx := context.NewControllerContext(nil, kubeClient, nil, nil, nil, nil, nil, saClient, nil, nil, kubeClient /*kube client to be used for events*/, gceClient, resourceNamer, kubeSystemUID, ctxConfig, klog.TODO())
+
+	return NewConsumerController(ctx, make(<-chan struct{}), klog.TODO())
+}
+
+// testServiceAttachmentURL returns the URL of a Service Attachment of the producer project
+func testServiceAttachmentURL(name string) string {
+	return cloud.SelfLink(meta.VersionGA, "producer-project", "serviceAttachments", meta.RegionalKey(name, "us-central1"))
+}
+
+// createConsumer creates a ServiceAttachmentConsumer CR with the provided spec and adds it to the lister
+func createConsumer(t *testing.T, controller *ConsumerController, spec sav1.ServiceAttachmentConsumerSpec) *sav1.ServiceAttachmentConsumer {
+	t.Helper()
+	cr := &sav1.ServiceAttachmentConsumer{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      consumerName,
+			UID:       consumerUID,
+		},
+		Spec: spec,
+	}
+	cr, err := controller.saClient.NetworkingV1().ServiceAttachmentConsumers(testNamespace).Create(context2.TODO(), cr, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("failed to create service attachment consumer: %v", err)
+	}
+	if err = controller.consumerLister.Add(cr); err != nil {
+		t.Fatalf("failed to add service attachment consumer to the lister: %v", err)
+	}
+	return cr
+}
+
+// updateConsumer applies the update to the ServiceAttachmentConsumer CR and syncs the lister
+func updateConsumer(t *testing.T, controller *ConsumerController, update func(*sav1.ServiceAttachmentConsumer)) {
+	t.Helper()
+	cr, err := controller.saClient.NetworkingV1().ServiceAttachmentConsumers(testNamespace).Get(context2.TODO(), consumerName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get service attachment consumer: %v", err)
+	}
+	update(cr)
+	if cr, err = controller.saClient.NetworkingV1().ServiceAttachmentConsumers(testNamespace).Update(context2.TODO(), cr, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update service attachment consumer: %v", err)
+	}
+	if err = controller.consumerLister.Update(cr); err != nil {
+		t.Fatalf("failed to update the service attachment consumer lister: %v", err)
+	}
+}
+
+// verifyPSCEndpoint verifies that the address and the forwarding rule of the PSC endpoint exist
+// and that the forwarding rule targets the provided Service Attachment
+func verifyPSCEndpoint(t *testing.T, controller *ConsumerController, gceName, saURL string) (*ga.Address, *ga.ForwardingRule) {
+	t.Helper()
+	addr, err := controller.cloud.GetRegionAddress(gceName, controller.cloud.Region())
+	if err != nil {
+		t.Fatalf("failed to get address %s: %v", gceName, err)
+	}
+	fr, err := controller.cloud.GetRegionForwardingRule(gceName, controller.cloud.Region())
+	if err != nil {
+		t.Fatalf("failed to get forwarding rule %s: %v", gceName, err)
+	}
+	if fr.Target != saURL {
+		t.Errorf("got forwarding rule target %s, want %s", fr.Target, saURL)
+	}
+	if fr.IPAddress != addr.SelfLink {
+		t.Errorf("got forwarding rule ip address %s, want %s", fr.IPAddress, addr.SelfLink)
+	}
+	if fr.LoadBalancingScheme != "" {
+		t.Errorf("got forwarding rule load balancing scheme %q, want empty", fr.LoadBalancingScheme)
+	}
+	return addr, fr
+}
+
+// verifyConsumerService verifies that the headless Service and the EndpointSlice of the
+// ServiceAttachmentConsumer exist and point at the ip, or that they do not exist
+func verifyConsumerService(t *testing.T, controller *ConsumerController, cr *sav1.ServiceAttachmentConsumer, expectSvc bool, ip string) {
+	t.Helper()
+	svc, err := controller.kubeClient.CoreV1().Services(testNamespace).Get(context2.TODO(), cr.Name, metav1.GetOptions{})
+	eps, epsErr := controller.kubeClient.DiscoveryV1().EndpointSlices(testNamespace).Get(context2.TODO(), cr.Name, metav1.GetOptions{})
+	if !expectSvc {
+		if !apierrors.IsNotFound(err) || !apierrors.IsNotFound(epsErr) {
+			t.Errorf("expected Service and EndpointSlice to not exist, got errors %v and %v", err, epsErr)
+		}
+		return
+	}
+	if err != nil || epsErr != nil {
+		t.Fatalf("failed to get Service and EndpointSlice: %v, %v", err, epsErr)
+	}
+
+	if svc.Spec.ClusterIP != v1.ClusterIPNone {
+		t.Errorf("got Service cluster ip %q, want %q", svc.Spec.ClusterIP, v1.ClusterIPNone)
+	}
+	if !metav1.IsControlledBy(svc, cr) || !metav1.IsControlledBy(eps, cr) {
+		t.Errorf("expected Service and EndpointSlice to be controlled by the service attachment consumer")
+	}
+	if len(svc.Spec.Ports) != len(cr.Spec.Service.Ports) || len(eps.Ports) != len(cr.Spec.Service.Ports) {
+		t.Errorf("got %d Service ports and %d EndpointSlice ports, want %d", len(svc.Spec.Ports), len(eps.Ports), len(cr.Spec.Service.Ports))
+	}
+	for i, p := range svc.Spec.Ports {
+		if p.Protocol == "" {
+			t.Errorf("expected Service port %d to have a protocol", i)
+		}
+	}
+	if eps.Labels[discoveryv1.LabelServiceName] != cr.Name {
+		t.Errorf("got EndpointSlice service name label %q, want %q", eps.Labels[discoveryv1.LabelServiceName], cr.Name)
+	}
+	if len(eps.Endpoints) != 1 || len(eps.Endpoints[0].Addresses) != 1 || eps.Endpoints[0].Addresses[0] != ip {
+		t.Errorf("got EndpointSlice endpoints %+v, want a single endpoint with address %s", eps.Endpoints, ip)
+	}
+}
@@ -18,10 +18,13 @@ package psc
 
 import (
 	context2 "context"
+	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -30,8 +33,10 @@ import (
 	ga "google.golang.org/api/compute/v1"
 	"google.golang.org/api/googleapi"
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -59,7 +64,10 @@ func init() {
 }
 
 const (
-	svcKind = "service"
+	svcKind     = "service"
+	ingressKind = "ingress"
+	// ingressAPIGroup is the API group of the Ingress resources that can be referenced.
+	ingressAPIGroup = "networking.k8s.io"
 
 	// SvcAttachmentGCError is the service attachment GC error event reason
 	SvcAttachmentGCError = "ServiceAttachmentGCError"
@@ -69,14 +77,27 @@ const (
 
 	// ServiceAttachmentGCPeriod is the interval at which Service Attachment GC will run
 	ServiceAttachmentGCPeriod = 2 * time.Minute
+
+	// consumerStatusPending is the status of consumer forwarding rules waiting for a connection decision.
+	consumerStatusPending = "PENDING"
 )
 
 var (
-	ServiceNotFoundError = errors.New("service not in store")
-	MismatchedILBIPError = errors.New("Mismatched ILB IP")
-	nonProcessFailures   = []error{
+	ServiceNotFoundError    = errors.New("service not in store")
+	IngressNotFoundError    = errors.New("ingress not in store")
+	NotInternalIngressError = errors.New("ingress is not an internal Ingress")
+	// IngressWithoutForwardingRuleError is returned until the Ingress controller records the forwarding rules of the Ingress.
+	IngressWithoutForwardingRuleError = errors.New("ingress has no forwarding rule")
+	MismatchedILBIPError              = errors.New("Mismatched ILB IP")
+	// InvalidConsumerConnectionDecisionError is returned when a consumer connection decision of the spec can't be applied.
+	InvalidConsumerConnectionDecisionError = errors.New("invalid consumer connection decision")
+	nonProcessFailures                     = []error{
 		ServiceNotFoundError,
+		IngressNotFoundError,
+		NotInternalIngressError,
+		IngressWithoutForwardingRuleError,
 		MismatchedILBIPError,
+		InvalidConsumerConnectionDecisionError,
 	}
 )
 
@@ -91,10 +112,13 @@ type Controller struct {
 	saNamer             namer.ServiceAttachmentNamer
 	svcAttachmentLister cache.Indexer
 	serviceLister       cache.Indexer
+	ingressLister       cache.Indexer
 	recorder            func(string) record.EventRecorder
 	collector           metrics.PSCMetricsCollector
 
 	hasSynced func() bool
+	// reconcilePeriod is the time between two reconciliations of all the ServiceAttachments.
+	reconcilePeriod time.Duration
 
 	// The following are used to generate a cluster link for service attachment descs
 	// These values should only be used for providing information and not for
@@ -121,7 +145,9 @@ func NewController(ctx *context.ControllerContext, stopCh <-chan struct{}, logge
 		svcAttachmentLister: ctx.SAInformer.GetIndexer(),
 		svcAttachmentQueue:  workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
 		serviceLister:       ctx.ServiceInformer.GetIndexer(),
+		ingressLister:       ctx.IngressInformer.GetIndexer(),
 		hasSynced:           ctx.HasSynced,
+		reconcilePeriod:     flags.F.PSCReconcilePeriod,
 		recorder:            ctx.Recorder,
 		collector:           ctx.ControllerMetrics,
 		clusterName:         flags.F.GKEClusterName,
@@ -175,6 +201,12 @@ func (c *Controller) Run() {
 
 	go wait.Until(func() { c.serviceAttachmentWorker(c.stopCh) }, time.Second, c.stopCh)
 
+	if c.reconcilePeriod > 0 {
+		// Periodically reconcile all the ServiceAttachments to revert changes made to the GCE
+		// Service Attachments outside of the controller.
+		go wait.Until(c.enqueueAllServiceAttachments, c.reconcilePeriod, c.stopCh)
+	}
+
 	go func() {
 		// Wait a GC period before starting to ensure that resources have enough time to sync
 		time.Sleep(ServiceAttachmentGCPeriod)
@@ -235,6 +267,18 @@ func (c *Controller) enqueueServiceAttachment(obj interface{}) {
 	c.svcAttachmentQueue.Add(key)
 }
 
+// enqueueAllServiceAttachments adds all the ServiceAttachments that are not being deleted to the queue
+func (c *Controller) enqueueAllServiceAttachments() {
+	c.logger.V(2).Info("Enqueuing all service attachments for reconciliation")
+	for _, obj := range c.svcAttachmentLister.List() {
+		sa := obj.(*sav1.ServiceAttachment)
+		if !sa.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		c.enqueueServiceAttachment(sa)
+	}
+}
+
 // addServiceToMetrics adds the metrics collector
 func (c *Controller) addServiceToMetrics(obj interface{}) {
 	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
@@ -301,12 +345,6 @@ func (c *Controller) processServiceAttachment(key string) error {
 		return err
 	}
 
-	var frURL string
-	frURL, err = c.getForwardingRule(namespace, updatedCR.Spec.ResourceRef.Name)
-	if err != nil {
-		return fmt.Errorf("failed to find forwarding rule: %w", err)
-	}
-
 	var subnetURLs []string
 	subnetURLs, err = c.getSubnetURLs(updatedCR.Spec.NATSubnets)
 	if err != nil {
@@ -325,6 +363,16 @@ func (c *Controller) processServiceAttachment(key string) error {
 		return fmt.Errorf("failed querying for GCE Service Attachment: %w", err)
 	}
 
+	var existingTarget string
+	if existingSA != nil {
+		existingTarget = existingSA.TargetService
+	}
+	var frURL string
+	frURL, err = c.getForwardingRule(namespace, updatedCR.Spec.ResourceRef, existingTarget)
+	if err != nil {
+		return fmt.Errorf("failed to find forwarding rule: %w", err)
+	}
+
 	gceSvcAttachment := &ga.ServiceAttachment{}
 	if existingSA != nil {
 		c.logger.V(4).Info("Found existing service attachment", "attachmentName", existingSA.Name)
@@ -339,20 +387,38 @@ func (c *Controller) processServiceAttachment(key string) error {
 	gceSvcAttachment.Region = c.cloud.Region()
 	gceSvcAttachment.Description = desc.String()
 	gceSvcAttachment.EnableProxyProtocol = updatedCR.Spec.ProxyProtocol
-	gceSvcAttachment.ConsumerAcceptLists = convertAllowList(updatedCR.Spec)
-	gceSvcAttachment.ConsumerRejectLists = updatedCR.Spec.ConsumerRejectList
+	var decisions map[string]sav1.ConsumerConnectionDecision
+	decisions, err = consumerConnectionDecisions(updatedCR.Spec)
+	if err != nil {
+		return err
+	}
+	gceSvcAttachment.ConsumerAcceptLists, gceSvcAttachment.ConsumerRejectLists = consumerLists(updatedCR.Spec, decisions)
+	gceSvcAttachment.ReconcileConnections = updatedCR.Spec.ReconcileConnections
+	gceSvcAttachment.DomainNames = updatedCR.Spec.DomainNames
+	var specHash string
+	specHash, err = appliedSpecHash(gceSvcAttachment)
+	if err != nil {
+		return err
+	}
 
 	if existingSA != nil {
-		// Most of the validation is left to the GCE Service Attachment API. needsUpdate only checks
-		// to see if the spec has changed and whether an update is necessary.
-		shouldUpdate, err := needsUpdate(existingSA, gceSvcAttachment)
+		// Most of the validation is left to the GCE Service Attachment API. diffServiceAttachments only
+		// checks to see if the spec has changed and whether an update is necessary.
+		changedFields, err := diffServiceAttachments(existingSA, gceSvcAttachment)
 		if err != nil {
 			return fmt.Errorf("unable to process Service Attachment Update: %w", err)
 		}
 
-		if shouldUpdate {
+		// Changes to a configuration that was already applied were made to the GCE Service
+		// Attachment outside of the controller.
+		var driftedFields []string
+		if len(changedFields) > 0 && updatedCR.Status.AppliedSpecHash == specHash {
+			driftedFields = changedFields
+		}
+
+		if len(changedFields) > 0 {
 			// In order for the update to be successful, the self link in the target service (same resource
-			// as the forwarding rule) must be exactly the same. needsUpdate throws an error in situations
+			// as the forwarding rule) must be exactly the same. diffServiceAttachments throws an error in situations
 			// the forwarding rule/targetservice was changed on the spec. GCE API only accepts updates where the
 			// target service/forwarding rule is the same so to ensure the target service is not changed,
 			// set the target service to match the existing. Otherwise, a mismatch between the target services
@@ -360,13 +426,23 @@ func (c *Controller) processServiceAttachment(key string) error {
 			// may use a different version causing the selflink to differ even if the resource is the same.
 			gceSvcAttachment.TargetService = existingSA.TargetService
 
-			c.logger.V(2).Info("Service Attachment CR was updated, it requires an update", "attachmentKey", klog.KRef(updatedCR.Namespace, updatedCR.Name), "attachmentName", saName)
+			if len(driftedFields) > 0 {
+				c.logger.Info("GCE Service Attachment was changed outside of the controller, reverting the changes", "attachmentKey", klog.KRef(updatedCR.Namespace, updatedCR.Name), "attachmentName", saName, "driftedFields", driftedFields)
+			} else {
+				c.logger.V(2).Info("Service Attachment CR was updated, it requires an update", "attachmentKey", klog.KRef(updatedCR.Namespace, updatedCR.Name), "attachmentName", saName, "changedFields", changedFields)
+			}
 			if err = c.cloud.Compute().ServiceAttachments().Patch(context2.Background(), gceSAKey, gceSvcAttachment); err != nil {
 				return fmt.Errorf("failed to update GCE Service Attachment: %w", err)
 			}
+			if len(driftedFields) > 0 {
+				metrics.PublishDriftMetrics(driftedFields)
+				c.recorder(updatedCR.Namespace).Eventf(updatedCR, v1.EventTypeWarning, "ServiceAttachmentDrifted",
+					"Fields %s of GCE Service Attachment %s were changed outside of the controller and were reverted", strings.Join(driftedFields, ", "), saName)
+			}
+			c.recordConsumerConnectionDecisions(updatedCR, existingSA, decisions)
 		}
 
-		_, err = c.updateServiceAttachmentStatus(updatedCR, gceSAKey)
+		_, err = c.updateServiceAttachmentStatus(updatedCR, gceSAKey, specHash, driftedFields)
 		return err
 	}
 
@@ -375,8 +451,9 @@ func (c *Controller) processServiceAttachment(key string) error {
 		return fmt.Errorf("failed to create GCE Service Attachment: %w", err)
 	}
 	c.logger.V(2).Info("Created service attachment", "attachmentName", saName)
+	c.recordConsumerConnectionDecisions(updatedCR, nil, decisions)
 
-	updatedCR, err = c.updateServiceAttachmentStatus(updatedCR, gceSAKey)
+	updatedCR, err = c.updateServiceAttachmentStatus(updatedCR, gceSAKey, specHash, nil)
 	c.logger.V(2).Info("Updated Service Attachment status", "attachmentKey", klog.KRef(updatedCR.Namespace, updatedCR.Name))
 
 	if err == nil {
@@ -458,78 +535,153 @@ func (c *Controller) deleteServiceAttachment(sa *sav1.ServiceAttachment) {
 	c.logger.V(2).Info("Removed finalizer on Service Attachment", "attachmentName", klog.KRef(sa.Namespace, sa.Name))
 }
 
-// getForwardingRule returns the URL of the forwarding rule based by using the service resource
-// and querying GCE. On ILB subsetting services, the forwarding rule annotation is used to find
-// the forwarding rule name. Otherwise the name is generated based on the service resource.
-func (c *Controller) getForwardingRule(namespace, svcName string) (string, error) {
+// getForwardingRule returns the URL of the forwarding rule of the referenced Service or Ingress.
+// When the resource has several forwarding rules, the forwarding rule targeted by the existing
+// GCE Service Attachment is preferred, since the target of a Service Attachment cannot be updated.
+func (c *Controller) getForwardingRule(namespace string, ref v1.TypedLocalObjectReference, existingTarget string) (string, error) {
+	var frNames, ips []string
+	var err error
+	if strings.ToLower(ref.Kind) == ingressKind {
+		frNames, ips, err = c.ingressForwardingRules(namespace, ref.Name)
+	} else {
+		frNames, ips, err = c.serviceForwardingRules(namespace, ref.Name)
+	}
+	if err != nil {
+		return "", err
+	}
 
+	if existingFR, err := cloud.ParseResourceURL(existingTarget); err == nil {
+		if i := slices.Index(frNames, existingFR.Key.Name); i > 0 {
+			frNames = slices.Insert(slices.Delete(frNames, i, i+1), 0, existingFR.Key.Name)
+		}
+	}
+
+	var fwdRuleErr error
+	for _, frName := range frNames {
+		fwdRule, err := c.cloud.Compute().ForwardingRules().Get(context2.Background(), meta.RegionalKey(frName, c.cloud.Region()))
+		if err != nil {
+			if fwdRuleErr == nil {
+				fwdRuleErr = fmt.Errorf("failed to get Forwarding Rule %s: %w", frName, err)
+			}
+			continue
+		}
+		// Verify that the forwarding rule found has the IP expected in the resource status
+		if !slices.Contains(ips, fwdRule.IPAddress) {
+			c.logger.V(2).Info("forwarding rule does not have matching ip to resource", "forwardingRuleName", frName, "resourceKey", klog.KRef(namespace, ref.Name))
+			if fwdRuleErr == nil || !errors.Is(fwdRuleErr, MismatchedILBIPError) {
+				fwdRuleErr = fmt.Errorf("forwarding rule does not have matching IPAddr to specified %s: %w", strings.ToLower(ref.Kind), MismatchedILBIPError)
+			}
+			continue
+		}
+		c.logger.V(2).Info("verified forwarding rule has matching ip to resource", "forwardingRuleName", frName, "resourceKey", klog.KRef(namespace, ref.Name))
+		return fwdRule.SelfLink, nil
+	}
+	return "", fwdRuleErr
+}
+
+// serviceForwardingRules returns the names of the forwarding rules of the Service, and the IPs of the Service.
+// Multi-protocol Services can have several forwarding rules.
+func (c *Controller) serviceForwardingRules(namespace, svcName string) ([]string, []string, error) {
 	svcKey := fmt.Sprintf("%s/%s", namespace, svcName)
 	obj, exists, err := c.serviceLister.GetByKey(svcKey)
 	if err != nil {
-		return "", fmt.Errorf("errored getting service %s/%s: %w", namespace, svcName, err)
+		return nil, nil, fmt.Errorf("errored getting service %s/%s: %w", namespace, svcName, err)
 	}
 
 	if !exists {
-		return "", fmt.Errorf("failed to get Service %s/%s: %w", namespace, svcName, ServiceNotFoundError)
+		return nil, nil, fmt.Errorf("failed to get Service %s/%s: %w", namespace, svcName, ServiceNotFoundError)
 	}
 
 	svc := obj.(*v1.Service)
 
-	// Check for annotation that has forwarding rule name on the service resource by looking for
-	// the TCP or UDP key. If it exists, then use the value as the forwarding rule name.
-	frName, ok := svc.Annotations[annotations.TCPForwardingRuleKey]
-	if !ok {
-		if frName, ok = svc.Annotations[annotations.UDPForwardingRuleKey]; !ok {
-			// The annotation only exists for ILB Subsetting LBs. If no annotation exists, fallback
-			// to finding the name by regenerating the name using the svc resource
-			frName = cloudprovider.DefaultLoadBalancerName(svc)
-			c.logger.V(2).Info("no forwarding rule annotation exists, falling back to autogenerated forwarding rule name", "serviceKey", klog.KRef(svc.Namespace, svc.Name), "forwardingRuleName", frName)
+	// Check for annotations that have forwarding rule names on the service resource by looking for
+	// the TCP, UDP and L3 keys. If they exist, then use the values as the forwarding rule names.
+	var frNames []string
+	for _, key := range []string{annotations.TCPForwardingRuleKey, annotations.UDPForwardingRuleKey, annotations.L3ForwardingRuleKey} {
+		if frName, ok := svc.Annotations[key]; ok {
+			frNames = append(frNames, frName)
 		}
 	}
-	fwdRule, err := c.cloud.Compute().ForwardingRules().Get(context2.Background(), meta.RegionalKey(frName, c.cloud.Region()))
-	if err != nil {
-		return "", fmt.Errorf("failed to get Forwarding Rule %s: %w", frName, err)
+	if len(frNames) == 0 {
+		// The annotation only exists for ILB Subsetting LBs. If no annotation exists, fallback
+		// to finding the name by regenerating the name using the svc resource
+		frName := cloudprovider.DefaultLoadBalancerName(svc)
+		c.logger.V(2).Info("no forwarding rule annotation exists, falling back to autogenerated forwarding rule name", "serviceKey", klog.KRef(svc.Namespace, svc.Name), "forwardingRuleName", frName)
+		frNames = append(frNames, frName)
 	}
 
-	// Verify that the forwarding rule found has the IP expected in Service.Status
-	foundMatchingIP := false
+	var ips []string
 	for _, ing := range svc.Status.LoadBalancer.Ingress {
-		if ing.IP == fwdRule.IPAddress {
-			c.logger.V(2).Info("verified forwarding rule has matching ip to service", "forwardingRuleName", frName, "serviceKey", klog.KRef(svc.Namespace, svc.Name))
-			foundMatchingIP = true
-			break
+		ips = append(ips, ing.IP)
+	}
+	return frNames, ips, nil
+}
+
+// ingressForwardingRules returns the names of the forwarding rules of the internal Ingress, and the
+// IPs of the Ingress. The HTTPS forwarding rule is preferred over the HTTP forwarding rule.
+func (c *Controller) ingressForwardingRules(namespace, ingName string) ([]string, []string, error) {
+	obj, exists, err := c.ingressLister.GetByKey(fmt.Sprintf("%s/%s", namespace, ingName))
+	if err != nil {
+		return nil, nil, fmt.Errorf("errored getting ingress %s/%s: %w", namespace, ingName, err)
+	}
+	if !exists {
+		return nil, nil, fmt.Errorf("failed to get Ingress %s/%s: %w", namespace, ingName, IngressNotFoundError)
+	}
+
+	ing := obj.(*networkingv1.Ingress)
+	// Only internal Ingresses have regional internal forwarding rules that PSC can publish.
+	if !utils.IsGCEL7ILBIngress(ing) {
+		return nil, nil, fmt.Errorf("Ingress %s/%s must have the %q class: %w", namespace, ingName, annotations.GceL7ILBIngressClass, NotInternalIngressError)
+	}
+
+	var frNames []string
+	for _, key := range []string{annotations.HttpsForwardingRuleKey, annotations.HttpForwardingRuleKey} {
+		if frName, ok := ing.Annotations[key]; ok {
+			frNames = append(frNames, frName)
 		}
 	}
+	if len(frNames) == 0 {
+		return nil, nil, fmt.Errorf("no forwarding rule annotation exists on Ingress %s/%s: %w", namespace, ingName, IngressWithoutForwardingRuleError)
+	}
 
-	if foundMatchingIP {
-		return fwdRule.SelfLink, nil
+	var ips []string
+	for _, ingStatus := range ing.Status.LoadBalancer.Ingress {
+		ips = append(ips, ingStatus.IP)
 	}
-	return "", fmt.Errorf("forwarding rule does not have matching IPAddr to specified service: %w", MismatchedILBIPError)
+	return frNames, ips, nil
 }
 
 // getSubnetURLs will query GCE and gather all the URLs of the provided subnet names
 func (c *Controller) getSubnetURLs(subnets []string) ([]string, error) {
 	var subnetURLs []string
 	for _, subnetName := range subnets {
-		// For shared vpc cases, users must specify full resource path of the subnet
-		_, err := cloud.ParseResourceURL(subnetName)
-		if err == nil {
-			subnetURLs = append(subnetURLs, subnetName)
-			continue
-		}
-		subnet, err := c.cloud.Compute().Subnetworks().Get(context2.Background(), meta.RegionalKey(subnetName, c.cloud.Region()))
+		subnetURL, err := getSubnetURL(c.cloud, subnetName)
 		if err != nil {
-			return subnetURLs, fmt.Errorf("failed to find Subnetwork %s/%s: %w", c.cloud.Region(), subnetName, err)
+			return subnetURLs, err
 		}
-		subnetURLs = append(subnetURLs, subnet.SelfLink)
-
+		subnetURLs = append(subnetURLs, subnetURL)
 	}
 	return subnetURLs, nil
 }
 
-// updateServiceAttachmentStatus updates the CR's status with the GCE Service Attachment URL
-// and the producer forwarding rule
-func (c *Controller) updateServiceAttachmentStatus(cr *sav1.ServiceAttachment, gceSAKey *meta.Key) (*sav1.ServiceAttachment, error) {
+// getSubnetURL returns the URL of the provided subnet name, querying GCE if the name is
+// not already a resource URL
+func getSubnetURL(gceCloud *gce.Cloud, subnetName string) (string, error) {
+	// For shared vpc cases, users must specify full resource path of the subnet
+	if _, err := cloud.ParseResourceURL(subnetName); err == nil {
+		return subnetName, nil
+	}
+	subnet, err := gceCloud.Compute().Subnetworks().Get(context2.Background(), meta.RegionalKey(subnetName, gceCloud.Region()))
+	if err != nil {
+		return "", fmt.Errorf("failed to find Subnetwork %s/%s: %w", gceCloud.Region(), subnetName, err)
+	}
+	return subnet.SelfLink, nil
+}
+
+// updateServiceAttachmentStatus updates the CR's status with the GCE Service Attachment URL,
+// the producer forwarding rule, the hash of the applied configuration and the Drifted condition
+// for the driftedFields that were reverted
+func (c *Controller) updateServiceAttachmentStatus(cr *sav1.ServiceAttachment, gceSAKey *meta.Key, specHash string, driftedFields []string) (*sav1.ServiceAttachment, error) {
 	gceSA, err := c.cloud.Compute().ServiceAttachments().Get(context2.Background(), gceSAKey)
 	if err != nil {
 		return cr, fmt.Errorf("failed to query GCE Service Attachment for key %+v: %w", gceSAKey, err)
@@ -548,6 +700,9 @@ func (c *Controller) updateServiceAttachmentStatus(cr *sav1.ServiceAttachment, g
 	}
 
 	updatedSA.Status.ConsumerForwardingRules = consumers
+	c.recordPendingConsumers(cr, consumers)
+	updatedSA.Status.AppliedSpecHash = specHash
+	setDriftedCondition(&updatedSA.Status, driftedFields)
 
 	if reflect.DeepEqual(cr.Status, updatedSA.Status) {
 		c.logger.V(2).Info("Service Attachment has no status update. Skipping patch", "attachmentKey", klog.KRef(cr.Namespace, cr.Name))
@@ -618,22 +773,32 @@ func (c *Controller) ensureSAFinalizerRemoved(cr *sav1.ServiceAttachment) error
 }
 
 // validateResourceReference will validate that the provided resource reference is
-// for a K8s Service
+// for a K8s Service or Ingress
 func validateResourceReference(ref v1.TypedLocalObjectReference) error {
-	if ref.APIGroup != nil && *ref.APIGroup != "" {
-		return fmt.Errorf("invalid resource reference: %s, apiGroup must be empty or nil", *ref.APIGroup)
+	var apiGroup string
+	if ref.APIGroup != nil {
+		apiGroup = *ref.APIGroup
 	}
 
-	if strings.ToLower(ref.Kind) != svcKind {
-		return fmt.Errorf("invalid resource reference %s, kind must be %q", ref.Kind, svcKind)
+	switch strings.ToLower(ref.Kind) {
+	case svcKind:
+		if apiGroup != "" {
+			return fmt.Errorf("invalid resource reference: %s, apiGroup must be empty or nil", apiGroup)
+		}
+	case ingressKind:
+		if apiGroup != ingressAPIGroup {
+			return fmt.Errorf("invalid resource reference: %s, apiGroup must be %q for kind %s", apiGroup, ingressAPIGroup, ref.Kind)
+		}
+	default:
+		return fmt.Errorf("invalid resource reference %s, kind must be %q or %q", ref.Kind, svcKind, ingressKind)
 	}
 	return nil
 }
 
-// needsUpdate will determine whether ServiceAttachment matches the GCE Service Attachment
-// resource. If not, needsUpdate will return true. needsUpdate will not validate whether
-// the update will be successful or not.
-func needsUpdate(existingSA, desiredSA *ga.ServiceAttachment) (bool, error) {
+// diffServiceAttachments will determine whether ServiceAttachment matches the GCE Service Attachment
+// resource. If not, diffServiceAttachments returns the names of the fields that do not match.
+// diffServiceAttachments will not validate whether the update will be successful or not.
+func diffServiceAttachments(existingSA, desiredSA *ga.ServiceAttachment) ([]string, error) {
 	// NOTE: The selflinks cannot be directly compared as the selflink we generate may not
 	// be the same as the one that eventually gets stored on the GCE object. For example
 	// the controller takes the forwarding rule from the GA FR resource, however if the GCE
@@ -645,21 +810,72 @@ func needsUpdate(existingSA, desiredSA *ga.ServiceAttachment) (bool, error) {
 	// forwarding rule (L4 ILB).
 	existingFR, err := cloud.ParseResourceURL(existingSA.TargetService)
 	if err != nil {
-		return false, fmt.Errorf("serviceAttachment existing target service URL, %q, is malformed: %w", existingSA.TargetService, err)
+		return nil, fmt.Errorf("serviceAttachment existing target service URL, %q, is malformed: %w", existingSA.TargetService, err)
 	}
 	desiredFR, err := cloud.ParseResourceURL(desiredSA.TargetService)
 	if err != nil {
-		return false, fmt.Errorf("serviceAttachment desired target service URL, %q, is malformed: %w", desiredSA.TargetService, err)
+		return nil, fmt.Errorf("serviceAttachment desired target service URL, %q, is malformed: %w", desiredSA.TargetService, err)
 	}
 	if !reflect.DeepEqual(existingFR, desiredFR) {
-		return true, fmt.Errorf("serviceAttachment target service cannot be updated from %s to %s", existingSA.TargetService, desiredSA.TargetService)
+		return []string{"targetService"}, fmt.Errorf("serviceAttachment target service cannot be updated from %s to %s", existingSA.TargetService, desiredSA.TargetService)
 	}
 
-	if len(existingSA.NatSubnets) != len(desiredSA.NatSubnets) {
+	var changedFields []string
+	subnetsChanged, err := natSubnetsChanged(existingSA.NatSubnets, desiredSA.NatSubnets)
+	if err != nil {
+		return nil, err
+	}
+	if subnetsChanged {
+		changedFields = append(changedFields, "natSubnets")
+	}
+
+	// The remaining fields set by the controller can be compared directly.
+	for _, field := range []struct {
+		name              string
+		existing, desired interface{}
+	}{
+		{"connectionPreference", existingSA.ConnectionPreference, desiredSA.ConnectionPreference},
+		{"description", existingSA.Description, desiredSA.Description},
+		{"enableProxyProtocol", existingSA.EnableProxyProtocol, desiredSA.EnableProxyProtocol},
+		{"consumerAcceptLists", existingSA.ConsumerAcceptLists, desiredSA.ConsumerAcceptLists},
+		{"consumerRejectLists", existingSA.ConsumerRejectLists, desiredSA.ConsumerRejectLists},
+		{"reconcileConnections", existingSA.ReconcileConnections, desiredSA.ReconcileConnections},
+		{"domainNames", existingSA.DomainNames, desiredSA.DomainNames},
+	} {
+		if !reflect.DeepEqual(field.existing, field.desired) {
+			changedFields = append(changedFields, field.name)
+		}
+	}
+
+	// Since the fields above were already compared, set them on the desiredCopy to be able to
+	// compare the rest of the fields.
+	desiredCopy := &ga.ServiceAttachment{}
+	*desiredCopy = *desiredSA
+	desiredCopy.TargetService = existingSA.TargetService
+	desiredCopy.NatSubnets = existingSA.NatSubnets
+	desiredCopy.ConnectionPreference = existingSA.ConnectionPreference
+	desiredCopy.Description = existingSA.Description
+	desiredCopy.EnableProxyProtocol = existingSA.EnableProxyProtocol
+	desiredCopy.ConsumerAcceptLists = existingSA.ConsumerAcceptLists
+	desiredCopy.ConsumerRejectLists = existingSA.ConsumerRejectLists
+	desiredCopy.ReconcileConnections = existingSA.ReconcileConnections
+	desiredCopy.DomainNames = existingSA.DomainNames
+	// Set region to avoid selflink mismatches
+	desiredCopy.Region = existingSA.Region
+	if !reflect.DeepEqual(desiredCopy, existingSA) {
+		changedFields = append(changedFields, "other")
+	}
+	return changedFields, nil
+}
+
+// natSubnetsChanged returns true if the existing NAT subnets are not the desired ones. Subnets
+// are compared by resource ID, as the API version of the self links may differ.
+func natSubnetsChanged(existingSubnets, desiredSubnets []string) (bool, error) {
+	if len(existingSubnets) != len(desiredSubnets) {
 		return true, nil
 	}
 	subnets := make(map[string]*cloud.ResourceID)
-	for _, subnet := range existingSA.NatSubnets {
+	for _, subnet := range existingSubnets {
 		existingSN, err := cloud.ParseResourceURL(subnet)
 		if err != nil {
 			return false, fmt.Errorf("serviceAttachment existing subnet URL, %q, is malformed: %w", subnet, err)
@@ -667,31 +883,68 @@ func needsUpdate(existingSA, desiredSA *ga.ServiceAttachment) (bool, error) {
 		subnets[existingSN.Key.Name] = existingSN
 	}
 
-	for _, desiredSubnet := range desiredSA.NatSubnets {
+	for _, desiredSubnet := range desiredSubnets {
 		desiredSN, err := cloud.ParseResourceURL(desiredSubnet)
 		if err != nil {
 			return false, fmt.Errorf("serviceAttachment desired subnet has malformed URL: %w", err)
 		}
 
-		if existingSubnet, ok := subnets[desiredSN.Key.Name]; ok {
-			if !reflect.DeepEqual(existingSubnet, desiredSN) {
-				return true, nil
-			}
-			continue
+		existingSubnet, ok := subnets[desiredSN.Key.Name]
+		if !ok || !reflect.DeepEqual(existingSubnet, desiredSN) {
+			return true, nil
 		}
-		return true, nil
 	}
+	return false, nil
+}
 
-	// Since forwarding rules and subnets are the same, set them on the desiredCopy to be able to
-	// compare the rest of the fields.
-	desiredCopy := &ga.ServiceAttachment{}
-	*desiredCopy = *desiredSA
-	desiredCopy.TargetService = existingSA.TargetService
-	desiredCopy.NatSubnets = existingSA.NatSubnets
-	// Set region to avoid selflink mismatches
-	desiredCopy.Region = existingSA.Region
+// appliedSpecHash returns the hash of the fields of the desired GCE Service Attachment that are
+// derived from the spec of the CR.
+func appliedSpecHash(sa *ga.ServiceAttachment) (string, error) {
+	applied := &ga.ServiceAttachment{
+		ConnectionPreference: sa.ConnectionPreference,
+		ConsumerAcceptLists:  sa.ConsumerAcceptLists,
+		ConsumerRejectLists:  sa.ConsumerRejectLists,
+		Description:          sa.Description,
+		DomainNames:          sa.DomainNames,
+		EnableProxyProtocol:  sa.EnableProxyProtocol,
+		NatSubnets:           sa.NatSubnets,
+		ReconcileConnections: sa.ReconcileConnections,
+	}
+	data, err := json.Marshal(applied)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash Service Attachment configuration: %w", err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
 
-	return !reflect.DeepEqual(desiredCopy, existingSA), nil
+// setDriftedCondition sets the Drifted condition of the status with the fields of the GCE
+// Service Attachment that were reverted.
+func setDriftedCondition(status *sav1.ServiceAttachmentStatus, driftedFields []string) {
+	condition := sav1.Condition{
+		Type:    sav1.Drifted,
+		Status:  metav1.ConditionFalse,
+		Reason:  "InSync",
+		Message: "GCE Service Attachment matches the spec",
+	}
+	if len(driftedFields) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ChangesReverted"
+		condition.Message = fmt.Sprintf("Fields %s of the GCE Service Attachment were changed outside of the controller and were reverted", strings.Join(driftedFields, ", "))
+	}
+
+	for i, existing := range status.Conditions {
+		if existing.Type != condition.Type {
+			continue
+		}
+		condition.LastTransitionTime = existing.LastTransitionTime
+		if existing.Status != condition.Status {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		status.Conditions[i] = condition
+		return
+	}
+	condition.LastTransitionTime = metav1.Now()
+	status.Conditions = append(status.Conditions, condition)
 }
 
 // shouldProcess checks if service attachment should be processed or not.
@@ -736,6 +989,100 @@ func convertAllowList(spec sav1.ServiceAttachmentSpec) []*ga.ServiceAttachmentCo
 	return acceptList
 }
 
+// consumerConnectionDecisions validates the consumer connection decisions of the Service
+// Attachment spec and returns them by consumer project. Decisions for projects that are in the
+// static allow or reject lists of the spec are ignored, as the static lists take precedence.
+func consumerConnectionDecisions(spec sav1.ServiceAttachmentSpec) (map[string]sav1.ConsumerConnectionDecision, error) {
+	staticProjects := sets.New(spec.ConsumerRejectList...)
+	for _, consumer := range spec.ConsumerAllowList {
+		staticProjects.Insert(consumer.Project)
+	}
+
+	decisions := make(map[string]sav1.ConsumerConnectionDecision)
+	for _, decision := range spec.ConsumerConnectionDecisions {
+		if decision.Decision != sav1.ConsumerConnectionAccept && decision.Decision != sav1.ConsumerConnectionReject {
+			return nil, fmt.Errorf("%w: decision for %q must be %q or %q, got %q", InvalidConsumerConnectionDecisionError, decision.ForwardingRuleURL, sav1.ConsumerConnectionAccept, sav1.ConsumerConnectionReject, decision.Decision)
+		}
+		// PSC accepts or rejects connections per consumer project, so the decision applies to
+		// the project of the consumer forwarding rule.
+		resourceID, err := cloud.ParseResourceURL(decision.ForwardingRuleURL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: forwarding rule URL %q is malformed: %v", InvalidConsumerConnectionDecisionError, decision.ForwardingRuleURL, err)
+		}
+		project := resourceID.ProjectID
+		if staticProjects.Has(project) {
+			continue
+		}
+		if existing, ok := decisions[project]; ok && existing.Decision != decision.Decision {
+			return nil, fmt.Errorf("%w: conflicting decisions for forwarding rules %q and %q of project %s", InvalidConsumerConnectionDecisionError, existing.ForwardingRuleURL, decision.ForwardingRuleURL, project)
+		}
+		decisions[project] = decision
+	}
+	return decisions, nil
+}
+
+// consumerLists returns the consumer accept and reject lists of the GCE Service Attachment,
+// made of the static lists of the spec followed by the projects of the connection decisions.
+func consumerLists(spec sav1.ServiceAttachmentSpec, decisions map[string]sav1.ConsumerConnectionDecision) ([]*ga.ServiceAttachmentConsumerProjectLimit, []string) {
+	acceptList := convertAllowList(spec)
+	rejectList := slices.Clone(spec.ConsumerRejectList)
+	for _, project := range sets.List(sets.KeySet(decisions)) {
+		decision := decisions[project]
+		if decision.Decision == sav1.ConsumerConnectionAccept {
+			acceptList = append(acceptList, &ga.ServiceAttachmentConsumerProjectLimit{
+				ConnectionLimit: decision.ConnectionLimit,
+				ProjectIdOrNum:  project,
+			})
+			continue
+		}
+		rejectList = append(rejectList, project)
+	}
+	return acceptList, rejectList
+}
+
+// recordConsumerConnectionDecisions emits an event for each connection decision that was newly
+// applied to the GCE Service Attachment, compared to the existingSA (nil on creation).
+func (c *Controller) recordConsumerConnectionDecisions(cr *sav1.ServiceAttachment, existingSA *ga.ServiceAttachment, decisions map[string]sav1.ConsumerConnectionDecision) {
+	accepted := sets.New[string]()
+	rejected := sets.New[string]()
+	if existingSA != nil {
+		for _, consumer := range existingSA.ConsumerAcceptLists {
+			accepted.Insert(consumer.ProjectIdOrNum)
+		}
+		rejected.Insert(existingSA.ConsumerRejectLists...)
+	}
+
+	for _, project := range sets.List(sets.KeySet(decisions)) {
+		decision := decisions[project]
+		switch {
+		case decision.Decision == sav1.ConsumerConnectionAccept && !accepted.Has(project):
+			c.recorder(cr.Namespace).Eventf(cr, v1.EventTypeNormal, "ConsumerConnectionAccepted",
+				"Consumer project %s was accepted following the decision for forwarding rule %s", project, decision.ForwardingRuleURL)
+		case decision.Decision == sav1.ConsumerConnectionReject && !rejected.Has(project):
+			c.recorder(cr.Namespace).Eventf(cr, v1.EventTypeNormal, "ConsumerConnectionRejected",
+				"Consumer project %s was rejected following the decision for forwarding rule %s", project, decision.ForwardingRuleURL)
+		}
+	}
+}
+
+// recordPendingConsumers emits an event for each consumer forwarding rule that is waiting for a
+// connection decision and was not already pending in the status of the CR.
+func (c *Controller) recordPendingConsumers(cr *sav1.ServiceAttachment, consumers []sav1.ConsumerForwardingRule) {
+	pending := sets.New[string]()
+	for _, consumer := range cr.Status.ConsumerForwardingRules {
+		if consumer.Status == consumerStatusPending {
+			pending.Insert(consumer.ForwardingRuleURL)
+		}
+	}
+	for _, consumer := range consumers {
+		if consumer.Status != consumerStatusPending || pending.Has(consumer.ForwardingRuleURL) {
+			continue
+		}
+		c.recorder(cr.Namespace).Eventf(cr, v1.EventTypeNormal, "ConsumerConnectionPending",
+			"Consumer forwarding rule %s is waiting for a connection decision", consumer.ForwardingRuleURL)
+	}
+}
+
 // SvcAttachmentKeyFunc provides the service attachment key used
 // by the svcAttachmentLister
 func SvcAttachmentKeyFunc(namespace, name string) string {
@@ -26,13 +26,16 @@ import (
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	ga "google.golang.org/api/compute/v1"
 	"google.golang.org/api/googleapi"
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/cloud-provider-gcp/providers/gce"
 	"k8s.io/ingress-gce/pkg/annotations"
@@ -40,6 +43,7 @@ import (
 	"k8s.io/ingress-gce/pkg/composite"
 	"k8s.io/ingress-gce/pkg/context"
 	"k8s.io/ingress-gce/pkg/flags"
+	"k8s.io/ingress-gce/pkg/psc/metrics"
 	safake "k8s.io/ingress-gce/pkg/serviceattachment/client/clientset/versioned/fake"
 	"k8s.io/ingress-gce/pkg/test"
 	"k8s.io/ingress-gce/pkg/utils/namer"
@@ -347,6 +351,211 @@ func TestServiceAttachmentCreation(t *testing.T) {
 	}
 }
 
+func TestServiceAttachmentIngressReference(t *testing.T) {
+	saName := "my-sa"
+	ingName := "my-ingress"
+	frIPAddr := "1.2.3.4"
+	ingressRef := v1.TypedLocalObjectReference{
+		APIGroup: utilpointer.StringPtr("networking.k8s.io"),
+		Kind:     "Ingress",
+		Name:     ingName,
+	}
+
+	testCases := []struct {
+		desc          string
+		resourceRef   v1.TypedLocalObjectReference
+		ingressClass  string
+		ingExists     bool
+		frAnnotations map[string]string
+		expectFRName  string
+		expectErr     bool
+		// expectErrType is the error wrapped by the returned error, if any.
+		expectErrType error
+	}{
+		{
+			desc:          "internal ingress with http and https forwarding rules",
+			resourceRef:   ingressRef,
+			ingressClass:  annotations.GceL7ILBIngressClass,
+			ingExists:     true,
+			frAnnotations: map[string]string{annotations.HttpForwardingRuleKey: "http-fr", annotations.HttpsForwardingRuleKey: "https-fr"},
+			expectFRName:  "https-fr",
+		},
+		{
+			desc:          "internal ingress with http forwarding rule",
+			resourceRef:   ingressRef,
+			ingressClass:  annotations.GceL7ILBIngressClass,
+			ingExists:     true,
+			frAnnotations: map[string]string{annotations.HttpForwardingRuleKey: "http-fr"},
+			expectFRName:  "http-fr",
+		},
+		{
+			desc:          "internal ingress without forwarding rules",
+			resourceRef:   ingressRef,
+			ingressClass:  annotations.GceL7ILBIngressClass,
+			ingExists:     true,
+			expectErr:     true,
+			expectErrType: IngressWithoutForwardingRuleError,
+		},
+		{
+			desc:          "external ingress",
+			resourceRef:   ingressRef,
+			ingressClass:  annotations.GceIngressClass,
+			ingExists:     true,
+			frAnnotations: map[string]string{annotations.HttpForwardingRuleKey: "http-fr"},
+			expectErr:     true,
+			expectErrType: NotInternalIngressError,
+		},
+		{
+			desc:          "ingress does not exist",
+			resourceRef:   ingressRef,
+			expectErr:     true,
+			expectErrType: IngressNotFoundError,
+		},
+		{
+			desc: "ingress reference without api group",
+			resourceRef: v1.TypedLocalObjectReference{
+				Kind: "Ingress",
+				Name: ingName,
+			},
+			ingressClass:  annotations.GceL7ILBIngressClass,
+			ingExists:     true,
+			frAnnotations: map[string]string{annotations.HttpForwardingRuleKey: "http-fr"},
+			expectErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			controller := newTestController("ZONAL")
+			fakeCloud := controller.cloud
+
+			if tc.ingExists {
+				ing := &networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:   testNamespace,
+						Name:        ingName,
+						Annotations: map[string]string{annotations.IngressClassKey: tc.ingressClass},
+					},
+					Status: networkingv1.IngressStatus{
+						LoadBalancer: networkingv1.IngressLoadBalancerStatus{
+							Ingress: []networkingv1.IngressLoadBalancerIngress{{IP: frIPAddr}},
+						},
+					},
+				}
+				for key, frName := range tc.frAnnotations {
+					ing.Annotations[key] = frName
+					if _, err := createForwardingRule(fakeCloud, frName, frIPAddr); err != nil {
+						t.Fatalf("%s", err)
+					}
+				}
+				if err := controller.ingressLister.Add(ing); err != nil {
+					t.Fatalf("Failed to add ingress to lister: %v", err)
+				}
+			}
+			if _, err := createNatSubnet(fakeCloud, "my-subnet"); err != nil {
+				t.Fatalf("%s", err)
+			}
+
+			saCR := testServiceAttachmentCR(saName, "", "service-attachment-uid", []string{"my-subnet"}, false, false)
+			saCR.Spec.ResourceRef = tc.resourceRef
+			controller.saClient.NetworkingV1().ServiceAttachments(testNamespace).Create(context2.TODO(), saCR, metav1.CreateOptions{})
+			syncServiceAttachmentLister(controller)
+
+			err := controller.processServiceAttachment(SvcAttachmentKeyFunc(testNamespace, saName))
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("processServiceAttachment() returned nil, want error")
+				}
+				if tc.expectErrType != nil && !errors.Is(err, tc.expectErrType) {
+					t.Errorf("processServiceAttachment() returned %v, want %v", err, tc.expectErrType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error processing Service Attachment: %s", err)
+			}
+
+			gceSAName := controller.saNamer.ServiceAttachment(testNamespace, saName, string(saCR.UID))
+			sa, err := getServiceAttachment(fakeCloud, gceSAName)
+			if err != nil {
+				t.Fatalf("%s", err)
+			}
+			targetFR, err := cloud.ParseResourceURL(sa.TargetService)
+			if err != nil {
+				t.Fatalf("Failed to parse target service %q: %v", sa.TargetService, err)
+			}
+			if targetFR.Key.Name != tc.expectFRName {
+				t.Errorf("Service attachment targets forwarding rule %q, want %q", targetFR.Key.Name, tc.expectFRName)
+			}
+		})
+	}
+}
+
+func TestServiceAttachmentMultiProtocolService(t *testing.T) {
+	saName := "my-sa"
+	svcName := "my-service"
+	frIPAddr := "1.2.3.4"
+	controller := newTestController("ZONAL")
+	fakeCloud := controller.cloud
+
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      svcName,
+			Annotations: map[string]string{
+				annotations.TCPForwardingRuleKey: "tcp-fr",
+				annotations.UDPForwardingRuleKey: "udp-fr",
+			},
+		},
+		Status: v1.ServiceStatus{
+			LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{IP: frIPAddr}},
+			},
+		},
+	}
+	if err := controller.serviceLister.Add(svc); err != nil {
+		t.Fatalf("Failed to add service to lister: %v", err)
+	}
+	// Only the UDP forwarding rule exists, the Service Attachment targets it.
+	if _, err := createForwardingRule(fakeCloud, "udp-fr", frIPAddr); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if _, err := createNatSubnet(fakeCloud, "my-subnet"); err != nil {
+		t.Fatalf("%s", err)
+	}
+	saCR := testServiceAttachmentCR(saName, svcName, "service-attachment-uid", []string{"my-subnet"}, false, false)
+	controller.saClient.NetworkingV1().ServiceAttachments(testNamespace).Create(context2.TODO(), saCR, metav1.CreateOptions{})
+	syncServiceAttachmentLister(controller)
+
+	gceSAName := controller.saNamer.ServiceAttachment(testNamespace, saName, string(saCR.UID))
+	verifyTarget := func() {
+		t.Helper()
+		if err := controller.processServiceAttachment(SvcAttachmentKeyFunc(testNamespace, saName)); err != nil {
+			t.Fatalf("unexpected error processing Service Attachment: %s", err)
+		}
+		sa, err := getServiceAttachment(fakeCloud, gceSAName)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		targetFR, err := cloud.ParseResourceURL(sa.TargetService)
+		if err != nil {
+			t.Fatalf("Failed to parse target service %q: %v", sa.TargetService, err)
+		}
+		if targetFR.Key.Name != "udp-fr" {
+			t.Errorf("Service attachment targets forwarding rule %q, want %q", targetFR.Key.Name, "udp-fr")
+		}
+	}
+	verifyTarget()
+
+	// The TCP forwarding rule is preferred for new Service Attachments, but the target of the
+	// existing Service Attachment is kept since it cannot be updated.
+	if _, err := createForwardingRule(fakeCloud, "tcp-fr", frIPAddr); err != nil {
+		t.Fatalf("%s", err)
+	}
+	syncServiceAttachmentLister(controller)
+	verifyTarget()
+}
+
 func TestServiceAttachmentConsumers(t *testing.T) {
 
 	saName := "my-sa"
@@ -429,6 +638,300 @@ func TestServiceAttachmentConsumers(t *testing.T) {
 	}
 }
 
+func TestServiceAttachmentConsumerConnectionDecisions(t *testing.T) {
+	saName := "my-sa"
+	svcName := "my-service"
+	saUID := "service-attachment-uid"
+	frIPAddr := "1.2.3.4"
+	controller := newTestController("ZONAL")
+	recorder := record.NewFakeRecorder(10)
+	controller.recorder = func(string) record.EventRecorder { return recorder }
+	gceSAName := controller.saNamer.ServiceAttachment(testNamespace, saName, saUID)
+	_, frName, err := createSvc(controller, svcName, "svc-uid", frIPAddr, annotations.TCPForwardingRuleKey)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if _, err = createForwardingRule(controller.cloud, frName, frIPAddr); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if _, err = createNatSubnet(controller.cloud, "my-subnet"); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	saCR := testServiceAttachmentCR(saName, svcName, saUID, []string{"my-subnet"}, false, false)
+	saCR.Spec.ConnectionPreference = "ACCEPT_MANUAL"
+	saCR.Spec.ConsumerRejectList = []string{"static-project"}
+	if _, err = controller.saClient.NetworkingV1().ServiceAttachments(testNamespace).Create(context2.TODO(), saCR, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create service attachment cr: %q", err)
+	}
+	syncServiceAttachmentLister(controller)
+	key := SvcAttachmentKeyFunc(testNamespace, saName)
+	if err = controller.processServiceAttachment(key); err != nil {
+		t.Fatalf("unexpected error processing service attachment: %q", err)
+	}
+	drainEvents(recorder)
+
+	// Consumers connect to the Service Attachment and wait for a decision.
+	consumerA := cloud.SelfLink(meta.VersionGA, "consumer-a", "forwardingRules", meta.RegionalKey("fr-a", controller.cloud.Region()))
+	consumerB := cloud.SelfLink(meta.VersionGA, "consumer-b", "forwardingRules", meta.RegionalKey("fr-b", controller.cloud.Region()))
+	staticConsumer := cloud.SelfLink(meta.VersionGA, "static-project", "forwardingRules", meta.RegionalKey("fr-static", controller.cloud.Region()))
+	gceSA, err := getServiceAttachment(controller.cloud, gceSAName)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	gceSA.ConnectedEndpoints = []*ga.ServiceAttachmentConnectedEndpoint{
+		{Endpoint: consumerA, Status: consumerStatusPending},
+		{Endpoint: consumerB, Status: consumerStatusPending},
+	}
+	// The mock does not allow updates of the connected endpoints, the GCE Service Attachment is recreated.
+	if err = deleteServiceAttachment(controller.cloud, gceSAName); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = insertServiceAttachment(controller.cloud, gceSA); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = controller.processServiceAttachment(key); err != nil {
+		t.Fatalf("unexpected error processing service attachment: %q", err)
+	}
+	expectEvents(t, recorder, []string{
+		fmt.Sprintf("Normal ConsumerConnectionPending Consumer forwarding rule %s is waiting for a connection decision", consumerA),
+		fmt.Sprintf("Normal ConsumerConnectionPending Consumer forwarding rule %s is waiting for a connection decision", consumerB),
+	})
+
+	// Decisions are translated into the consumer lists. The static reject list takes precedence.
+	saCR, err = controller.saClient.NetworkingV1().ServiceAttachments(testNamespace).Get(context2.TODO(), saName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	saCR.Spec.ConsumerConnectionDecisions = []sav1.ConsumerConnectionDecision{
+		{ForwardingRuleURL: consumerA, Decision: sav1.ConsumerConnectionAccept, ConnectionLimit: 5},
+		{ForwardingRuleURL: consumerB, Decision: sav1.ConsumerConnectionReject},
+		{ForwardingRuleURL: staticConsumer, Decision: sav1.ConsumerConnectionAccept, ConnectionLimit: 5},
+	}
+	if _, err = controller.saClient.NetworkingV1().ServiceAttachments(testNamespace).Update(context2.TODO(), saCR, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("%s", err)
+	}
+	syncServiceAttachmentLister(controller)
+	if err = controller.processServiceAttachment(key); err != nil {
+		t.Fatalf("unexpected error processing service attachment: %q", err)
+	}
+	gceSA, err = getServiceAttachment(controller.cloud, gceSAName)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	expectedAcceptList := []*ga.ServiceAttachmentConsumerProjectLimit{{ProjectIdOrNum: "consumer-a", ConnectionLimit: 5}}
+	if !reflect.DeepEqual(gceSA.ConsumerAcceptLists, expectedAcceptList) {
+		t.Errorf("ConsumerAcceptLists = %+v, want %+v", gceSA.ConsumerAcceptLists, expectedAcceptList)
+	}
+	expectedRejectList := []string{"static-project", "consumer-b"}
+	if !reflect.DeepEqual(gceSA.ConsumerRejectLists, expectedRejectList) {
+		t.Errorf("ConsumerRejectLists = %v, want %v", gceSA.ConsumerRejectLists, expectedRejectList)
+	}
+	expectEvents(t, recorder, []string{
+		fmt.Sprintf("Normal ConsumerConnectionAccepted Consumer project consumer-a was accepted following the decision for forwarding rule %s", consumerA),
+		fmt.Sprintf("Normal ConsumerConnectionRejected Consumer project consumer-b was rejected following the decision for forwarding rule %s", consumerB),
+	})
+
+	// Decisions that are already applied are not recorded again.
+	if err = controller.processServiceAttachment(key); err != nil {
+		t.Fatalf("unexpected error processing service attachment: %q", err)
+	}
+	expectEvents(t, recorder, nil)
+
+	// Invalid decisions are not applied.
+	saCR.Spec.ConsumerConnectionDecisions = []sav1.ConsumerConnectionDecision{
+		{ForwardingRuleURL: consumerA, Decision: "MAYBE"},
+	}
+	if _, err = controller.saClient.NetworkingV1().ServiceAttachments(testNamespace).Update(context2.TODO(), saCR, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("%s", err)
+	}
+	syncServiceAttachmentLister(controller)
+	if err = controller.processServiceAttachment(key); !errors.Is(err, InvalidConsumerConnectionDecisionError) {
+		t.Errorf("processServiceAttachment() returned error %v, want %v", err, InvalidConsumerConnectionDecisionError)
+	}
+}
+
+func TestConsumerConnectionDecisions(t *testing.T) {
+	consumerA1 := cloud.SelfLink(meta.VersionGA, "consumer-a", "forwardingRules", meta.RegionalKey("fr-1", "us-central1"))
+	consumerA2 := cloud.SelfLink(meta.VersionGA, "consumer-a", "forwardingRules", meta.RegionalKey("fr-2", "us-central1"))
+	consumerB := cloud.SelfLink(meta.VersionGA, "consumer-b", "forwardingRules", meta.RegionalKey("fr-1", "us-central1"))
+
+	testCases := []struct {
+		desc      string
+		spec      sav1.ServiceAttachmentSpec
+		expected  map[string]sav1.ConsumerConnectionDecision
+		expectErr bool
+	}{
+		{
+			desc:     "no decisions",
+			expected: map[string]sav1.ConsumerConnectionDecision{},
+		},
+		{
+			desc: "decisions by project",
+			spec: sav1.ServiceAttachmentSpec{
+				ConsumerConnectionDecisions: []sav1.ConsumerConnectionDecision{
+					{ForwardingRuleURL: consumerA1, Decision: sav1.ConsumerConnectionAccept, ConnectionLimit: 2},
+					{ForwardingRuleURL: consumerA2, Decision: sav1.ConsumerConnectionAccept, ConnectionLimit: 2},
+					{ForwardingRuleURL: consumerB, Decision: sav1.ConsumerConnectionReject},
+				},
+			},
+			expected: map[string]sav1.ConsumerConnectionDecision{
+				"consumer-a": {ForwardingRuleURL: consumerA2, Decision: sav1.ConsumerConnectionAccept, ConnectionLimit: 2},
+				"consumer-b": {ForwardingRuleURL: consumerB, Decision: sav1.ConsumerConnectionReject},
+			},
+		},
+		{
+			desc: "static allow list takes precedence",
+			spec: sav1.ServiceAttachmentSpec{
+				ConsumerAllowList: []sav1.ConsumerProject{{Project: "consumer-b", ConnectionLimit: 1}},
+				ConsumerConnectionDecisions: []sav1.ConsumerConnectionDecision{
+					{ForwardingRuleURL: consumerB, Decision: sav1.ConsumerConnectionReject},
+				},
+			},
+			expected: map[string]sav1.ConsumerConnectionDecision{},
+		},
+		{
+			desc: "conflicting decisions for a project",
+			spec: sav1.ServiceAttachmentSpec{
+				ConsumerConnectionDecisions: []sav1.ConsumerConnectionDecision{
+					{ForwardingRuleURL: consumerA1, Decision: sav1.ConsumerConnectionAccept},
+					{ForwardingRuleURL: consumerA2, Decision: sav1.ConsumerConnectionReject},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			desc: "malformed forwarding rule URL",
+			spec: sav1.ServiceAttachmentSpec{
+				ConsumerConnectionDecisions: []sav1.ConsumerConnectionDecision{
+					{ForwardingRuleURL: "fr-1", Decision: sav1.ConsumerConnectionAccept},
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			decisions, err := consumerConnectionDecisions(tc.spec)
+			if tc.expectErr {
+				if !errors.Is(err, InvalidConsumerConnectionDecisionError) {
+					t.Errorf("consumerConnectionDecisions() returned error %v, want %v", err, InvalidConsumerConnectionDecisionError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("consumerConnectionDecisions() returned unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(decisions, tc.expected) {
+				t.Errorf("consumerConnectionDecisions() = %+v, want %+v", decisions, tc.expected)
+			}
+		})
+	}
+}
+
+func TestServiceAttachmentDrift(t *testing.T) {
+	saName := "my-sa"
+	svcName := "my-service"
+	saUID := "service-attachment-uid"
+	frIPAddr := "1.2.3.4"
+	controller := newTestController("ZONAL")
+	recorder := record.NewFakeRecorder(10)
+	controller.recorder = func(string) record.EventRecorder { return recorder }
+	gceSAName := controller.saNamer.ServiceAttachment(testNamespace, saName, saUID)
+	_, frName, err := createSvc(controller, svcName, "svc-uid", frIPAddr, annotations.TCPForwardingRuleKey)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if _, err = createForwardingRule(controller.cloud, frName, frIPAddr); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if _, err = createNatSubnet(controller.cloud, "my-subnet"); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	saCR := testServiceAttachmentCR(saName, svcName, saUID, []string{"my-subnet"}, false, true)
+	saCR.Spec.ReconcileConnections = true
+	saCR.Spec.DomainNames = []string{"my-sa.example.com."}
+	if _, err = controller.saClient.NetworkingV1().ServiceAttachments(testNamespace).Create(context2.TODO(), saCR, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create service attachment cr: %q", err)
+	}
+	syncServiceAttachmentLister(controller)
+	key := SvcAttachmentKeyFunc(testNamespace, saName)
+	if err = controller.processServiceAttachment(key); err != nil {
+		t.Fatalf("unexpected error processing service attachment: %q", err)
+	}
+	createdSA, err := getServiceAttachment(controller.cloud, gceSAName)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !createdSA.EnableProxyProtocol || !createdSA.ReconcileConnections || !reflect.DeepEqual(createdSA.DomainNames, saCR.Spec.DomainNames) {
+		t.Errorf("GCE Service Attachment %+v does not match the spec %+v", createdSA, saCR.Spec)
+	}
+	verifyDriftedCondition(t, controller, saName, metav1.ConditionFalse)
+	drainEvents(recorder)
+
+	// The GCE Service Attachment is changed outside of the controller.
+	driftedSA := &ga.ServiceAttachment{}
+	*driftedSA = *createdSA
+	driftedSA.EnableProxyProtocol = false
+	driftedSA.ReconcileConnections = false
+	saKey := meta.RegionalKey(gceSAName, controller.cloud.Region())
+	if err = controller.cloud.Compute().ServiceAttachments().Patch(context2.TODO(), saKey, driftedSA); err != nil {
+		t.Fatalf("%s", err)
+	}
+	driftBefore := testutil.ToFloat64(metrics.DriftedFields.WithLabelValues("enableProxyProtocol"))
+	syncServiceAttachmentLister(controller)
+	if err = controller.processServiceAttachment(key); err != nil {
+		t.Fatalf("unexpected error processing service attachment: %q", err)
+	}
+	revertedSA, err := getServiceAttachment(controller.cloud, gceSAName)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !revertedSA.EnableProxyProtocol || !revertedSA.ReconcileConnections {
+		t.Errorf("GCE Service Attachment changes were not reverted, got %+v", revertedSA)
+	}
+	expectEvents(t, recorder, []string{
+		fmt.Sprintf("Warning ServiceAttachmentDrifted Fields enableProxyProtocol, reconcileConnections of GCE Service Attachment %s were changed outside of the controller and were reverted", gceSAName),
+	})
+	verifyDriftedCondition(t, controller, saName, metav1.ConditionTrue)
+	if got := testutil.ToFloat64(metrics.DriftedFields.WithLabelValues("enableProxyProtocol")) - driftBefore; got != 1 {
+		t.Errorf("Drifted enableProxyProtocol metric increased by %v, want 1", got)
+	}
+
+	// The next reconciliation finds the GCE Service Attachment in sync.
+	syncServiceAttachmentLister(controller)
+	if err = controller.processServiceAttachment(key); err != nil {
+		t.Fatalf("unexpected error processing service attachment: %q", err)
+	}
+	expectEvents(t, recorder, nil)
+	verifyDriftedCondition(t, controller, saName, metav1.ConditionFalse)
+
+	// Updates of the spec are not drift.
+	saCR, err = controller.saClient.NetworkingV1().ServiceAttachments(testNamespace).Get(context2.TODO(), saName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	saCR.Spec.ProxyProtocol = false
+	if _, err = controller.saClient.NetworkingV1().ServiceAttachments(testNamespace).Update(context2.TODO(), saCR, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("%s", err)
+	}
+	syncServiceAttachmentLister(controller)
+	if err = controller.processServiceAttachment(key); err != nil {
+		t.Fatalf("unexpected error processing service attachment: %q", err)
+	}
+	updatedSA, err := getServiceAttachment(controller.cloud, gceSAName)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if updatedSA.EnableProxyProtocol {
+		t.Errorf("GCE Service Attachment was not updated with the spec, got %+v", updatedSA)
+	}
+	expectEvents(t, recorder, nil)
+	verifyDriftedCondition(t, controller, saName, metav1.ConditionFalse)
+}
+
 func TestServiceAttachmentUpdate(t *testing.T) {
 	saName := "my-sa"
 	svcName := "my-service"
@@ -444,11 +947,12 @@ func TestServiceAttachmentUpdate(t *testing.T) {
 	saCRWithAnnotation.Annotations = map[string]string{"some-key": "some-value"}
 
 	testcases := []struct {
-		desc            string
-		updatedSACR     *sav1.ServiceAttachment
-		expectSAUpdate  bool
-		expectError     bool
-		expectedSubnets []string
+		desc               string
+		updatedSACR        *sav1.ServiceAttachment
+		expectSAUpdate     bool
+		expectStatusUpdate bool
+		expectError        bool
+		expectedSubnets    []string
 	}{
 		{
 			desc:            "update metadata annotation",
@@ -470,10 +974,12 @@ func TestServiceAttachmentUpdate(t *testing.T) {
 			expectedSubnets: []string{subnet1, subnet2},
 		},
 		{ // though this case checks that the SA is updated, this would fail on the GCE update because subnets cannot be removed
-			desc:            "update one of the subnets",
-			updatedSACR:     testServiceAttachmentCR(saName, svcName, saUID, []string{subnet1, subnet3}, false, true),
-			expectSAUpdate:  true,
-			expectedSubnets: []string{subnet1, subnet3},
+			desc:           "update one of the subnets",
+			updatedSACR:    testServiceAttachmentCR(saName, svcName, saUID, []string{subnet1, subnet3}, false, true),
+			expectSAUpdate: true,
+			// The hash of the applied configuration changes.
+			expectStatusUpdate: true,
+			expectedSubnets:    []string{subnet1, subnet3},
 		},
 	}
 
@@ -579,15 +1085,15 @@ func TestServiceAttachmentUpdate(t *testing.T) {
 				t.Fatalf("Failed to get service attachment cr: %q", err)
 			}
 
-			// No changes in the GCE SA status so CR status should not be updated
-			if err = validateSAStatus(updatedCR.Status, expectedSA, beforeTS, false); err != nil {
+			// CR status should only be updated if the applied configuration changed
+			if err = validateSAStatus(updatedCR.Status, expectedSA, beforeTS, tc.expectStatusUpdate); err != nil {
 				t.Errorf("ServiceAttachment CR does not have correct status: %q", err)
 			}
 		})
 	}
 }
 
-func TestNeedsUpdate(t *testing.T) {
+func TestDiffServiceAttachments(t *testing.T) {
 	subnet1 := "https://www.googleapis.com/compute/v1/projects/test-project/regions/us-central1/subnetworks/subnet-1"
 	subnet2 := "https://www.googleapis.com/compute/v1/projects/test-project/regions/us-central1/subnetworks/subnet-2"
 	subnet3 := "https://www.googleapis.com/compute/v1/projects/test-project/regions/us-central1/subnetworks/subnet-3"
@@ -622,58 +1128,79 @@ func TestNeedsUpdate(t *testing.T) {
 	*saDiffSpec = *originalSA
 	saDiffSpec.EnableProxyProtocol = false
 
+	saDiffLists := &ga.ServiceAttachment{}
+	*saDiffLists = *originalSA
+	saDiffLists.ConsumerAcceptLists = []*ga.ServiceAttachmentConsumerProjectLimit{{ProjectIdOrNum: "consumer", ConnectionLimit: 2}}
+	saDiffLists.ReconcileConnections = true
+	saDiffLists.DomainNames = []string{"my-sa.example.com."}
+
+	saDiffOther := &ga.ServiceAttachment{}
+	*saDiffOther = *originalSA
+	saDiffOther.Name = "other-sa"
+
 	saNoChange := &ga.ServiceAttachment{}
 	*saNoChange = *originalSA
 
 	testcases := []struct {
-		desc         string
-		newSA        *ga.ServiceAttachment
-		expectError  bool
-		expectUpdate bool
+		desc                  string
+		newSA                 *ga.ServiceAttachment
+		expectError           bool
+		expectedChangedFields []string
 	}{
 		{
-			desc:         "change the target service/forwarding rule",
-			newSA:        saDiffService,
-			expectError:  true,
-			expectUpdate: true,
+			desc:                  "change the target service/forwarding rule",
+			newSA:                 saDiffService,
+			expectError:           true,
+			expectedChangedFields: []string{"targetService"},
 		},
 		{
-			desc:         "change a subnet",
-			newSA:        saDiffSubnets,
-			expectError:  false,
-			expectUpdate: true,
+			desc:                  "change a subnet",
+			newSA:                 saDiffSubnets,
+			expectError:           false,
+			expectedChangedFields: []string{"natSubnets"},
 		},
 		{
-			desc:         "add a subnet",
-			newSA:        saAddSubnet,
-			expectError:  false,
-			expectUpdate: true,
+			desc:                  "add a subnet",
+			newSA:                 saAddSubnet,
+			expectError:           false,
+			expectedChangedFields: []string{"natSubnets"},
 		},
 		{
-			desc:         "change spec",
-			newSA:        saDiffSpec,
-			expectError:  false,
-			expectUpdate: true,
+			desc:                  "change spec",
+			newSA:                 saDiffSpec,
+			expectError:           false,
+			expectedChangedFields: []string{"enableProxyProtocol"},
 		},
 		{
-			desc:         "no change",
-			newSA:        saNoChange,
-			expectError:  false,
-			expectUpdate: false,
+			desc:                  "change consumer list and connection fields",
+			newSA:                 saDiffLists,
+			expectError:           false,
+			expectedChangedFields: []string{"consumerAcceptLists", "reconcileConnections", "domainNames"},
+		},
+		{
+			desc:                  "change a field without special handling",
+			newSA:                 saDiffOther,
+			expectError:           false,
+			expectedChangedFields: []string{"other"},
+		},
+		{
+			desc:        "no change",
+			newSA:       saNoChange,
+			expectError: false,
 		},
 	}
 
 	for _, tc := range testcases {
 		t.Run(tc.desc, func(t *testing.T) {
-			needsUpdate, err := needsUpdate(originalSA, tc.newSA)
+			changedFields, err := diffServiceAttachments(originalSA, tc.newSA)
 			if tc.expectError && err == nil {
 				t.Errorf("expected an error but got none")
 			} else if !tc.expectError && err != nil {
 				t.Errorf("unexpected error: %q", err)
 			}
 
-			if needsUpdate != tc.expectUpdate {
-				t.Errorf("needsUpdate returned %t, expected %t", needsUpdate, tc.expectUpdate)
+			if !reflect.DeepEqual(changedFields, tc.expectedChangedFields) {
+				t.Errorf("diffServiceAttachments returned %v, expected %v", changedFields, tc.expectedChangedFields)
 			}
 		})
 	}
@@ -1341,3 +1868,53 @@ func verifyGCEServiceAttachmentDeletion(controller *Controller, sa *sav1.Service
 	}
 	return nil
 }
+
+// drainEvents discards the events recorded so far.
+func drainEvents(recorder *record.FakeRecorder) {
+	for {
+		select {
+		case <-recorder.Events:
+		default:
+			return
+		}
+	}
+}
+
+// expectEvents checks that the recorder received exactly the expected events, in order.
+func expectEvents(t *testing.T, recorder *record.FakeRecorder, expected []string) {
+	t.Helper()
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+			continue
+		default:
+		}
+		break
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("recorded events = %q, want %q", events, expected)
+	}
+}
+
+// verifyDriftedCondition checks the status of the Drifted condition of the ServiceAttachment CR.
+func verifyDriftedCondition(t *testing.T, controller *Controller, saName string, expectedStatus metav1.ConditionStatus) {
+	t.Helper()
+	cr, err := controller.saClient.NetworkingV1().ServiceAttachments(testNamespace).Get(context2.TODO(), saName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get service attachment cr: %q", err)
+	}
+	if cr.Status.AppliedSpecHash == "" {
+		t.Errorf("ServiceAttachment CR status has no applied spec hash")
+	}
+	for _, condition := range cr.Status.Conditions {
+		if condition.Type == sav1.Drifted {
+			if condition.Status != expectedStatus {
+				t.Errorf("Drifted condition status is %s, want %s: %+v", condition.Status, expectedStatus, condition)
+			}
+			return
+		}
+	}
+	t.Errorf("ServiceAttachment CR status has no Drifted condition")
+}
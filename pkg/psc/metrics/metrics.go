@@ -27,6 +27,7 @@ const (
 	pscControllerSubsystem = "psc_controller"
 	pscProcessLatency      = "psc_process_duration_seconds"
 	lastProcessTimestamp   = "psc_process_timestamp"
+	driftedFields          = "psc_drifted_fields_total"
 
 	resultSuccess = "success"
 	resultError   = "error"
@@ -45,6 +46,10 @@ var (
 		"process", // type of process loop
 	}
 
+	pscDriftMetricsLabels = []string{
+		"field", // GCE Service Attachment field changed outside of the controller
+	}
+
 	PSCProcessLatency = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Subsystem: pscControllerSubsystem,
@@ -64,6 +69,15 @@ var (
 		},
 		pscProcessTSMetricsLabels,
 	)
+
+	DriftedFields = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: pscControllerSubsystem,
+			Name:      driftedFields,
+			Help:      "Number of GCE Service Attachment fields changed outside of the PSC controller and reverted.",
+		},
+		pscDriftMetricsLabels,
+	)
 )
 
 var register sync.Once
@@ -72,6 +86,7 @@ func RegisterMetrics() {
 	register.Do(func() {
 		prometheus.MustRegister(PSCProcessLatency)
 		prometheus.MustRegister(LastProcessTimestamp)
+		prometheus.MustRegister(DriftedFields)
 	})
 }
 
@@ -88,3 +103,10 @@ func PublishPSCProcessMetrics(process string, err error, start time.Time) {
 	}
 	PSCProcessLatency.WithLabelValues(process, result).Observe(time.Since(start).Seconds())
 }
+
+// PublishDriftMetrics publishes the fields of a GCE Service Attachment that were reverted
+func PublishDriftMetrics(fields []string) {
+	for _, field := range fields {
+		DriftedFields.WithLabelValues(field).Inc()
+	}
+}
@@ -32,6 +32,10 @@ func (c *FakeNetworkingV1) ServiceAttachments(namespace string) v1.ServiceAttach
 	return &FakeServiceAttachments{c, namespace}
 }
 
+func (c *FakeNetworkingV1) ServiceAttachmentConsumers(namespace string) v1.ServiceAttachmentConsumerInterface {
+	return &FakeServiceAttachmentConsumers{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeNetworkingV1) RESTClient() rest.Interface {
@@ -27,6 +27,7 @@ import (
 type NetworkingV1Interface interface {
 	RESTClient() rest.Interface
 	ServiceAttachmentsGetter
+	ServiceAttachmentConsumersGetter
 }
 
 // NetworkingV1Client is used to interact with features provided by the networking.gke.io group.
@@ -38,6 +39,10 @@ func (c *NetworkingV1Client) ServiceAttachments(namespace string) ServiceAttachm
 	return newServiceAttachments(c, namespace)
 }
 
+func (c *NetworkingV1Client) ServiceAttachmentConsumers(namespace string) ServiceAttachmentConsumerInterface {
+	return newServiceAttachmentConsumers(c, namespace)
+}
+
 // NewForConfig creates a new NetworkingV1Client for the given config.
 func NewForConfig(c *rest.Config) (*NetworkingV1Client, error) {
 	config := *c
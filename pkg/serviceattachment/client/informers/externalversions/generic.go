@@ -56,6 +56,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=networking.gke.io, Version=v1
 	case v1.SchemeGroupVersion.WithResource("serviceattachments"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Networking().V1().ServiceAttachments().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("serviceattachmentconsumers"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Networking().V1().ServiceAttachmentConsumers().Informer()}, nil
 
 		// Group=networking.gke.io, Version=v1beta1
 	case v1beta1.SchemeGroupVersion.WithResource("serviceattachments"):
@@ -26,6 +26,8 @@ import (
 type Interface interface {
 	// ServiceAttachments returns a ServiceAttachmentInformer.
 	ServiceAttachments() ServiceAttachmentInformer
+	// ServiceAttachmentConsumers returns a ServiceAttachmentConsumerInformer.
+	ServiceAttachmentConsumers() ServiceAttachmentConsumerInformer
 }
 
 type version struct {
@@ -43,3 +45,8 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) ServiceAttachments() ServiceAttachmentInformer {
 	return &serviceAttachmentInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// ServiceAttachmentConsumers returns a ServiceAttachmentConsumerInformer.
+func (v *version) ServiceAttachmentConsumers() ServiceAttachmentConsumerInformer {
+	return &serviceAttachmentConsumerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -25,3 +25,11 @@ type ServiceAttachmentListerExpansion interface{}
 // ServiceAttachmentNamespaceListerExpansion allows custom methods to be added to
 // ServiceAttachmentNamespaceLister.
 type ServiceAttachmentNamespaceListerExpansion interface{}
+
+// ServiceAttachmentConsumerListerExpansion allows custom methods to be added to
+// ServiceAttachmentConsumerLister.
+type ServiceAttachmentConsumerListerExpansion interface{}
+
+// ServiceAttachmentConsumerNamespaceListerExpansion allows custom methods to be added to
+// ServiceAttachmentConsumerNamespaceLister.
+type ServiceAttachmentConsumerNamespaceListerExpansion interface{}
@@ -37,3 +37,18 @@ func CRDMeta() *crd.CRDMeta {
 	)
 	return meta
 }
+
+// ConsumerCRDMeta returns the CRD meta of the ServiceAttachmentConsumer resource
+func ConsumerCRDMeta() *crd.CRDMeta {
+	meta := crd.NewCRDMeta(
+		apisserviceattachment.GroupName,
+		"ServiceAttachmentConsumer",
+		"ServiceAttachmentConsumerList",
+		"serviceattachmentconsumer",
+		"serviceattachmentconsumers",
+		[]*crd.Version{
+			crd.NewVersion("v1", "k8s.io/ingress-gce/pkg/apis/serviceattachment/v1.ServiceAttachmentConsumer", svcattachv1.GetOpenAPIDefinitions, false),
+		},
+	)
+	return meta
+}
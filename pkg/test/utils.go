@@ -550,6 +550,12 @@ func FakeGoogleAPIRequestEntityTooLargeError() *googleapi.Error {
 	return &googleapi.Error{Code: http.StatusRequestEntityTooLarge}
 }
 
+// FakeGoogleAPIMemberAlreadyInAnotherInstanceGroupError creates a BadRequest error with type googleapi.Error
+// returned when adding an instance which is already a member of another load balanced instance group
+func FakeGoogleAPIMemberAlreadyInAnotherInstanceGroupError() *googleapi.Error {
+	return &googleapi.Error{Code: http.StatusBadRequest, Message: "Resource is already a member of another load balanced instance group"}
+}
+
 // FakeGoogleAPIRequestServerError creates a StatusInternalServerError error with type googleapi.Error
 func FakeGoogleAPIRequestServerError() *googleapi.Error {
 	return &googleapi.Error{Code: http.StatusInternalServerError}
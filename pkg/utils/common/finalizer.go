@@ -41,6 +41,9 @@ const (
 	NegFinalizerKey = "networking.gke.io/neg-finalizer"
 	// NetLBFinalizerV2 is the finalizer used by newer controllers that manage L4 External LoadBalancer services.
 	NetLBFinalizerV2 = "gke.networking.io/l4-netlb-v2"
+	// PSCConsumerFinalizerKey is the finalizer used by the PSC consumer controller to ensure the GCE resources
+	// of a ServiceAttachmentConsumer are deleted before the CR
+	PSCConsumerFinalizerKey = "networking.gke.io/psc-consumer-finalizer"
 	// LoadBalancerCleanupFinalizer added by original kubernetes service controller. This is not required in L4 RBS/ILB-subsetting services.
 	LoadBalancerCleanupFinalizer = "service.kubernetes.io/load-balancer-cleanup"
 )
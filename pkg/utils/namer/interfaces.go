@@ -60,12 +60,21 @@ type BackendNamer interface {
 	// RXLBBackendName returns the Regional External Ingress backend name,
 	// based on the service namespace, name and target port.
 	RXLBBackendName(namespace, name string, port int32) string
+	// ResourceNEG returns the gce neg name of the NEG declared in the spec of
+	// a ServiceNetworkEndpointGroup, based on its namespace and name.
+	ResourceNEG(namespace, name string) string
 	// L4Backend returns the name for L4 LB backend resources, based on the service namespace and name.
 	// It supports ILB with subsetting enabled (VM_IP_NEGs) and NetLB with RBS enabled.
 	// The second output parameter indicates if the namer is supported.
 	L4Backend(namespace, name string) string
 	// InstanceGroup constructs the name for an Instance Group.
 	InstanceGroup() string
+	// InstanceGroupShard constructs the name for the shard of an Instance
+	// Group with the given index.
+	InstanceGroupShard(shard int) string
+	// IsInstanceGroupShard returns true if the given name is the name of a
+	// shard of the Instance Group other than the first one.
+	IsInstanceGroupShard(name string) bool
 	// NamedPort returns the name for a named port.
 	NamedPort(port int64) string
 	// NameBelongsToCluster checks if a given backend resource name is tagged with
@@ -104,10 +113,15 @@ type L4ResourcesNamer interface {
 	L4IPv6HealthCheckFirewall(namespace, name string, shared bool) string
 	// IsNEG returns if the given name is a VM_IP_NEG name.
 	IsNEG(name string) bool
+	// IsL4Resource returns if the given name is the name of an L4 LoadBalancing resource of the cluster.
+	IsL4Resource(name string) bool
 }
 
 type ServiceAttachmentNamer interface {
 	// ServiceAttachment returns the name of the GCE Service Attachment resource for the given namespace,
 	// name, and Service Attachment CR UID
 	ServiceAttachment(namespace, name, saUID string) string
+	// ServiceAttachmentConsumer returns the name of the GCE Address and Forwarding Rule resources of
+	// the PSC consumer endpoint for the given namespace, name, and ServiceAttachmentConsumer CR UID
+	ServiceAttachmentConsumer(namespace, name, consumerUID string) string
 }
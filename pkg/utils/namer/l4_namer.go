@@ -142,6 +142,20 @@ func (namer *L4Namer) IsNEG(name string) bool {
 	return strings.HasPrefix(name, namer.v2Prefix+"-"+namer.v2ClusterUID)
 }
 
+// IsL4Resource indicates if the given name follows the L4 naming convention of this cluster.
+// Forwarding rule names have the protocol between the prefix and the cluster UID.
+func (namer *L4Namer) IsL4Resource(name string) bool {
+	rest, found := strings.CutPrefix(name, namer.v2Prefix+"-")
+	if !found {
+		return false
+	}
+	if strings.HasPrefix(rest, namer.v2ClusterUID+"-") {
+		return true
+	}
+	_, rest, found = strings.Cut(rest, "-")
+	return found && strings.HasPrefix(rest, namer.v2ClusterUID+"-")
+}
+
 // getClusterSuffix returns hash string of length 8 of a concatenated string generated from
 // kube-system uid, namespace and name. These fields in combination define an l4 load-balancer uniquely.
 func (n *L4Namer) getClusterSuffix(namespace, name string) string {
@@ -125,3 +125,24 @@ func TestL4Namer(t *testing.T) {
 		}
 	}
 }
+
+func TestL4NamerIsL4Resource(t *testing.T) {
+	newNamer := NewL4Namer(kubeSystemUID, nil)
+	for _, tc := range []struct {
+		name string
+		want bool
+	}{
+		{name: newNamer.L4Backend("namespace", "name"), want: true},
+		{name: newNamer.L4ForwardingRule("namespace", "name", "tcp"), want: true},
+		{name: newNamer.L4IPv6ForwardingRule("namespace", "name", "l3"), want: true},
+		{name: newNamer.L4HealthCheck("namespace", "name", true), want: true},
+		{name: NewL4Namer("other-cluster", nil).L4Backend("namespace", "name"), want: false},
+		{name: NewL4Namer("other-cluster", nil).L4ForwardingRule("namespace", "name", "udp"), want: false},
+		{name: "k8s1-7kpbhpki-namespace-name-956p2p7x", want: false},
+		{name: "k8s2-7kpbhpki", want: false},
+	} {
+		if got := newNamer.IsL4Resource(tc.name); got != tc.want {
+			t.Errorf("IsL4Resource(%q) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -319,6 +319,27 @@ func (n *Namer) InstanceGroup() string {
 	return n.decorateName(n.prefix + "-" + igPrefix)
 }
 
+// InstanceGroupShard constructs the name for the shard of the Instance Group
+// with the given index, when the nodes of a zone are split between multiple
+// Instance Groups. The first shard is the Instance Group itself.
+func (n *Namer) InstanceGroupShard(shard int) string {
+	if shard == 0 {
+		return n.InstanceGroup()
+	}
+	return n.decorateName(fmt.Sprintf("%v-%v-%d", n.prefix, igPrefix, shard))
+}
+
+// IsInstanceGroupShard returns true if the given name is the name of a shard
+// of the Instance Group other than the first one.
+func (n *Namer) IsInstanceGroupShard(name string) bool {
+	base := name
+	if uid := n.UID(); uid != "" {
+		base = strings.TrimSuffix(name, clusterNameDelimiter+uid)
+	}
+	shard, err := strconv.Atoi(strings.TrimPrefix(base, fmt.Sprintf("%v-%v-", n.prefix, igPrefix)))
+	return err == nil && shard > 0 && n.InstanceGroupShard(shard) == name
+}
+
 // firewallRuleSuffix constructs the glbc specific suffix for the FirewallRule.
 func (n *Namer) firewallRuleSuffix() string {
 	firewallName := n.Firewall()
@@ -461,6 +482,47 @@ func (n *Namer) RXLBBackendName(namespace, name string, port int32) string {
 	return fmt.Sprintf("%s-e-%s-%s-%s-%s", n.negPrefix(), truncNamespace, truncName, truncPort, negSuffix(n.shortUID(), namespace, name, portStr, ""))
 }
 
+// ResourceNEG returns the gce neg name of the NEG declared in the spec of the
+// ServiceNetworkEndpointGroup with the given namespace and name. Naming convention:
+//
+//	{prefix}{version}-{clusterid}-r-{namespace}-{name}-{hash}
+//
+// Dots, which are valid in Kubernetes object names, are replaced with dashes.
+// Output name is at most 63 characters.
+func (n *Namer) ResourceNEG(namespace, name string) string {
+	// minus 2, as we added "-r" to prefix
+	truncFields := TrimFieldsEvenly(maxNEGDescriptiveLabel-2, namespace, strings.ReplaceAll(name, ".", "-"))
+	truncNamespace := truncFields[0]
+	truncName := truncFields[1]
+	return fmt.Sprintf("%s-r-%s-%s-%s", n.negPrefix(), truncNamespace, truncName, negSuffix(n.shortUID(), namespace, name, "", ""))
+}
+
+// IngressFirewallRule returns the name of the L7 firewall rule of the Ingress
+// with the given namespace and name, when firewall rules are managed per load
+// balancer. Naming convention:
+//
+//	{prefix}{version}-{clusterid}-fw-{namespace}-{name}-{hash}
+//
+// Dots, which are valid in Kubernetes object names, are replaced with dashes.
+// Output name is at most 63 characters.
+func (n *Namer) IngressFirewallRule(namespace, name string) string {
+	// minus 3, as we added "-fw" to prefix
+	truncFields := TrimFieldsEvenly(maxNEGDescriptiveLabel-3, namespace, strings.ReplaceAll(name, ".", "-"))
+	truncNamespace := truncFields[0]
+	truncName := truncFields[1]
+	return fmt.Sprintf("%s-%s-%s-%s", n.ingressFirewallRulePrefix(), truncNamespace, truncName, negSuffix(n.shortUID(), namespace, name, "", ""))
+}
+
+// IsIngressFirewallRule returns true if the name is a per Ingress L7 firewall
+// rule owned by this cluster.
+func (n *Namer) IsIngressFirewallRule(name string) bool {
+	return strings.HasPrefix(name, n.ingressFirewallRulePrefix()+"-")
+}
+
+func (n *Namer) ingressFirewallRulePrefix() string {
+	return fmt.Sprintf("%s-fw", n.negPrefix())
+}
+
 // IsNEG returns true if the name is a NEG owned by this cluster.
 // It checks that the UID is present and a substring of the
 // cluster uid, since the NEG naming schema truncates it to 8 characters.
@@ -20,6 +20,7 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"k8s.io/klog/v2"
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -134,6 +135,7 @@ func TestNameBelongsToCluster(t *testing.T) {
 			// short names
 			newNamer.IGBackend(80),
 			newNamer.InstanceGroup(),
+			newNamer.InstanceGroupShard(3),
 			newNamer.TargetProxy(lbName, HTTPProtocol),
 			newNamer.TargetProxy(lbName, HTTPSProtocol),
 			newNamer.SSLCertName("default/my-ing", secretHash),
@@ -282,6 +284,36 @@ func TestNamerInstanceGroup(t *testing.T) {
 	}
 }
 
+func TestNamerInstanceGroupShard(t *testing.T) {
+	newNamer := NewNamer("uid1", "fw1", klog.TODO())
+	for shard, want := range map[int]string{
+		0:  "k8s-ig--uid1",
+		1:  "k8s-ig-1--uid1",
+		12: "k8s-ig-12--uid1",
+	} {
+		if name := newNamer.InstanceGroupShard(shard); name != want {
+			t.Errorf("newNamer.InstanceGroupShard(%d) = %q, want %q", shard, name, want)
+		}
+	}
+}
+
+func TestNamerIsInstanceGroupShard(t *testing.T) {
+	newNamer := NewNamer("uid1", "fw1", klog.TODO())
+	for name, want := range map[string]bool{
+		"k8s-ig-1--uid1":  true,
+		"k8s-ig-12--uid1": true,
+		"k8s-ig--uid1":    false,
+		"k8s-ig-0--uid1":  false,
+		"k8s-ig-1--uid2":  false,
+		"k8s-ig-foo":      false,
+		"k8s-ig-1":        false,
+	} {
+		if got := newNamer.IsInstanceGroupShard(name); got != want {
+			t.Errorf("newNamer.IsInstanceGroupShard(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
 func TestNamerFirewallRule(t *testing.T) {
 	newNamer := NewNamer("uid1", "fw1", klog.TODO())
 	name := newNamer.FirewallRule()
@@ -475,6 +507,111 @@ func TestNamerNEG(t *testing.T) {
 	}
 }
 
+func TestNamerResourceNEG(t *testing.T) {
+	longstring := "01234567890123456789012345678901234567890123456789"
+	gceNameRegexp := regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+	testCases := []struct {
+		desc      string
+		namespace string
+		name      string
+	}{
+		{
+			desc:      "simple case",
+			namespace: "namespace",
+			name:      "name",
+		},
+		{
+			desc:      "name with dots",
+			namespace: "namespace",
+			name:      "api.example.com",
+		},
+		{
+			desc:      "long name and namespace",
+			namespace: longstring,
+			name:      longstring + longstring + longstring + longstring + longstring,
+		},
+	}
+
+	newNamer := NewNamer(clusterId, "", klog.TODO())
+	for _, tc := range testCases {
+		res := newNamer.ResourceNEG(tc.namespace, tc.name)
+		if len(res) > 63 {
+			t.Errorf("%s: got len(res) == %v, want <= 63", tc.desc, len(res))
+		}
+		if !gceNameRegexp.MatchString(res) {
+			t.Errorf("%s: got %q, want a valid GCE resource name", tc.desc, res)
+		}
+		if !newNamer.IsNEG(res) {
+			t.Errorf("%s: newNamer.IsNEG(%q) = false, want true", tc.desc, res)
+		}
+	}
+
+	if got := newNamer.ResourceNEG("namespace", "name"); got != "k8s1-01234567-r-namespace-name-"+negSuffix(newNamer.shortUID(), "namespace", "name", "", "") {
+		t.Errorf(`newNamer.ResourceNEG("namespace", "name") = %q`, got)
+	}
+	// Names are unique across namespaces, even when truncated.
+	if newNamer.ResourceNEG("ns-a", "neg") == newNamer.ResourceNEG("ns-b", "neg") {
+		t.Errorf("ResourceNEG() returned the same name for CRs in different namespaces")
+	}
+	if newNamer.ResourceNEG("ns", "a.b") == newNamer.ResourceNEG("ns", "a-b") {
+		t.Errorf("ResourceNEG() returned the same name for CRs that only differ by dots")
+	}
+}
+
+func TestNamerIngressFirewallRule(t *testing.T) {
+	longstring := "01234567890123456789012345678901234567890123456789"
+	gceNameRegexp := regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+	testCases := []struct {
+		desc      string
+		namespace string
+		name      string
+	}{
+		{
+			desc:      "simple case",
+			namespace: "namespace",
+			name:      "name",
+		},
+		{
+			desc:      "name with dots",
+			namespace: "namespace",
+			name:      "api.example.com",
+		},
+		{
+			desc:      "long name and namespace",
+			namespace: longstring,
+			name:      longstring + longstring + longstring + longstring + longstring,
+		},
+	}
+
+	newNamer := NewNamer(clusterId, "", klog.TODO())
+	for _, tc := range testCases {
+		res := newNamer.IngressFirewallRule(tc.namespace, tc.name)
+		if len(res) > 63 {
+			t.Errorf("%s: got len(res) == %v, want <= 63", tc.desc, len(res))
+		}
+		if !gceNameRegexp.MatchString(res) {
+			t.Errorf("%s: got %q, want a valid GCE resource name", tc.desc, res)
+		}
+		if !newNamer.IsIngressFirewallRule(res) {
+			t.Errorf("%s: newNamer.IsIngressFirewallRule(%q) = false, want true", tc.desc, res)
+		}
+	}
+
+	if got := newNamer.IngressFirewallRule("namespace", "name"); got != "k8s1-01234567-fw-namespace-name-"+negSuffix(newNamer.shortUID(), "namespace", "name", "", "") {
+		t.Errorf(`newNamer.IngressFirewallRule("namespace", "name") = %q`, got)
+	}
+	if newNamer.IsIngressFirewallRule(newNamer.FirewallRule()) {
+		t.Errorf("IsIngressFirewallRule(%q) = true, want false", newNamer.FirewallRule())
+	}
+	otherNamer := NewNamer("other-cluster", "", klog.TODO())
+	if newNamer.IsIngressFirewallRule(otherNamer.IngressFirewallRule("namespace", "name")) {
+		t.Errorf("IsIngressFirewallRule() = true for the rule of another cluster, want false")
+	}
+	if newNamer.IngressFirewallRule("ns-a", "ing") == newNamer.IngressFirewallRule("ns-b", "ing") {
+		t.Errorf("IngressFirewallRule() returned the same name for Ingresses in different namespaces")
+	}
+}
+
 func TestNamerRXLBBackendName(t *testing.T) {
 	longstring := "01234567890123456789012345678901234567890123456789"
 	testCases := []struct {
@@ -26,6 +26,10 @@ const (
 	// - 2 (service attachment identifier prefix) - 8 (truncated kube system id) - 8 (suffix hash)
 	// - 5 (hyphen connectors) = 39
 	maxSADescriptiveLabel = 39
+
+	// maxPSCConsumerDescriptiveLabel is the max length for prefix, namespace, and name for
+	// PSC consumer endpoints. Same as maxSADescriptiveLabel with a 3 character identifier prefix.
+	maxPSCConsumerDescriptiveLabel = 38
 )
 
 // V1ServiceAttachment implements ServiceAttachmentNamer. This is a wrapper on top of namer.Namer.
@@ -37,14 +41,20 @@ type V1ServiceAttachmentNamer struct {
 	// attachment name.
 	// maxSADescriptiveLabel - len(prefix)
 	maxDescriptiveLabel int
+
+	// maxConsumerDescriptiveLabel is the max length for the namespace and name fields in the
+	// PSC consumer endpoint name.
+	// maxPSCConsumerDescriptiveLabel - len(prefix)
+	maxConsumerDescriptiveLabel int
 }
 
 // NewServiceAttachmentNamer returns a v1 namer for Service Attachments
 func NewServiceAttachmentNamer(namer *Namer, kubeSystemUID string) ServiceAttachmentNamer {
 	return &V1ServiceAttachmentNamer{
-		kubeSystemUID:       kubeSystemUID,
-		prefix:              namer.prefix,
-		maxDescriptiveLabel: maxSADescriptiveLabel - len(namer.prefix),
+		kubeSystemUID:               kubeSystemUID,
+		prefix:                      namer.prefix,
+		maxDescriptiveLabel:         maxSADescriptiveLabel - len(namer.prefix),
+		maxConsumerDescriptiveLabel: maxPSCConsumerDescriptiveLabel - len(namer.prefix),
 	}
 }
 
@@ -66,6 +76,24 @@ func (n *V1ServiceAttachmentNamer) ServiceAttachment(namespace, name, saUID stri
 	return fmt.Sprintf("%s%s-sa-%s-%s-%s-%s", n.prefix, schemaVersionV1, clusterUID, truncFields[0], truncFields[1], hash)
 }
 
+// ServiceAttachmentConsumer returns the name of the gce Address and Forwarding Rule of
+// a PSC consumer endpoint based on the ServiceAttachmentConsumer name, and namespace.
+// PSC consumer endpoint naming convention:
+//
+// k8s{naming version}-psc-{cluster-uid}-{namespace}-{name}-{hash}
+// Output name is at most 63 characters.
+// Hash is generated from the KubeSystemUID, Namespace, Name, and ServiceAttachmentConsumer UID
+//
+// WARNING: Controllers will use the naming convention to correlate between
+// the ServiceAttachmentConsumer CR and the consumer resources in GCE,
+// so modifications must be backwards compatible.
+func (n *V1ServiceAttachmentNamer) ServiceAttachmentConsumer(namespace, name, consumerUID string) string {
+	clusterUID := common.ContentHash(n.kubeSystemUID, clusterUIDLength)
+	hash := n.suffix(8, n.kubeSystemUID, namespace, name, consumerUID)
+	truncFields := TrimFieldsEvenly(n.maxConsumerDescriptiveLabel, namespace, name)
+	return fmt.Sprintf("%s%s-psc-%s-%s-%s-%s", n.prefix, schemaVersionV1, clusterUID, truncFields[0], truncFields[1], hash)
+}
+
 // hash returns an 8 character hash code of the provided fields
 func (n *V1ServiceAttachmentNamer) suffix(numCharacters int, fields ...string) string {
 	concatenatedString := strings.Join(fields, ";")
@@ -97,3 +97,69 @@ func TestNamerServiceAttachment(t *testing.T) {
 		}
 	}
 }
+
+func TestNamerServiceAttachmentConsumer(t *testing.T) {
+	longstring := "01234567890123456789012345678901234567890123456789"
+	consumerUID := "consumer-uid"
+	prefix := "prefix"
+	testCases := []struct {
+		desc                string
+		namespace           string
+		name                string
+		expectDefaultPrefix string
+		expectCustomPrefix  string
+	}{
+		{
+			"simple case",
+			"namespace",
+			"name",
+			"k8s1-psc-7kpbhpki-namespace-name-25dc2qg0",
+			"prefix1-psc-7kpbhpki-namespace-name-25dc2qg0",
+		},
+		{
+			"long namespace",
+			longstring,
+			"name",
+			"k8s1-psc-7kpbhpki-012345678901234567890123456789012-na-liodsyd3",
+			"prefix1-psc-7kpbhpki-012345678901234567890123456789-na-liodsyd3",
+		},
+		{
+			"long name and namespace",
+			longstring,
+			longstring,
+			"k8s1-psc-7kpbhpki-012345678901234567-01234567890123456-a7069zgc",
+			"prefix1-psc-7kpbhpki-0123456789012345-0123456789012345-a7069zgc",
+		},
+		{
+			"long name",
+			"namespace",
+			longstring,
+			"k8s1-psc-7kpbhpki-namesp-01234567890123456789012345678-wmakfwxs",
+			"prefix1-psc-7kpbhpki-names-012345678901234567890123456-wmakfwxs",
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, withPrefix := range []bool{true, false} {
+			var oldNamer *Namer
+			var expectedName string
+
+			if withPrefix {
+				oldNamer = NewNamer(clusterId, "", klog.TODO())
+				expectedName = tc.expectDefaultPrefix
+			} else {
+				oldNamer = NewNamerWithPrefix(prefix, clusterId, "", klog.TODO())
+				expectedName = tc.expectCustomPrefix
+			}
+
+			newNamer := NewServiceAttachmentNamer(oldNamer, kubeSystemUID)
+			res := newNamer.ServiceAttachmentConsumer(tc.namespace, tc.name, consumerUID)
+			if len(res) > 63 {
+				t.Errorf("%s: got len(res) == %v, want <= 63", tc.desc, len(res))
+			}
+			if res != expectedName {
+				t.Errorf("%s: got %q, want %q", tc.desc, res, expectedName)
+			}
+		}
+	}
+}
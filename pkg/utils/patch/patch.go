@@ -76,6 +76,15 @@ func PatchServiceObjectMetadata(client coreclient.CoreV1Interface, svc *corev1.S
 	return err
 }
 
+// PatchServiceConditions patches the given service's status conditions
+// based on the new conditions.
+func PatchServiceConditions(client coreclient.CoreV1Interface, svc *corev1.Service, newConditions []metav1.Condition) error {
+	newSvc := svc.DeepCopy()
+	newSvc.Status.Conditions = newConditions
+	_, err := svchelpers.PatchService(client, svc, newSvc)
+	return err
+}
+
 // PatchServiceLoadBalancerStatus patches the given service's LoadBalancerStatus
 // based on new service's load-balancer status.
 func PatchServiceLoadBalancerStatus(client coreclient.CoreV1Interface, svc *corev1.Service, newStatus corev1.LoadBalancerStatus) error {
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/ingress-gce/pkg/annotations"
 	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	negv1beta1 "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1"
 	"k8s.io/ingress-gce/pkg/utils/namer"
 )
 
@@ -73,6 +74,10 @@ type ServicePort struct {
 	// Traffic policy fields that apply if non-nil.
 	MaxRatePerEndpoint *float64
 	CapacityScaler     *float64
+	// ResourceNEGType is set if the backend is a NEG declared in the spec of a
+	// ServiceNetworkEndpointGroup rather than a Service. In this case,
+	// ID.Service holds the namespace and name of the ServiceNetworkEndpointGroup.
+	ResourceNEGType negv1beta1.NetworkEndpointType
 }
 
 // GetDescription returns a Description for this ServicePort.
@@ -85,6 +90,9 @@ func (sp *ServicePort) GetDescription() Description {
 
 // BackendName returns the name of the backend which would be used for this ServicePort.
 func (sp *ServicePort) BackendName() string {
+	if sp.IsResourceNEG() {
+		return sp.NEGName()
+	}
 	if sp.L7XLBRegionalEnabled {
 		return sp.BackendNamer.RXLBBackendName(sp.ID.Service.Namespace, sp.ID.Service.Name, sp.Port)
 	} else if sp.NEGEnabled || sp.VMIPNEGEnabled || sp.L4RBSEnabled {
@@ -95,6 +103,10 @@ func (sp *ServicePort) BackendName() string {
 }
 
 func (sp *ServicePort) NEGName() string {
+	if sp.IsResourceNEG() {
+		// Resource NEGs are named after the ServiceNetworkEndpointGroup.
+		return sp.BackendNamer.ResourceNEG(sp.ID.Service.Namespace, sp.ID.Service.Name)
+	}
 	if sp.VMIPNEGEnabled || sp.L4RBSEnabled {
 		// Use L4 Backend name for both Internal and External LoadBalancers
 		return sp.BackendNamer.L4Backend(sp.ID.Service.Namespace, sp.ID.Service.Name)
@@ -102,6 +114,12 @@ func (sp *ServicePort) NEGName() string {
 	return sp.BackendNamer.NEG(sp.ID.Service.Namespace, sp.ID.Service.Name, sp.Port)
 }
 
+// IsResourceNEG returns true if the backend is a NEG declared in a
+// ServiceNetworkEndpointGroup.
+func (sp *ServicePort) IsResourceNEG() bool {
+	return sp.ResourceNEGType != ""
+}
+
 // IGName returns the name of the instance group which would be used for this ServicePort.
 func (sp *ServicePort) IGName() string {
 	return sp.BackendNamer.InstanceGroup()
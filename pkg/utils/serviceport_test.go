@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"k8s.io/apimachinery/pkg/types"
+	negv1beta1 "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1"
 	"k8s.io/ingress-gce/pkg/utils/namer"
 )
 
@@ -252,6 +253,21 @@ func TestBackendName(t *testing.T) {
 			},
 			wantBackendName: "k8s1-uid1-namespacenamespacenam-namenamenamename-1-e3670135",
 		},
+		{
+			desc: "Resource NEG",
+			svcPort: ServicePort{
+				ResourceNEGType: negv1beta1.ServerlessEndpointType,
+				ID: ServicePortID{
+					Service: types.NamespacedName{
+						Namespace: shortNamespace,
+						Name:      shortName,
+					},
+				},
+				BackendNamer: defaultNamer,
+			},
+			// The backend service is named after the NEG it targets.
+			wantBackendName: defaultNamer.ResourceNEG(shortNamespace, shortName),
+		},
 		{
 			desc: "short Instance Group",
 			svcPort: ServicePort{
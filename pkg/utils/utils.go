@@ -90,6 +90,13 @@ const (
 
 	// LabelNodeSubnet specifies the subnet name of this node.
 	LabelNodeSubnet = "cloud.google.com/gke-node-pool-subnet"
+
+	// L3DefaultProtocol is the forwarding rule protocol used for L4 Services
+	// that mix TCP and UDP ports on a single VIP.
+	L3DefaultProtocol = "L3_DEFAULT"
+	// UnspecifiedProtocol is the backend service protocol that has to be used
+	// together with L3DefaultProtocol forwarding rules.
+	UnspecifiedProtocol = "UNSPECIFIED"
 )
 
 var networkTierErrorRegexp = regexp.MustCompile(`The network tier of external IP is STANDARD|PREMIUM, that of Address must be the same.`)
@@ -640,6 +647,46 @@ func GetProtocol(svcPorts []api_v1.ServicePort) api_v1.Protocol {
 	return svcPorts[0].Protocol
 }
 
+// HasMixedProtocols returns true if the given Service ports use more than one protocol.
+func HasMixedProtocols(svcPorts []api_v1.ServicePort) bool {
+	for _, p := range svcPorts {
+		if p.Protocol != svcPorts[0].Protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// GetForwardingRuleProtocol returns the protocol of the L4 forwarding rule for the given Service ports.
+// Services that mix protocols are served by a single L3_DEFAULT forwarding rule.
+func GetForwardingRuleProtocol(svcPorts []api_v1.ServicePort) string {
+	if HasMixedProtocols(svcPorts) {
+		return L3DefaultProtocol
+	}
+	return string(GetProtocol(svcPorts))
+}
+
+// GetBackendServiceProtocol returns the protocol of the L4 backend service for the given Service ports.
+func GetBackendServiceProtocol(svcPorts []api_v1.ServicePort) string {
+	if HasMixedProtocols(svcPorts) {
+		return UnspecifiedProtocol
+	}
+	return string(GetProtocol(svcPorts))
+}
+
+// GetServicePortRangesByProtocol returns the port ranges of the given Service ports, grouped by protocol.
+func GetServicePortRangesByProtocol(svcPorts []api_v1.ServicePort) map[string][]string {
+	portsByProtocol := map[string][]api_v1.ServicePort{}
+	for _, p := range svcPorts {
+		portsByProtocol[string(p.Protocol)] = append(portsByProtocol[string(p.Protocol)], p)
+	}
+	ranges := map[string][]string{}
+	for protocol, ports := range portsByProtocol {
+		ranges[protocol] = GetServicePortRanges(ports)
+	}
+	return ranges
+}
+
 func GetPorts(svcPorts []api_v1.ServicePort) []string {
 	ports := []string{}
 	for _, p := range svcPorts {
@@ -1398,6 +1398,61 @@ func TestGetProtocol(t *testing.T) {
 	}
 }
 
+func TestMixedProtocols(t *testing.T) {
+	tcpPort := api_v1.ServicePort{Name: "tcp", Port: 53, Protocol: api_v1.ProtocolTCP}
+	udpPort := api_v1.ServicePort{Name: "udp", Port: 53, Protocol: api_v1.ProtocolUDP}
+	udpPort2 := api_v1.ServicePort{Name: "udp2", Port: 54, Protocol: api_v1.ProtocolUDP}
+
+	testCases := []struct {
+		desc                 string
+		ports                []api_v1.ServicePort
+		wantMixed            bool
+		wantFRProtocol       string
+		wantBSProtocol       string
+		wantRangesByProtocol map[string][]string
+	}{
+		{
+			desc:                 "Empty ports",
+			ports:                []api_v1.ServicePort{},
+			wantFRProtocol:       "TCP",
+			wantBSProtocol:       "TCP",
+			wantRangesByProtocol: map[string][]string{},
+		},
+		{
+			desc:                 "Single protocol",
+			ports:                []api_v1.ServicePort{udpPort, udpPort2},
+			wantFRProtocol:       "UDP",
+			wantBSProtocol:       "UDP",
+			wantRangesByProtocol: map[string][]string{"UDP": {"53-54"}},
+		},
+		{
+			desc:                 "Mixed protocols",
+			ports:                []api_v1.ServicePort{tcpPort, udpPort, udpPort2},
+			wantMixed:            true,
+			wantFRProtocol:       L3DefaultProtocol,
+			wantBSProtocol:       UnspecifiedProtocol,
+			wantRangesByProtocol: map[string][]string{"TCP": {"53"}, "UDP": {"53-54"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := HasMixedProtocols(tc.ports); got != tc.wantMixed {
+				t.Errorf("HasMixedProtocols() = %v, want %v", got, tc.wantMixed)
+			}
+			if got := GetForwardingRuleProtocol(tc.ports); got != tc.wantFRProtocol {
+				t.Errorf("GetForwardingRuleProtocol() = %q, want %q", got, tc.wantFRProtocol)
+			}
+			if got := GetBackendServiceProtocol(tc.ports); got != tc.wantBSProtocol {
+				t.Errorf("GetBackendServiceProtocol() = %q, want %q", got, tc.wantBSProtocol)
+			}
+			if got := GetServicePortRangesByProtocol(tc.ports); !reflect.DeepEqual(got, tc.wantRangesByProtocol) {
+				t.Errorf("GetServicePortRangesByProtocol() = %v, want %v", got, tc.wantRangesByProtocol)
+			}
+		})
+	}
+}
+
 func TestGetPorts(t *testing.T) {
 	testCases := []struct {
 		ports         []api_v1.ServicePort